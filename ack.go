@@ -0,0 +1,130 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrEventDiscarded is reported to TrackWithAck when the client discarded the
+// event before the server acknowledged it, e.g. because it was sampled out,
+// expired or evicted, or the client was disposed. It is wrapped with the
+// reason; check for it with errors.Is.
+var ErrEventDiscarded = errors.New("event discarded before delivery")
+
+// discarded returns ErrEventDiscarded wrapped with reason.
+func discarded(reason string) error {
+	return fmt.Errorf("%w: %s", ErrEventDiscarded, reason)
+}
+
+// ackRegistry holds the channels of events tracked with TrackWithAck, keyed
+// by event ID, until their delivery outcome is known.
+type ackRegistry struct {
+	mu      sync.Mutex
+	waiters map[string]chan error
+}
+
+// register returns the channel resolved with id's outcome.
+func (r *ackRegistry) register(id string) <-chan error {
+	ack := make(chan error, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.waiters == nil {
+		r.waiters = make(map[string]chan error)
+	}
+	r.waiters[id] = ack
+	return ack
+}
+
+// forget drops id's channel without resolving it.
+func (r *ackRegistry) forget(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.waiters, id)
+}
+
+// resolve sends err to, and closes, the channels of any of events awaiting
+// an ack.
+func (r *ackRegistry) resolve(events []Event, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.waiters) == 0 {
+		return
+	}
+	for _, event := range events {
+		if ack, ok := r.waiters[event.ID]; ok && event.ID != "" {
+			delete(r.waiters, event.ID)
+			ack <- err
+			close(ack)
+		}
+	}
+}
+
+// resolveAll resolves every pending channel with err.
+func (r *ackRegistry) resolveAll(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, ack := range r.waiters {
+		delete(r.waiters, id)
+		ack <- err
+		close(ack)
+	}
+}
+
+// resolvedAck returns a channel already resolved with err.
+func resolvedAck(err error) <-chan error {
+	ack := make(chan error, 1)
+	ack <- err
+	close(ack)
+	return ack
+}
+
+// EnqueueWithAck enqueues event like Enqueue and returns a channel that
+// receives nil once a batch containing it is acknowledged with a 2xx
+// response, or the error it was dropped with, and is then closed. The event
+// is given an ID if it has none.
+func (d *Dispatcher) EnqueueWithAck(event Event) (<-chan error, error) {
+	if event.ID == "" {
+		event.ID = newUUID()
+	}
+	ack := d.acks.register(event.ID)
+	if err := d.enqueue(event); err != nil {
+		d.acks.forget(event.ID)
+		return nil, err
+	}
+	return ack, nil
+}
+
+// TrackWithAck tracks an event like Track and returns a channel resolved
+// with its delivery outcome: nil once the batch containing it is
+// acknowledged by the server, or an error if it is dropped, either an
+// *HTTPError from the server or ErrEventDiscarded. The channel receives one
+// value and is then closed. Events that fail and are re-queued stay pending
+// until delivered or dropped, so wait with a timeout, e.g.:
+//
+//	ack, err := client.TrackWithAck("payment_captured", payload, nil)
+//	if err != nil {
+//		return err
+//	}
+//	select {
+//	case err := <-ack:
+//		// nil: the server has the event
+//	case <-ctx.Done():
+//	}
+//
+// The event is sent with an ID (see ClientConfig.EventIDs) to match the ack.
+func (c *Client) TrackWithAck(name string, payload, metadata map[string]any) (<-chan error, error) {
+	if c.disposed {
+		return nil, errDisposed
+	}
+	event, err := c.buildEvent(name, payload, metadata)
+	if event == nil {
+		if err != nil {
+			return nil, err
+		}
+		return resolvedAck(discarded("not tracked")), nil
+	}
+
+	c.loggerAdapter.Debug("Tracking event with ack: %s", name)
+	return c.dispatcher.EnqueueWithAck(*event)
+}
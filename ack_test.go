@@ -0,0 +1,154 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// awaitAck returns the value ack resolves with, failing after a second.
+func awaitAck(t *testing.T, ack <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-ack:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("ack not resolved")
+		return nil
+	}
+}
+
+// assertPending fails if ack has been resolved.
+func assertPending(t *testing.T, ack <-chan error) {
+	t.Helper()
+	select {
+	case err := <-ack:
+		t.Fatalf("expected ack to be pending, got %v", err)
+	default:
+	}
+}
+
+func TestDispatcher_EnqueueWithAck(t *testing.T) {
+	newDispatcher := func(httpAdapter HTTPAdapter) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  10,
+			MaxRetries:    0,
+		}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should resolve with nil once the batch is acknowledged", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newDispatcher(httpAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		ack, err := d.EnqueueWithAck(Event{Name: "audit"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertPending(t, ack)
+		d.Flush()
+
+		if err := awaitAck(t, ack); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		if _, open := <-ack; open {
+			t.Fatal("expected the channel to be closed")
+		}
+		if httpAdapter.Requests()[0].Events[0].ID == "" {
+			t.Fatal("expected the event to be sent with an ID")
+		}
+	})
+
+	t.Run("should stay pending while a failed batch is re-queued", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503},
+			adapters.Scenario{Status: 200},
+		)
+		d := newDispatcher(httpAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		ack, _ := d.EnqueueWithAck(Event{Name: "audit"})
+		d.Flush()
+		assertPending(t, ack)
+
+		d.Flush()
+		if err := awaitAck(t, ack); err != nil {
+			t.Fatalf("expected nil after the retry, got %v", err)
+		}
+	})
+
+	t.Run("should resolve with the error of a dropped batch", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 400})
+		d := newDispatcher(httpAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		ack, _ := d.EnqueueWithAck(Event{Name: "audit"})
+		d.Flush()
+
+		var httpErr *HTTPError
+		if err := awaitAck(t, ack); !errors.As(err, &httpErr) || httpErr.Status != 400 {
+			t.Fatalf("expected a 400 HTTPError, got %v", err)
+		}
+	})
+
+	t.Run("should report pending events as discarded on dispose", func(t *testing.T) {
+		d := newDispatcher(adapters.NewScriptedHTTPAdapter())
+		d.Restore()
+
+		ack, _ := d.EnqueueWithAck(Event{Name: "audit"})
+		d.Dispose()
+
+		if err := awaitAck(t, ack); !errors.Is(err, ErrEventDiscarded) {
+			t.Fatalf("expected ErrEventDiscarded, got %v", err)
+		}
+	})
+
+	t.Run("should report events dropped from the queue as discarded", func(t *testing.T) {
+		d := newDispatcher(adapters.NewScriptedHTTPAdapter())
+		d.Restore()
+		defer d.Dispose()
+
+		ack, _ := d.EnqueueWithAck(Event{Name: "audit"})
+		if dropped := d.DropQueue(); dropped != 1 {
+			t.Fatalf("expected 1 dropped event, got %d", dropped)
+		}
+
+		if err := awaitAck(t, ack); !errors.Is(err, ErrEventDiscarded) {
+			t.Fatalf("expected ErrEventDiscarded, got %v", err)
+		}
+	})
+}
+
+func TestClient_TrackWithAck(t *testing.T) {
+	t.Run("should resolve events that are not tracked as discarded", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+		client.Disable()
+
+		ack, err := client.TrackWithAck("audit", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := awaitAck(t, ack); !errors.Is(err, ErrEventDiscarded) {
+			t.Fatalf("expected ErrEventDiscarded, got %v", err)
+		}
+	})
+
+	t.Run("should return tracking errors", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if _, err := client.TrackWithAck("", nil, nil); err == nil {
+			t.Fatal("expected error for an empty name")
+		}
+	})
+}
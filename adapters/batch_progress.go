@@ -0,0 +1,86 @@
+package adapters
+
+import "sync"
+
+// maxProgressBatches bounds how many partially delivered batches a
+// batchProgress remembers. Beyond it the oldest are forgotten and, if
+// retried, resent in full.
+const maxProgressBatches = 1024
+
+// itemState is the outcome of one part of a batch, such as a sink or an
+// event, in earlier attempts.
+type itemState uint8
+
+const (
+	itemPending itemState = iota
+	itemAccepted
+	itemRejected
+)
+
+// batchProgress remembers which parts of partially delivered batches are
+// done, keyed by Batch.ID, so adapters delivering a batch in several parts
+// only resend the pending ones when the dispatcher retries it.
+type batchProgress struct {
+	mu     sync.Mutex
+	states map[string][]itemState
+	order  []string // batch IDs in states, oldest first
+}
+
+// load returns a copy of the states of the n parts of the batch with id,
+// all pending if it is unknown or id is empty.
+func (p *batchProgress) load(id string, n int) []itemState {
+	states := make([]itemState, n)
+	if id == "" {
+		return states
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	copy(states, p.states[id])
+	return states
+}
+
+// save records the states of the batch with id.
+func (p *batchProgress) save(id string, states []itemState) {
+	if id == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.states == nil {
+		p.states = make(map[string][]itemState)
+	}
+	if _, ok := p.states[id]; !ok {
+		p.order = append(p.order, id)
+		if len(p.order) > maxProgressBatches {
+			delete(p.states, p.order[0])
+			p.order = p.order[1:]
+		}
+	}
+	p.states[id] = states
+}
+
+// drop forgets the batch with id once it is fully delivered.
+func (p *batchProgress) drop(id string) {
+	if id == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.states[id]; !ok {
+		return
+	}
+	delete(p.states, id)
+	for i, pending := range p.order {
+		if pending == id {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// len returns the number of remembered batches.
+func (p *batchProgress) len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.states)
+}
@@ -0,0 +1,48 @@
+package adapters
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestBatchProgress(t *testing.T) {
+	t.Run("should remember states until dropped", func(t *testing.T) {
+		var p batchProgress
+		p.save("b-1", []itemState{itemAccepted, itemPending})
+
+		states := p.load("b-1", 2)
+		if states[0] != itemAccepted || states[1] != itemPending {
+			t.Fatalf("unexpected states: %v", states)
+		}
+		states[1] = itemRejected
+		if p.load("b-1", 2)[1] != itemPending {
+			t.Fatal("expected load to return a copy")
+		}
+
+		p.drop("b-1")
+		if p.len() != 0 || p.load("b-1", 2)[0] != itemPending {
+			t.Fatal("expected the batch to be forgotten")
+		}
+	})
+
+	t.Run("should not track batches without an ID", func(t *testing.T) {
+		var p batchProgress
+		p.save("", []itemState{itemAccepted})
+		if p.len() != 0 {
+			t.Fatal("expected nothing to be remembered")
+		}
+	})
+
+	t.Run("should forget the oldest batches beyond the limit", func(t *testing.T) {
+		var p batchProgress
+		for i := range maxProgressBatches + 1 {
+			p.save(strconv.Itoa(i), []itemState{itemAccepted})
+		}
+		if p.len() != maxProgressBatches || len(p.order) != maxProgressBatches {
+			t.Fatalf("expected %d remembered batches, got %d", maxProgressBatches, p.len())
+		}
+		if p.load("0", 1)[0] != itemPending {
+			t.Fatal("expected the oldest batch to be forgotten")
+		}
+	})
+}
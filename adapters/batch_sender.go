@@ -0,0 +1,56 @@
+package adapters
+
+import (
+	"context"
+	"time"
+)
+
+// Batch is a single delivery attempt of a batch of events, with the metadata
+// the dispatcher tracks for it.
+type Batch struct {
+	// ID identifies the batch and stays the same across retries, so servers
+	// can use it as an idempotency key. It is also sent as a header.
+	ID string
+
+	// Endpoint is the URL the batch should be sent to.
+	Endpoint string
+
+	// Events are the events in the batch.
+	Events []Event
+
+	// Headers are the HTTP headers to send, including the API key.
+	Headers map[string]string
+
+	// Attempt is the retry attempt, starting at 0.
+	Attempt int
+
+	// SentAt is when this attempt was made. Unlike the events' IssuedAt it
+	// changes on every retry, so servers can compute how long events waited
+	// in the client. Adapters use the current time if it is zero.
+	SentAt time.Time
+}
+
+// BatchSender is an optional extension of HTTPAdapter. When implemented, the
+// dispatcher calls SendBatch instead of SendWithContext, giving the adapter
+// access to batch metadata such as the batch ID and retry attempt.
+type BatchSender interface {
+	// SendBatch sends a batch and returns the HTTP response or error, with
+	// the same semantics as HTTPAdapter.SendWithContext.
+	SendBatch(ctx context.Context, batch Batch) (*HTTPResponse, error)
+}
+
+// EventRetainer is an optional extension of HTTPAdapter. The dispatcher
+// reuses the slices it sends batches from, so it copies the events of every
+// send unless the adapter implements EventRetainer and reports false.
+type EventRetainer interface {
+	// RetainsEvents reports whether the adapter may still read the events
+	// slice of a send after the send has returned.
+	RetainsEvents() bool
+}
+
+// retainsEvents reports whether adapter may keep the events of a send,
+// assuming it does unless it implements EventRetainer.
+func retainsEvents(adapter HTTPAdapter) bool {
+	retainer, ok := adapter.(EventRetainer)
+	return !ok || retainer.RetainsEvents()
+}
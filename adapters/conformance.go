@@ -0,0 +1,315 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// conformanceLargeBatchSize is the batch size used by the large-batch checks.
+const conformanceLargeBatchSize = 1000
+
+// TestHTTPAdapter runs the SDK's contract checks against an HTTPAdapter.
+// Call it from a regular test in the package implementing the adapter:
+//
+//	func TestMyHTTPAdapter(t *testing.T) {
+//		adapters.TestHTTPAdapter(t, func() adapters.HTTPAdapter { return NewMyHTTPAdapter() })
+//	}
+//
+// The factory is called once per check. The adapter must deliver events to an
+// arbitrary endpoint as a JSON POST body of the form {"events": [...]}.
+func TestHTTPAdapter(t *testing.T, factory func() HTTPAdapter) {
+	t.Helper()
+
+	type received struct {
+		method      string
+		contentType string
+		headers     http.Header
+		events      []Event
+	}
+
+	newServer := func(t *testing.T, status int) (*httptest.Server, <-chan received) {
+		ch := make(chan received, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Events []Event `json:"events"`
+			}
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			select {
+			case ch <- received{
+				method:      r.Method,
+				contentType: r.Header.Get("Content-Type"),
+				headers:     r.Header.Clone(),
+				events:      body.Events,
+			}:
+			default:
+			}
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+		return server, ch
+	}
+
+	t.Run("should POST events as JSON", func(t *testing.T) {
+		server, ch := newServer(t, http.StatusOK)
+		events := conformanceEvents(3)
+
+		resp, err := factory().SendWithContext(context.Background(), server.URL, events, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp == nil || resp.Status != http.StatusOK {
+			t.Fatalf("expected status 200, got %+v", resp)
+		}
+
+		got := <-ch
+		if got.method != http.MethodPost {
+			t.Errorf("expected POST, got %s", got.method)
+		}
+		if got.contentType != "application/json" {
+			t.Errorf("expected Content-Type application/json, got %q", got.contentType)
+		}
+		assertEventsEqual(t, events, got.events)
+	})
+
+	t.Run("should pass headers", func(t *testing.T) {
+		server, ch := newServer(t, http.StatusOK)
+		headers := map[string]string{"X-API-Key": "conformance", "X-Custom": "value"}
+
+		if _, err := factory().SendWithContext(context.Background(), server.URL, conformanceEvents(1), headers); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := <-ch
+		for key, value := range headers {
+			if got.headers.Get(key) != value {
+				t.Errorf("expected header %s=%q, got %q", key, value, got.headers.Get(key))
+			}
+		}
+	})
+
+	if _, ok := factory().(BatchSender); ok {
+		t.Run("should send batches with their headers", func(t *testing.T) {
+			server, ch := newServer(t, http.StatusOK)
+			events := conformanceEvents(2)
+			batch := Batch{
+				ID:       "conformance-batch",
+				Endpoint: server.URL,
+				Events:   events,
+				Headers:  map[string]string{"X-Batch-Id": "conformance-batch"},
+			}
+
+			resp, err := factory().(BatchSender).SendBatch(context.Background(), batch)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp == nil || resp.Status != http.StatusOK {
+				t.Fatalf("expected status 200, got %+v", resp)
+			}
+
+			got := <-ch
+			if got.headers.Get("X-Batch-Id") != "conformance-batch" {
+				t.Errorf("expected X-Batch-Id header, got %q", got.headers.Get("X-Batch-Id"))
+			}
+			assertEventsEqual(t, events, got.events)
+		})
+	}
+
+	t.Run("should report non-2xx statuses without error", func(t *testing.T) {
+		for _, status := range []int{http.StatusBadRequest, http.StatusInternalServerError} {
+			server, _ := newServer(t, status)
+
+			resp, err := factory().SendWithContext(context.Background(), server.URL, conformanceEvents(1), nil)
+			if err != nil {
+				t.Fatalf("status %d: unexpected error: %v", status, err)
+			}
+			if resp == nil || resp.Status != status {
+				t.Fatalf("expected status %d, got %+v", status, resp)
+			}
+		}
+	})
+
+	t.Run("should deliver large batches intact", func(t *testing.T) {
+		server, ch := newServer(t, http.StatusOK)
+		events := conformanceEvents(conformanceLargeBatchSize)
+
+		if _, err := factory().SendWithContext(context.Background(), server.URL, events, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assertEventsEqual(t, events, (<-ch).events)
+	})
+
+	t.Run("should support Send without context", func(t *testing.T) {
+		server, _ := newServer(t, http.StatusOK)
+
+		resp, err := factory().Send(server.URL, conformanceEvents(1), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp == nil || resp.Status != http.StatusOK {
+			t.Fatalf("expected status 200, got %+v", resp)
+		}
+	})
+
+	t.Run("should return an error for a cancelled context", func(t *testing.T) {
+		server, _ := newServer(t, http.StatusOK)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := factory().SendWithContext(ctx, server.URL, conformanceEvents(1), nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should return an error for an unreachable endpoint", func(t *testing.T) {
+		server, _ := newServer(t, http.StatusOK)
+		url := server.URL
+		server.Close()
+
+		if _, err := factory().SendWithContext(context.Background(), url, conformanceEvents(1), nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+// TestStorageAdapter runs the SDK's contract checks against a StorageAdapter.
+// Call it from a regular test in the package implementing the adapter:
+//
+//	func TestMyStorageAdapter(t *testing.T) {
+//		adapters.TestStorageAdapter(t, func() adapters.StorageAdapter { return NewMyStorageAdapter(t.TempDir()) })
+//	}
+//
+// The factory is called once per check and must return an adapter with empty
+// storage. Save must replace, not append to, the stored events, because the
+// dispatcher always saves its full queue.
+func TestStorageAdapter(t *testing.T, factory func() StorageAdapter) {
+	t.Helper()
+
+	newAdapter := func(t *testing.T) StorageAdapter {
+		storage := factory()
+		t.Cleanup(func() { _ = storage.Close() })
+		return storage
+	}
+
+	t.Run("should load nothing from empty storage", func(t *testing.T) {
+		events, err := newAdapter(t).Load()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("expected no events, got %d", len(events))
+		}
+	})
+
+	t.Run("should round-trip saved events", func(t *testing.T) {
+		storage := newAdapter(t)
+		events := conformanceEvents(3)
+
+		if err := storage.Save(events); err != nil {
+			t.Fatalf("unexpected save error: %v", err)
+		}
+		loaded, err := storage.Load()
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		assertEventsEqual(t, events, loaded)
+	})
+
+	t.Run("should replace events on save", func(t *testing.T) {
+		storage := newAdapter(t)
+		_ = storage.Save(conformanceEvents(5))
+		events := conformanceEvents(2)
+
+		if err := storage.Save(events); err != nil {
+			t.Fatalf("unexpected save error: %v", err)
+		}
+		loaded, _ := storage.Load()
+		assertEventsEqual(t, events, loaded)
+	})
+
+	t.Run("should clear stored events", func(t *testing.T) {
+		storage := newAdapter(t)
+		_ = storage.Save(conformanceEvents(3))
+
+		if err := storage.Clear(); err != nil {
+			t.Fatalf("unexpected clear error: %v", err)
+		}
+		loaded, err := storage.Load()
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		if len(loaded) != 0 {
+			t.Fatalf("expected no events after clear, got %d", len(loaded))
+		}
+	})
+
+	t.Run("should clear idempotently", func(t *testing.T) {
+		storage := newAdapter(t)
+
+		if err := storage.Clear(); err != nil {
+			t.Fatalf("unexpected error clearing empty storage: %v", err)
+		}
+		_ = storage.Save(conformanceEvents(1))
+		for i := 0; i < 2; i++ {
+			if err := storage.Clear(); err != nil {
+				t.Fatalf("unexpected error on clear %d: %v", i+1, err)
+			}
+		}
+	})
+
+	t.Run("should save and load large batches", func(t *testing.T) {
+		storage := newAdapter(t)
+		events := conformanceEvents(conformanceLargeBatchSize)
+
+		if err := storage.Save(events); err != nil {
+			t.Fatalf("unexpected save error: %v", err)
+		}
+		loaded, err := storage.Load()
+		if err != nil {
+			t.Fatalf("unexpected load error: %v", err)
+		}
+		assertEventsEqual(t, events, loaded)
+	})
+
+	t.Run("should close without error", func(t *testing.T) {
+		if err := factory().Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+// conformanceEvents builds n events with every field populated.
+func conformanceEvents(n int) []Event {
+	sessionID := "session-1"
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = Event{
+			Name:      fmt.Sprintf("event_%d", i),
+			Payload:   map[string]any{"index": i, "nested": map[string]any{"ok": true}},
+			Metadata:  map[string]any{"schemaVersion": "1.0.0"},
+			IssuedAt:  int64(1700000000000 + i),
+			SessionID: &sessionID,
+			Platform:  &Platform{Type: "server"},
+		}
+	}
+	return events
+}
+
+// assertEventsEqual compares events by their JSON encoding, since that is the
+// form adapters transmit and persist.
+func assertEventsEqual(t *testing.T, want, got []Event) {
+	t.Helper()
+	if len(want) != len(got) {
+		t.Fatalf("expected %d events, got %d", len(want), len(got))
+	}
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(wantJSON) != string(gotJSON) {
+		t.Fatalf("events differ:\nwant %s\n got %s", wantJSON, gotJSON)
+	}
+}
@@ -0,0 +1,44 @@
+package adapters
+
+import (
+	"sync"
+	"testing"
+)
+
+// memoryStorageAdapter is a minimal conforming StorageAdapter.
+type memoryStorageAdapter struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (m *memoryStorageAdapter) Save(events []Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append([]Event(nil), events...)
+	return nil
+}
+
+func (m *memoryStorageAdapter) Load() ([]Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Event(nil), m.events...), nil
+}
+
+func (m *memoryStorageAdapter) Clear() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = nil
+	return nil
+}
+
+func (m *memoryStorageAdapter) Close() error {
+	return nil
+}
+
+func TestNetHTTPAdapter_Conformance(t *testing.T) {
+	TestHTTPAdapter(t, NewNetHTTPAdapter)
+}
+
+func TestStorageAdapter_Conformance(t *testing.T) {
+	TestStorageAdapter(t, func() StorageAdapter { return &memoryStorageAdapter{} })
+}
@@ -0,0 +1,126 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// quarantineSuffix marks files holding corrupt storage moved aside by
+// FileStorageAdapter.
+const quarantineSuffix = ".corrupt"
+
+// FileStorageAdapter persists events as a JSON array in a single file.
+// Saves write a temporary file and rename it over the previous one, so a
+// crash mid-write leaves either the old or the new contents.
+type FileStorageAdapter struct {
+	// QuarantineCorrupt makes Load move a file that is not valid JSON aside
+	// to "<path>.<timestamp>.corrupt" and return a *StorageCorruptedError,
+	// instead of failing on every Load. The client logs it as a warning
+	// and starts with an empty queue. See QuarantinedFiles.
+	QuarantineCorrupt bool
+
+	path string
+	mu   sync.Mutex
+}
+
+// Ensure FileStorageAdapter implements StorageAdapter interface
+var _ StorageAdapter = (*FileStorageAdapter)(nil)
+
+// NewFileStorageAdapter creates a FileStorageAdapter storing events at path.
+func NewFileStorageAdapter(path string) *FileStorageAdapter {
+	return &FileStorageAdapter{path: path}
+}
+
+// Save replaces the stored events with events.
+func (f *FileStorageAdapter) Save(events []Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// Load returns the stored events, or none if the file does not exist.
+func (f *FileStorageAdapter) Load() ([]Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		if !f.QuarantineCorrupt {
+			return nil, fmt.Errorf("failed to decode %s: %w", f.path, err)
+		}
+		return nil, f.quarantine(err)
+	}
+	return events, nil
+}
+
+// quarantine moves the corrupt file aside and describes it.
+// Caller must hold f.mu.
+func (f *FileStorageAdapter) quarantine(decodeErr error) error {
+	target := fmt.Sprintf("%s.%d%s", f.path, time.Now().UnixNano(), quarantineSuffix)
+	if err := os.Rename(f.path, target); err != nil {
+		return fmt.Errorf("failed to quarantine corrupt %s: %w", f.path, err)
+	}
+	return &StorageCorruptedError{Path: f.path, QuarantinePath: target, Err: decodeErr}
+}
+
+// Clear removes the storage file.
+func (f *FileStorageAdapter) Clear() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Close does nothing; the file is not held open between calls.
+func (f *FileStorageAdapter) Close() error {
+	return nil
+}
+
+// QuarantinedFiles returns the paths of corrupt files moved aside by Load,
+// oldest first, for inspection or manual recovery.
+func (f *FileStorageAdapter) QuarantinedFiles() ([]string, error) {
+	dir, base := filepath.Split(f.path)
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, base+".") && strings.HasSuffix(name, quarantineSuffix) {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
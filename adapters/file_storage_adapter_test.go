@@ -0,0 +1,97 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStorageAdapter_Conformance(t *testing.T) {
+	dir := t.TempDir()
+	n := 0
+	TestStorageAdapter(t, func() StorageAdapter {
+		n++
+		return NewFileStorageAdapter(filepath.Join(dir, fmt.Sprintf("events-%d.json", n)))
+	})
+}
+
+func TestFileStorageAdapter_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.json")
+	adapter := NewFileStorageAdapter(path)
+
+	events, err := adapter.Load()
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no events from a missing file, got %v, %v", events, err)
+	}
+
+	if err := adapter.Save([]Event{{Name: "a"}, {Name: "b"}}); err != nil {
+		t.Fatalf("unexpected save error: %v", err)
+	}
+	events, err = adapter.Load()
+	if err != nil || len(events) != 2 || events[1].Name != "b" {
+		t.Fatalf("unexpected load result: %v, %v", events, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("expected temporary file to be renamed away")
+	}
+
+	if err := adapter.Clear(); err != nil {
+		t.Fatalf("unexpected clear error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected storage file to be removed")
+	}
+}
+
+func TestFileStorageAdapter_Corruption(t *testing.T) {
+	t.Run("should fail on corrupt data by default", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.json")
+		if err := os.WriteFile(path, []byte(`[{"name":`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		adapter := NewFileStorageAdapter(path)
+
+		_, err := adapter.Load()
+		var corrupted *StorageCorruptedError
+		if err == nil || errors.As(err, &corrupted) {
+			t.Fatalf("expected a plain decode error, got %v", err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Error("expected corrupt file to be left in place")
+		}
+	})
+
+	t.Run("should quarantine corrupt data when enabled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.json")
+		if err := os.WriteFile(path, []byte(`[{"name":`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		adapter := NewFileStorageAdapter(path)
+		adapter.QuarantineCorrupt = true
+
+		_, err := adapter.Load()
+		var corrupted *StorageCorruptedError
+		if !errors.As(err, &corrupted) {
+			t.Fatalf("expected *StorageCorruptedError, got %v", err)
+		}
+		if corrupted.Path != path || corrupted.Err == nil {
+			t.Fatalf("unexpected error fields: %+v", corrupted)
+		}
+
+		quarantined, err := adapter.QuarantinedFiles()
+		if err != nil || len(quarantined) != 1 || quarantined[0] != corrupted.QuarantinePath {
+			t.Fatalf("expected quarantined file %q, got %v, %v", corrupted.QuarantinePath, quarantined, err)
+		}
+		data, err := os.ReadFile(quarantined[0])
+		if err != nil || string(data) != `[{"name":` {
+			t.Fatalf("expected corrupt data preserved, got %q, %v", data, err)
+		}
+
+		events, err := adapter.Load()
+		if err != nil || len(events) != 0 {
+			t.Fatalf("expected empty storage after quarantine, got %v, %v", events, err)
+		}
+	})
+}
@@ -1,15 +1,37 @@
 package adapters
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // HTTPResponse represents the response from an HTTP request.
 type HTTPResponse struct {
 	Status int
-	Data   any
+
+	// Data is the decoded response body: generic JSON values
+	// (map[string]any, []any, ...) for JSON bodies, a string otherwise, or
+	// nil if the body was empty.
+	Data any
+
+	// RejectedEvents holds the batch indices of events the server rejected
+	// while accepting the rest, e.g. with a 207 or 422 response. Adapters
+	// that learn this out of band may set it; otherwise the dispatcher reads
+	// a "rejectedEvents" array from a JSON Data body.
+	RejectedEvents []int
+
+	// Backoff is how long the server asked the client to pause sending,
+	// e.g. while ingestion is overloaded. NetHTTPAdapter reads it from the
+	// BackoffHeader; zero means no pause was requested.
+	Backoff time.Duration
 }
 
 // HTTPAdapter is an interface for HTTP communication.
 // Implement this interface to use custom HTTP clients.
+//
+// Implementations may keep the events slice after a send returns; the
+// dispatcher reuses its buffers only for the adapters in this package that
+// are done with the events by then.
 type HTTPAdapter interface {
 	// Send events to the specified endpoint without context.
 	//
@@ -0,0 +1,234 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const defaultKVPrefix = "ripple/"
+
+// Key suffixes under the adapter's prefix.
+const (
+	kvEventsKey   = "events/"
+	kvSequenceKey = "sequence/"
+	kvIdentityKey = "identity"
+)
+
+// KVStore is a transactional embedded key-value store, such as bbolt or
+// Badger. Wrap the store's Update and View so that KVStorageAdapter needs no
+// dependency on it: with bbolt, run fn against one bucket of the *bolt.Tx;
+// with Badger, against the *badger.Txn.
+type KVStore interface {
+	// Update runs fn in a read-write transaction, committed atomically and
+	// durably if fn returns nil and discarded otherwise.
+	Update(fn func(tx KVTx) error) error
+
+	// View runs fn in a read-only transaction.
+	View(fn func(tx KVTx) error) error
+}
+
+// KVTx is a transaction of a KVStore. Byte slices passed to fn by ForEach or
+// returned by Get are only valid within the transaction.
+type KVTx interface {
+	// Get returns the value of key, or nil if it does not exist.
+	Get(key []byte) ([]byte, error)
+
+	// Put sets the value of key.
+	Put(key, value []byte) error
+
+	// Delete removes key. Deleting a missing key must not fail.
+	Delete(key []byte) error
+
+	// ForEach calls fn for every key starting with prefix, in key order,
+	// stopping at the first error.
+	ForEach(prefix []byte, fn func(key, value []byte) error) error
+}
+
+// KVStorageConfig configures a KVStorageAdapter.
+type KVStorageConfig struct {
+	// Store is the key-value store.
+	Store KVStore
+
+	// Prefix is prepended to every key, so several clients can share a
+	// store.
+	//
+	// Default: "ripple/".
+	Prefix string
+}
+
+// KVStorageAdapter is a StorageAdapter backed by an embedded key-value store.
+// Each event is a key of its own, and Save, Load and Clear each run in a
+// single transaction, so a crash leaves either the old or the new events and
+// never a partially written file. It also persists sequence numbers and the
+// identified user in the same store.
+type KVStorageAdapter struct {
+	store  KVStore
+	prefix string
+}
+
+// Ensure KVStorageAdapter implements StorageAdapter and its extensions
+var (
+	_ StorageAdapter = (*KVStorageAdapter)(nil)
+	_ SequenceStore  = (*KVStorageAdapter)(nil)
+	_ IdentityStore  = (*KVStorageAdapter)(nil)
+)
+
+// NewKVStorageAdapter creates a key-value storage adapter. It returns an
+// error if config has no Store.
+func NewKVStorageAdapter(config KVStorageConfig) (*KVStorageAdapter, error) {
+	if config.Store == nil {
+		return nil, errors.New("kv storage adapter requires a store")
+	}
+	if config.Prefix == "" {
+		config.Prefix = defaultKVPrefix
+	}
+	return &KVStorageAdapter{store: config.Store, prefix: config.Prefix}, nil
+}
+
+// Save replaces the stored events with events in one transaction.
+func (k *KVStorageAdapter) Save(events []Event) error {
+	values := make([][]byte, len(events))
+	for i := range events {
+		data, err := json.Marshal(&events[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+		values[i] = data
+	}
+
+	return k.store.Update(func(tx KVTx) error {
+		if err := k.deleteEvents(tx); err != nil {
+			return err
+		}
+		for i, value := range values {
+			if err := tx.Put(k.eventKey(i), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Load returns the stored events in the order they were saved.
+func (k *KVStorageAdapter) Load() ([]Event, error) {
+	events := []Event{}
+	err := k.store.View(func(tx KVTx) error {
+		return tx.ForEach(k.key(kvEventsKey), func(key, value []byte) error {
+			var event Event
+			if err := json.Unmarshal(value, &event); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", key, err)
+			}
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Clear removes the stored events in one transaction. Sequence numbers and
+// the identity are kept.
+func (k *KVStorageAdapter) Clear() error {
+	return k.store.Update(k.deleteEvents)
+}
+
+// Close does nothing; the store is owned by the caller.
+func (k *KVStorageAdapter) Close() error {
+	return nil
+}
+
+// LoadSequence returns the last sequence number saved for producerID.
+func (k *KVStorageAdapter) LoadSequence(producerID string) (uint64, error) {
+	var seq uint64
+	err := k.store.View(func(tx KVTx) error {
+		value, err := tx.Get(k.key(kvSequenceKey + producerID))
+		if err != nil || value == nil {
+			return err
+		}
+		if len(value) != 8 {
+			return fmt.Errorf("invalid sequence for producer %q", producerID)
+		}
+		seq = binary.BigEndian.Uint64(value)
+		return nil
+	})
+	return seq, err
+}
+
+// SaveSequence records the last sequence number assigned by producerID.
+func (k *KVStorageAdapter) SaveSequence(producerID string, seq uint64) error {
+	return k.store.Update(func(tx KVTx) error {
+		return tx.Put(k.key(kvSequenceKey+producerID), binary.BigEndian.AppendUint64(nil, seq))
+	})
+}
+
+// kvIdentity is the stored form of the identified user.
+type kvIdentity struct {
+	UserID string         `json:"userId"`
+	Traits map[string]any `json:"traits,omitempty"`
+}
+
+// LoadIdentity returns the persisted user ID and traits.
+func (k *KVStorageAdapter) LoadIdentity() (string, map[string]any, error) {
+	var identity kvIdentity
+	err := k.store.View(func(tx KVTx) error {
+		value, err := tx.Get(k.key(kvIdentityKey))
+		if err != nil || value == nil {
+			return err
+		}
+		return json.Unmarshal(value, &identity)
+	})
+	return identity.UserID, identity.Traits, err
+}
+
+// SaveIdentity persists the user ID and traits.
+func (k *KVStorageAdapter) SaveIdentity(userID string, traits map[string]any) error {
+	data, err := json.Marshal(kvIdentity{UserID: userID, Traits: traits})
+	if err != nil {
+		return fmt.Errorf("failed to marshal identity: %w", err)
+	}
+	return k.store.Update(func(tx KVTx) error {
+		return tx.Put(k.key(kvIdentityKey), data)
+	})
+}
+
+// ClearIdentity removes the persisted identity.
+func (k *KVStorageAdapter) ClearIdentity() error {
+	return k.store.Update(func(tx KVTx) error {
+		return tx.Delete(k.key(kvIdentityKey))
+	})
+}
+
+// deleteEvents removes every stored event within tx. Keys are collected
+// first, as stores such as bbolt do not allow deleting while iterating.
+func (k *KVStorageAdapter) deleteEvents(tx KVTx) error {
+	var keys [][]byte
+	err := tx.ForEach(k.key(kvEventsKey), func(key, _ []byte) error {
+		keys = append(keys, bytes.Clone(key))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := tx.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventKey returns the key of the i-th event. The index is big-endian so
+// keys sort in event order.
+func (k *KVStorageAdapter) eventKey(i int) []byte {
+	return binary.BigEndian.AppendUint64(k.key(kvEventsKey), uint64(i))
+}
+
+// key returns name under the adapter's prefix.
+func (k *KVStorageAdapter) key(name string) []byte {
+	return []byte(k.prefix + name)
+}
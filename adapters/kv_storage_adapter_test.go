@@ -0,0 +1,142 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryKVStore is a KVStore whose transactions work on a copy of the data,
+// swapped in on commit.
+type memoryKVStore struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	failPut int // fail the nth Put of the next Update, if > 0
+}
+
+type memoryKVTx struct {
+	data    map[string][]byte
+	puts    int
+	failPut int
+}
+
+func newMemoryKVStore() *memoryKVStore {
+	return &memoryKVStore{data: make(map[string][]byte)}
+}
+
+func (m *memoryKVStore) Update(fn func(tx KVTx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tx := &memoryKVTx{data: maps.Clone(m.data), failPut: m.failPut}
+	m.failPut = 0
+	if err := fn(tx); err != nil {
+		return err
+	}
+	m.data = tx.data
+	return nil
+}
+
+func (m *memoryKVStore) View(fn func(tx KVTx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return fn(&memoryKVTx{data: m.data})
+}
+
+func (tx *memoryKVTx) Get(key []byte) ([]byte, error) {
+	return tx.data[string(key)], nil
+}
+
+func (tx *memoryKVTx) Put(key, value []byte) error {
+	tx.puts++
+	if tx.puts == tx.failPut {
+		return errors.New("disk full")
+	}
+	tx.data[string(key)] = bytes.Clone(value)
+	return nil
+}
+
+func (tx *memoryKVTx) Delete(key []byte) error {
+	delete(tx.data, string(key))
+	return nil
+}
+
+func (tx *memoryKVTx) ForEach(prefix []byte, fn func(key, value []byte) error) error {
+	keys := slices.Sorted(maps.Keys(tx.data))
+	for _, key := range keys {
+		if strings.HasPrefix(key, string(prefix)) {
+			if err := fn([]byte(key), tx.data[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestKVStorageAdapterConformance(t *testing.T) {
+	TestStorageAdapter(t, func() StorageAdapter {
+		adapter, _ := NewKVStorageAdapter(KVStorageConfig{Store: newMemoryKVStore()})
+		return adapter
+	})
+}
+
+func TestKVStorageAdapter(t *testing.T) {
+	t.Run("should keep the old events if a save fails", func(t *testing.T) {
+		store := newMemoryKVStore()
+		adapter, _ := NewKVStorageAdapter(KVStorageConfig{Store: store})
+		_ = adapter.Save([]Event{{Name: "a"}})
+
+		store.failPut = 2
+		if err := adapter.Save([]Event{{Name: "b"}, {Name: "c"}}); err == nil {
+			t.Fatal("expected the save to fail")
+		}
+		events, _ := adapter.Load()
+		if len(events) != 1 || events[0].Name != "a" {
+			t.Fatalf("expected the previous events, got %v", events)
+		}
+	})
+
+	t.Run("should keep clients with different prefixes apart", func(t *testing.T) {
+		store := newMemoryKVStore()
+		checkout, _ := NewKVStorageAdapter(KVStorageConfig{Store: store, Prefix: "checkout/"})
+		search, _ := NewKVStorageAdapter(KVStorageConfig{Store: store, Prefix: "search/"})
+		_ = checkout.Save([]Event{{Name: "a"}})
+		_ = search.Save([]Event{{Name: "b"}})
+		_ = search.Clear()
+
+		if events, _ := checkout.Load(); len(events) != 1 {
+			t.Fatalf("expected checkout's events to remain, got %v", events)
+		}
+	})
+
+	t.Run("should persist sequences and identity across clears", func(t *testing.T) {
+		adapter, _ := NewKVStorageAdapter(KVStorageConfig{Store: newMemoryKVStore()})
+		if seq, err := adapter.LoadSequence("p-1"); err != nil || seq != 0 {
+			t.Fatalf("expected no sequence, got %d, %v", seq, err)
+		}
+		_ = adapter.SaveSequence("p-1", 42)
+		_ = adapter.SaveIdentity("user-1", map[string]any{"plan": "pro"})
+		_ = adapter.Clear()
+
+		if seq, _ := adapter.LoadSequence("p-1"); seq != 42 {
+			t.Fatalf("expected sequence 42, got %d", seq)
+		}
+		userID, traits, err := adapter.LoadIdentity()
+		if err != nil || userID != "user-1" || traits["plan"] != "pro" {
+			t.Fatalf("unexpected identity: %q, %v, %v", userID, traits, err)
+		}
+		_ = adapter.ClearIdentity()
+		if userID, _, _ := adapter.LoadIdentity(); userID != "" {
+			t.Fatalf("expected no identity, got %q", userID)
+		}
+	})
+
+	t.Run("should require a store", func(t *testing.T) {
+		if _, err := NewKVStorageAdapter(KVStorageConfig{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
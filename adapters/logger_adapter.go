@@ -23,3 +23,10 @@ type LoggerAdapter interface {
 	// Error logs an error message
 	Error(message string, args ...any)
 }
+
+// LevelSetter is an optional LoggerAdapter extension for loggers whose level
+// can be changed at runtime.
+type LevelSetter interface {
+	// SetLevel changes the minimum level that is logged.
+	SetLevel(level LogLevel)
+}
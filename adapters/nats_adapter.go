@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// natsFlushTimeout bounds the flush after a core NATS publish when the
+// context has no deadline, as nats.go requires one.
+const natsFlushTimeout = 10 * time.Second
+
+// NATSConn is the subset of *nats.Conn used by NATSAdapter. A *nats.Conn
+// from github.com/nats-io/nats.go satisfies it directly.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+	FlushWithContext(ctx context.Context) error
+}
+
+// JetStreamPublishFunc publishes data to a JetStream subject and returns once
+// the stream has acknowledged it. Wrap a JetStream context, e.g.:
+//
+//	func(ctx context.Context, subject string, data []byte) error {
+//		_, err := js.Publish(ctx, subject, data)
+//		return err
+//	}
+type JetStreamPublishFunc func(ctx context.Context, subject string, data []byte) error
+
+// NATSAdapter is an HTTPAdapter that publishes batches to a NATS subject
+// instead of an HTTP endpoint. The endpoint passed to Send is used as the
+// subject, and the message body is the same {"events": [...]} JSON the HTTP
+// adapter sends. Headers are not transmitted; authenticate with NATS
+// credentials instead.
+//
+// A publish that completes is reported as status 200. Failures are returned
+// as errors, so the dispatcher retries them like network errors.
+type NATSAdapter struct {
+	conn      NATSConn
+	jetStream JetStreamPublishFunc
+}
+
+// Ensure NATSAdapter implements HTTPAdapter and EventRetainer
+var (
+	_ HTTPAdapter   = (*NATSAdapter)(nil)
+	_ EventRetainer = (*NATSAdapter)(nil)
+)
+
+// NewNATSAdapter creates an adapter publishing with core NATS. Each publish
+// is followed by a flush, so success means the server received the batch but
+// not that any subscriber processed it. It returns an error if conn is nil.
+func NewNATSAdapter(conn NATSConn) (*NATSAdapter, error) {
+	if conn == nil {
+		return nil, errors.New("nats adapter requires a connection")
+	}
+	return &NATSAdapter{conn: conn}, nil
+}
+
+// NewJetStreamAdapter creates an adapter publishing to a JetStream stream.
+// Success means the stream persisted the batch and acknowledged it. It
+// returns an error if publish is nil.
+func NewJetStreamAdapter(publish JetStreamPublishFunc) (*NATSAdapter, error) {
+	if publish == nil {
+		return nil, errors.New("jetstream adapter requires a publish function")
+	}
+	return &NATSAdapter{jetStream: publish}, nil
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it marshals the events before sending.
+func (n *NATSAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send publishes events to the subject named by endpoint.
+func (n *NATSAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return n.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext publishes events to the subject named by endpoint and
+// waits for the server (core NATS) or stream (JetStream) to confirm receipt.
+func (n *NATSAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	if endpoint == "" {
+		return nil, errors.New("nats subject cannot be empty")
+	}
+
+	data, err := json.Marshal(map[string]any{"events": events})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	if n.jetStream != nil {
+		if err := n.jetStream(ctx, endpoint, data); err != nil {
+			return nil, fmt.Errorf("failed to publish to jetstream: %w", err)
+		}
+		return &HTTPResponse{Status: 200}, nil
+	}
+
+	if err := n.conn.Publish(endpoint, data); err != nil {
+		return nil, fmt.Errorf("failed to publish to nats: %w", err)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, natsFlushTimeout)
+		defer cancel()
+	}
+	if err := n.conn.FlushWithContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to flush nats connection: %w", err)
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
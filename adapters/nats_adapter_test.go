@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type fakeNATSConn struct {
+	subject     string
+	data        []byte
+	publishErr  error
+	flushErr    error
+	hadDeadline bool
+}
+
+func (c *fakeNATSConn) Publish(subject string, data []byte) error {
+	c.subject = subject
+	c.data = data
+	return c.publishErr
+}
+
+func (c *fakeNATSConn) FlushWithContext(ctx context.Context) error {
+	_, c.hadDeadline = ctx.Deadline()
+	return c.flushErr
+}
+
+func TestNATSAdapter(t *testing.T) {
+	t.Run("should publish events to the endpoint subject", func(t *testing.T) {
+		conn := &fakeNATSConn{}
+		adapter, _ := NewNATSAdapter(conn)
+
+		resp, err := adapter.Send("events.ingest", []Event{{Name: "test"}}, map[string]string{"X-API-Key": "k"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 200 {
+			t.Fatalf("expected status 200, got %d", resp.Status)
+		}
+		if conn.subject != "events.ingest" {
+			t.Fatalf("expected subject events.ingest, got %q", conn.subject)
+		}
+		var body struct {
+			Events []Event `json:"events"`
+		}
+		if err := json.Unmarshal(conn.data, &body); err != nil || len(body.Events) != 1 || body.Events[0].Name != "test" {
+			t.Fatalf("unexpected body %s: %v", conn.data, err)
+		}
+		if !conn.hadDeadline {
+			t.Fatal("expected flush to run with a deadline")
+		}
+	})
+
+	t.Run("should return publish and flush errors", func(t *testing.T) {
+		for _, conn := range []*fakeNATSConn{
+			{publishErr: errors.New("publish")},
+			{flushErr: errors.New("flush")},
+		} {
+			adapter, _ := NewNATSAdapter(conn)
+			if _, err := adapter.Send("events", []Event{{Name: "test"}}, nil); err == nil {
+				t.Fatal("expected error")
+			}
+		}
+	})
+
+	t.Run("should reject an empty subject", func(t *testing.T) {
+		adapter, _ := NewNATSAdapter(&fakeNATSConn{})
+		if _, err := adapter.Send("", nil, nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should require a connection", func(t *testing.T) {
+		if _, err := NewNATSAdapter(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestJetStreamAdapter(t *testing.T) {
+	t.Run("should succeed when the stream acknowledges", func(t *testing.T) {
+		var subject string
+		adapter, _ := NewJetStreamAdapter(func(ctx context.Context, subj string, data []byte) error {
+			subject = subj
+			return nil
+		})
+
+		resp, err := adapter.SendWithContext(context.Background(), "events.ingest", []Event{{Name: "test"}}, nil)
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("expected success, got %+v, %v", resp, err)
+		}
+		if subject != "events.ingest" {
+			t.Fatalf("expected subject events.ingest, got %q", subject)
+		}
+	})
+
+	t.Run("should return an error without an ack", func(t *testing.T) {
+		ackErr := errors.New("no responders")
+		adapter, _ := NewJetStreamAdapter(func(ctx context.Context, subj string, data []byte) error {
+			return ackErr
+		})
+
+		if _, err := adapter.Send("events", []Event{{Name: "test"}}, nil); !errors.Is(err, ackErr) {
+			t.Fatalf("expected ack error, got %v", err)
+		}
+	})
+	t.Run("should require a publish function", func(t *testing.T) {
+		if _, err := NewJetStreamAdapter(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
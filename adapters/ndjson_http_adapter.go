@@ -0,0 +1,64 @@
+package adapters
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NDJSONContentType is the Content-Type of batches sent by the adapter
+// returned from NewNDJSONHTTPAdapter.
+const NDJSONContentType = "application/x-ndjson"
+
+// ndjsonChunkBytes is how much encoded NDJSON is buffered before it is
+// written to the request as a chunk.
+const ndjsonChunkBytes = 32 << 10
+
+// NewNDJSONHTTPAdapter creates a NetHTTPAdapter that streams each batch as
+// newline-delimited JSON, one event per line, in a chunked POST. Events are
+// encoded while the request is being written, so a batch is never held in
+// memory as a whole. The endpoint must accept NDJSON; the server package's
+// collector does. Requests are not replayed on redirects.
+func NewNDJSONHTTPAdapter() HTTPAdapter {
+	return &NetHTTPAdapter{
+		client: &http.Client{},
+		ndjson: true,
+	}
+}
+
+// newNDJSONRequest builds a chunked POST whose body is encoded from events by
+// a goroutine as the transport reads it. done stops the encoder and waits for
+// it, so events are no longer read once it returns.
+func newNDJSONRequest(ctx context.Context, endpoint string, events []Event) (req *http.Request, done func(), err error) {
+	pr, pw := io.Pipe()
+	req, err = http.NewRequestWithContext(ctx, "POST", endpoint, pr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", NDJSONContentType)
+
+	encoded := make(chan struct{})
+	go func() {
+		defer close(encoded)
+		w := bufio.NewWriterSize(pw, ndjsonChunkBytes)
+		enc := json.NewEncoder(w)
+		for _, event := range events {
+			if err := enc.Encode(event); err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to marshal events: %w", err))
+				return
+			}
+		}
+		pw.CloseWithError(w.Flush())
+	}()
+
+	done = func() {
+		// Unblocks the encoder if the transport stopped reading early,
+		// e.g. after an error response.
+		_ = pr.Close()
+		<-encoded
+	}
+	return req, done, nil
+}
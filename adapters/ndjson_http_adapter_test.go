@@ -0,0 +1,75 @@
+package adapters
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONHTTPAdapter_Send(t *testing.T) {
+	t.Run("should stream one event per line in a chunked request", func(t *testing.T) {
+		var names []string
+		var contentType string
+		var contentLength int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			contentLength = r.ContentLength
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var event Event
+				if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				names = append(names, event.Name)
+			}
+		}))
+		defer server.Close()
+
+		resp, err := NewNDJSONHTTPAdapter().Send(server.URL, []Event{{Name: "a"}, {Name: "b"}}, map[string]string{"X-API-Key": "k"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.Status)
+		}
+		if contentType != NDJSONContentType || contentLength != -1 {
+			t.Fatalf("expected chunked NDJSON, got %q with length %d", contentType, contentLength)
+		}
+		if strings.Join(names, ",") != "a,b" {
+			t.Fatalf("expected events a,b, got %v", names)
+		}
+	})
+
+	t.Run("should return the status of a server that stops reading", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}))
+		defer server.Close()
+
+		events := make([]Event, 5000)
+		for i := range events {
+			events[i] = Event{Name: "a", Payload: map[string]any{"data": strings.Repeat("x", 100)}}
+		}
+		resp, err := NewNDJSONHTTPAdapter().Send(server.URL, events, nil)
+		if err == nil && resp.Status != http.StatusRequestEntityTooLarge {
+			t.Fatalf("expected 413 or an error, got %d", resp.Status)
+		}
+	})
+
+	t.Run("should fail on unmarshalable events", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		events := []Event{{Name: "a", Payload: map[string]any{"invalid": make(chan int)}}}
+		if _, err := NewNDJSONHTTPAdapter().Send(server.URL, events, nil); err == nil {
+			t.Fatal("expected error for unmarshalable data")
+		}
+	})
+}
@@ -1,9 +1,16 @@
 package adapters
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNetHTTPAdapter_Send(t *testing.T) {
@@ -88,3 +95,164 @@ func TestNetHTTPAdapter_SendInvalidMethod(t *testing.T) {
 		t.Fatal("expected error for invalid URL")
 	}
 }
+
+func TestNetHTTPAdapter_SendBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Batch-Id") != "batch-1" {
+			t.Errorf("expected batch ID header, got %q", r.Header.Get("X-Batch-Id"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	adapter := NewNetHTTPAdapter().(BatchSender)
+	resp, err := adapter.SendBatch(context.Background(), Batch{
+		ID:       "batch-1",
+		Endpoint: server.URL,
+		Events:   []Event{{Name: "test"}},
+		Headers:  map[string]string{"X-Batch-Id": "batch-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != 200 {
+		t.Fatalf("expected status 200, got %d", resp.Status)
+	}
+}
+
+func TestNetHTTPAdapter_ResponseData(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		check       func(t *testing.T, data any)
+	}{
+		{
+			name: "should decode a JSON body",
+			body: `{"code":"invalid_event","rejectedEvents":[1]}`,
+			check: func(t *testing.T, data any) {
+				body, ok := data.(map[string]any)
+				if !ok || body["code"] != "invalid_event" {
+					t.Fatalf("expected decoded JSON, got %#v", data)
+				}
+			},
+		},
+		{
+			name: "should keep a plain-text body as a string",
+			body: "upstream unavailable\n",
+			check: func(t *testing.T, data any) {
+				if data != "upstream unavailable\n" {
+					t.Fatalf("expected the raw body, got %#v", data)
+				}
+			},
+		},
+		{
+			name: "should leave an empty body nil",
+			check: func(t *testing.T, data any) {
+				if data != nil {
+					t.Fatalf("expected nil, got %#v", data)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			resp, err := NewNetHTTPAdapter().Send(server.URL, []Event{{Name: "test"}}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.check(t, resp.Data)
+		})
+	}
+}
+
+func TestNetHTTPAdapter_PooledBodies(t *testing.T) {
+	t.Run("should replay the body on redirect", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+		})
+		var body string
+		mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		_, err := NewNetHTTPAdapter().Send(server.URL+"/old", []Event{{Name: "a"}}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(body, `"name":"a"`) {
+			t.Fatalf("expected redirected body to contain the event, got %q", body)
+		}
+	})
+
+	t.Run("should not mix bodies of concurrent sends", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload struct {
+				Events []Event `json:"events"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.Events) != 1 ||
+				payload.Events[0].Name != r.Header.Get("X-Name") {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer server.Close()
+
+		adapter := NewNetHTTPAdapter()
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					resp, err := adapter.Send(server.URL, []Event{{Name: name}}, map[string]string{"X-Name": name})
+					if err != nil || resp.Status != http.StatusOK {
+						t.Errorf("send %s failed: %v %+v", name, err, resp)
+						return
+					}
+				}
+			}(strconv.Itoa(i))
+		}
+		wg.Wait()
+	})
+}
+
+func TestNetHTTPAdapter_Backoff(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"30", 30 * time.Second},
+		{"1.5", 1500 * time.Millisecond},
+		{"", 0},
+		{"0", 0},
+		{"-5", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tt.header != "" {
+				w.Header().Set(BackoffHeader, tt.header)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		resp, err := NewNetHTTPAdapter().Send(server.URL, []Event{{Name: "a"}}, nil)
+		server.Close()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Backoff != tt.want {
+			t.Errorf("header %q: expected backoff %v, got %v", tt.header, tt.want, resp.Backoff)
+		}
+	}
+}
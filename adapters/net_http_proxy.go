@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// errInvalidProxyURL is returned for a malformed ProxyURL. The URL itself is
+// left out, as it may hold credentials.
+var errInvalidProxyURL = errors.New("invalid proxy url")
+
+// newProxyFunc returns the http.Transport Proxy function for a NetHTTPConfig:
+// proxyURL, or the environment's proxy if empty, except for hosts matching
+// noProxy.
+func newProxyFunc(proxyURL string, noProxy []string) (func(*http.Request) (*url.URL, error), error) {
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return nil, errInvalidProxyURL
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, errors.New("proxy url scheme must be http, https, socks5 or socks5h")
+		}
+		proxy = http.ProxyURL(u)
+	}
+	if len(noProxy) == 0 {
+		return proxy, nil
+	}
+
+	bypass := parseNoProxy(noProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.matches(req.URL) {
+			return nil, nil
+		}
+		return proxy(req)
+	}, nil
+}
+
+// noProxyRule is one NO_PROXY entry. Exactly one of domain, prefix or all is
+// set; port is empty for entries matching any port.
+type noProxyRule struct {
+	domain string
+	prefix netip.Prefix
+	all    bool
+	port   string
+}
+
+// noProxyRules is a parsed NO_PROXY list.
+type noProxyRules []noProxyRule
+
+// parseNoProxy parses NO_PROXY entries, skipping empty ones.
+func parseNoProxy(entries []string) noProxyRules {
+	var rules noProxyRules
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			rules = append(rules, noProxyRule{all: true})
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			rules = append(rules, noProxyRule{prefix: prefix.Masked()})
+			continue
+		}
+
+		host, port := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		if addr, err := netip.ParseAddr(host); err == nil {
+			rules = append(rules, noProxyRule{prefix: netip.PrefixFrom(addr, addr.BitLen()), port: port})
+			continue
+		}
+		rules = append(rules, noProxyRule{domain: strings.TrimPrefix(host, "."), port: port})
+	}
+	return rules
+}
+
+// matches reports whether requests to u bypass the proxy.
+func (rules noProxyRules) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	addr, addrErr := netip.ParseAddr(host)
+
+	for _, rule := range rules {
+		if rule.all {
+			return true
+		}
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+		if rule.prefix.IsValid() {
+			if addrErr == nil && rule.prefix.Contains(addr.Unmap()) {
+				return true
+			}
+			continue
+		}
+		if host == rule.domain || strings.HasSuffix(host, "."+rule.domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package adapters
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNetHTTPAdapter_Proxy(t *testing.T) {
+	t.Run("should send requests through an authenticated proxy", func(t *testing.T) {
+		var gotAuth, gotTarget string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = r.Header.Get("Proxy-Authorization")
+			gotTarget = r.URL.String()
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer proxy.Close()
+
+		proxyURL, _ := url.Parse(proxy.URL)
+		proxyURL.User = url.UserPassword("user", "secret")
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{ProxyURL: proxyURL.String()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := adapter.Send("http://collector.example/events", []Event{{Name: "a"}}, nil)
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("expected the proxy to answer, got %+v, %v", resp, err)
+		}
+		if gotTarget != "http://collector.example/events" {
+			t.Fatalf("expected the proxy to receive the target url, got %q", gotTarget)
+		}
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+		if gotAuth != want {
+			t.Fatalf("expected Proxy-Authorization %q, got %q", want, gotAuth)
+		}
+	})
+
+	t.Run("should bypass the proxy for NoProxy hosts", func(t *testing.T) {
+		proxied := false
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = true
+		}))
+		defer proxy.Close()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{
+			ProxyURL: proxy.URL,
+			NoProxy:  []string{"127.0.0.0/8"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil)
+		if err != nil || resp.Status != http.StatusAccepted {
+			t.Fatalf("expected a direct response, got %+v, %v", resp, err)
+		}
+		if proxied {
+			t.Fatal("expected the proxy to be bypassed")
+		}
+	})
+
+	t.Run("should reject invalid proxy urls without exposing credentials", func(t *testing.T) {
+		for _, proxyURL := range []string{"http://user:secret@%zz", "ftp://proxy:21", "proxy.corp:3128"} {
+			_, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{ProxyURL: proxyURL})
+			if err == nil {
+				t.Errorf("expected error for %q", proxyURL)
+			} else if strings.Contains(err.Error(), "secret") {
+				t.Errorf("error exposes credentials: %v", err)
+			}
+		}
+	})
+}
+
+func TestNoProxyRules(t *testing.T) {
+	rules := parseNoProxy([]string{"example.com", ".internal", "10.0.0.0/8", "192.168.1.5", "metrics.local:8080", " "})
+	tests := []struct {
+		url    string
+		bypass bool
+	}{
+		{"https://example.com/events", true},
+		{"https://api.example.com/events", true},
+		{"https://notexample.com/events", false},
+		{"http://svc.internal/events", true},
+		{"http://10.1.2.3/events", true},
+		{"http://11.0.0.1/events", false},
+		{"http://192.168.1.5:9000/events", true},
+		{"http://metrics.local:8080/events", true},
+		{"http://metrics.local/events", false},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := rules.matches(u); got != tt.bypass {
+			t.Errorf("%s: expected bypass=%v, got %v", tt.url, tt.bypass, got)
+		}
+	}
+
+	all := parseNoProxy([]string{"*"})
+	if u, _ := url.Parse("https://anything.example"); !all.matches(u) {
+		t.Error("expected * to bypass every host")
+	}
+}
@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures TLS for a NetHTTPAdapter. Certificates and keys are
+// PEM encoded and may be given as files or inline, e.g. from a secret.
+type TLSConfig struct {
+	// CertFile and KeyFile hold the client certificate and its private key,
+	// sent to endpoints requiring mutual TLS.
+	//
+	// Optional: Set both or neither.
+	CertFile string
+	KeyFile  string
+
+	// CertPEM and KeyPEM are the client certificate and key, as an
+	// alternative to CertFile and KeyFile.
+	//
+	// Optional: Set both or neither.
+	CertPEM []byte
+	KeyPEM  []byte
+
+	// CAFile is a bundle of CA certificates trusted in addition to the
+	// system roots, for endpoints signed by a private CA.
+	//
+	// Optional.
+	CAFile string
+
+	// CAPEM is a CA bundle, as an alternative to CAFile.
+	//
+	// Optional.
+	CAPEM []byte
+
+	// InsecureSkipVerify disables verification of the endpoint's
+	// certificate. Use only in development.
+	//
+	// Default: false.
+	InsecureSkipVerify bool
+}
+
+// build loads the certificates into a tls.Config.
+func (c *TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	certPEM, keyPEM := c.CertPEM, c.KeyPEM
+	if c.CertFile != "" || c.KeyFile != "" {
+		if len(certPEM) > 0 || len(keyPEM) > 0 {
+			return nil, errors.New("tls: set either cert and key files or pem, not both")
+		}
+		var err error
+		if certPEM, err = os.ReadFile(c.CertFile); err != nil {
+			return nil, fmt.Errorf("tls: failed to read client certificate: %w", err)
+		}
+		if keyPEM, err = os.ReadFile(c.KeyFile); err != nil {
+			return nil, fmt.Errorf("tls: failed to read client key: %w", err)
+		}
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("tls: invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	caPEM := c.CAPEM
+	if c.CAFile != "" {
+		if len(caPEM) > 0 {
+			return nil, errors.New("tls: set either a ca file or ca pem, not both")
+		}
+		var err error
+		if caPEM, err = os.ReadFile(c.CAFile); err != nil {
+			return nil, fmt.Errorf("tls: failed to read ca bundle: %w", err)
+		}
+	}
+	if len(caPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New("tls: no certificates found in ca bundle")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,152 @@
+package adapters
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newClientCert returns a self-signed client certificate and key as PEM.
+func newClientCert(t *testing.T) (certPEM, keyPEM []byte, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ripple-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ = x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, cert
+}
+
+// newMTLSServer starts a TLS server that requires a client certificate
+// issued by clientCA.
+func newMTLSServer(t *testing.T, clientCA *x509.Certificate) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func serverCAPEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+func TestNetHTTPAdapter_TLS(t *testing.T) {
+	certPEM, keyPEM, clientCert := newClientCert(t)
+
+	t.Run("should authenticate with a client certificate from files", func(t *testing.T) {
+		server := newMTLSServer(t, clientCert)
+		dir := t.TempDir()
+		files := map[string][]byte{"client.crt": certPEM, "client.key": keyPEM, "ca.pem": serverCAPEM(server)}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &TLSConfig{
+			CertFile: filepath.Join(dir, "client.crt"),
+			KeyFile:  filepath.Join(dir, "client.key"),
+			CAFile:   filepath.Join(dir, "ca.pem"),
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil)
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("expected mTLS request to succeed, got %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("should authenticate with inline PEM", func(t *testing.T) {
+		server := newMTLSServer(t, clientCert)
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &TLSConfig{
+			CertPEM: certPEM,
+			KeyPEM:  keyPEM,
+			CAPEM:   serverCAPEM(server),
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should fail without a client certificate", func(t *testing.T) {
+		server := newMTLSServer(t, clientCert)
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &TLSConfig{CAPEM: serverCAPEM(server)}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected handshake to fail")
+		}
+	})
+
+	t.Run("should reject an untrusted endpoint unless verification is skipped", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		strict, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &TLSConfig{}})
+		if _, err := strict.Send(server.URL, []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected certificate verification to fail")
+		}
+		insecure, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &TLSConfig{InsecureSkipVerify: true}})
+		if _, err := insecure.Send(server.URL, []Event{{Name: "a"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should report invalid certificate settings", func(t *testing.T) {
+		configs := []TLSConfig{
+			{CertPEM: certPEM},
+			{CertFile: "missing.crt", KeyFile: "missing.key"},
+			{CertFile: "client.crt", CertPEM: certPEM},
+			{CAPEM: []byte("not a certificate")},
+			{CAFile: "missing.pem"},
+		}
+		for _, config := range configs {
+			if _, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{TLS: &config}); err == nil {
+				t.Errorf("expected error for %+v", config)
+			}
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package adapters
+
+import "context"
+
+// NoOpTracerProvider implements TracerProvider with spans that record nothing.
+type NoOpTracerProvider struct{}
+
+// NewNoOpTracerProvider creates a new no-op tracer provider.
+func NewNoOpTracerProvider() *NoOpTracerProvider {
+	return &NoOpTracerProvider{}
+}
+
+// Start returns ctx unchanged and a span that ignores all calls.
+func (n *NoOpTracerProvider) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noOpSpan{}
+}
+
+type noOpSpan struct{}
+
+func (noOpSpan) SetAttribute(key string, value any) {}
+
+func (noOpSpan) RecordError(err error) {}
+
+func (noOpSpan) End() {}
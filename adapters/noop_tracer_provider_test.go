@@ -0,0 +1,22 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNoOpTracerProvider(t *testing.T) {
+	var tracer TracerProvider = NewNoOpTracerProvider()
+
+	ctx := context.Background()
+	spanCtx, span := tracer.Start(ctx, "test")
+	if spanCtx != ctx {
+		t.Error("expected context to be returned unchanged")
+	}
+
+	// Should not panic
+	span.SetAttribute("key", "value")
+	span.RecordError(errors.New("boom"))
+	span.End()
+}
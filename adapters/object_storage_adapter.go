@@ -0,0 +1,229 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultObjectPrefix  = "ripple/"
+	defaultObjectTimeout = 30 * time.Second
+
+	// objectKeyTime formats the UTC timestamp in object keys so that keys
+	// sort chronologically.
+	objectKeyTime = "20060102T150405.000000000Z"
+)
+
+// ObjectStore is the subset of an object storage client used by
+// ObjectStorageAdapter. Implement it with the Amazon S3 or Google Cloud
+// Storage SDK (PutObject/GetObject/ListObjectsV2/DeleteObject, or a bucket
+// handle's object writers, readers and iterator), so the SDK itself has no
+// cloud dependency.
+type ObjectStore interface {
+	// PutObject creates or replaces the object at key.
+	PutObject(ctx context.Context, key string, data []byte) error
+
+	// GetObject returns the contents of the object at key.
+	GetObject(ctx context.Context, key string) ([]byte, error)
+
+	// ListObjects returns the keys of all objects starting with prefix.
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+
+	// DeleteObject removes the object at key. Deleting a missing object
+	// must not fail.
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// ObjectStorageConfig configures an ObjectStorageAdapter.
+type ObjectStorageConfig struct {
+	// Store is the object storage client.
+	Store ObjectStore
+
+	// Prefix is prepended to every object key, e.g. "ripple/checkout/".
+	//
+	// Default: "ripple/".
+	Prefix string
+
+	// Timeout bounds each object storage call.
+	//
+	// Default: 30 seconds.
+	Timeout time.Duration
+}
+
+// ObjectStorageAdapter is a StorageAdapter that persists events as NDJSON
+// objects in a cloud object store, so stateless containers can recover
+// events after a redeploy where local files are gone.
+//
+// Each Save writes a new object named "<prefix><UTC timestamp>-<random>.ndjson"
+// and then deletes the objects it replaces. Load reads every object under the
+// prefix, oldest first, including objects left by instances that no longer
+// run; those are deleted by the next Save or Clear. Instances running at the
+// same time should use distinct prefixes, or they may load and deliver each
+// other's events.
+type ObjectStorageAdapter struct {
+	store   ObjectStore
+	prefix  string
+	timeout time.Duration
+
+	mu    sync.Mutex
+	owned []string // objects holding the stored events
+}
+
+// Ensure ObjectStorageAdapter implements StorageAdapter interface
+var _ StorageAdapter = (*ObjectStorageAdapter)(nil)
+
+// NewObjectStorageAdapter creates an object storage adapter. It returns an
+// error if config has no Store.
+func NewObjectStorageAdapter(config ObjectStorageConfig) (*ObjectStorageAdapter, error) {
+	if config.Store == nil {
+		return nil, errors.New("object storage adapter requires a store")
+	}
+	if config.Prefix == "" {
+		config.Prefix = defaultObjectPrefix
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultObjectTimeout
+	}
+	return &ObjectStorageAdapter{
+		store:   config.Store,
+		prefix:  config.Prefix,
+		timeout: config.Timeout,
+	}, nil
+}
+
+// Save replaces the stored events with events: it writes them to a new
+// object, unless there are none, and deletes the previous objects.
+func (o *ObjectStorageAdapter) Save(events []Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var written []string
+	if len(events) > 0 {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for i := range events {
+			if err := enc.Encode(&events[i]); err != nil {
+				return fmt.Errorf("failed to marshal events: %w", err)
+			}
+		}
+		key, err := o.newKey()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+		defer cancel()
+		if err := o.store.PutObject(ctx, key, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write object %s: %w", key, err)
+		}
+		written = []string{key}
+	}
+
+	remaining, err := o.deleteObjects(o.owned)
+	o.owned = append(written, remaining...)
+	return err
+}
+
+// Load returns the events of every object under the prefix, oldest first.
+func (o *ObjectStorageAdapter) Load() ([]Event, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+	keys, err := o.store.ListObjects(ctx, o.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects under %s: %w", o.prefix, err)
+	}
+	slices.Sort(keys)
+
+	events := []Event{}
+	for _, key := range keys {
+		getCtx, cancel := context.WithTimeout(context.Background(), o.timeout)
+		data, err := o.store.GetObject(getCtx, key)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+		}
+		decoded, err := decodeNDJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode object %s: %w", key, err)
+		}
+		events = append(events, decoded...)
+	}
+	for _, key := range keys {
+		if !slices.Contains(o.owned, key) {
+			o.owned = append(o.owned, key)
+		}
+	}
+	return events, nil
+}
+
+// Clear deletes the objects holding the stored events.
+func (o *ObjectStorageAdapter) Clear() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	remaining, err := o.deleteObjects(o.owned)
+	o.owned = remaining
+	return err
+}
+
+// Close does nothing; the store is owned by the caller.
+func (o *ObjectStorageAdapter) Close() error {
+	return nil
+}
+
+// deleteObjects deletes keys and returns those that could not be deleted.
+// Caller must hold o.mu.
+func (o *ObjectStorageAdapter) deleteObjects(keys []string) ([]string, error) {
+	var remaining []string
+	var errs []error
+	for _, key := range keys {
+		ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+		err := o.store.DeleteObject(ctx, key)
+		cancel()
+		if err != nil {
+			remaining = append(remaining, key)
+			errs = append(errs, fmt.Errorf("failed to delete object %s: %w", key, err))
+		}
+	}
+	return remaining, errors.Join(errs...)
+}
+
+// newKey returns a new timestamped object key.
+func (o *ObjectStorageAdapter) newKey() (string, error) {
+	var suffix [8]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", fmt.Errorf("failed to generate object key: %w", err)
+	}
+	return fmt.Sprintf("%s%s-%s.ndjson", o.prefix, time.Now().UTC().Format(objectKeyTime), hex.EncodeToString(suffix[:])), nil
+}
+
+// decodeNDJSON decodes one event per non-empty line.
+func decodeNDJSON(data []byte) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryObjectStore is an in-memory ObjectStore.
+type memoryObjectStore struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	deleteErr error
+}
+
+func newMemoryObjectStore() *memoryObjectStore {
+	return &memoryObjectStore{objects: make(map[string][]byte)}
+}
+
+func (m *memoryObjectStore) PutObject(ctx context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = slices.Clone(data)
+	return nil
+}
+
+func (m *memoryObjectStore) GetObject(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func (m *memoryObjectStore) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryObjectStore) DeleteObject(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memoryObjectStore) keys() []string {
+	keys, _ := m.ListObjects(context.Background(), "")
+	slices.Sort(keys)
+	return keys
+}
+
+func TestObjectStorageAdapterConformance(t *testing.T) {
+	TestStorageAdapter(t, func() StorageAdapter {
+		adapter, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: newMemoryObjectStore()})
+		return adapter
+	})
+}
+
+func TestObjectStorageAdapter(t *testing.T) {
+	t.Run("should keep the events in one timestamped NDJSON object", func(t *testing.T) {
+		store := newMemoryObjectStore()
+		adapter, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store, Prefix: "events/checkout/"})
+
+		_ = adapter.Save([]Event{{Name: "a"}, {Name: "b"}})
+		_ = adapter.Save([]Event{{Name: "c"}})
+
+		keys := store.keys()
+		if len(keys) != 1 || !strings.HasPrefix(keys[0], "events/checkout/") || !strings.HasSuffix(keys[0], ".ndjson") {
+			t.Fatalf("unexpected objects: %v", keys)
+		}
+		if data := string(store.objects[keys[0]]); strings.Count(data, "\n") != 1 || !strings.Contains(data, `"name":"c"`) {
+			t.Fatalf("unexpected object contents: %q", data)
+		}
+
+		_ = adapter.Save(nil)
+		if len(store.keys()) != 0 {
+			t.Fatal("expected saving no events to delete the object")
+		}
+	})
+
+	t.Run("should recover the events of a previous instance", func(t *testing.T) {
+		store := newMemoryObjectStore()
+		previous, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store})
+		_ = previous.Save([]Event{{Name: "a"}})
+		other, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store})
+		_ = other.Save([]Event{{Name: "b"}})
+
+		next, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store})
+		events, err := next.Load()
+		if err != nil || len(events) != 2 || events[0].Name != "a" || events[1].Name != "b" {
+			t.Fatalf("expected both instances' events oldest first, got %v, %v", events, err)
+		}
+
+		_ = next.Save([]Event{{Name: "c"}})
+		if len(store.keys()) != 1 {
+			t.Fatalf("expected the recovered objects to be replaced, got %v", store.keys())
+		}
+	})
+
+	t.Run("should retry deleting objects that failed to delete", func(t *testing.T) {
+		store := newMemoryObjectStore()
+		adapter, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store})
+		_ = adapter.Save([]Event{{Name: "a"}})
+
+		store.deleteErr = errors.New("access denied")
+		if err := adapter.Clear(); err == nil {
+			t.Fatal("expected the delete error")
+		}
+		store.deleteErr = nil
+		if err := adapter.Clear(); err != nil || len(store.keys()) != 0 {
+			t.Fatalf("expected the object to be deleted, got %v, %v", err, store.keys())
+		}
+	})
+
+	t.Run("should fail on corrupt objects", func(t *testing.T) {
+		store := newMemoryObjectStore()
+		store.objects["ripple/bad.ndjson"] = []byte("{not json\n")
+		adapter, _ := NewObjectStorageAdapter(ObjectStorageConfig{Store: store})
+		if _, err := adapter.Load(); err == nil || !strings.Contains(err.Error(), "ripple/bad.ndjson") {
+			t.Fatalf("expected a decode error naming the object, got %v", err)
+		}
+	})
+
+	t.Run("should require a store", func(t *testing.T) {
+		if _, err := NewObjectStorageAdapter(ObjectStorageConfig{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
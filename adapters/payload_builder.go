@@ -0,0 +1,76 @@
+package adapters
+
+import "time"
+
+// PayloadBuilder builds the JSON body NetHTTPAdapter sends for a batch, so
+// the envelope can match an ingestion API without a custom adapter.
+type PayloadBuilder interface {
+	// BuildPayload returns the value encoded as the request body. It is
+	// called once per delivery attempt and must be safe for concurrent use.
+	BuildPayload(batch Batch) (any, error)
+}
+
+// PayloadBuilderFunc adapts a function to the PayloadBuilder interface.
+type PayloadBuilderFunc func(batch Batch) (any, error)
+
+// BuildPayload calls f(batch).
+func (f PayloadBuilderFunc) BuildPayload(batch Batch) (any, error) {
+	return f(batch)
+}
+
+// defaultPayload is the {"sentAt": ..., "events": [...]} body sent without a
+// PayloadBuilder.
+func defaultPayload(batch Batch) any {
+	return map[string]any{"sentAt": batch.SentAt.UnixMilli(), "events": batch.Events}
+}
+
+// Envelope is a PayloadBuilder that wraps the events of a batch with
+// top-level fields, e.g.
+//
+//	{"version": "2", "batchId": "...", "sentAt": 1700000000000, "sdk": "ripple-go", "events": [...]}
+type Envelope struct {
+	// Version is sent as "version" so the server can tell envelope formats
+	// apart.
+	//
+	// Optional: If empty, no version is sent.
+	Version string
+
+	// Fields are static top-level fields, such as SDK information. They
+	// cannot replace "events" or the fields set by the options below.
+	//
+	// Optional.
+	Fields map[string]any
+
+	// BatchID adds the batch ID as "batchId".
+	//
+	// Default: false.
+	BatchID bool
+
+	// SentAt adds the batch's SentAt as Unix milliseconds as "sentAt".
+	//
+	// Default: false.
+	SentAt bool
+}
+
+// BuildPayload returns the envelope for batch.
+func (e *Envelope) BuildPayload(batch Batch) (any, error) {
+	payload := make(map[string]any, len(e.Fields)+4)
+	for k, v := range e.Fields {
+		payload[k] = v
+	}
+	if e.Version != "" {
+		payload["version"] = e.Version
+	}
+	if e.BatchID {
+		payload["batchId"] = batch.ID
+	}
+	if e.SentAt {
+		sentAt := batch.SentAt
+		if sentAt.IsZero() {
+			sentAt = time.Now()
+		}
+		payload["sentAt"] = sentAt.UnixMilli()
+	}
+	payload["events"] = batch.Events
+	return payload, nil
+}
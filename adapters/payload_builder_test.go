@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// captureBody starts a server recording the last request body.
+func captureBody(t *testing.T) (*httptest.Server, func() map[string]any) {
+	t.Helper()
+	bodies := make(chan map[string]any, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("invalid body %q: %v", data, err)
+		}
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() map[string]any { return <-bodies }
+}
+
+func TestNetHTTPAdapter_PayloadBuilder(t *testing.T) {
+	t.Run("should send the envelope built for the batch", func(t *testing.T) {
+		server, body := captureBody(t)
+		adapter, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{
+			PayloadBuilder: &Envelope{
+				Version: "2",
+				Fields:  map[string]any{"sdk": "ripple-go", "events": "ignored"},
+				BatchID: true,
+				SentAt:  true,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		batch := Batch{ID: "b-1", Endpoint: server.URL, Events: []Event{{Name: "a"}}, SentAt: time.UnixMilli(1700000000000)}
+		if _, err := adapter.(BatchSender).SendBatch(context.Background(), batch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := body()
+		if got["version"] != "2" || got["batchId"] != "b-1" || got["sdk"] != "ripple-go" || got["sentAt"] != float64(1700000000000) {
+			t.Fatalf("unexpected envelope: %v", got)
+		}
+		if events, ok := got["events"].([]any); !ok || len(events) != 1 {
+			t.Fatalf("expected the batch's events, got %v", got["events"])
+		}
+	})
+
+	t.Run("should use a builder function", func(t *testing.T) {
+		server, body := captureBody(t)
+		adapter, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{
+			PayloadBuilder: PayloadBuilderFunc(func(batch Batch) (any, error) {
+				return map[string]any{"data": map[string]any{"items": batch.Events}}, nil
+			}),
+		})
+
+		if _, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := body()["data"].(map[string]any)["items"]; !ok {
+			t.Fatal("expected the custom envelope")
+		}
+	})
+
+	t.Run("should return the builder's error without sending", func(t *testing.T) {
+		adapter, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{
+			PayloadBuilder: PayloadBuilderFunc(func(Batch) (any, error) {
+				return nil, errors.New("boom")
+			}),
+		})
+		if _, err := adapter.Send("http://127.0.0.1:0", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should send events and sentAt without a builder", func(t *testing.T) {
+		server, body := captureBody(t)
+		adapter, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{})
+		batch := Batch{Endpoint: server.URL, Events: []Event{{Name: "a"}}, SentAt: time.UnixMilli(1700000000000)}
+		if _, err := adapter.(BatchSender).SendBatch(context.Background(), batch); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := body(); len(got) != 2 || got["events"] == nil || got["sentAt"] != float64(1700000000000) {
+			t.Fatalf("unexpected body: %v", got)
+		}
+	})
+
+	t.Run("should stamp sentAt when the batch has none", func(t *testing.T) {
+		server, body := captureBody(t)
+		adapter, _ := NewNetHTTPAdapterWithConfig(NetHTTPConfig{})
+		before := time.Now().UnixMilli()
+		if _, err := adapter.Send(server.URL, []Event{{Name: "a"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sentAt, _ := body()["sentAt"].(float64); int64(sentAt) < before {
+			t.Fatalf("expected a current sentAt, got %v", sentAt)
+		}
+	})
+
+	t.Run("should reject a builder combined with NDJSON", func(t *testing.T) {
+		_, err := NewNetHTTPAdapterWithConfig(NetHTTPConfig{NDJSON: true, PayloadBuilder: &Envelope{}})
+		if !errors.Is(err, errPayloadBuilderWithNDJSON) {
+			t.Fatalf("expected errPayloadBuilderWithNDJSON, got %v", err)
+		}
+	})
+}
@@ -2,10 +2,12 @@ package adapters
 
 import (
 	"log"
+	"sync"
 )
 
 // PrintLoggerAdapter implements LoggerAdapter using standard log package
 type PrintLoggerAdapter struct {
+	mu    sync.RWMutex
 	level LogLevel
 }
 
@@ -14,6 +16,14 @@ func NewPrintLoggerAdapter(level LogLevel) *PrintLoggerAdapter {
 	return &PrintLoggerAdapter{level: level}
 }
 
+// SetLevel changes the minimum level that is logged. It is safe to call
+// concurrently with logging.
+func (p *PrintLoggerAdapter) SetLevel(level LogLevel) {
+	p.mu.Lock()
+	p.level = level
+	p.mu.Unlock()
+}
+
 func (p *PrintLoggerAdapter) shouldLog(level LogLevel) bool {
 	levels := map[LogLevel]int{
 		LogLevelDebug: 0,
@@ -22,6 +32,8 @@ func (p *PrintLoggerAdapter) shouldLog(level LogLevel) bool {
 		LogLevelError: 3,
 		LogLevelNone:  4,
 	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	return levels[level] >= levels[p.level]
 }
 
@@ -0,0 +1,9 @@
+package adapters
+
+// OpenFilesReporter is an optional extension of HTTPAdapter and
+// StorageAdapter that reports the file descriptors (files, sockets) the
+// adapter currently holds open, for per-client resource accounting.
+type OpenFilesReporter interface {
+	// OpenFiles returns the number of file descriptors currently held.
+	OpenFiles() int
+}
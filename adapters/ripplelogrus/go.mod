@@ -0,0 +1,14 @@
+module github.com/Tap30/ripple-go/adapters/ripplelogrus
+
+go 1.25
+
+require (
+	github.com/Tap30/ripple-go v0.0.1
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+
+// Dependents resolve the tagged release above; in this repository the
+// module is built against the working tree.
+replace github.com/Tap30/ripple-go => ../..
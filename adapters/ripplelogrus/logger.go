@@ -0,0 +1,40 @@
+// Package ripplelogrus routes Ripple's internal logs to a logrus logger. It
+// is a separate module so the SDK itself does not depend on logrus.
+package ripplelogrus
+
+import (
+	"github.com/Tap30/ripple-go/adapters"
+	"github.com/sirupsen/logrus"
+)
+
+// LogrusLoggerAdapter implements adapters.LoggerAdapter with a
+// *logrus.Logger.
+type LogrusLoggerAdapter struct {
+	entry *logrus.Entry
+}
+
+// Ensure LogrusLoggerAdapter implements adapters.LoggerAdapter
+var _ adapters.LoggerAdapter = (*LogrusLoggerAdapter)(nil)
+
+// NewLogrusLoggerAdapter creates a logger adapter writing to logger, with the
+// field component=ripple. Which levels are logged is decided by logger's
+// level, so the client's log level setting does not apply.
+func NewLogrusLoggerAdapter(logger *logrus.Logger) *LogrusLoggerAdapter {
+	return &LogrusLoggerAdapter{entry: logger.WithField("component", "ripple")}
+}
+
+func (l *LogrusLoggerAdapter) Debug(message string, args ...any) {
+	l.entry.Debugf(message, args...)
+}
+
+func (l *LogrusLoggerAdapter) Info(message string, args ...any) {
+	l.entry.Infof(message, args...)
+}
+
+func (l *LogrusLoggerAdapter) Warn(message string, args ...any) {
+	l.entry.Warnf(message, args...)
+}
+
+func (l *LogrusLoggerAdapter) Error(message string, args ...any) {
+	l.entry.Errorf(message, args...)
+}
@@ -0,0 +1,54 @@
+package ripplelogrus
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLogrusLoggerAdapter(t *testing.T) {
+	t.Run("should log formatted messages at the matching level", func(t *testing.T) {
+		base, hook := test.NewNullLogger()
+		base.SetLevel(logrus.DebugLevel)
+		logger := NewLogrusLoggerAdapter(base)
+
+		logger.Debug("flushing %d events", 3)
+		logger.Info("started")
+		logger.Warn("retrying in %s", "1s")
+		logger.Error("dropped batch: %v", "400")
+
+		entries := hook.AllEntries()
+		if len(entries) != 4 {
+			t.Fatalf("expected 4 entries, got %d", len(entries))
+		}
+		want := []struct {
+			level   logrus.Level
+			message string
+		}{
+			{logrus.DebugLevel, "flushing 3 events"},
+			{logrus.InfoLevel, "started"},
+			{logrus.WarnLevel, "retrying in 1s"},
+			{logrus.ErrorLevel, "dropped batch: 400"},
+		}
+		for i, w := range want {
+			if entries[i].Level != w.level || entries[i].Message != w.message || entries[i].Data["component"] != "ripple" {
+				t.Errorf("entry %d: expected %s %q from ripple, got %s %q %v", i, w.level, w.message, entries[i].Level, entries[i].Message, entries[i].Data)
+			}
+		}
+	})
+
+	t.Run("should skip levels the logger disables", func(t *testing.T) {
+		base, hook := test.NewNullLogger()
+		base.SetLevel(logrus.WarnLevel)
+		logger := NewLogrusLoggerAdapter(base)
+
+		logger.Debug("hidden")
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		if len(hook.AllEntries()) != 1 {
+			t.Fatalf("expected 1 entry, got %d", len(hook.AllEntries()))
+		}
+	})
+}
@@ -0,0 +1,14 @@
+module github.com/Tap30/ripple-go/adapters/ripplezap
+
+go 1.25
+
+require (
+	github.com/Tap30/ripple-go v0.0.1
+	go.uber.org/zap v1.27.0
+)
+
+require go.uber.org/multierr v1.10.0 // indirect
+
+// Dependents resolve the tagged release above; in this repository the
+// module is built against the working tree.
+replace github.com/Tap30/ripple-go => ../..
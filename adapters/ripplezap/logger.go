@@ -0,0 +1,50 @@
+// Package ripplezap routes Ripple's internal logs to a zap logger. It is a
+// separate module so the SDK itself does not depend on zap.
+package ripplezap
+
+import (
+	"fmt"
+
+	"github.com/Tap30/ripple-go/adapters"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ZapLoggerAdapter implements adapters.LoggerAdapter with a *zap.Logger.
+type ZapLoggerAdapter struct {
+	logger *zap.Logger
+}
+
+// Ensure ZapLoggerAdapter implements adapters.LoggerAdapter
+var _ adapters.LoggerAdapter = (*ZapLoggerAdapter)(nil)
+
+// NewZapLoggerAdapter creates a logger adapter writing to logger, named
+// "ripple". Which levels are logged is decided by logger's core, so the
+// client's log level setting does not apply. Messages are only formatted
+// when their level is enabled.
+func NewZapLoggerAdapter(logger *zap.Logger) *ZapLoggerAdapter {
+	return &ZapLoggerAdapter{logger: logger.Named("ripple").WithOptions(zap.AddCallerSkip(2))}
+}
+
+func (z *ZapLoggerAdapter) Debug(message string, args ...any) {
+	z.log(zapcore.DebugLevel, message, args)
+}
+
+func (z *ZapLoggerAdapter) Info(message string, args ...any) {
+	z.log(zapcore.InfoLevel, message, args)
+}
+
+func (z *ZapLoggerAdapter) Warn(message string, args ...any) {
+	z.log(zapcore.WarnLevel, message, args)
+}
+
+func (z *ZapLoggerAdapter) Error(message string, args ...any) {
+	z.log(zapcore.ErrorLevel, message, args)
+}
+
+func (z *ZapLoggerAdapter) log(level zapcore.Level, message string, args []any) {
+	if entry := z.logger.Check(level, message); entry != nil {
+		entry.Message = fmt.Sprintf(message, args...)
+		entry.Write()
+	}
+}
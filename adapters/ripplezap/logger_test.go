@@ -0,0 +1,53 @@
+package ripplezap
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapLoggerAdapter(t *testing.T) {
+	t.Run("should log formatted messages at the matching level", func(t *testing.T) {
+		core, logs := observer.New(zapcore.DebugLevel)
+		logger := NewZapLoggerAdapter(zap.New(core))
+
+		logger.Debug("flushing %d events", 3)
+		logger.Info("started")
+		logger.Warn("retrying in %s", "1s")
+		logger.Error("dropped batch: %v", "400")
+
+		entries := logs.AllUntimed()
+		if len(entries) != 4 {
+			t.Fatalf("expected 4 entries, got %d", len(entries))
+		}
+		want := []struct {
+			level   zapcore.Level
+			message string
+		}{
+			{zapcore.DebugLevel, "flushing 3 events"},
+			{zapcore.InfoLevel, "started"},
+			{zapcore.WarnLevel, "retrying in 1s"},
+			{zapcore.ErrorLevel, "dropped batch: 400"},
+		}
+		for i, w := range want {
+			if entries[i].Level != w.level || entries[i].Message != w.message || entries[i].LoggerName != "ripple" {
+				t.Errorf("entry %d: expected %s %q from ripple, got %+v", i, w.level, w.message, entries[i].Entry)
+			}
+		}
+	})
+
+	t.Run("should skip levels the core disables", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		logger := NewZapLoggerAdapter(zap.New(core))
+
+		logger.Debug("hidden")
+		logger.Info("hidden")
+		logger.Warn("shown")
+
+		if logs.Len() != 1 {
+			t.Fatalf("expected 1 entry, got %d", logs.Len())
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Scenario scripts the outcome of one or more consecutive sends of a
+// ScriptedHTTPAdapter.
+type Scenario struct {
+	// Status is the response status. Zero means 200 unless Err is set.
+	Status int
+
+	// Err, if set, is returned instead of a response, like a network error.
+	Err error
+
+	// Delay is waited before responding. A send whose context is done
+	// first returns the context's error.
+	Delay time.Duration
+
+	// Backoff is set as the response's server-requested pause.
+	Backoff time.Duration
+
+	// Times is how many consecutive sends the scenario answers.
+	// Zero means once.
+	Times int
+}
+
+// ScriptedRequest is a send received by a ScriptedHTTPAdapter.
+type ScriptedRequest struct {
+	Endpoint string
+	Events   []Event
+	Headers  map[string]string
+}
+
+// ScriptedHTTPAdapter is an HTTPAdapter for tests that answers sends with a
+// scripted sequence of statuses, delays and errors, and records every send.
+// Once the script is exhausted, the last scenario repeats; an empty script
+// always answers 200.
+type ScriptedHTTPAdapter struct {
+	mu        sync.Mutex
+	scenarios []Scenario
+	next      int
+	used      int
+	requests  []ScriptedRequest
+}
+
+// Ensure ScriptedHTTPAdapter implements HTTPAdapter and EventRetainer
+var (
+	_ HTTPAdapter   = (*ScriptedHTTPAdapter)(nil)
+	_ EventRetainer = (*ScriptedHTTPAdapter)(nil)
+)
+
+// NewScriptedHTTPAdapter creates an adapter answering sends with scenarios
+// in order, e.g. two 503s followed by success:
+//
+//	adapters.NewScriptedHTTPAdapter(
+//		adapters.Scenario{Status: 503, Times: 2},
+//		adapters.Scenario{Status: 200},
+//	)
+func NewScriptedHTTPAdapter(scenarios ...Scenario) *ScriptedHTTPAdapter {
+	return &ScriptedHTTPAdapter{scenarios: scenarios}
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it records copies of the events.
+func (s *ScriptedHTTPAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send answers with the next scenario.
+func (s *ScriptedHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return s.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext records the send and answers with the next scenario.
+func (s *ScriptedHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	scenario := s.take(ScriptedRequest{
+		Endpoint: endpoint,
+		Events:   append([]Event(nil), events...),
+		Headers:  headers,
+	})
+
+	if scenario.Delay > 0 {
+		timer := time.NewTimer(scenario.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if scenario.Err != nil {
+		return nil, scenario.Err
+	}
+	status := scenario.Status
+	if status == 0 {
+		status = 200
+	}
+	return &HTTPResponse{Status: status, Backoff: scenario.Backoff}, nil
+}
+
+// take records req and returns the scenario answering it.
+func (s *ScriptedHTTPAdapter) take(req ScriptedRequest) Scenario {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	if len(s.scenarios) == 0 {
+		return Scenario{}
+	}
+	if s.next >= len(s.scenarios) {
+		return s.scenarios[len(s.scenarios)-1]
+	}
+
+	scenario := s.scenarios[s.next]
+	s.used++
+	if s.used >= max(scenario.Times, 1) {
+		s.next++
+		s.used = 0
+	}
+	return scenario
+}
+
+// Calls returns the number of sends received.
+func (s *ScriptedHTTPAdapter) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// Requests returns every send received, in order.
+func (s *ScriptedHTTPAdapter) Requests() []ScriptedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ScriptedRequest(nil), s.requests...)
+}
@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScriptedHTTPAdapter(t *testing.T) {
+	t.Run("should answer scenarios in order and repeat the last one", func(t *testing.T) {
+		networkErr := errors.New("connection reset")
+		adapter := NewScriptedHTTPAdapter(
+			Scenario{Status: 503, Times: 2},
+			Scenario{Err: networkErr},
+			Scenario{Status: 202},
+		)
+
+		var got []any
+		for i := 0; i < 5; i++ {
+			resp, err := adapter.Send("http://test", []Event{{Name: "a"}}, nil)
+			if err != nil {
+				got = append(got, err)
+			} else {
+				got = append(got, resp.Status)
+			}
+		}
+
+		want := []any{503, 503, networkErr, 202, 202}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("send %d: expected %v, got %v", i, want[i], got[i])
+			}
+		}
+		if adapter.Calls() != 5 {
+			t.Fatalf("expected 5 calls, got %d", adapter.Calls())
+		}
+	})
+
+	t.Run("should answer 200 without a script", func(t *testing.T) {
+		adapter := NewScriptedHTTPAdapter()
+		resp, err := adapter.Send("http://test", nil, nil)
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("expected 200, got %v, %v", resp, err)
+		}
+	})
+
+	t.Run("should record requests", func(t *testing.T) {
+		adapter := NewScriptedHTTPAdapter()
+		headers := map[string]string{"X-API-Key": "k"}
+		_, _ = adapter.Send("http://test", []Event{{Name: "a"}, {Name: "b"}}, headers)
+
+		requests := adapter.Requests()
+		if len(requests) != 1 || requests[0].Endpoint != "http://test" || len(requests[0].Events) != 2 || requests[0].Headers["X-API-Key"] != "k" {
+			t.Fatalf("unexpected requests: %+v", requests)
+		}
+	})
+
+	t.Run("should delay and honour cancellation", func(t *testing.T) {
+		adapter := NewScriptedHTTPAdapter(Scenario{Status: 200, Delay: time.Hour})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if _, err := adapter.SendWithContext(ctx, "http://test", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
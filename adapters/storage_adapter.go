@@ -27,3 +27,30 @@ type StorageAdapter interface {
 	// Returns error if close fails.
 	Close() error
 }
+
+// SequenceStore is an optional extension of StorageAdapter that persists the
+// event sequence counter of each producer across restarts.
+// Storage adapters that do not implement it fall back to the highest
+// sequence number among restored events.
+type SequenceStore interface {
+	// LoadSequence returns the last sequence number saved for producerID,
+	// or 0 if none was saved.
+	LoadSequence(producerID string) (uint64, error)
+
+	// SaveSequence records the last sequence number assigned by producerID.
+	SaveSequence(producerID string, seq uint64) error
+}
+
+// IdentityStore is an optional extension of StorageAdapter that persists the
+// identified user across restarts (see Client.Identify).
+type IdentityStore interface {
+	// LoadIdentity returns the persisted user ID and traits, or an empty
+	// user ID if none was saved.
+	LoadIdentity() (userID string, traits map[string]any, err error)
+
+	// SaveIdentity persists the user ID and traits.
+	SaveIdentity(userID string, traits map[string]any) error
+
+	// ClearIdentity removes the persisted identity.
+	ClearIdentity() error
+}
@@ -0,0 +1,271 @@
+package adapters
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StorageMiddleware wraps a StorageAdapter with extra behavior, such as
+// metrics, retries or encryption.
+type StorageMiddleware func(StorageAdapter) StorageAdapter
+
+// ChainStorage applies middleware to storage so that the first one is
+// outermost, e.g. ChainStorage(s, StorageMetrics(f), StorageRetry(3, d))
+// measures each call including its retries.
+//
+// The wrapped adapter keeps the inner adapter's SequenceStore and
+// IdentityStore, which are called directly without the middleware.
+func ChainStorage(storage StorageAdapter, middleware ...StorageMiddleware) StorageAdapter {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		storage = withExtensions(middleware[i](storage), storage)
+	}
+	return storage
+}
+
+// withExtensions returns wrapper, also implementing the optional extensions
+// inner implements.
+func withExtensions(wrapper, inner StorageAdapter) StorageAdapter {
+	sequences, hasSequences := inner.(SequenceStore)
+	identity, hasIdentity := inner.(IdentityStore)
+	switch {
+	case hasSequences && hasIdentity:
+		return struct {
+			StorageAdapter
+			SequenceStore
+			IdentityStore
+		}{wrapper, sequences, identity}
+	case hasSequences:
+		return struct {
+			StorageAdapter
+			SequenceStore
+		}{wrapper, sequences}
+	case hasIdentity:
+		return struct {
+			StorageAdapter
+			IdentityStore
+		}{wrapper, identity}
+	default:
+		return wrapper
+	}
+}
+
+// StorageOp names a StorageAdapter method.
+type StorageOp string
+
+const (
+	StorageOpSave  StorageOp = "save"
+	StorageOpLoad  StorageOp = "load"
+	StorageOpClear StorageOp = "clear"
+	StorageOpClose StorageOp = "close"
+)
+
+// StorageCall describes one completed StorageAdapter call.
+type StorageCall struct {
+	// Op is the method called.
+	Op StorageOp
+
+	// Events is the number of events saved or loaded.
+	Events int
+
+	// Duration is how long the call took.
+	Duration time.Duration
+
+	// Err is the error the call returned, if any.
+	Err error
+}
+
+// StorageMetrics reports every call to the wrapped adapter to observe, e.g.
+// to export storage latency and error counts. observe runs synchronously
+// after each call and must be safe for concurrent use.
+func StorageMetrics(observe func(StorageCall)) StorageMiddleware {
+	return func(inner StorageAdapter) StorageAdapter {
+		return &metricsStorage{inner: inner, observe: observe}
+	}
+}
+
+type metricsStorage struct {
+	inner   StorageAdapter
+	observe func(StorageCall)
+}
+
+func (m *metricsStorage) Save(events []Event) error {
+	start := time.Now()
+	err := m.inner.Save(events)
+	m.observe(StorageCall{Op: StorageOpSave, Events: len(events), Duration: time.Since(start), Err: err})
+	return err
+}
+
+func (m *metricsStorage) Load() ([]Event, error) {
+	start := time.Now()
+	events, err := m.inner.Load()
+	m.observe(StorageCall{Op: StorageOpLoad, Events: len(events), Duration: time.Since(start), Err: err})
+	return events, err
+}
+
+func (m *metricsStorage) Clear() error {
+	start := time.Now()
+	err := m.inner.Clear()
+	m.observe(StorageCall{Op: StorageOpClear, Duration: time.Since(start), Err: err})
+	return err
+}
+
+func (m *metricsStorage) Close() error {
+	start := time.Now()
+	err := m.inner.Close()
+	m.observe(StorageCall{Op: StorageOpClose, Duration: time.Since(start), Err: err})
+	return err
+}
+
+// StorageRetry retries failed Save, Load and Clear calls up to attempts
+// times in total, waiting backoff after the first failure and doubling it
+// after each further one. A *StorageQuotaExceededError or
+// *StorageCorruptedError is returned without retrying, as retrying cannot
+// fix it. Close is not retried.
+func StorageRetry(attempts int, backoff time.Duration) StorageMiddleware {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return func(inner StorageAdapter) StorageAdapter {
+		return &retryStorage{inner: inner, attempts: attempts, backoff: backoff, sleep: time.Sleep}
+	}
+}
+
+type retryStorage struct {
+	inner    StorageAdapter
+	attempts int
+	backoff  time.Duration
+	sleep    func(time.Duration)
+}
+
+// retry calls fn until it succeeds, fails permanently or runs out of
+// attempts.
+func (r *retryStorage) retry(fn func() error) error {
+	delay := r.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= r.attempts || permanentStorageError(err) {
+			return err
+		}
+		r.sleep(delay)
+		delay *= 2
+	}
+}
+
+// permanentStorageError reports whether retrying cannot fix err.
+func permanentStorageError(err error) bool {
+	var quotaErr *StorageQuotaExceededError
+	var corruptedErr *StorageCorruptedError
+	return errors.As(err, &quotaErr) || errors.As(err, &corruptedErr)
+}
+
+func (r *retryStorage) Save(events []Event) error {
+	return r.retry(func() error { return r.inner.Save(events) })
+}
+
+func (r *retryStorage) Load() ([]Event, error) {
+	var events []Event
+	err := r.retry(func() error {
+		var err error
+		events, err = r.inner.Load()
+		return err
+	})
+	return events, err
+}
+
+func (r *retryStorage) Clear() error {
+	return r.retry(r.inner.Clear)
+}
+
+func (r *retryStorage) Close() error {
+	return r.inner.Close()
+}
+
+// encryptedPayloadKey holds an encrypted event in the payload of the event
+// stored in its place.
+const encryptedPayloadKey = "ripple.encrypted"
+
+// StorageEncryption encrypts events at rest with AES-GCM. Each event is
+// stored as an event whose payload holds the encrypted event, so the inner
+// adapter sees no names, payloads or metadata. Loaded events that are not
+// encrypted, e.g. saved before encryption was enabled, are returned as they
+// are. key must be 16, 24 or 32 bytes, selecting AES-128, -192 or -256.
+func StorageEncryption(key []byte) (StorageMiddleware, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage encryption key: %w", err)
+	}
+	return func(inner StorageAdapter) StorageAdapter {
+		return &encryptedStorage{inner: inner, aead: aead}
+	}, nil
+}
+
+type encryptedStorage struct {
+	inner StorageAdapter
+	aead  cipher.AEAD
+}
+
+func (e *encryptedStorage) Save(events []Event) error {
+	sealed := make([]Event, len(events))
+	for i := range events {
+		plaintext, err := json.Marshal(&events[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+		nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(plaintext)+e.aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		ciphertext := e.aead.Seal(nonce, nonce, plaintext, nil)
+		sealed[i] = Event{Payload: map[string]any{
+			encryptedPayloadKey: base64.StdEncoding.EncodeToString(ciphertext),
+		}}
+	}
+	return e.inner.Save(sealed)
+}
+
+func (e *encryptedStorage) Load() ([]Event, error) {
+	events, err := e.inner.Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		encoded, ok := events[i].Payload[encryptedPayloadKey].(string)
+		if !ok {
+			continue
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(ciphertext) < e.aead.NonceSize() {
+			return nil, errors.New("failed to decrypt stored event: malformed ciphertext")
+		}
+		nonce, sealed := ciphertext[:e.aead.NonceSize()], ciphertext[e.aead.NonceSize():]
+		plaintext, err := e.aead.Open(nil, nonce, sealed, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt stored event: %w", err)
+		}
+		var event Event
+		if err := json.Unmarshal(plaintext, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode stored event: %w", err)
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+func (e *encryptedStorage) Clear() error {
+	return e.inner.Clear()
+}
+
+func (e *encryptedStorage) Close() error {
+	return e.inner.Close()
+}
@@ -0,0 +1,209 @@
+package adapters
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyStorage fails the first failures calls with err.
+type flakyStorage struct {
+	memoryStorageAdapter
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyStorage) fail() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.failures > 0 {
+		f.failures--
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyStorage) Save(events []Event) error {
+	if err := f.fail(); err != nil {
+		return err
+	}
+	return f.memoryStorageAdapter.Save(events)
+}
+
+func (f *flakyStorage) Load() ([]Event, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return f.memoryStorageAdapter.Load()
+}
+
+func TestStorageMiddlewareConformance(t *testing.T) {
+	encryption, _ := StorageEncryption(bytes.Repeat([]byte{1}, 32))
+	TestStorageAdapter(t, func() StorageAdapter {
+		return ChainStorage(&memoryStorageAdapter{},
+			StorageMetrics(func(StorageCall) {}),
+			StorageRetry(3, 0),
+			encryption,
+		)
+	})
+}
+
+func TestStorageMetrics(t *testing.T) {
+	var calls []StorageCall
+	storage := ChainStorage(&flakyStorage{failures: 1, err: errors.New("disk full")},
+		StorageMetrics(func(call StorageCall) { calls = append(calls, call) }),
+	)
+
+	_ = storage.Save([]Event{{Name: "a"}})
+	_ = storage.Save([]Event{{Name: "a"}, {Name: "b"}})
+	_, _ = storage.Load()
+	_ = storage.Clear()
+
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 calls, got %d", len(calls))
+	}
+	if calls[0].Op != StorageOpSave || calls[0].Err == nil {
+		t.Fatalf("expected a failed save, got %+v", calls[0])
+	}
+	if calls[2].Op != StorageOpLoad || calls[2].Events != 2 || calls[2].Err != nil {
+		t.Fatalf("expected a load of 2 events, got %+v", calls[2])
+	}
+	if calls[3].Op != StorageOpClear {
+		t.Fatalf("expected a clear, got %+v", calls[3])
+	}
+}
+
+func TestStorageRetry(t *testing.T) {
+	newRetry := func(inner StorageAdapter, attempts int) (StorageAdapter, *[]time.Duration) {
+		var sleeps []time.Duration
+		storage := StorageRetry(attempts, 10*time.Millisecond)(inner).(*retryStorage)
+		storage.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+		return storage, &sleeps
+	}
+
+	t.Run("should retry with doubling backoff", func(t *testing.T) {
+		inner := &flakyStorage{failures: 2, err: errors.New("timeout")}
+		storage, sleeps := newRetry(inner, 3)
+
+		if err := storage.Save([]Event{{Name: "a"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if inner.calls != 3 || len(*sleeps) != 2 || (*sleeps)[0] != 10*time.Millisecond || (*sleeps)[1] != 20*time.Millisecond {
+			t.Fatalf("unexpected retries: %d calls, sleeps %v", inner.calls, *sleeps)
+		}
+		if events, err := storage.Load(); err != nil || len(events) != 1 {
+			t.Fatalf("unexpected load: %v, %v", events, err)
+		}
+	})
+
+	t.Run("should give up after the last attempt", func(t *testing.T) {
+		inner := &flakyStorage{failures: 5, err: errors.New("timeout")}
+		storage, _ := newRetry(inner, 2)
+		if _, err := storage.Load(); err == nil || inner.calls != 2 {
+			t.Fatalf("expected 2 failed attempts, got %d, %v", inner.calls, err)
+		}
+	})
+
+	t.Run("should not retry permanent errors", func(t *testing.T) {
+		inner := &flakyStorage{failures: 5, err: &StorageQuotaExceededError{}}
+		storage, _ := newRetry(inner, 3)
+		if err := storage.Save(nil); err == nil || inner.calls != 1 {
+			t.Fatalf("expected 1 attempt, got %d, %v", inner.calls, err)
+		}
+	})
+}
+
+func TestStorageEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	t.Run("should hide events from the inner adapter", func(t *testing.T) {
+		inner := &memoryStorageAdapter{}
+		encryption, _ := StorageEncryption(key)
+		storage := ChainStorage(inner, encryption)
+
+		_ = storage.Save([]Event{{Name: "purchase", Payload: map[string]any{"card": "4111"}}})
+
+		stored, _ := inner.Load()
+		if stored[0].Name != "" || len(stored[0].Payload) != 1 || strings.Contains(stored[0].Payload[encryptedPayloadKey].(string), "4111") {
+			t.Fatalf("expected an opaque event, got %+v", stored[0])
+		}
+		events, err := storage.Load()
+		if err != nil || events[0].Name != "purchase" || events[0].Payload["card"] != "4111" {
+			t.Fatalf("unexpected events: %+v, %v", events, err)
+		}
+	})
+
+	t.Run("should pass through events saved before encryption", func(t *testing.T) {
+		inner := &memoryStorageAdapter{events: []Event{{Name: "old"}}}
+		encryption, _ := StorageEncryption(key)
+		if events, err := encryption(inner).Load(); err != nil || events[0].Name != "old" {
+			t.Fatalf("unexpected events: %v, %v", events, err)
+		}
+	})
+
+	t.Run("should fail to load with another key", func(t *testing.T) {
+		inner := &memoryStorageAdapter{}
+		encryption, _ := StorageEncryption(key)
+		_ = encryption(inner).Save([]Event{{Name: "a"}})
+
+		other, _ := StorageEncryption(bytes.Repeat([]byte{8}, 32))
+		if _, err := other(inner).Load(); err == nil {
+			t.Fatal("expected a decryption error")
+		}
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		if _, err := StorageEncryption([]byte("short")); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+// sequenceMemoryStorage is a memoryStorageAdapter implementing SequenceStore.
+type sequenceMemoryStorage struct {
+	memoryStorageAdapter
+	seq uint64
+}
+
+func (s *sequenceMemoryStorage) LoadSequence(string) (uint64, error) { return s.seq, nil }
+
+func (s *sequenceMemoryStorage) SaveSequence(_ string, seq uint64) error {
+	s.seq = seq
+	return nil
+}
+
+func TestChainStorage(t *testing.T) {
+	t.Run("should keep the inner adapter's extensions", func(t *testing.T) {
+		inner := &sequenceMemoryStorage{}
+		storage := ChainStorage(inner, StorageMetrics(func(StorageCall) {}), StorageRetry(2, 0))
+
+		store, ok := storage.(SequenceStore)
+		if !ok {
+			t.Fatal("expected the chain to implement SequenceStore")
+		}
+		_ = store.SaveSequence("p", 9)
+		if inner.seq != 9 {
+			t.Fatal("expected the call to reach the inner adapter")
+		}
+		if _, ok := storage.(IdentityStore); ok {
+			t.Fatal("expected no IdentityStore without one inside")
+		}
+	})
+
+	t.Run("should apply the first middleware outermost", func(t *testing.T) {
+		var order []string
+		trace := func(name string) StorageMiddleware {
+			return StorageMetrics(func(StorageCall) { order = append(order, name) })
+		}
+		_ = ChainStorage(&memoryStorageAdapter{}, trace("outer"), trace("inner")).Clear()
+		if strings.Join(order, ",") != "inner,outer" {
+			t.Fatalf("unexpected order: %v", order)
+		}
+	})
+}
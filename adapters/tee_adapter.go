@@ -0,0 +1,165 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// TeeSink is one destination of a TeeAdapter.
+type TeeSink struct {
+	// Name identifies the sink in errors.
+	Name string
+
+	// Adapter delivers batches to the sink, e.g. a NetHTTPAdapter, a
+	// NATSAdapter or a file WriterHTTPAdapter.
+	Adapter HTTPAdapter
+
+	// Endpoint replaces the batch's endpoint for this sink.
+	//
+	// Optional: If empty, the batch's endpoint is used.
+	Endpoint string
+}
+
+// TeeAdapter is an HTTPAdapter that delivers each batch to several sinks
+// concurrently. A batch succeeds once every sink has accepted it. Sinks keep
+// independent retry state: when the dispatcher retries a batch (recognized by
+// its Batch.ID), only the sinks that have not accepted it yet are sent to, so
+// a failing sink does not cause duplicates in the others.
+type TeeAdapter struct {
+	sinks    []TeeSink
+	progress batchProgress
+}
+
+// Ensure TeeAdapter implements HTTPAdapter, BatchSender and EventRetainer
+var (
+	_ HTTPAdapter   = (*TeeAdapter)(nil)
+	_ BatchSender   = (*TeeAdapter)(nil)
+	_ EventRetainer = (*TeeAdapter)(nil)
+)
+
+// NewTeeAdapter creates an adapter fanning batches out to sinks. It returns
+// an error if there are no sinks or a sink has no adapter.
+func NewTeeAdapter(sinks ...TeeSink) (*TeeAdapter, error) {
+	if len(sinks) == 0 {
+		return nil, errors.New("tee adapter requires at least one sink")
+	}
+	for i, sink := range sinks {
+		if sink.Adapter == nil {
+			return nil, fmt.Errorf("tee sink %d (%q) requires an adapter", i, sink.Name)
+		}
+	}
+	return &TeeAdapter{sinks: sinks}, nil
+}
+
+// RetainsEvents implements EventRetainer. It reports whether any sink may
+// keep the events, since sinks are passed the batch's events as they are.
+func (t *TeeAdapter) RetainsEvents() bool {
+	for _, sink := range t.sinks {
+		if retainsEvents(sink.Adapter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Send delivers events to every sink without context.
+func (t *TeeAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return t.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext delivers events to every sink. Without a batch ID there
+// is no retry state, so every call sends to all sinks.
+func (t *TeeAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return t.SendBatch(ctx, Batch{Endpoint: endpoint, Events: events, Headers: headers})
+}
+
+// SendBatch delivers batch to every sink that has not accepted it yet.
+//
+// It returns the first failure in sink order, either an error or a non-2xx
+// response, so the dispatcher retries or drops the batch as it would for a
+// single sink. Otherwise it returns the response of the first sink sent to.
+func (t *TeeAdapter) SendBatch(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	states := t.progress.load(batch.ID, len(t.sinks))
+
+	type result struct {
+		resp *HTTPResponse
+		err  error
+	}
+	results := make([]result, len(t.sinks))
+	var wg sync.WaitGroup
+	for i, sink := range t.sinks {
+		if states[i] == itemAccepted {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := sink.send(ctx, batch)
+			if resp == nil && err == nil {
+				err = errors.New("no response")
+			}
+			results[i] = result{resp, err}
+		}()
+	}
+	wg.Wait()
+
+	var first *HTTPResponse
+	var failure *result
+	for i := range results {
+		if states[i] == itemAccepted {
+			continue
+		}
+		r := &results[i]
+		if r.err != nil || r.resp.Status < 200 || r.resp.Status >= 300 {
+			if failure == nil {
+				failure = r
+				if r.err != nil {
+					r.err = fmt.Errorf("tee sink %q: %w", t.sinks[i].Name, r.err)
+				}
+			}
+			continue
+		}
+		states[i] = itemAccepted
+		if first == nil {
+			first = r.resp
+		}
+	}
+
+	if failure != nil {
+		t.progress.save(batch.ID, states)
+		return failure.resp, failure.err
+	}
+	t.progress.drop(batch.ID)
+	if first == nil {
+		first = &HTTPResponse{Status: 200}
+	}
+	return first, nil
+}
+
+// Close closes every sink adapter that implements io.Closer.
+func (t *TeeAdapter) Close() error {
+	var errs []error
+	for _, sink := range t.sinks {
+		if closer, ok := sink.Adapter.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("tee sink %q: %w", sink.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send delivers batch to the sink, through SendBatch if it implements
+// BatchSender.
+func (s TeeSink) send(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	if s.Endpoint != "" {
+		batch.Endpoint = s.Endpoint
+	}
+	if sender, ok := s.Adapter.(BatchSender); ok {
+		return sender.SendBatch(ctx, batch)
+	}
+	return s.Adapter.SendWithContext(ctx, batch.Endpoint, batch.Events, batch.Headers)
+}
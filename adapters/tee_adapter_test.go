@@ -0,0 +1,98 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTeeAdapter(t *testing.T) {
+	batch := Batch{ID: "b-1", Endpoint: "http://ingest", Events: []Event{{Name: "a"}}}
+
+	t.Run("should deliver to every sink", func(t *testing.T) {
+		primary, archive := NewScriptedHTTPAdapter(Scenario{Status: 202}), NewScriptedHTTPAdapter()
+		tee, _ := NewTeeAdapter(
+			TeeSink{Name: "primary", Adapter: primary},
+			TeeSink{Name: "archive", Adapter: archive, Endpoint: "file://archive"},
+		)
+
+		resp, err := tee.SendBatch(context.Background(), batch)
+		if err != nil || resp.Status != 202 {
+			t.Fatalf("expected the primary's response, got %v, %v", resp, err)
+		}
+		if primary.Requests()[0].Endpoint != "http://ingest" || archive.Requests()[0].Endpoint != "file://archive" {
+			t.Fatal("expected each sink to receive the batch at its endpoint")
+		}
+	})
+
+	t.Run("should retry only the sinks that failed", func(t *testing.T) {
+		primary := NewScriptedHTTPAdapter()
+		kafka := NewScriptedHTTPAdapter(Scenario{Status: 503}, Scenario{Status: 200})
+		tee, _ := NewTeeAdapter(TeeSink{Name: "primary", Adapter: primary}, TeeSink{Name: "kafka", Adapter: kafka})
+
+		if resp, _ := tee.SendBatch(context.Background(), batch); resp.Status != 503 {
+			t.Fatalf("expected the failing sink's response, got %v", resp)
+		}
+		if resp, err := tee.SendBatch(context.Background(), batch); err != nil || resp.Status != 200 {
+			t.Fatalf("expected the retry to succeed, got %v, %v", resp, err)
+		}
+		if primary.Calls() != 1 || kafka.Calls() != 2 {
+			t.Fatalf("expected 1 primary and 2 kafka calls, got %d and %d", primary.Calls(), kafka.Calls())
+		}
+		if tee.progress.len() != 0 {
+			t.Fatal("expected the retry state to be dropped once delivered")
+		}
+	})
+
+	t.Run("should name the sink in errors", func(t *testing.T) {
+		sinkErr := errors.New("connection refused")
+		tee, _ := NewTeeAdapter(
+			TeeSink{Name: "primary", Adapter: NewScriptedHTTPAdapter()},
+			TeeSink{Name: "file", Adapter: NewScriptedHTTPAdapter(Scenario{Err: sinkErr})},
+		)
+
+		_, err := tee.SendBatch(context.Background(), batch)
+		if !errors.Is(err, sinkErr) || !strings.Contains(err.Error(), `"file"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should send to every sink without a batch ID", func(t *testing.T) {
+		primary := NewScriptedHTTPAdapter()
+		failing := NewScriptedHTTPAdapter(Scenario{Status: 500})
+		tee, _ := NewTeeAdapter(TeeSink{Adapter: primary}, TeeSink{Adapter: failing})
+
+		_, _ = tee.Send("http://ingest", batch.Events, nil)
+		_, _ = tee.Send("http://ingest", batch.Events, nil)
+		if primary.Calls() != 2 {
+			t.Fatalf("expected 2 primary calls, got %d", primary.Calls())
+		}
+	})
+
+	t.Run("should retain events if any sink may", func(t *testing.T) {
+		scripted, _ := NewTeeAdapter(TeeSink{Name: "primary", Adapter: NewScriptedHTTPAdapter()})
+		if scripted.RetainsEvents() {
+			t.Fatal("expected a tee of scripted adapters not to retain events")
+		}
+
+		// Embedding the interface hides the scripted adapter's RetainsEvents.
+		opaque := struct{ HTTPAdapter }{NewScriptedHTTPAdapter()}
+		mixed, _ := NewTeeAdapter(
+			TeeSink{Name: "primary", Adapter: NewScriptedHTTPAdapter()},
+			TeeSink{Name: "custom", Adapter: opaque},
+		)
+		if !mixed.RetainsEvents() {
+			t.Fatal("expected a sink without EventRetainer to be assumed to retain events")
+		}
+	})
+
+	t.Run("should require sinks with adapters", func(t *testing.T) {
+		if _, err := NewTeeAdapter(); err == nil {
+			t.Fatal("expected an error without sinks")
+		}
+		if _, err := NewTeeAdapter(TeeSink{Name: "empty"}); err == nil {
+			t.Fatal("expected an error for a sink without an adapter")
+		}
+	})
+}
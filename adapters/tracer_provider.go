@@ -0,0 +1,29 @@
+package adapters
+
+import "context"
+
+// Span is a single traced operation started by a TracerProvider.
+type Span interface {
+	// SetAttribute attaches a key/value attribute to the span.
+	SetAttribute(key string, value any)
+
+	// RecordError records an error on the span and marks it as failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// TracerProvider is an interface for distributed tracing.
+// Implement this interface to bridge SDK spans into OpenTelemetry or any
+// other tracing system without adding a dependency to the SDK itself.
+type TracerProvider interface {
+	// Start begins a new span as a child of any span carried by ctx.
+	//
+	// Parameters:
+	//   - ctx: Parent context
+	//   - name: Span name
+	//
+	// Returns a context carrying the new span and the span itself.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
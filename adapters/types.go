@@ -1,5 +1,7 @@
 package adapters
 
+import "fmt"
+
 // Event represents a tracked event.
 type Event struct {
 	Name      string         `json:"name"`
@@ -8,6 +10,18 @@ type Event struct {
 	IssuedAt  int64          `json:"issuedAt"`
 	SessionID *string        `json:"sessionId"`
 	Platform  *Platform      `json:"platform"`
+
+	// ProducerID identifies the client instance that produced the event.
+	// Only set when sequence numbers are enabled.
+	ProducerID string `json:"producerId,omitempty"`
+
+	// Sequence is the producer's monotonic event counter, starting at 1.
+	// Only set when sequence numbers are enabled.
+	Sequence uint64 `json:"seq,omitempty"`
+
+	// ID is a random UUID that lets the backend deduplicate events delivered
+	// more than once. Only set when event IDs are enabled.
+	ID string `json:"eventId,omitempty"`
 }
 
 // EventMetadata contains optional event metadata.
@@ -16,6 +30,37 @@ type EventMetadata = map[string]any
 // Platform represents server platform information.
 type Platform struct {
 	Type string `json:"type"`
+
+	// OS, Arch and Hostname describe the host, and ContainerID and
+	// Kubernetes the container it runs in, when detected.
+	OS          string          `json:"os,omitempty"`
+	Arch        string          `json:"arch,omitempty"`
+	Hostname    string          `json:"hostname,omitempty"`
+	ContainerID string          `json:"containerId,omitempty"`
+	Kubernetes  *KubernetesInfo `json:"kubernetes,omitempty"`
+
+	// PID and Runtime describe the producing process. Only set when
+	// enrichment is enabled.
+	PID     int    `json:"pid,omitempty"`
+	Runtime string `json:"runtime,omitempty"`
+
+	// App identifies the producing service.
+	App *AppInfo `json:"app,omitempty"`
+}
+
+// KubernetesInfo locates the pod that produced an event.
+type KubernetesInfo struct {
+	Pod       string `json:"pod,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Node      string `json:"node,omitempty"`
+}
+
+// AppInfo identifies the service that produced an event.
+type AppInfo struct {
+	Name        string `json:"name,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Region      string `json:"region,omitempty"`
 }
 
 // StorageQuotaExceededError indicates that the storage quota has been exceeded.
@@ -31,3 +76,25 @@ func (e *StorageQuotaExceededError) Error() string {
 	}
 	return "storage quota exceeded"
 }
+
+// StorageCorruptedError indicates that persisted events could not be decoded.
+// Storage adapters that quarantine corrupt data return it from Load after
+// moving the data aside, so the client logs a warning and starts empty.
+type StorageCorruptedError struct {
+	// Path is where the corrupt data was read from.
+	Path string
+
+	// QuarantinePath is where the corrupt data was moved to.
+	QuarantinePath string
+
+	// Err is the decoding error.
+	Err error
+}
+
+func (e *StorageCorruptedError) Error() string {
+	return fmt.Sprintf("storage %s is corrupt, quarantined to %s: %v", e.Path, e.QuarantinePath, e.Err)
+}
+
+func (e *StorageCorruptedError) Unwrap() error {
+	return e.Err
+}
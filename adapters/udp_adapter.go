@@ -0,0 +1,199 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxDatagramBytes keeps datagrams within a typical Ethernet MTU
+	// so they are not fragmented.
+	defaultMaxDatagramBytes = 1400
+
+	// defaultSyslogAppName is the syslog APP-NAME used when none is set.
+	defaultSyslogAppName = "ripple"
+
+	// syslogPriority is facility local0 (16) with severity informational (6).
+	syslogPriority = 16*8 + 6
+)
+
+// UDPConfig configures a UDPAdapter.
+type UDPConfig struct {
+	// MaxDatagramBytes caps the size of each datagram. Events that do not
+	// fit in a datagram of their own are dropped and reported as rejected.
+	//
+	// Default: 1400.
+	MaxDatagramBytes int
+
+	// Syslog sends each event as an RFC 5424 syslog message, one per
+	// datagram, with the event JSON as the message. Otherwise events are
+	// packed into datagrams as NDJSON, one event per line.
+	//
+	// Default: false.
+	Syslog bool
+
+	// AppName is the syslog APP-NAME.
+	//
+	// Default: "ripple".
+	AppName string
+}
+
+// UDPAdapter is an HTTPAdapter that writes batches as UDP datagrams, for
+// metrics-like events where losing some is cheaper than retrying them. The
+// endpoint passed to Send is a "host:port" address, optionally prefixed with
+// "udp://". Headers are not transmitted.
+//
+// UDP gives no delivery confirmation: a batch whose datagrams were written is
+// reported as status 200, with events too large for a datagram listed in
+// RejectedEvents (status 207). Write errors are returned as errors.
+type UDPAdapter struct {
+	config   UDPConfig
+	hostname string
+	mu       sync.Mutex
+	conns    map[string]net.Conn
+}
+
+// Ensure UDPAdapter implements HTTPAdapter and EventRetainer
+var (
+	_ HTTPAdapter   = (*UDPAdapter)(nil)
+	_ EventRetainer = (*UDPAdapter)(nil)
+)
+
+// NewUDPAdapter creates an adapter writing datagrams as configured.
+func NewUDPAdapter(config UDPConfig) *UDPAdapter {
+	if config.MaxDatagramBytes <= 0 {
+		config.MaxDatagramBytes = defaultMaxDatagramBytes
+	}
+	if config.AppName == "" {
+		config.AppName = defaultSyslogAppName
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &UDPAdapter{
+		config:   config,
+		hostname: hostname,
+		conns:    make(map[string]net.Conn),
+	}
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it marshals the events before sending.
+func (u *UDPAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send writes events to the address named by endpoint.
+func (u *UDPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return u.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext writes events to the address named by endpoint.
+func (u *UDPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	conn, err := u.conn(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	datagrams, rejected, err := u.datagrams(events)
+	if err != nil {
+		return nil, err
+	}
+	for _, datagram := range datagrams {
+		if _, err := conn.Write(datagram); err != nil {
+			return nil, fmt.Errorf("failed to write datagram: %w", err)
+		}
+	}
+
+	if len(rejected) > 0 {
+		return &HTTPResponse{Status: http.StatusMultiStatus, RejectedEvents: rejected}, nil
+	}
+	return &HTTPResponse{Status: http.StatusOK}, nil
+}
+
+// conn returns the connected socket for endpoint, dialling it on first use.
+func (u *UDPAdapter) conn(ctx context.Context, endpoint string) (net.Conn, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if conn, ok := u.conns[endpoint]; ok {
+		return conn, nil
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", strings.TrimPrefix(endpoint, "udp://"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial udp endpoint: %w", err)
+	}
+	u.conns[endpoint] = conn
+	return conn, nil
+}
+
+// datagrams encodes events into datagrams of at most MaxDatagramBytes,
+// returning the indices of events too large to send.
+func (u *UDPAdapter) datagrams(events []Event) ([][]byte, []int, error) {
+	var datagrams [][]byte
+	var rejected []int
+	var current []byte
+	for i, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal events: %w", err)
+		}
+		if u.config.Syslog {
+			data = u.syslogMessage(event, data)
+		} else {
+			data = append(data, '\n')
+		}
+
+		if len(data) > u.config.MaxDatagramBytes {
+			rejected = append(rejected, i)
+			continue
+		}
+		if u.config.Syslog || len(current)+len(data) > u.config.MaxDatagramBytes {
+			if len(current) > 0 {
+				datagrams = append(datagrams, current)
+			}
+			current = nil
+		}
+		current = append(current, data...)
+	}
+	if len(current) > 0 {
+		datagrams = append(datagrams, current)
+	}
+	return datagrams, rejected, nil
+}
+
+// syslogMessage wraps an encoded event in an RFC 5424 header.
+func (u *UDPAdapter) syslogMessage(event Event, data []byte) []byte {
+	timestamp := "-"
+	if event.IssuedAt > 0 {
+		timestamp = time.UnixMilli(event.IssuedAt).UTC().Format("2006-01-02T15:04:05.000Z07:00")
+	}
+	header := fmt.Sprintf("<%d>1 %s %s %s %d - - ", syslogPriority, timestamp, u.hostname, u.config.AppName, os.Getpid())
+	return append([]byte(header), data...)
+}
+
+// Close closes the adapter's sockets.
+func (u *UDPAdapter) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var firstErr error
+	for endpoint, conn := range u.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(u.conns, endpoint)
+	}
+	return firstErr
+}
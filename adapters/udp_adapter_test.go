@@ -0,0 +1,120 @@
+package adapters
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP opens a local UDP socket and returns it with its address.
+func listenUDP(t *testing.T) (net.PacketConn, string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, conn.LocalAddr().String()
+}
+
+// readDatagrams reads n datagrams from conn.
+func readDatagrams(t *testing.T, conn net.PacketConn, n int) []string {
+	t.Helper()
+	var datagrams []string
+	buf := make([]byte, 64<<10)
+	for range n {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		size, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to read datagram %d: %v", len(datagrams), err)
+		}
+		datagrams = append(datagrams, string(buf[:size]))
+	}
+	return datagrams
+}
+
+func TestUDPAdapter(t *testing.T) {
+	t.Run("should pack events into NDJSON datagrams", func(t *testing.T) {
+		listener, addr := listenUDP(t)
+		adapter := NewUDPAdapter(UDPConfig{})
+		defer adapter.Close()
+
+		resp, err := adapter.Send("udp://"+addr, []Event{{Name: "a"}, {Name: "b"}}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 200 {
+			t.Fatalf("expected status 200, got %d", resp.Status)
+		}
+
+		datagram := readDatagrams(t, listener, 1)[0]
+		lines := strings.Split(strings.TrimSuffix(datagram, "\n"), "\n")
+		if len(lines) != 2 || !strings.Contains(lines[0], `"name":"a"`) || !strings.Contains(lines[1], `"name":"b"`) {
+			t.Fatalf("unexpected datagram %q", datagram)
+		}
+	})
+
+	t.Run("should split datagrams at MaxDatagramBytes and reject oversized events", func(t *testing.T) {
+		listener, addr := listenUDP(t)
+		adapter := NewUDPAdapter(UDPConfig{MaxDatagramBytes: 200})
+		defer adapter.Close()
+
+		events := []Event{
+			{Name: "a"},
+			{Name: "huge", Payload: map[string]any{"data": strings.Repeat("x", 300)}},
+			{Name: "b"},
+			{Name: "c"},
+		}
+		resp, err := adapter.Send(addr, events, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 207 || len(resp.RejectedEvents) != 1 || resp.RejectedEvents[0] != 1 {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+
+		total := 0
+		for _, datagram := range readDatagrams(t, listener, 2) {
+			if len(datagram) > 200 {
+				t.Fatalf("datagram of %d bytes exceeds the limit", len(datagram))
+			}
+			total += bytes.Count([]byte(datagram), []byte("\n"))
+		}
+		if total != 3 {
+			t.Fatalf("expected 3 events across datagrams, got %d", total)
+		}
+	})
+
+	t.Run("should send one RFC 5424 message per event in syslog mode", func(t *testing.T) {
+		listener, addr := listenUDP(t)
+		adapter := NewUDPAdapter(UDPConfig{Syslog: true, AppName: "checkout"})
+		defer adapter.Close()
+
+		issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli()
+		if _, err := adapter.Send(addr, []Event{{Name: "a", IssuedAt: issuedAt}, {Name: "b"}}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		datagrams := readDatagrams(t, listener, 2)
+		if !strings.HasPrefix(datagrams[0], "<134>1 2024-01-02T03:04:05.000Z ") {
+			t.Fatalf("unexpected syslog header %q", datagrams[0])
+		}
+		if !strings.Contains(datagrams[0], " checkout ") || !strings.HasSuffix(datagrams[0], "}") {
+			t.Fatalf("unexpected syslog message %q", datagrams[0])
+		}
+		if !strings.HasPrefix(datagrams[1], "<134>1 - ") {
+			t.Fatalf("expected nil timestamp without IssuedAt, got %q", datagrams[1])
+		}
+	})
+
+	t.Run("should return dial errors", func(t *testing.T) {
+		adapter := NewUDPAdapter(UDPConfig{})
+		defer adapter.Close()
+
+		if _, err := adapter.Send("not an address", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected dial error")
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// webhookEventPlaceholder is replaced with the event name in webhook URLs.
+const webhookEventPlaceholder = "{event}"
+
+// WebhookConfig configures a WebhookAdapter.
+type WebhookConfig struct {
+	// Routes maps event names to the webhook URLs their events are POSTed
+	// to. The "*" route receives events without a route of their own, and
+	// "{event}" in a URL is replaced with the path-escaped event name, e.g.
+	// "https://hooks.example.com/ripple/{event}". Events without a route are
+	// skipped.
+	Routes map[string]string
+
+	// Headers are added to every webhook request, e.g. a shared secret.
+	// The client's headers, including its API key, are not forwarded.
+	//
+	// Optional.
+	Headers map[string]string
+
+	// Client sends the webhook requests.
+	//
+	// Default: &http.Client{}.
+	Client *http.Client
+}
+
+// WebhookAdapter is an HTTPAdapter that delivers events to user-defined
+// webhooks instead of an ingestion API, to trigger automation from specific
+// events. Each routed event is POSTed on its own as a JSON object, in batch
+// order. The client's Endpoint is not used.
+//
+// A webhook answering 2xx accepts its event. A 4xx other than 408 or 429
+// rejects it, and it is reported in HTTPResponse.RejectedEvents and dropped.
+// Any other answer or a network error fails the batch so the client retries
+// it; events accepted or rejected in earlier attempts of the same Batch.ID
+// are not sent again.
+type WebhookAdapter struct {
+	routes   map[string]string
+	headers  map[string]string
+	client   *http.Client
+	progress batchProgress
+}
+
+// Ensure WebhookAdapter implements HTTPAdapter, BatchSender and EventRetainer
+var (
+	_ HTTPAdapter   = (*WebhookAdapter)(nil)
+	_ BatchSender   = (*WebhookAdapter)(nil)
+	_ EventRetainer = (*WebhookAdapter)(nil)
+)
+
+// NewWebhookAdapter creates a webhook adapter. It returns an error if there
+// are no routes or a route is not an absolute http or https URL.
+func NewWebhookAdapter(config WebhookConfig) (*WebhookAdapter, error) {
+	if len(config.Routes) == 0 {
+		return nil, errors.New("webhook adapter requires at least one route")
+	}
+	for name, route := range config.Routes {
+		u, err := url.Parse(strings.ReplaceAll(route, webhookEventPlaceholder, "event"))
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("webhook route for %q must be an absolute http or https URL", name)
+		}
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &WebhookAdapter{
+		routes:  config.Routes,
+		headers: config.Headers,
+		client:  client,
+	}, nil
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it delivers the events before returning.
+func (w *WebhookAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send delivers events to their webhooks without context.
+func (w *WebhookAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return w.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext delivers events to their webhooks. Without a batch ID
+// there is no retry state, so every call sends all routed events.
+func (w *WebhookAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return w.SendBatch(ctx, Batch{Endpoint: endpoint, Events: events, Headers: headers})
+}
+
+// SendBatch delivers the pending events of batch to their webhooks. It
+// returns the first failure, or status 200, or 207 with the rejected events.
+func (w *WebhookAdapter) SendBatch(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	states := w.progress.load(batch.ID, len(batch.Events))
+
+	var failure *HTTPResponse
+	var failureErr error
+	for i := range batch.Events {
+		if states[i] != itemPending {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			failureErr = err
+			break
+		}
+		state, resp, err := w.deliver(ctx, &batch.Events[i])
+		states[i] = state
+		if state == itemPending && failure == nil && failureErr == nil {
+			failure, failureErr = resp, err
+		}
+	}
+
+	if failure != nil || failureErr != nil {
+		w.progress.save(batch.ID, states)
+		return failure, failureErr
+	}
+	w.progress.drop(batch.ID)
+
+	var rejected []int
+	for i, state := range states {
+		if state == itemRejected {
+			rejected = append(rejected, i)
+		}
+	}
+	if len(rejected) > 0 {
+		return &HTTPResponse{Status: http.StatusMultiStatus, RejectedEvents: rejected}, nil
+	}
+	return &HTTPResponse{Status: http.StatusOK}, nil
+}
+
+// deliver POSTs event to its webhook. Events without a route are accepted.
+// A pending state comes with the response or error to report.
+func (w *WebhookAdapter) deliver(ctx context.Context, event *Event) (itemState, *HTTPResponse, error) {
+	target, ok := w.route(event.Name)
+	if !ok {
+		return itemAccepted, nil, nil
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		// Retrying cannot fix an unserializable event.
+		return itemRejected, nil, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return itemRejected, nil, nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return itemPending, nil, fmt.Errorf("webhook for %q failed: %w", event.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	status := resp.StatusCode
+	switch {
+	case status >= 200 && status < 300:
+		return itemAccepted, nil, nil
+	case status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+		return itemRejected, nil, nil
+	default:
+		return itemPending, &HTTPResponse{
+			Status:  status,
+			Data:    decodeResponseBody(resp.Body),
+			Backoff: parseBackoff(resp.Header.Get(BackoffHeader)),
+		}, nil
+	}
+}
+
+// route returns the webhook URL for an event name.
+func (w *WebhookAdapter) route(name string) (string, bool) {
+	target, ok := w.routes[name]
+	if !ok {
+		target, ok = w.routes["*"]
+	}
+	if !ok {
+		return "", false
+	}
+	return strings.ReplaceAll(target, webhookEventPlaceholder, url.PathEscape(name)), true
+}
@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// webhookServer records the paths of webhook requests and answers each path
+// with the next of its scripted statuses, or 200.
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	paths    []string
+	statuses map[string][]int
+}
+
+func newWebhookServer(t *testing.T, statuses map[string][]int) *webhookServer {
+	t.Helper()
+	s := &webhookServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil || r.Header.Get("X-Secret") != "s3cret" {
+			t.Errorf("unexpected request: %v, %v", err, r.Header)
+		}
+		s.mu.Lock()
+		s.paths = append(s.paths, r.URL.Path)
+		status := http.StatusOK
+		if queue := s.statuses[r.URL.Path]; len(queue) > 0 {
+			status, s.statuses[r.URL.Path] = queue[0], queue[1:]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.paths)
+}
+
+func newTestWebhookAdapter(t *testing.T, server *webhookServer, routes map[string]string) *WebhookAdapter {
+	t.Helper()
+	for name, route := range routes {
+		routes[name] = server.URL + route
+	}
+	adapter, err := NewWebhookAdapter(WebhookConfig{Routes: routes, Headers: map[string]string{"X-Secret": "s3cret"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return adapter
+}
+
+func TestWebhookAdapter(t *testing.T) {
+	t.Run("should post routed events to their webhooks", func(t *testing.T) {
+		server := newWebhookServer(t, nil)
+		adapter := newTestWebhookAdapter(t, server, map[string]string{
+			"signup": "/crm",
+			"*":      "/hooks/{event}",
+		})
+
+		events := []Event{{Name: "signup"}, {Name: "order placed"}}
+		resp, err := adapter.Send("unused", events, map[string]string{"X-API-Key": "secret"})
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("unexpected result: %v, %v", resp, err)
+		}
+		if got := server.requests(); !slices.Equal(got, []string{"/crm", "/hooks/order placed"}) {
+			t.Fatalf("unexpected requests: %v", got)
+		}
+	})
+
+	t.Run("should skip events without a route", func(t *testing.T) {
+		server := newWebhookServer(t, nil)
+		adapter := newTestWebhookAdapter(t, server, map[string]string{"signup": "/crm"})
+
+		resp, err := adapter.Send("unused", []Event{{Name: "page_view"}}, nil)
+		if err != nil || resp.Status != 200 || len(server.requests()) != 0 {
+			t.Fatalf("unexpected result: %v, %v, %v", resp, err, server.requests())
+		}
+	})
+
+	t.Run("should retry only events not yet delivered", func(t *testing.T) {
+		server := newWebhookServer(t, map[string][]int{"/b": {503}})
+		adapter := newTestWebhookAdapter(t, server, map[string]string{"*": "/{event}"})
+		batch := Batch{ID: "b-1", Events: []Event{{Name: "a"}, {Name: "b"}}}
+
+		if resp, _ := adapter.SendBatch(context.Background(), batch); resp == nil || resp.Status != 503 {
+			t.Fatalf("expected the failing webhook's response, got %v", resp)
+		}
+		if resp, err := adapter.SendBatch(context.Background(), batch); err != nil || resp.Status != 200 {
+			t.Fatalf("expected the retry to succeed, got %v, %v", resp, err)
+		}
+		if got := server.requests(); !slices.Equal(got, []string{"/a", "/b", "/b"}) {
+			t.Fatalf("unexpected requests: %v", got)
+		}
+		if adapter.progress.len() != 0 {
+			t.Fatal("expected the retry state to be dropped once delivered")
+		}
+	})
+
+	t.Run("should report events rejected with a 4xx", func(t *testing.T) {
+		server := newWebhookServer(t, map[string][]int{"/b": {400}, "/c": {429}})
+		adapter := newTestWebhookAdapter(t, server, map[string]string{"*": "/{event}"})
+		batch := Batch{ID: "b-2", Events: []Event{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+
+		if resp, _ := adapter.SendBatch(context.Background(), batch); resp.Status != 429 {
+			t.Fatalf("expected 429 to be retried, got %v", resp)
+		}
+		resp, err := adapter.SendBatch(context.Background(), batch)
+		if err != nil || resp.Status != 207 || !slices.Equal(resp.RejectedEvents, []int{1}) {
+			t.Fatalf("expected event 1 to be rejected, got %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("should return network errors", func(t *testing.T) {
+		adapter, _ := NewWebhookAdapter(WebhookConfig{Routes: map[string]string{"*": "http://127.0.0.1:0/hook"}})
+		if _, err := adapter.Send("unused", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("should reject invalid routes", func(t *testing.T) {
+		for _, routes := range []map[string]string{nil, {"a": "/relative"}, {"a": "ftp://host/{event}"}} {
+			if _, err := NewWebhookAdapter(WebhookConfig{Routes: routes}); err == nil {
+				t.Fatalf("expected an error for %v", routes)
+			}
+		}
+	})
+}
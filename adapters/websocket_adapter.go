@@ -0,0 +1,297 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// webSocketTextMessage is the WebSocket text frame type, as defined by
+// RFC 6455 and github.com/gorilla/websocket.
+const webSocketTextMessage = 1
+
+// errWebSocketClosed is returned for sends on a closed WebSocketAdapter.
+var errWebSocketClosed = errors.New("websocket adapter closed")
+
+// WebSocketConn is the subset of a WebSocket connection used by
+// WebSocketAdapter. A *websocket.Conn from github.com/gorilla/websocket
+// satisfies it directly.
+type WebSocketConn interface {
+	WriteMessage(messageType int, data []byte) error
+	ReadMessage() (messageType int, data []byte, err error)
+	Close() error
+}
+
+// WebSocketDialFunc opens a WebSocket connection to endpoint, sending headers
+// (including the API key) with the handshake. Wrap a dialer, e.g.:
+//
+//	func(ctx context.Context, endpoint string, headers map[string]string) (adapters.WebSocketConn, error) {
+//		h := http.Header{}
+//		for k, v := range headers {
+//			h.Set(k, v)
+//		}
+//		conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, h)
+//		return conn, err
+//	}
+type WebSocketDialFunc func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error)
+
+// webSocketBatch is the message a batch is sent as.
+type webSocketBatch struct {
+	BatchID string  `json:"batchId"`
+	Events  []Event `json:"events"`
+}
+
+// webSocketAck is the message the server answers a batch with. Status
+// defaults to 200 when omitted.
+type webSocketAck struct {
+	BatchID        string  `json:"batchId"`
+	Status         int     `json:"status"`
+	RejectedEvents []int   `json:"rejectedEvents"`
+	Error          string  `json:"error"`
+	BackoffSeconds float64 `json:"backoffSeconds"`
+}
+
+// webSocketResult is the outcome of a batch waiting for its ack.
+type webSocketResult struct {
+	resp *HTTPResponse
+	err  error
+}
+
+// WebSocketAdapter is an HTTPAdapter that sends batches over a long-lived
+// WebSocket connection instead of one HTTP request per batch. Each batch is a
+// text message {"batchId": "...", "events": [...]}, and the server answers
+// asynchronously with {"batchId": "...", "status": 200}, optionally with
+// "rejectedEvents", "error" and "backoffSeconds". Acks may arrive in any
+// order, so concurrent sends share the connection.
+//
+// The connection is dialled on the first send and re-dialled on the next send
+// after it drops. Batches waiting for an ack when it drops fail with an
+// error, so the dispatcher retries them like network errors and, once out of
+// retries, re-queues and persists them for delivery after reconnecting.
+type WebSocketAdapter struct {
+	dial    WebSocketDialFunc
+	nextID  atomic.Uint64
+	mu      sync.Mutex
+	session *webSocketSession
+	closed  bool
+}
+
+// Ensure WebSocketAdapter implements HTTPAdapter, BatchSender and EventRetainer
+var (
+	_ HTTPAdapter   = (*WebSocketAdapter)(nil)
+	_ BatchSender   = (*WebSocketAdapter)(nil)
+	_ EventRetainer = (*WebSocketAdapter)(nil)
+)
+
+// NewWebSocketAdapter creates an adapter sending batches over connections
+// opened with dial. The endpoint passed to Send is the WebSocket URL, e.g.
+// "wss://ingest.example.com/events". It returns an error if dial is nil.
+func NewWebSocketAdapter(dial WebSocketDialFunc) (*WebSocketAdapter, error) {
+	if dial == nil {
+		return nil, errors.New("websocket adapter requires a dial function")
+	}
+	return &WebSocketAdapter{dial: dial}, nil
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it marshals the events before sending.
+func (a *WebSocketAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send sends events and waits for the server's ack.
+func (a *WebSocketAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendBatch sends a batch under its batch ID and waits for the server's ack.
+func (a *WebSocketAdapter) SendBatch(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	return a.send(ctx, batch.Endpoint, batch.ID, batch.Events, batch.Headers)
+}
+
+// SendWithContext sends events under a generated batch ID and waits for the
+// server's ack or for ctx to be done.
+func (a *WebSocketAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.send(ctx, endpoint, "", events, headers)
+}
+
+func (a *WebSocketAdapter) send(ctx context.Context, endpoint, batchID string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	if batchID == "" {
+		batchID = strconv.FormatUint(a.nextID.Add(1), 10)
+	}
+	data, err := json.Marshal(webSocketBatch{BatchID: batchID, Events: events})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	session, err := a.connect(ctx, endpoint, headers)
+	if err != nil {
+		return nil, err
+	}
+	result, err := session.expect(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.write(data); err != nil {
+		session.fail(err)
+		return nil, fmt.Errorf("failed to send batch: %w", err)
+	}
+
+	select {
+	case res := <-result:
+		return res.resp, res.err
+	case <-ctx.Done():
+		session.forget(batchID)
+		return nil, ctx.Err()
+	}
+}
+
+// connect returns the open session for endpoint, dialling a new one if there
+// is none or the previous one dropped.
+func (a *WebSocketAdapter) connect(ctx context.Context, endpoint string, headers map[string]string) (*webSocketSession, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return nil, errWebSocketClosed
+	}
+	if a.session != nil {
+		if a.session.endpoint == endpoint && a.session.alive() {
+			return a.session, nil
+		}
+		a.session.fail(errors.New("websocket endpoint changed"))
+	}
+
+	conn, err := a.dial(ctx, endpoint, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect websocket: %w", err)
+	}
+	a.session = newWebSocketSession(endpoint, conn)
+	return a.session, nil
+}
+
+// Close closes the connection. Batches waiting for an ack fail, and later
+// sends return an error.
+func (a *WebSocketAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	if a.session != nil {
+		a.session.fail(errWebSocketClosed)
+		a.session = nil
+	}
+	return nil
+}
+
+// webSocketSession is one WebSocket connection and the batches waiting for
+// an ack on it.
+type webSocketSession struct {
+	endpoint string
+	conn     WebSocketConn
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	pending  map[string]chan webSocketResult
+	err      error
+}
+
+// newWebSocketSession wraps conn and starts reading acks from it.
+func newWebSocketSession(endpoint string, conn WebSocketConn) *webSocketSession {
+	s := &webSocketSession{
+		endpoint: endpoint,
+		conn:     conn,
+		pending:  make(map[string]chan webSocketResult),
+	}
+	go s.readAcks()
+	return s
+}
+
+// alive reports whether the connection is still usable.
+func (s *webSocketSession) alive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err == nil
+}
+
+// expect registers a batch waiting for an ack.
+func (s *webSocketSession) expect(batchID string) (<-chan webSocketResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, fmt.Errorf("websocket connection lost: %w", s.err)
+	}
+	result := make(chan webSocketResult, 1)
+	s.pending[batchID] = result
+	return result, nil
+}
+
+// forget stops waiting for a batch's ack.
+func (s *webSocketSession) forget(batchID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, batchID)
+}
+
+// write sends a message. The connection supports one writer at a time.
+func (s *webSocketSession) write(data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteMessage(webSocketTextMessage, data)
+}
+
+// readAcks delivers acks to waiting batches until the connection fails.
+// Messages that are not acks are ignored.
+func (s *webSocketSession) readAcks() {
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+
+		var ack webSocketAck
+		if err := json.Unmarshal(data, &ack); err != nil || ack.BatchID == "" {
+			continue
+		}
+		if ack.Status == 0 {
+			ack.Status = 200
+		}
+		resp := &HTTPResponse{Status: ack.Status, RejectedEvents: ack.RejectedEvents}
+		if ack.BackoffSeconds > 0 {
+			resp.Backoff = time.Duration(ack.BackoffSeconds * float64(time.Second))
+		}
+		if ack.Error != "" {
+			resp.Data = map[string]any{"error": ack.Error}
+		}
+
+		s.mu.Lock()
+		result, ok := s.pending[ack.BatchID]
+		delete(s.pending, ack.BatchID)
+		s.mu.Unlock()
+		if ok {
+			result <- webSocketResult{resp: resp}
+		}
+	}
+}
+
+// fail closes the connection and fails every batch waiting for an ack.
+func (s *webSocketSession) fail(err error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.err = err
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	_ = s.conn.Close()
+	for _, result := range pending {
+		result <- webSocketResult{err: fmt.Errorf("websocket connection lost: %w", err)}
+	}
+}
@@ -0,0 +1,196 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWebSocketConn answers each batch by calling respond, which returns the
+// ack to send back or nil to send none.
+type fakeWebSocketConn struct {
+	respond func(batch webSocketBatch) *webSocketAck
+	acks    chan []byte
+	closed  chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	batches []webSocketBatch
+}
+
+func newFakeWebSocketConn(respond func(batch webSocketBatch) *webSocketAck) *fakeWebSocketConn {
+	return &fakeWebSocketConn{
+		respond: respond,
+		acks:    make(chan []byte, 16),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (c *fakeWebSocketConn) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-c.closed:
+		return errors.New("closed")
+	default:
+	}
+	var batch webSocketBatch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.batches = append(c.batches, batch)
+	c.mu.Unlock()
+	if ack := c.respond(batch); ack != nil {
+		data, _ := json.Marshal(ack)
+		c.acks <- data
+	}
+	return nil
+}
+
+func (c *fakeWebSocketConn) ReadMessage() (int, []byte, error) {
+	select {
+	case data := <-c.acks:
+		return webSocketTextMessage, data, nil
+	case <-c.closed:
+		return 0, nil, errors.New("closed")
+	}
+}
+
+func (c *fakeWebSocketConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func ackAll(status int) func(batch webSocketBatch) *webSocketAck {
+	return func(batch webSocketBatch) *webSocketAck {
+		return &webSocketAck{BatchID: batch.BatchID, Status: status}
+	}
+}
+
+func TestWebSocketAdapter(t *testing.T) {
+	t.Run("should send batches and return their acks", func(t *testing.T) {
+		conn := newFakeWebSocketConn(ackAll(0))
+		var dialHeaders map[string]string
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			dialHeaders = headers
+			return conn, nil
+		})
+		defer adapter.Close()
+
+		resp, err := adapter.SendBatch(context.Background(), Batch{
+			ID:       "batch-1",
+			Endpoint: "wss://test",
+			Events:   []Event{{Name: "a"}},
+			Headers:  map[string]string{"X-API-Key": "k"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 200 {
+			t.Fatalf("expected status 200, got %d", resp.Status)
+		}
+		if dialHeaders["X-API-Key"] != "k" {
+			t.Fatalf("expected headers on dial, got %v", dialHeaders)
+		}
+		if len(conn.batches) != 1 || conn.batches[0].BatchID != "batch-1" || conn.batches[0].Events[0].Name != "a" {
+			t.Fatalf("unexpected batches %+v", conn.batches)
+		}
+	})
+
+	t.Run("should reuse the connection", func(t *testing.T) {
+		dials := 0
+		conn := newFakeWebSocketConn(ackAll(200))
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			dials++
+			return conn, nil
+		})
+		defer adapter.Close()
+
+		for i := 0; i < 3; i++ {
+			if _, err := adapter.Send("wss://test", []Event{{Name: "a"}}, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if dials != 1 {
+			t.Fatalf("expected 1 dial, got %d", dials)
+		}
+	})
+
+	t.Run("should return ack statuses and rejected events", func(t *testing.T) {
+		conn := newFakeWebSocketConn(func(batch webSocketBatch) *webSocketAck {
+			return &webSocketAck{BatchID: batch.BatchID, Status: 207, RejectedEvents: []int{1}}
+		})
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			return conn, nil
+		})
+		defer adapter.Close()
+
+		resp, err := adapter.Send("wss://test", []Event{{Name: "a"}, {Name: "b"}}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 207 || len(resp.RejectedEvents) != 1 || resp.RejectedEvents[0] != 1 {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+	})
+
+	t.Run("should fail pending batches and reconnect after the connection drops", func(t *testing.T) {
+		first := newFakeWebSocketConn(func(batch webSocketBatch) *webSocketAck { return nil })
+		second := newFakeWebSocketConn(ackAll(200))
+		conns := []*fakeWebSocketConn{first, second}
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			conn := conns[0]
+			conns = conns[1:]
+			return conn, nil
+		})
+		defer adapter.Close()
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			first.Close()
+		}()
+		if _, err := adapter.Send("wss://test", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected error when the connection drops")
+		}
+
+		resp, err := adapter.Send("wss://test", []Event{{Name: "a"}}, nil)
+		if err != nil || resp.Status != 200 {
+			t.Fatalf("expected send after reconnect to succeed, got %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		conn := newFakeWebSocketConn(func(batch webSocketBatch) *webSocketAck { return nil })
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			return conn, nil
+		})
+		defer adapter.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := adapter.SendWithContext(ctx, "wss://test", []Event{{Name: "a"}}, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("should return dial errors and fail after close", func(t *testing.T) {
+		adapter, _ := NewWebSocketAdapter(func(ctx context.Context, endpoint string, headers map[string]string) (WebSocketConn, error) {
+			return nil, errors.New("refused")
+		})
+		if _, err := adapter.Send("wss://test", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected dial error")
+		}
+
+		adapter.Close()
+		if _, err := adapter.Send("wss://test", []Event{{Name: "a"}}, nil); !errors.Is(err, errWebSocketClosed) {
+			t.Fatalf("expected closed error, got %v", err)
+		}
+	})
+
+	t.Run("should require a dial function", func(t *testing.T) {
+		if _, err := NewWebSocketAdapter(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterHTTPAdapter is an HTTPAdapter for local development that "sends"
+// batches by writing them to an io.Writer as NDJSON, one event per line,
+// instead of making HTTP requests. Every batch succeeds with status 200.
+// Headers are not written, so API keys never end up in the output.
+type WriterHTTPAdapter struct {
+	w      io.Writer
+	closer io.Closer
+	mu     sync.Mutex
+}
+
+// Ensure WriterHTTPAdapter implements HTTPAdapter and EventRetainer
+var (
+	_ HTTPAdapter   = (*WriterHTTPAdapter)(nil)
+	_ EventRetainer = (*WriterHTTPAdapter)(nil)
+)
+
+// NewWriterHTTPAdapter creates an adapter writing NDJSON to w. It returns an
+// error if w is nil.
+func NewWriterHTTPAdapter(w io.Writer) (*WriterHTTPAdapter, error) {
+	if w == nil {
+		return nil, errors.New("writer adapter requires a writer")
+	}
+	return &WriterHTTPAdapter{w: w}, nil
+}
+
+// NewStdoutHTTPAdapter creates an adapter writing NDJSON to standard output.
+func NewStdoutHTTPAdapter() *WriterHTTPAdapter {
+	return &WriterHTTPAdapter{w: os.Stdout}
+}
+
+// NewFileHTTPAdapter creates an adapter appending NDJSON to the file at path,
+// creating it if needed. Call Close to release the file.
+func NewFileHTTPAdapter(path string) (*WriterHTTPAdapter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export file: %w", err)
+	}
+	return &WriterHTTPAdapter{w: f, closer: f}, nil
+}
+
+// RetainsEvents implements EventRetainer. It returns false: it writes the events before returning.
+func (a *WriterHTTPAdapter) RetainsEvents() bool {
+	return false
+}
+
+// Send writes events as NDJSON.
+func (a *WriterHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+// SendWithContext writes events as NDJSON. A batch is written in a single
+// call, so concurrent batches never interleave.
+func (a *WriterHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return nil, fmt.Errorf("failed to marshal events: %w", err)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write events: %w", err)
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
+
+// Close closes the underlying file for adapters created with
+// NewFileHTTPAdapter. It is a no-op otherwise.
+func (a *WriterHTTPAdapter) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// OpenFiles reports the export file held open by NewFileHTTPAdapter adapters.
+func (a *WriterHTTPAdapter) OpenFiles() int {
+	if a.closer == nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,88 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriterHTTPAdapter(t *testing.T) {
+	t.Run("should write one JSON event per line", func(t *testing.T) {
+		var buf bytes.Buffer
+		adapter, _ := NewWriterHTTPAdapter(&buf)
+
+		resp, err := adapter.Send("http://unused", []Event{{Name: "a"}, {Name: "b"}}, map[string]string{"X-API-Key": "secret"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Status != 200 {
+			t.Fatalf("expected status 200, got %d", resp.Status)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %q", buf.String())
+		}
+		for i, name := range []string{"a", "b"} {
+			var event Event
+			if err := json.Unmarshal([]byte(lines[i]), &event); err != nil || event.Name != name {
+				t.Fatalf("line %d: expected event %q, got %q (%v)", i, name, lines[i], err)
+			}
+		}
+		if strings.Contains(buf.String(), "secret") {
+			t.Fatal("headers must not be written")
+		}
+	})
+
+	t.Run("should fail for a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		adapter, _ := NewWriterHTTPAdapter(&bytes.Buffer{})
+		if _, err := adapter.SendWithContext(ctx, "", []Event{{Name: "a"}}, nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should require a writer", func(t *testing.T) {
+		if _, err := NewWriterHTTPAdapter(nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestFileHTTPAdapter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	adapter, err := NewFileHTTPAdapter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adapter.OpenFiles() != 1 {
+		t.Fatalf("expected 1 open file, got %d", adapter.OpenFiles())
+	}
+
+	adapter.Send("", []Event{{Name: "a"}}, nil)
+	adapter.Send("", []Event{{Name: "b"}}, nil)
+	if err := adapter.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 {
+		t.Fatalf("expected batches to be appended, got %q", data)
+	}
+}
+
+func TestStdoutHTTPAdapter(t *testing.T) {
+	adapter := NewStdoutHTTPAdapter()
+	if adapter.OpenFiles() != 0 || adapter.Close() != nil {
+		t.Fatal("stdout adapter should not own a file")
+	}
+}
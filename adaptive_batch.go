@@ -0,0 +1,127 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAdaptiveMaxFactor     = 10
+	defaultAdaptiveTargetLatency = time.Second
+)
+
+// AdaptiveBatching tunes the batch size to the endpoint. Starting from
+// MaxBatchSize, the size doubles after each full batch delivered within
+// TargetLatency and halves after a timed-out attempt or a 413 response.
+type AdaptiveBatching struct {
+	// MinBatchSize is the smallest batch size the dispatcher shrinks to.
+	//
+	// Default: 1.
+	MinBatchSize int
+
+	// MaxBatchSize is the largest batch size the dispatcher grows to.
+	//
+	// Default: 10 times ClientConfig.MaxBatchSize.
+	MaxBatchSize int
+
+	// TargetLatency is the delivery time under which a full batch counts as
+	// fast enough to grow the batch size.
+	//
+	// Default: 1 second.
+	TargetLatency time.Duration
+}
+
+// batchSizer holds the current adaptive batch size.
+type batchSizer struct {
+	mu     sync.Mutex
+	size   int
+	min    int
+	max    int
+	target time.Duration
+}
+
+// newBatchSizer creates a sizer starting at initial, clamped to the bounds.
+func newBatchSizer(config AdaptiveBatching, initial int) *batchSizer {
+	s := &batchSizer{
+		min:    config.MinBatchSize,
+		max:    config.MaxBatchSize,
+		target: config.TargetLatency,
+	}
+	if s.min <= 0 {
+		s.min = 1
+	}
+	if s.max <= 0 {
+		s.max = initial * defaultAdaptiveMaxFactor
+	}
+	if s.max < s.min {
+		s.max = s.min
+	}
+	if s.target <= 0 {
+		s.target = defaultAdaptiveTargetLatency
+	}
+	s.size = min(max(initial, s.min), s.max)
+	return s
+}
+
+// current returns the batch size to use for the next flush.
+func (s *batchSizer) current() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// reset sets the batch size, clamped to the bounds.
+func (s *batchSizer) reset(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.size = min(max(size, s.min), s.max)
+}
+
+// observe adjusts the batch size after a delivery attempt of a batch with n
+// events. Returns the new size and whether it changed.
+func (s *batchSizer) observe(n int, latency time.Duration, resp *HTTPResponse, err error) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := s.size
+	switch {
+	case errors.Is(err, context.DeadlineExceeded),
+		err == nil && resp.Status == http.StatusRequestEntityTooLarge:
+		size = max(s.size/2, s.min)
+	case err == nil && resp.Status >= 200 && resp.Status < 300 && n >= s.size && latency < s.target:
+		size = min(s.size*2, s.max)
+	}
+	changed := size != s.size
+	s.size = size
+	return size, changed
+}
+
+// batchSize returns the maximum number of events per batch.
+func (d *Dispatcher) batchSize() int {
+	if d.sizer != nil {
+		return d.sizer.current()
+	}
+	return int(d.maxBatchSize.Load())
+}
+
+// adaptBatchSize feeds a delivery attempt into adaptive batching.
+func (d *Dispatcher) adaptBatchSize(n int, latency time.Duration, resp *HTTPResponse, err error) {
+	if d.sizer == nil {
+		return
+	}
+	if size, changed := d.sizer.observe(n, latency, resp, err); changed {
+		d.loggerAdapter.Debug("Adjusted batch size", map[string]any{
+			"batchSize": size,
+			"latency":   latency.String(),
+		})
+	}
+}
+
+// tooLargeForBatch reports whether a 413 response should re-queue the batch to
+// be resent in smaller batches rather than drop it.
+func (d *Dispatcher) tooLargeForBatch(status int, events []Event) bool {
+	return d.sizer != nil && status == http.StatusRequestEntityTooLarge && len(events) > d.sizer.current()
+}
@@ -0,0 +1,104 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestBatchSizer(t *testing.T) {
+	ok := &HTTPResponse{Status: 200}
+
+	t.Run("should apply defaults around the initial size", func(t *testing.T) {
+		s := newBatchSizer(AdaptiveBatching{}, 10)
+		if s.current() != 10 || s.min != 1 || s.max != 100 || s.target != time.Second {
+			t.Fatalf("unexpected defaults: size=%d min=%d max=%d target=%v", s.current(), s.min, s.max, s.target)
+		}
+		if s := newBatchSizer(AdaptiveBatching{MinBatchSize: 20, MaxBatchSize: 50}, 10); s.current() != 20 {
+			t.Fatalf("expected the initial size clamped to the minimum, got %d", s.current())
+		}
+	})
+
+	t.Run("should grow after fast full batches up to the maximum", func(t *testing.T) {
+		s := newBatchSizer(AdaptiveBatching{MaxBatchSize: 30, TargetLatency: 100 * time.Millisecond}, 10)
+		if size, changed := s.observe(10, 10*time.Millisecond, ok, nil); !changed || size != 20 {
+			t.Fatalf("expected growth to 20, got %d", size)
+		}
+		if size, _ := s.observe(20, 10*time.Millisecond, ok, nil); size != 30 {
+			t.Fatalf("expected growth capped at 30, got %d", size)
+		}
+	})
+
+	t.Run("should not grow after slow or partial batches", func(t *testing.T) {
+		s := newBatchSizer(AdaptiveBatching{TargetLatency: 100 * time.Millisecond}, 10)
+		if _, changed := s.observe(10, 200*time.Millisecond, ok, nil); changed {
+			t.Fatal("expected no growth after a slow batch")
+		}
+		if _, changed := s.observe(3, 10*time.Millisecond, ok, nil); changed {
+			t.Fatal("expected no growth after a partial batch")
+		}
+		if _, changed := s.observe(10, 10*time.Millisecond, &HTTPResponse{Status: 500}, nil); changed {
+			t.Fatal("expected no change after a server error")
+		}
+	})
+
+	t.Run("should shrink after timeouts and 413 responses down to the minimum", func(t *testing.T) {
+		s := newBatchSizer(AdaptiveBatching{MinBatchSize: 3}, 10)
+		if size, _ := s.observe(10, time.Second, nil, context.DeadlineExceeded); size != 5 {
+			t.Fatalf("expected shrink to 5 after a timeout, got %d", size)
+		}
+		if size, _ := s.observe(5, time.Second, &HTTPResponse{Status: 413}, nil); size != 3 {
+			t.Fatalf("expected shrink floored at 3 after a 413, got %d", size)
+		}
+		if _, changed := s.observe(3, time.Second, nil, errors.New("connection refused")); changed {
+			t.Fatal("expected other network errors to leave the size alone")
+		}
+	})
+}
+
+func TestDispatcher_AdaptiveBatching(t *testing.T) {
+	t.Run("should resend a rejected oversized batch in smaller batches", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 413},
+			adapters.Scenario{Status: 200},
+		)
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:           "test-key",
+			Endpoint:         "http://test.com",
+			FlushInterval:    10 * time.Second,
+			MaxBatchSize:     4,
+			AdaptiveBatching: &AdaptiveBatching{},
+		}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+		defer d.Dispose()
+
+		for _, name := range []string{"a", "b", "c", "d"} {
+			d.queue.Enqueue(Event{Name: name})
+		}
+		d.Flush()
+		if d.queue.Len() != 4 {
+			t.Fatalf("expected the rejected batch to be re-queued, got %d queued", d.queue.Len())
+		}
+		if stats := d.Stats(); stats.BatchSize != 2 {
+			t.Fatalf("expected the batch size to halve, got %d", stats.BatchSize)
+		}
+
+		d.Flush()
+		requests := httpAdapter.Requests()
+		if len(requests) != 3 || len(requests[1].Events) != 2 || len(requests[2].Events) != 2 {
+			t.Fatalf("expected two batches of 2 after the 413, got %+v", requests)
+		}
+		if stats := d.Stats(); stats.EventsSent != 4 || stats.BatchesFailed != 0 {
+			t.Fatalf("expected every event delivered, got %+v", stats)
+		}
+	})
+
+	t.Run("should report the configured batch size when disabled", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{})
+		if stats := d.Stats(); stats.BatchSize != d.config.MaxBatchSize {
+			t.Fatalf("expected batch size %d, got %d", d.config.MaxBatchSize, stats.BatchSize)
+		}
+	})
+}
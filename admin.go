@@ -0,0 +1,107 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AdminOp identifies an operator action supported by Client.Command.
+type AdminOp string
+
+const (
+	// AdminForceFlush sends all queued events immediately.
+	AdminForceFlush AdminOp = "force_flush"
+
+	// AdminDropQueue discards every queued and persisted event.
+	AdminDropQueue AdminOp = "drop_queue"
+
+	// AdminRotateStorage clears storage and rewrites it from the in-memory
+	// queue, discarding stale or corrupt persisted data.
+	AdminRotateStorage AdminOp = "rotate_storage"
+
+	// AdminSetSampling replaces the sampling rate and/or rules.
+	AdminSetSampling AdminOp = "set_sampling"
+
+	// AdminHoldEvents quarantines queued events named in EventNames.
+	AdminHoldEvents AdminOp = "hold_events"
+
+	// AdminReleaseEvents re-queues held events named in EventNames, or all
+	// held events if EventNames is empty.
+	AdminReleaseEvents AdminOp = "release_events"
+
+	// AdminPurgeHeld discards held events named in EventNames, or all held
+	// events if EventNames is empty.
+	AdminPurgeHeld AdminOp = "purge_held"
+)
+
+// AdminCommand is an operator instruction for a live client. It is designed
+// to be decoded from an internal admin endpoint or built by a signal handler.
+type AdminCommand struct {
+	// Op is the action to perform.
+	Op AdminOp `json:"op"`
+
+	// SamplingRate is the new global sampling rate for AdminSetSampling.
+	// Nil leaves the current rate unchanged.
+	SamplingRate *float64 `json:"samplingRate,omitempty"`
+
+	// SamplingRules replaces per-event sampling rules for AdminSetSampling.
+	// Nil leaves the current rules unchanged.
+	SamplingRules map[string]float64 `json:"samplingRules,omitempty"`
+
+	// EventNames selects events for AdminHoldEvents, AdminReleaseEvents and
+	// AdminPurgeHeld.
+	EventNames []string `json:"eventNames,omitempty"`
+}
+
+// Command applies an admin command to the client. It is safe to call
+// concurrently with Track and Flush.
+func (c *Client) Command(cmd AdminCommand) error {
+	switch cmd.Op {
+	case AdminForceFlush:
+		c.loggerAdapter.Info("Admin command: force flush")
+		c.dispatcher.Flush()
+	case AdminDropQueue:
+		dropped := c.dispatcher.DropQueue()
+		c.loggerAdapter.Info("Admin command: dropped queue", map[string]any{"eventsCount": dropped})
+	case AdminRotateStorage:
+		c.loggerAdapter.Info("Admin command: rotate storage")
+		return c.dispatcher.RotateStorage()
+	case AdminSetSampling:
+		if cmd.SamplingRate == nil && cmd.SamplingRules == nil {
+			return errors.New("set_sampling requires a sampling rate or rules")
+		}
+		if cmd.SamplingRate != nil && !validateSampleRate(*cmd.SamplingRate) {
+			return errors.New("sampling rate must be between 0 and 1")
+		}
+		for name, rate := range cmd.SamplingRules {
+			if !validateSampleRate(rate) {
+				return fmt.Errorf("sampling rate for %q must be between 0 and 1", name)
+			}
+		}
+		c.sampler.update(cmd.SamplingRate, cmd.SamplingRules)
+		c.loggerAdapter.Info("Admin command: sampling updated")
+	case AdminHoldEvents:
+		if len(cmd.EventNames) == 0 {
+			return errors.New("hold_events requires event names")
+		}
+		held := c.HoldEvents(EventNameFilter(cmd.EventNames...))
+		c.loggerAdapter.Info("Admin command: held events", map[string]any{"eventsCount": held})
+	case AdminReleaseEvents:
+		released := c.ReleaseEvents(adminEventFilter(cmd.EventNames))
+		c.loggerAdapter.Info("Admin command: released events", map[string]any{"eventsCount": released})
+	case AdminPurgeHeld:
+		purged := c.PurgeHeldEvents(adminEventFilter(cmd.EventNames))
+		c.loggerAdapter.Info("Admin command: purged held events", map[string]any{"eventsCount": purged})
+	default:
+		return fmt.Errorf("unknown admin command %q", cmd.Op)
+	}
+	return nil
+}
+
+// adminEventFilter selects events by name, or every event if names is empty.
+func adminEventFilter(names []string) EventFilter {
+	if len(names) == 0 {
+		return nil
+	}
+	return EventNameFilter(names...)
+}
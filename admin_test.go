@@ -0,0 +1,109 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestClient_Command(t *testing.T) {
+	t.Run("force flush sends queued events", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		if err := client.Command(AdminCommand{Op: AdminForceFlush}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 HTTP call, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("drop queue clears memory and storage", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+		if err := client.Command(AdminCommand{Op: AdminDropQueue}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Stats().QueueLength != 0 {
+			t.Error("expected queue to be empty")
+		}
+		if len(storage.getSaved()) != 0 {
+			t.Error("expected storage to be cleared")
+		}
+	})
+
+	t.Run("rotate storage rewrites from queue", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		config.PersistencePolicy = PersistNever()
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		if err := client.Command(AdminCommand{Op: AdminRotateStorage}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if storage.clearCalls != 1 || len(storage.getSaved()) != 1 {
+			t.Errorf("expected clear then save of 1 event, got %d clears and %d saved", storage.clearCalls, len(storage.getSaved()))
+		}
+	})
+
+	t.Run("rotate storage reports failures", func(t *testing.T) {
+		config := createTestConfig()
+		config.StorageAdapter = &mockStorageAdapter{clearErr: errors.New("clear failed")}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Command(AdminCommand{Op: AdminRotateStorage}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("set sampling from JSON", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		var cmd AdminCommand
+		if err := json.Unmarshal([]byte(`{"op":"set_sampling","samplingRules":{"noisy":0}}`), &cmd); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.Command(cmd); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		client.Track("noisy", nil, nil)
+		if client.Stats().EventsSampledOut != 1 {
+			t.Error("expected updated rules to apply")
+		}
+	})
+
+	t.Run("rejects invalid commands", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		invalidRate := 2.0
+		cases := []AdminCommand{
+			{Op: "reboot"},
+			{Op: AdminSetSampling},
+			{Op: AdminSetSampling, SamplingRate: &invalidRate},
+			{Op: AdminSetSampling, SamplingRules: map[string]float64{"x": -1}},
+		}
+		for _, cmd := range cases {
+			if err := client.Command(cmd); err == nil {
+				t.Errorf("expected error for %+v", cmd)
+			}
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errAuditStorageRequired is returned by TrackAudit when no AuditStorage is
+// configured.
+var errAuditStorageRequired = errors.New("audit events require an audit storage adapter")
+
+// auditJournal durably records audit events from before they are enqueued
+// until the backend answers their batch. Audit events are recognized by ID
+// and exempt from sampling, eviction, EventTTL and fire-and-forget delivery.
+type auditJournal struct {
+	mu      sync.Mutex
+	storage StorageAdapter
+	loaded  bool
+	events  []Event
+	ids     map[string]struct{}
+}
+
+// enabled reports whether an audit storage adapter is configured.
+func (j *auditJournal) enabled() bool {
+	return j.storage != nil
+}
+
+// load reads the journal from storage, replacing its in-memory copy.
+func (j *auditJournal) load() ([]Event, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.loadLocked(); err != nil {
+		return nil, err
+	}
+	return append([]Event(nil), j.events...), nil
+}
+
+func (j *auditJournal) loadLocked() error {
+	events, err := j.storage.Load()
+	if err != nil {
+		return err
+	}
+	j.events = events
+	j.ids = make(map[string]struct{}, len(events))
+	for _, event := range events {
+		j.ids[event.ID] = struct{}{}
+	}
+	j.loaded = true
+	return nil
+}
+
+// append durably adds event to the journal. The journal is left unchanged if
+// storage rejects the write.
+func (j *auditJournal) append(event Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.loaded {
+		// Never overwrite events journaled by a previous run.
+		if err := j.loadLocked(); err != nil {
+			return fmt.Errorf("failed to load audit events: %w", err)
+		}
+	}
+
+	next := make([]Event, 0, len(j.events)+1)
+	next = append(next, j.events...)
+	next = append(next, event)
+	if err := j.storage.Save(next); err != nil {
+		return fmt.Errorf("failed to persist audit event: %w", err)
+	}
+	j.events = next
+	j.ids[event.ID] = struct{}{}
+	return nil
+}
+
+// remove deletes answered events from the journal. If the write fails they
+// stay in storage and are sent again after a restart.
+func (j *auditJournal) remove(events []Event) error {
+	if !j.enabled() {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	removed := make(map[string]struct{})
+	for _, event := range events {
+		if _, ok := j.ids[event.ID]; ok {
+			removed[event.ID] = struct{}{}
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	next := make([]Event, 0, len(j.events))
+	for _, event := range j.events {
+		if _, ok := removed[event.ID]; !ok {
+			next = append(next, event)
+		}
+	}
+	if err := j.storage.Save(next); err != nil {
+		return err
+	}
+	j.events = next
+	for id := range removed {
+		delete(j.ids, id)
+	}
+	return nil
+}
+
+// contains reports whether event is a journaled audit event.
+func (j *auditJournal) contains(event Event) bool {
+	if !j.enabled() || event.ID == "" {
+		return false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, ok := j.ids[event.ID]
+	return ok
+}
+
+// restoreAudit queues journaled audit events that are not already queued,
+// e.g. because the regular storage was cleared or never checkpointed them.
+func (d *Dispatcher) restoreAudit() {
+	if !d.audit.enabled() {
+		return
+	}
+	events, err := d.audit.load()
+	if err != nil {
+		d.logStorageError("Failed to restore audit events", err, nil)
+		return
+	}
+	queued := make(map[string]struct{}, d.queue.Len())
+	for _, event := range d.queue.ToSlice() {
+		queued[event.ID] = struct{}{}
+	}
+	for _, event := range events {
+		if _, ok := queued[event.ID]; !ok {
+			d.queue.Enqueue(event)
+		}
+	}
+}
+
+// EnqueueAudit durably journals event to AuditStorage, enqueues it and
+// starts a flush. The returned channel is resolved like EnqueueWithAck's.
+// If the journal write fails the event is not enqueued and the error is
+// returned. The event is given an ID if it has none.
+func (d *Dispatcher) EnqueueAudit(event Event) (<-chan error, error) {
+	if !d.audit.enabled() {
+		return nil, errAuditStorageRequired
+	}
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if disposed {
+		return nil, errDisposed
+	}
+
+	if event.ID == "" {
+		event.ID = newUUID()
+	}
+	if err := d.audit.append(event); err != nil {
+		d.logStorageError("Failed to persist audit event", err, nil)
+		return nil, err
+	}
+	ack := d.acks.register(event.ID)
+	if err := d.enqueue(event); err != nil {
+		d.acks.forget(event.ID)
+		return nil, err
+	}
+	d.stopTimer()
+	d.scheduleFlushIn(0)
+	return ack, nil
+}
+
+// TrackAudit tracks a compliance-grade event and blocks until the backend
+// acknowledges it with a 2xx response. Audit events are written to
+// AuditStorage before being queued, are never sampled, evicted, expired by
+// EventTTL or sent fire-and-forget, and stay in AuditStorage until their
+// batch is answered, so a crash or Dispose before delivery resends them
+// after restart.
+//
+// It returns nil once the server has the event, an *HTTPError if the server
+// rejected it, ErrEventDiscarded if a BeforeSend hook dropped it or the
+// client was disposed first, or ctx.Err() if ctx is done first; in the last
+// two cases the event remains journaled and is still delivered.
+func (c *Client) TrackAudit(ctx context.Context, name string, payload, metadata map[string]any) error {
+	if c.disposed {
+		return errDisposed
+	}
+	event, err := c.assembleEvent(name, payload, false, metadata)
+	if event == nil {
+		if err != nil {
+			return err
+		}
+		return discarded("not tracked")
+	}
+
+	if event.ID == "" {
+		event.ID = newUUID()
+	}
+	c.loggerAdapter.Debug("Tracking audit event: %s", name)
+	ack, err := c.dispatcher.EnqueueAudit(*event)
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-ack:
+		return err
+	case <-ctx.Done():
+		c.dispatcher.acks.forget(event.ID)
+		return ctx.Err()
+	}
+}
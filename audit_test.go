@@ -0,0 +1,204 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestDispatcher_EnqueueAudit(t *testing.T) {
+	newDispatcher := func(config DispatcherConfig, httpAdapter HTTPAdapter, audit *mockStorageAdapter) *Dispatcher {
+		config.APIKey = "test-key"
+		config.Endpoint = "http://test.com"
+		config.FlushInterval = 10 * time.Second
+		config.MaxBatchSize = 10
+		config.AuditStorage = audit
+		return NewDispatcher(config, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should journal the event and remove it once acknowledged", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		audit := &mockStorageAdapter{}
+		d := newDispatcher(DispatcherConfig{}, httpAdapter, audit)
+		d.Restore()
+		defer d.Dispose()
+
+		ack, err := d.EnqueueAudit(Event{Name: "login"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := awaitAck(t, ack); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+		for deadline := time.Now().Add(time.Second); len(audit.getSaved()) != 0; {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the journal to be emptied, got %d events", len(audit.getSaved()))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if httpAdapter.Calls() != 1 {
+			t.Fatalf("expected the event to be flushed promptly, got %d calls", httpAdapter.Calls())
+		}
+	})
+
+	t.Run("should not enqueue the event if the journal write fails", func(t *testing.T) {
+		audit := &mockStorageAdapter{err: errors.New("disk full")}
+		d := newDispatcher(DispatcherConfig{}, &mockHTTPAdapter{}, audit)
+		d.Restore()
+		defer d.Dispose()
+
+		if _, err := d.EnqueueAudit(Event{Name: "login"}); err == nil {
+			t.Fatal("expected an error")
+		}
+		if d.queue.Len() != 0 {
+			t.Fatalf("expected an empty queue, got %d", d.queue.Len())
+		}
+	})
+
+	t.Run("should keep the event journaled after a failed delivery", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		audit := &mockStorageAdapter{}
+		d := newDispatcher(DispatcherConfig{}, httpAdapter, audit)
+		d.Restore()
+		defer d.Dispose()
+
+		ack, _ := d.EnqueueAudit(Event{Name: "login"})
+		d.Flush()
+
+		assertPending(t, ack)
+		if len(audit.getSaved()) != 1 {
+			t.Fatalf("expected the event to stay journaled, got %d events", len(audit.getSaved()))
+		}
+	})
+
+	t.Run("should never evict or expire audit events", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503},
+			adapters.Scenario{Status: 200},
+		)
+		d := newDispatcher(DispatcherConfig{MaxBufferSize: 2, EventTTL: time.Hour}, httpAdapter, &mockStorageAdapter{})
+		d.Restore()
+		defer d.Dispose()
+
+		stale := time.Now().Add(-2 * time.Hour).UnixMilli()
+		ack, _ := d.EnqueueAudit(Event{Name: "login", IssuedAt: stale})
+		for deadline := time.Now().Add(time.Second); httpAdapter.Calls() == 0; {
+			if time.Now().After(deadline) {
+				t.Fatal("expected the audit event to be flushed")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		for range 3 {
+			d.Enqueue(Event{Name: "view", IssuedAt: time.Now().UnixMilli()})
+		}
+
+		queued := d.queue.ToSlice()
+		if len(queued) != 2 || queued[0].Name != "login" {
+			t.Fatalf("expected the audit event to survive eviction, got %+v", queued)
+		}
+
+		d.Flush()
+		if err := awaitAck(t, ack); err != nil {
+			t.Fatalf("expected the audit event to outlive EventTTL, got %v", err)
+		}
+		if d.Stats().EventsExpired != 0 {
+			t.Fatal("expected no expired events")
+		}
+	})
+
+	t.Run("should restore journaled events that are not queued", func(t *testing.T) {
+		audit := &mockStorageAdapter{loaded: []Event{{ID: "a1", Name: "login"}}}
+		storage := &mockStorageAdapter{loaded: []Event{{ID: "a1", Name: "login"}, {ID: "e1", Name: "view"}}}
+		d := NewDispatcher(DispatcherConfig{
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			AuditStorage:  audit,
+		}, &mockHTTPAdapter{}, storage, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		if d.queue.Len() != 2 {
+			t.Fatalf("expected 2 queued events, got %d", d.queue.Len())
+		}
+
+		storage.loaded = nil
+		d.Dispose()
+		d.Restore()
+		if d.queue.Len() != 1 || d.queue.ToSlice()[0].ID != "a1" {
+			t.Fatalf("expected the audit event to be restored, got %+v", d.queue.ToSlice())
+		}
+	})
+
+	t.Run("should return an error without audit storage", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{Endpoint: "http://test.com"}, &mockHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		if _, err := d.EnqueueAudit(Event{Name: "login"}); !errors.Is(err, errAuditStorageRequired) {
+			t.Fatalf("expected errAuditStorageRequired, got %v", err)
+		}
+	})
+}
+
+func TestClient_TrackAudit(t *testing.T) {
+	t.Run("should return once the server acknowledges the event", func(t *testing.T) {
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		config.AuditStorage = &mockStorageAdapter{}
+		config.SamplingRules = map[string]float64{"login": 0}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := client.TrackAudit(ctx, "login", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Stats().EventsSampledOut != 0 {
+			t.Fatal("expected audit events to bypass sampling")
+		}
+	})
+
+	t.Run("should return the server's rejection", func(t *testing.T) {
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 400})
+		config.AuditStorage = &mockStorageAdapter{}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		var httpErr *HTTPError
+		if err := client.TrackAudit(context.Background(), "login", nil, nil); !errors.As(err, &httpErr) {
+			t.Fatalf("expected an *HTTPError, got %v", err)
+		}
+	})
+
+	t.Run("should return the context error while delivery is pending", func(t *testing.T) {
+		audit := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		config.AuditStorage = audit
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := client.TrackAudit(ctx, "login", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+		if len(audit.getSaved()) != 1 {
+			t.Fatalf("expected the event to stay journaled, got %d events", len(audit.getSaved()))
+		}
+	})
+
+	t.Run("should require audit storage", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if err := client.TrackAudit(context.Background(), "login", nil, nil); !errors.Is(err, errAuditStorageRequired) {
+			t.Fatalf("expected errAuditStorageRequired, got %v", err)
+		}
+	})
+}
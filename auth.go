@@ -0,0 +1,183 @@
+package ripple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AuthorizationHeader carries the AuthProvider's token as a bearer token.
+	AuthorizationHeader = "Authorization"
+
+	// tokenRefreshMargin is how long before expiry a cached token is
+	// refreshed, so it does not expire in flight.
+	tokenRefreshMargin = 30 * time.Second
+
+	// maxTokenResponseBytes bounds how much of a token response is read.
+	maxTokenResponseBytes = 1 << 20
+)
+
+// AuthProvider supplies the bearer token sent with each batch, for endpoints
+// authenticated with short-lived credentials such as OAuth2 access tokens.
+// Token is called before every attempt, so implementations should cache the
+// token until it nears expiry. It must be safe for concurrent use.
+type AuthProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// AuthInvalidator is implemented by AuthProviders that cache tokens. When a
+// batch is answered with 401 Unauthorized, the dispatcher calls Invalidate
+// and resends the batch once with a fresh token.
+type AuthInvalidator interface {
+	Invalidate()
+}
+
+// authorize sets the AuthProvider's token on headers.
+func (d *Dispatcher) authorize(ctx context.Context, headers map[string]string) error {
+	token, err := d.config.AuthProvider.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get auth token: %w", err)
+	}
+	headers[AuthorizationHeader] = "Bearer " + token
+	return nil
+}
+
+// reauthorize drops the cached token after a 401 and fetches a new one.
+// Returns false if the provider cannot invalidate its token, in which case
+// resending would not help.
+func (d *Dispatcher) reauthorize(ctx context.Context, headers map[string]string) (bool, error) {
+	invalidator, ok := d.config.AuthProvider.(AuthInvalidator)
+	if !ok {
+		return false, nil
+	}
+	invalidator.Invalidate()
+	d.loggerAdapter.Debug("Auth token rejected, refreshing", nil)
+	return true, d.authorize(ctx, headers)
+}
+
+// ClientCredentialsConfig configures an OAuth2 client credentials flow.
+type ClientCredentialsConfig struct {
+	// TokenURL is the authorization server's token endpoint.
+	//
+	// Required.
+	TokenURL string
+
+	// ClientID and ClientSecret authenticate the client, sent with HTTP
+	// Basic authentication.
+	//
+	// Required.
+	ClientID     string
+	ClientSecret string
+
+	// Scopes are requested with the token.
+	//
+	// Optional.
+	Scopes []string
+
+	// HTTPClient makes token requests.
+	//
+	// Default: a new http.Client.
+	HTTPClient *http.Client
+}
+
+// ClientCredentialsAuth is an AuthProvider obtaining tokens with the OAuth2
+// client credentials grant (RFC 6749, section 4.4). Tokens are cached until
+// 30 seconds before they expire, or until invalidated by a 401.
+type ClientCredentialsAuth struct {
+	config ClientCredentialsConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Ensure ClientCredentialsAuth implements AuthProvider and AuthInvalidator
+var (
+	_ AuthProvider    = (*ClientCredentialsAuth)(nil)
+	_ AuthInvalidator = (*ClientCredentialsAuth)(nil)
+)
+
+// NewClientCredentialsAuth creates an AuthProvider for the client
+// credentials flow.
+func NewClientCredentialsAuth(config ClientCredentialsConfig) *ClientCredentialsAuth {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{}
+	}
+	return &ClientCredentialsAuth{config: config, now: time.Now}
+}
+
+// Token returns the cached access token, requesting a new one if there is
+// none or it is about to expire. Concurrent callers share one request.
+func (a *ClientCredentialsAuth) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && (a.expires.IsZero() || a.now().Before(a.expires.Add(-tokenRefreshMargin))) {
+		return a.token, nil
+	}
+	token, expiresIn, err := a.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	a.token = token
+	a.expires = time.Time{}
+	if expiresIn > 0 {
+		a.expires = a.now().Add(expiresIn)
+	}
+	return token, nil
+}
+
+// Invalidate drops the cached token, so the next Token call requests one.
+func (a *ClientCredentialsAuth) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = ""
+}
+
+// tokenResponse is the token endpoint's successful response.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// fetch requests a token from the token endpoint.
+func (a *ClientCredentialsAuth) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.config.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.config.ClientID), url.QueryEscape(a.config.ClientSecret))
+
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", 0, &HTTPError{Status: resp.StatusCode, Endpoint: a.config.TokenURL}
+	}
+	var body tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseBytes)).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return "", 0, errors.New("token response has no access_token")
+	}
+	return body.AccessToken, time.Duration(body.ExpiresIn) * time.Second, nil
+}
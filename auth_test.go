@@ -0,0 +1,212 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// mockAuthProvider hands out "token-1", "token-2", ... advancing on each
+// Invalidate.
+type mockAuthProvider struct {
+	mu          sync.Mutex
+	generation  int
+	invalidated int
+	err         error
+}
+
+func (p *mockAuthProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return "", p.err
+	}
+	return "token-" + strconv.Itoa(p.generation+1), nil
+}
+
+func (p *mockAuthProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.generation++
+	p.invalidated++
+}
+
+// withAuthProvider authenticates with provider instead of an API key and
+// disables retries.
+func withAuthProvider(provider AuthProvider) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.APIKey = ""
+		c.AuthProvider = provider
+		c.MaxRetries = 0
+	}
+}
+
+func TestDispatcher_AuthProvider(t *testing.T) {
+	t.Run("should send the token as a bearer token", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withAuthProvider(&mockAuthProvider{}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		requests := httpAdapter.Requests()
+		if len(requests) != 1 || requests[0].Headers[AuthorizationHeader] != "Bearer token-1" {
+			t.Fatalf("unexpected requests %+v", requests)
+		}
+		if _, ok := requests[0].Headers["X-API-Key"]; ok {
+			t.Fatal("expected no api key header without an api key")
+		}
+	})
+
+	t.Run("should refresh the token and resend once after a 401", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 401, Times: 1},
+			adapters.Scenario{Status: 200},
+		)
+		provider := &mockAuthProvider{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withAuthProvider(provider))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		requests := httpAdapter.Requests()
+		if len(requests) != 2 || requests[1].Headers[AuthorizationHeader] != "Bearer token-2" {
+			t.Fatalf("unexpected requests %+v", requests)
+		}
+		if stats := d.Stats(); stats.EventsSent != 1 {
+			t.Fatalf("expected the resend to deliver, got %+v", stats)
+		}
+	})
+
+	t.Run("should drop the batch when the fresh token is also rejected", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 401})
+		provider := &mockAuthProvider{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withAuthProvider(provider))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if got := len(httpAdapter.Requests()); got != 2 {
+			t.Fatalf("expected 2 sends, got %d", got)
+		}
+		if provider.invalidated != 1 {
+			t.Fatalf("expected 1 invalidation, got %d", provider.invalidated)
+		}
+		if stats := d.Stats(); stats.BatchesFailed != 1 {
+			t.Fatalf("expected 1 failed batch, got %+v", stats)
+		}
+	})
+
+	t.Run("should treat token errors like network errors", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withAuthProvider(&mockAuthProvider{err: errors.New("token endpoint down")}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if len(httpAdapter.Requests()) != 0 {
+			t.Fatal("expected no sends without a token")
+		}
+		if d.queue.Len() != 1 {
+			t.Fatalf("expected the batch to be re-queued, got %d queued", d.queue.Len())
+		}
+	})
+}
+
+func TestClientCredentialsAuth(t *testing.T) {
+	newTokenServer := func(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := requests.Add(1)
+			id, secret, _ := r.BasicAuth()
+			if id != "client" || secret != "secret" || r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "events:write" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests
+	}
+	newAuth := func(server *httptest.Server) *ClientCredentialsAuth {
+		return NewClientCredentialsAuth(ClientCredentialsConfig{
+			TokenURL:     server.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scopes:       []string{"events:write"},
+		})
+	}
+
+	t.Run("should cache the token until it nears expiry", func(t *testing.T) {
+		server, requests := newTokenServer(t, 0)
+		auth := newAuth(server)
+		now := time.Now()
+		auth.now = func() time.Time { return now }
+
+		// expires_in of 0 means the server gave no expiry.
+		for range 3 {
+			if token, err := auth.Token(context.Background()); err != nil || token != "access-1" {
+				t.Fatalf("unexpected token %q, %v", token, err)
+			}
+		}
+		if requests.Load() != 1 {
+			t.Fatalf("expected 1 token request, got %d", requests.Load())
+		}
+	})
+
+	t.Run("should refresh tokens about to expire", func(t *testing.T) {
+		server, requests := newTokenServer(t, 3600)
+		auth := newAuth(server)
+		now := time.Now()
+		auth.now = func() time.Time { return now }
+
+		auth.Token(context.Background())
+		now = now.Add(time.Hour - time.Minute)
+		if token, _ := auth.Token(context.Background()); token != "access-1" {
+			t.Fatalf("expected the cached token, got %q", token)
+		}
+		now = now.Add(45 * time.Second)
+		if token, _ := auth.Token(context.Background()); token != "access-2" || requests.Load() != 2 {
+			t.Fatalf("expected a refreshed token, got %q after %d requests", token, requests.Load())
+		}
+	})
+
+	t.Run("should request a new token after Invalidate", func(t *testing.T) {
+		server, _ := newTokenServer(t, 0)
+		auth := newAuth(server)
+
+		auth.Token(context.Background())
+		auth.Invalidate()
+		if token, _ := auth.Token(context.Background()); token != "access-2" {
+			t.Fatalf("expected a new token, got %q", token)
+		}
+	})
+
+	t.Run("should return token endpoint errors", func(t *testing.T) {
+		server, _ := newTokenServer(t, 0)
+		auth := NewClientCredentialsAuth(ClientCredentialsConfig{TokenURL: server.URL, ClientID: "client", ClientSecret: "wrong"})
+
+		var httpErr *HTTPError
+		if _, err := auth.Token(context.Background()); !errors.As(err, &httpErr) || httpErr.Status != 401 {
+			t.Fatalf("expected a 401 HTTPError, got %v", err)
+		}
+	})
+}
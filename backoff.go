@@ -0,0 +1,94 @@
+package ripple
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBackoffBase   = time.Second
+	defaultBackoffMax    = 30 * time.Second
+	defaultBackoffJitter = time.Second
+)
+
+// BackoffPolicy decides how long to wait before retrying a failed batch.
+type BackoffPolicy interface {
+	// NextDelay returns the delay before retry attempt+1, where attempt is
+	// the zero-based attempt that just failed.
+	NextDelay(attempt int) time.Duration
+}
+
+// BackoffPolicyFunc adapts a function to the BackoffPolicy interface.
+type BackoffPolicyFunc func(attempt int) time.Duration
+
+// NextDelay calls f(attempt).
+func (f BackoffPolicyFunc) NextDelay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// ExponentialBackoff doubles the delay after every attempt, starting at base
+// and capped at maxDelay. A non-positive maxDelay means no cap.
+func ExponentialBackoff(base, maxDelay time.Duration) BackoffPolicy {
+	return BackoffPolicyFunc(func(attempt int) time.Duration {
+		delay := base
+		for i := 0; i < attempt; i++ {
+			if maxDelay > 0 && delay >= maxDelay || delay > math.MaxInt64/2 {
+				break
+			}
+			delay *= 2
+		}
+		return capDelay(delay, maxDelay)
+	})
+}
+
+// ExponentialBackoffWithJitter is ExponentialBackoff plus a random delay in
+// [0, jitter), so that clients failing together do not retry together.
+// The jitter is added after the cap.
+func ExponentialBackoffWithJitter(base, maxDelay, jitter time.Duration) BackoffPolicy {
+	exponential := ExponentialBackoff(base, maxDelay)
+	return BackoffPolicyFunc(func(attempt int) time.Duration {
+		delay := exponential.NextDelay(attempt)
+		if jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(jitter)))
+		}
+		return delay
+	})
+}
+
+// ConstantBackoff waits the same delay before every retry.
+func ConstantBackoff(delay time.Duration) BackoffPolicy {
+	return BackoffPolicyFunc(func(int) time.Duration {
+		return delay
+	})
+}
+
+// FibonacciBackoff grows the delay along the Fibonacci sequence (base, base,
+// 2*base, 3*base, 5*base, ...), capped at maxDelay. It backs off more gently
+// than ExponentialBackoff. A non-positive maxDelay means no cap.
+func FibonacciBackoff(base, maxDelay time.Duration) BackoffPolicy {
+	return BackoffPolicyFunc(func(attempt int) time.Duration {
+		prev, delay := time.Duration(0), base
+		for i := 0; i < attempt; i++ {
+			if maxDelay > 0 && delay >= maxDelay || delay > math.MaxInt64/2 {
+				break
+			}
+			prev, delay = delay, prev+delay
+		}
+		return capDelay(delay, maxDelay)
+	})
+}
+
+// defaultBackoffPolicy waits 1s, 2s, 4s, ... up to 30s, plus up to 1s of
+// jitter.
+func defaultBackoffPolicy() BackoffPolicy {
+	return ExponentialBackoffWithJitter(defaultBackoffBase, defaultBackoffMax, defaultBackoffJitter)
+}
+
+// capDelay limits delay to maxDelay, if it is positive.
+func capDelay(delay, maxDelay time.Duration) time.Duration {
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,89 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoffPolicies(t *testing.T) {
+	delays := func(policy BackoffPolicy, attempts int) []time.Duration {
+		var out []time.Duration
+		for attempt := 0; attempt < attempts; attempt++ {
+			out = append(out, policy.NextDelay(attempt))
+		}
+		return out
+	}
+	equal := func(t *testing.T, got, want []time.Duration) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+	}
+
+	t.Run("should double exponential delays up to the cap", func(t *testing.T) {
+		equal(t, delays(ExponentialBackoff(time.Second, 10*time.Second), 6),
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second})
+	})
+
+	t.Run("should not overflow without a cap", func(t *testing.T) {
+		if delay := ExponentialBackoff(time.Second, 0).NextDelay(200); delay <= 0 {
+			t.Fatalf("expected a positive delay, got %v", delay)
+		}
+		if delay := FibonacciBackoff(time.Second, 0).NextDelay(200); delay <= 0 {
+			t.Fatalf("expected a positive delay, got %v", delay)
+		}
+	})
+
+	t.Run("should add bounded jitter after the cap", func(t *testing.T) {
+		policy := ExponentialBackoffWithJitter(time.Second, 4*time.Second, 500*time.Millisecond)
+		for i := 0; i < 50; i++ {
+			delay := policy.NextDelay(5)
+			if delay < 4*time.Second || delay >= 4500*time.Millisecond {
+				t.Fatalf("expected delay in [4s, 4.5s), got %v", delay)
+			}
+		}
+	})
+
+	t.Run("should keep constant delays constant", func(t *testing.T) {
+		equal(t, delays(ConstantBackoff(250*time.Millisecond), 3),
+			[]time.Duration{250 * time.Millisecond, 250 * time.Millisecond, 250 * time.Millisecond})
+	})
+
+	t.Run("should follow the Fibonacci sequence up to the cap", func(t *testing.T) {
+		equal(t, delays(FibonacciBackoff(time.Second, 6*time.Second), 7),
+			[]time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 6 * time.Second, 6 * time.Second})
+	})
+}
+
+func TestDispatcher_BackoffPolicy(t *testing.T) {
+	var attempts []int
+	httpAdapter := &mockHTTPAdapter{fail: true, networkError: true}
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:        "test-key",
+		APIKeyHeader:  "X-API-Key",
+		Endpoint:      "http://test.com",
+		FlushInterval: 10 * time.Second,
+		MaxBatchSize:  10,
+		MaxRetries:    2,
+		BackoffPolicy: BackoffPolicyFunc(func(attempt int) time.Duration {
+			attempts = append(attempts, attempt)
+			return time.Millisecond
+		}),
+	}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+
+	d.Enqueue(Event{Name: "test"})
+	d.FlushContext(context.Background())
+
+	if httpAdapter.getCalls() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", httpAdapter.getCalls())
+	}
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Fatalf("expected the policy to be asked for attempts 0 and 1, got %v", attempts)
+	}
+}
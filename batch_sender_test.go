@@ -0,0 +1,124 @@
+package ripple
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecordingAdapter implements BatchSender and fails the first failures
+// attempts with a 503.
+type batchRecordingAdapter struct {
+	mu       sync.Mutex
+	failures int
+	batches  []Batch
+}
+
+func (a *batchRecordingAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	panic("Send should not be called when SendBatch is implemented")
+}
+
+func (a *batchRecordingAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	panic("SendWithContext should not be called when SendBatch is implemented")
+}
+
+func (a *batchRecordingAdapter) SendBatch(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.batches = append(a.batches, batch)
+	if a.failures > 0 {
+		a.failures--
+		return &HTTPResponse{Status: 503}, nil
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
+
+func TestDispatcher_BatchID(t *testing.T) {
+	newDispatcher := func(adapter HTTPAdapter, maxBatchSize int) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  maxBatchSize,
+			MaxRetries:    3,
+		}, adapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should keep the batch ID across retries", func(t *testing.T) {
+		adapter := &batchRecordingAdapter{failures: 1}
+		d := newDispatcher(adapter, 10)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if len(adapter.batches) != 2 {
+			t.Fatalf("expected 2 attempts, got %d", len(adapter.batches))
+		}
+		first, retry := adapter.batches[0], adapter.batches[1]
+		if first.ID == "" || first.ID != retry.ID {
+			t.Fatalf("expected stable batch ID, got %q and %q", first.ID, retry.ID)
+		}
+		if first.Headers[BatchIDHeader] != first.ID || retry.Headers[BatchIDHeader] != first.ID {
+			t.Fatalf("expected %s header to match batch ID", BatchIDHeader)
+		}
+		if first.Attempt != 0 || retry.Attempt != 1 {
+			t.Fatalf("expected attempts 0 and 1, got %d and %d", first.Attempt, retry.Attempt)
+		}
+		if first.Endpoint != "http://test.com" || len(first.Events) != 1 {
+			t.Fatalf("unexpected batch: %+v", first)
+		}
+	})
+
+	t.Run("should stamp each attempt with its own sentAt", func(t *testing.T) {
+		adapter := &batchRecordingAdapter{failures: 1}
+		d := newDispatcher(adapter, 10)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		first, retry := adapter.batches[0], adapter.batches[1]
+		if first.SentAt.IsZero() || !retry.SentAt.After(first.SentAt) {
+			t.Fatalf("expected increasing sentAt per attempt, got %v and %v", first.SentAt, retry.SentAt)
+		}
+		if retry.Headers[SentAtHeader] != strconv.FormatInt(retry.SentAt.UnixMilli(), 10) {
+			t.Fatalf("expected %s header to match SentAt", SentAtHeader)
+		}
+	})
+
+	t.Run("should use a distinct ID per batch", func(t *testing.T) {
+		adapter := &batchRecordingAdapter{}
+		d := newDispatcher(adapter, 1)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+
+		if len(adapter.batches) != 2 || adapter.batches[0].ID == adapter.batches[1].ID {
+			t.Fatalf("expected 2 batches with distinct IDs, got %+v", adapter.batches)
+		}
+	})
+
+	t.Run("should send the batch ID header through plain HTTP adapters", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newDispatcher(httpAdapter, 10)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		httpAdapter.mu.Lock()
+		defer httpAdapter.mu.Unlock()
+		if httpAdapter.lastHeaders[BatchIDHeader] == "" {
+			t.Fatalf("expected %s header", BatchIDHeader)
+		}
+	})
+}
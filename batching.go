@@ -0,0 +1,128 @@
+package ripple
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// batchEnvelopeBytes approximates the serialized overhead of the
+// {"sentAt":...,"events":[...]} envelope around a batch.
+const batchEnvelopeBytes = len(`{"events":[],"sentAt":1700000000000}`)
+
+// OversizedEventPolicy controls how Track handles events larger than
+// MaxEventBytes.
+type OversizedEventPolicy int
+
+const (
+	// OversizedEventReject returns an *EventTooLargeError from Track.
+	OversizedEventReject OversizedEventPolicy = iota
+
+	// OversizedEventTruncate drops the event payload, marks the event with
+	// a "payloadTruncated" metadata field, and rejects it only if it is
+	// still too large.
+	OversizedEventTruncate
+)
+
+// payloadTruncatedMetadataKey marks events whose payload was dropped to fit
+// MaxEventBytes.
+const payloadTruncatedMetadataKey = "payloadTruncated"
+
+// EventTooLargeError is returned by Track when an event exceeds MaxEventBytes.
+type EventTooLargeError struct {
+	Name  string
+	Size  int
+	Limit int
+}
+
+func (e *EventTooLargeError) Error() string {
+	return fmt.Sprintf("event %q is %d bytes, exceeding the %d byte limit", e.Name, e.Size, e.Limit)
+}
+
+// eventSize returns the serialized size of an event in bytes.
+func eventSize(event *Event) (int, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("event %q is not serializable: %w", event.Name, err)
+	}
+	return len(data), nil
+}
+
+// maxPooledFlushEvents bounds the flush buffers kept for reuse, so a flush
+// after a long outage does not keep its backlog's buffer alive.
+const maxPooledFlushEvents = 4096
+
+// flushBufferPool recycles the slices a flush drains the queue into. Its
+// batches are sub-slices of this buffer, so everything that outlives the
+// flush, such as scheduled retries and re-queued events, copies them, and so
+// do sends to adapters that may keep them (see EventRetainer) and OnDelivery.
+var flushBufferPool = sync.Pool{
+	New: func() any { return new([]Event) },
+}
+
+// getFlushBuffer returns an empty buffer from flushBufferPool.
+func getFlushBuffer() *[]Event {
+	return flushBufferPool.Get().(*[]Event)
+}
+
+// putFlushBuffer clears buf, so pooled buffers do not keep payloads alive,
+// and returns it to flushBufferPool.
+func putFlushBuffer(buf *[]Event) {
+	if cap(*buf) > maxPooledFlushEvents {
+		return
+	}
+	clear(*buf)
+	*buf = (*buf)[:0]
+	flushBufferPool.Put(buf)
+}
+
+// retainsEvents reports whether adapter may keep the events slice of a send
+// after it returns. Only adapters implementing EventRetainer and reporting
+// false are passed pooled batches directly.
+func retainsEvents(adapter HTTPAdapter) bool {
+	retainer, ok := adapter.(EventRetainer)
+	return !ok || retainer.RetainsEvents()
+}
+
+// flattenBatches concatenates batches back into a single slice of events.
+func flattenBatches(batches [][]Event) []Event {
+	var events []Event
+	for _, batch := range batches {
+		events = append(events, batch...)
+	}
+	return events
+}
+
+// splitBatches groups events into batches of at most maxCount events and,
+// when maxBytes > 0, at most maxBytes serialized bytes. An event that alone
+// exceeds maxBytes is sent in a batch of its own.
+func splitBatches(events []Event, maxCount, maxBytes int) [][]Event {
+	var batches [][]Event
+	start, size := 0, batchEnvelopeBytes
+
+	for i := range events {
+		eventBytes := 0
+		if maxBytes > 0 {
+			// Unserializable events are counted as zero bytes; the HTTP
+			// adapter reports the marshal error when the batch is sent.
+			eventBytes, _ = eventSize(&events[i])
+			if i > start {
+				eventBytes++ // separating comma
+			}
+		}
+
+		full := i-start >= maxCount || (maxBytes > 0 && i > start && size+eventBytes > maxBytes)
+		if full {
+			batches = append(batches, events[start:i])
+			start, size = i, batchEnvelopeBytes
+			if maxBytes > 0 {
+				eventBytes-- // no comma at the start of a batch
+			}
+		}
+		size += eventBytes
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
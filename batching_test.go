@@ -0,0 +1,197 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSplitBatches(t *testing.T) {
+	events := make([]Event, 7)
+	for i := range events {
+		events[i] = Event{Name: "e", Payload: map[string]any{"data": strings.Repeat("x", 100)}}
+	}
+	size, _ := eventSize(&events[0])
+
+	t.Run("by count only", func(t *testing.T) {
+		batches := splitBatches(events, 3, 0)
+		if len(batches) != 3 || len(batches[0]) != 3 || len(batches[2]) != 1 {
+			t.Fatalf("unexpected batches: %d", len(batches))
+		}
+	})
+
+	t.Run("by bytes", func(t *testing.T) {
+		maxBytes := batchEnvelopeBytes + 2*size + 1
+		batches := splitBatches(events, 10, maxBytes)
+		if len(batches) != 4 {
+			t.Fatalf("expected 4 batches of up to 2 events, got %d", len(batches))
+		}
+		for _, batch := range batches {
+			if len(batch) > 2 {
+				t.Errorf("batch exceeds byte limit with %d events", len(batch))
+			}
+		}
+	})
+
+	t.Run("oversized event is sent alone", func(t *testing.T) {
+		batches := splitBatches(events[:3], 10, 10)
+		if len(batches) != 3 {
+			t.Fatalf("expected each event in its own batch, got %d", len(batches))
+		}
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		if batches := splitBatches(nil, 10, 100); len(batches) != 0 {
+			t.Fatalf("expected no batches, got %d", len(batches))
+		}
+	})
+}
+
+func TestClient_MaxEventBytes(t *testing.T) {
+	bigPayload := map[string]any{"data": strings.Repeat("x", 500)}
+
+	t.Run("should reject oversized events", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxEventBytes = 200
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		err := client.Track("big", bigPayload, nil)
+		var tooLarge *EventTooLargeError
+		if !errors.As(err, &tooLarge) {
+			t.Fatalf("expected EventTooLargeError, got %v", err)
+		}
+		if tooLarge.Limit != 200 || tooLarge.Size <= 200 || tooLarge.Name != "big" {
+			t.Errorf("unexpected error details: %+v", tooLarge)
+		}
+		if client.dispatcher.queue.Len() != 0 {
+			t.Error("expected oversized event not to be queued")
+		}
+	})
+
+	t.Run("should truncate payload when configured", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxEventBytes = 200
+		config.OversizedEventPolicy = OversizedEventTruncate
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("big", bigPayload, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		event, ok := client.dispatcher.queue.Dequeue()
+		if !ok {
+			t.Fatal("expected truncated event to be queued")
+		}
+		if event.Payload != nil || event.Metadata[payloadTruncatedMetadataKey] != true {
+			t.Errorf("expected payload truncated, got %+v", event)
+		}
+	})
+
+	t.Run("should report unserializable events", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxEventBytes = 200
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("bad", map[string]any{"ch": make(chan int)}, nil); err == nil {
+			t.Fatal("expected serialization error")
+		}
+	})
+
+	t.Run("should reject event limit above batch limit", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxEventBytes = 2000
+		config.MaxBatchBytes = 1000
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestDispatcher_MaxBatchBytes(t *testing.T) {
+	httpAdapter := &mockHTTPAdapter{}
+	config := createTestConfig()
+	config.HTTPAdapter = httpAdapter
+	config.MaxBatchSize = 100
+	config.MaxBatchBytes = 300
+	client, _ := NewClient(config)
+	defer client.Dispose()
+
+	for i := 0; i < 4; i++ {
+		client.Track("e", map[string]any{"data": strings.Repeat("x", 100)}, nil)
+	}
+	client.Flush()
+
+	if httpAdapter.getCalls() < 2 {
+		t.Fatalf("expected batches split by size, got %d calls", httpAdapter.getCalls())
+	}
+}
+
+// retainingHTTPAdapter keeps the events slices it is sent, like an adapter
+// that delivers asynchronously.
+type retainingHTTPAdapter struct {
+	mu      sync.Mutex
+	batches [][]Event
+}
+
+func (r *retainingHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return r.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (r *retainingHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, events)
+	return &HTTPResponse{Status: 200}, nil
+}
+
+func TestDispatcher_PooledFlushBuffers(t *testing.T) {
+	// names returns the event names of each batch.
+	names := func(batches [][]Event) [][]string {
+		var result [][]string
+		for _, batch := range batches {
+			var batchNames []string
+			for _, event := range batch {
+				batchNames = append(batchNames, event.Name)
+			}
+			result = append(result, batchNames)
+		}
+		return result
+	}
+
+	httpAdapter := &retainingHTTPAdapter{}
+	var mu sync.Mutex
+	var delivered [][]Event
+	config := createTestConfig()
+	config.HTTPAdapter = httpAdapter
+	config.OnDelivery = func(batch []Event, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, batch)
+	}
+	client, _ := NewClient(config)
+	defer client.Dispose()
+
+	client.Track("a", nil, nil)
+	client.Flush()
+	client.Track("b", nil, nil)
+	client.Flush()
+
+	want := "[[a] [b]]"
+	httpAdapter.mu.Lock()
+	sent := names(httpAdapter.batches)
+	httpAdapter.mu.Unlock()
+	mu.Lock()
+	reported := names(delivered)
+	mu.Unlock()
+	if got := fmt.Sprint(sent); got != want {
+		t.Errorf("expected the adapter's batches to stay %s, got %s", want, got)
+	}
+	if got := fmt.Sprint(reported); got != want {
+		t.Errorf("expected the OnDelivery batches to stay %s, got %s", want, got)
+	}
+}
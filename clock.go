@@ -0,0 +1,71 @@
+package ripple
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the dispatcher's source of time. Flush scheduling, retry backoff,
+// rate limiting, periodic checks and timestamps all go through it, so a test
+// clock can drive them without real waits. See rippletest.Clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTicker returns a ticker that fires every d.
+	NewTicker(d time.Duration) Ticker
+
+	// AfterFunc calls f in its own goroutine once d has elapsed.
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// Sleep blocks until d has elapsed or ctx is done. Returns true if d
+	// elapsed, false if ctx was done first.
+	Sleep(ctx context.Context, d time.Duration) bool
+}
+
+// Ticker delivers ticks on a channel, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer is a pending AfterFunc call, like time.Timer.
+type Timer interface {
+	// Stop prevents the call from running. Returns false if it has already
+	// run or been stopped.
+	Stop() bool
+}
+
+// systemClock is the Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
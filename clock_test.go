@@ -0,0 +1,47 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSystemClock(t *testing.T) {
+	var c Clock = systemClock{}
+
+	t.Run("should sleep for the duration", func(t *testing.T) {
+		start := c.Now()
+		if !c.Sleep(context.Background(), 5*time.Millisecond) {
+			t.Fatal("expected sleep to complete")
+		}
+		if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+			t.Fatalf("expected to sleep at least 5ms, slept %v", elapsed)
+		}
+	})
+
+	t.Run("should stop sleeping when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if c.Sleep(ctx, time.Hour) {
+			t.Fatal("expected cancelled sleep to report false")
+		}
+	})
+
+	t.Run("should tick and run AfterFunc", func(t *testing.T) {
+		ticker := c.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		select {
+		case <-ticker.C():
+		case <-time.After(time.Second):
+			t.Fatal("expected a tick")
+		}
+
+		fired := make(chan struct{})
+		c.AfterFunc(time.Millisecond, func() { close(fired) })
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatal("expected AfterFunc to run")
+		}
+	})
+}
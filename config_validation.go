@@ -0,0 +1,322 @@
+package ripple
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// ConfigError reports every problem found in a ClientConfig, so that a
+// misconfigured client can be fixed in one pass.
+type ConfigError struct {
+	// Problems describes each invalid setting, in field order.
+	Problems []string
+}
+
+// Error returns the single problem, or all of them joined with "; ".
+func (e *ConfigError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0]
+	}
+	return fmt.Sprintf("invalid client config (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the configuration without applying defaults and returns a
+// *ConfigError listing every problem found, or nil if it is valid. NewClient
+// calls it before creating the client.
+func (c ClientConfig) Validate() error {
+	var problems []string
+	add := func(problem string) {
+		problems = append(problems, problem)
+	}
+
+	// Required fields
+	if c.APIKey == "" && c.AuthProvider == nil {
+		add("api key is required")
+	}
+	if len(c.Endpoints) > 0 && len(c.RegionalEndpoints) > 0 {
+		add("endpoints and regional endpoints cannot both be set")
+	}
+	if c.Endpoint == "" && len(c.Endpoints) == 0 && len(c.RegionalEndpoints) == 0 {
+		add("endpoint is required")
+	}
+	schemes := endpointSchemes(c.HTTPAdapter)
+	if c.Endpoint != "" && !validEndpointURL(c.Endpoint, schemes...) {
+		add(fmt.Sprintf("endpoint %q must be an absolute %s URL", c.Endpoint, strings.Join(schemes, " or ")))
+	}
+	for _, problem := range validateEndpointList("regional endpoints", c.RegionalEndpoints, schemes) {
+		add(problem)
+	}
+	for _, problem := range validateEndpointList("endpoints", c.Endpoints, schemes) {
+		add(problem)
+	}
+	if c.HTTPAdapter == nil {
+		add("http adapter is required")
+	}
+	if c.StorageAdapter == nil {
+		add("storage adapter is required")
+	}
+	if c.APIKeyHeader != nil && *c.APIKeyHeader == "" {
+		add("api key header cannot be empty")
+	}
+	apiKeyHeader := defaultAPIKeyHeader
+	if c.APIKeyHeader != nil {
+		apiKeyHeader = *c.APIKeyHeader
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Headers)) {
+		if name == "" {
+			add("header names cannot be empty")
+		} else if isReservedHeader(name, apiKeyHeader, c.AuthProvider != nil) {
+			add(fmt.Sprintf("header %q is set by the client and cannot be overridden", name))
+		}
+	}
+	for _, hook := range c.BeforeSend {
+		if hook == nil {
+			add("before send hooks cannot be nil")
+			break
+		}
+	}
+	if !validateSampleRate(c.SamplingRate) {
+		add("sampling rate must be between 0 and 1")
+	}
+	for name, rate := range c.SamplingRules {
+		if !validateSampleRate(rate) {
+			add(fmt.Sprintf("sampling rate for %q must be between 0 and 1", name))
+		}
+	}
+	for _, problem := range validateEventPatterns("allowed events", c.AllowedEvents) {
+		add(problem)
+	}
+	for _, problem := range validateEventPatterns("denied events", c.DeniedEvents) {
+		add(problem)
+	}
+
+	// Numeric values
+	if c.FlushInterval < 0 || (c.FlushInterval > 0 && c.FlushInterval < time.Millisecond) {
+		add("flush interval must be a positive duration")
+	}
+	if !(c.FlushIntervalJitter >= 0 && c.FlushIntervalJitter < 1) {
+		add("flush interval jitter must be between 0 and 1")
+	}
+	if c.MaxBatchSize < 0 {
+		add("max batch size must be a positive number")
+	}
+	if c.MaxRetries < 0 {
+		add("max retries must be a non-negative number")
+	}
+	if c.RetryMode != RetryInline && c.RetryMode != RetryScheduled {
+		add(fmt.Sprintf("retry mode must be RetryInline or RetryScheduled, got %d", c.RetryMode))
+	}
+	if c.FlushTimeout < 0 {
+		add("flush timeout must be a positive duration")
+	}
+	if c.RequestTimeout < 0 {
+		add("request timeout must be a positive duration")
+	}
+	if adaptive := c.AdaptiveBatching; adaptive != nil {
+		if adaptive.MinBatchSize < 0 || adaptive.MaxBatchSize < 0 || adaptive.TargetLatency < 0 {
+			add("adaptive batching bounds must be positive")
+		}
+		if adaptive.MaxBatchSize > 0 && adaptive.MinBatchSize > adaptive.MaxBatchSize {
+			add("adaptive batching min batch size must not exceed max batch size")
+		}
+	}
+	if c.MaxBufferSize < 0 {
+		add("max buffer size must be a positive number")
+	}
+	batchSize := c.MaxBatchSize
+	if batchSize == 0 {
+		batchSize = defaultMaxBatchSize
+	}
+	if c.MaxBufferSize > 0 && batchSize > 0 && c.MaxBufferSize < batchSize {
+		add(fmt.Sprintf("max buffer size (%d) must be greater than or equal to max batch size (%d)", c.MaxBufferSize, batchSize))
+	}
+	if c.EndpointProbeInterval < 0 {
+		add("endpoint probe interval must be a positive duration")
+	}
+	if c.EndpointStrategy != EndpointFailover && c.EndpointStrategy != EndpointRoundRobin {
+		add(fmt.Sprintf("endpoint strategy must be EndpointFailover or EndpointRoundRobin, got %d", c.EndpointStrategy))
+	}
+	if c.EndpointFailureThreshold < 0 {
+		add("endpoint failure threshold must be a positive number")
+	}
+	if c.EndpointCooldown < 0 {
+		add("endpoint cooldown must be a positive duration")
+	}
+	if c.Ordering < OrderingNone || c.Ordering > OrderingPerKey {
+		add(fmt.Sprintf("ordering must be OrderingNone, OrderingGlobal or OrderingPerKey, got %d", c.Ordering))
+	}
+	if c.Ordering == OrderingPerKey && !validOrderingKey(c.OrderingKey) {
+		add(`per-key ordering requires an ordering key of "sessionId" or a payload. or metadata. path`)
+	}
+	if c.OrderingKey != "" && c.Ordering != OrderingPerKey {
+		add("ordering key requires per-key ordering")
+	}
+	if c.Ordering != OrderingNone && c.RetryMode == RetryScheduled {
+		add("ordering cannot be combined with scheduled retries")
+	}
+	if c.DeliveryGuarantee != DeliveryBestEffort && c.DeliveryGuarantee != DeliveryAtLeastOnce {
+		add(fmt.Sprintf("delivery guarantee must be DeliveryBestEffort or DeliveryAtLeastOnce, got %d", c.DeliveryGuarantee))
+	}
+	if c.DeliveryGuarantee == DeliveryAtLeastOnce {
+		spills := c.MemoryPressure != nil || c.SpillThreshold > 0 ||
+			(c.ResourceBudget != nil && c.ResourceBudget.Degradation == BudgetDegradeSpill)
+		if spills {
+			add("at-least-once delivery cannot be combined with spilling to storage")
+		}
+		if c.MaxBufferSize > 0 || c.MaxStorageEvents > 0 || c.MaxStorageBytes > 0 {
+			add("at-least-once delivery cannot be combined with a max buffer size or storage quotas, which would evict accepted events")
+		}
+	}
+	if c.MaxStorageEvents < 0 || c.MaxStorageBytes < 0 {
+		add("storage quotas must be positive numbers")
+	}
+	if c.FireAndForget != nil {
+		for _, problem := range c.FireAndForget.validate(c.EventPriority) {
+			add(problem)
+		}
+	}
+	if c.StorageEviction != StorageEvictOldest && c.StorageEviction != StorageEvictLowestPriority {
+		add(fmt.Sprintf("storage eviction must be StorageEvictOldest or StorageEvictLowestPriority, got %d", c.StorageEviction))
+	}
+	if c.StorageEviction == StorageEvictLowestPriority && c.EventPriority == nil {
+		add("lowest priority eviction requires an event priority function")
+	}
+	if c.PersistInterval < 0 {
+		add("persist interval must be a positive duration")
+	}
+	if c.SpillThreshold < 0 {
+		add("spill threshold must be a positive number")
+	}
+	if c.MemoryCheckInterval < 0 {
+		add("memory check interval must be a positive duration")
+	}
+	if c.LogRateLimit < 0 {
+		add("log rate limit must be a positive number")
+	}
+	if !validRate(c.MaxRequestsPerSecond) {
+		add("max requests per second must be a positive number")
+	}
+	if c.RateLimitBurst < 0 {
+		add("rate limit burst must be a positive number")
+	}
+	if c.RateLimitMode != RateLimitWait && c.RateLimitMode != RateLimitSpill {
+		add(fmt.Sprintf("rate limit mode must be RateLimitWait or RateLimitSpill, got %d", c.RateLimitMode))
+	}
+	if c.ImportBatchSize < 0 {
+		add("import batch size must be a positive number")
+	}
+	if !validRate(c.ImportRequestsPerSecond) {
+		add("import requests per second must be a positive number")
+	}
+	if c.MaxBatchBytes < 0 {
+		add("max batch bytes must be a positive number")
+	}
+	if c.MaxEventBytes < 0 {
+		add("max event bytes must be a positive number")
+	}
+	if c.MaxBatchBytes > 0 && c.MaxEventBytes > c.MaxBatchBytes {
+		add(fmt.Sprintf("max event bytes (%d) must be less than or equal to max batch bytes (%d)", c.MaxEventBytes, c.MaxBatchBytes))
+	}
+	if budget := c.ResourceBudget; budget != nil {
+		if budget.MaxQueueBytes < 0 || budget.MaxStorageBytesWritten < 0 {
+			add("resource budget limits must be positive numbers")
+		}
+		if budget.Degradation < BudgetDegradeFlush || budget.Degradation > BudgetDegradeDropNewest {
+			add(fmt.Sprintf("resource budget degradation must be BudgetDegradeFlush, BudgetDegradeSpill or BudgetDegradeDropNewest, got %d", budget.Degradation))
+		}
+	}
+	if c.DedupWindow < 0 {
+		add("dedup window must be a positive duration")
+	}
+	if c.DedupWindow > 0 && !c.EventIDs {
+		add("dedup window requires event ids")
+	}
+	if c.EventTTL < 0 {
+		add("event ttl must be a positive duration")
+	}
+	if c.ShutdownTimeout < 0 {
+		add("shutdown timeout must be a positive duration")
+	}
+	if c.Redactor != nil {
+		for _, problem := range c.Redactor.validate() {
+			add(problem)
+		}
+	}
+	for _, problem := range validateTransformers(c.Transformers) {
+		add(problem)
+	}
+	for _, problem := range validateMigrations(c.SchemaMigrations) {
+		add(problem)
+	}
+	if remote := c.RemoteConfig; remote != nil {
+		if remote.Source == nil && !validEndpointURL(remote.URL, "http", "https") {
+			add(fmt.Sprintf("remote config url %q must be an absolute http or https URL", remote.URL))
+		}
+		if remote.Interval < 0 {
+			add("remote config interval must be a positive duration")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
+// validRate reports whether rate is a finite, non-negative number of events
+// or requests per second.
+func validRate(rate float64) bool {
+	return rate >= 0 && !math.IsInf(rate, 1)
+}
+
+// validateEndpointList reports empty and malformed entries of an endpoint list.
+func validateEndpointList(name string, endpoints []string, schemes []string) []string {
+	var problems []string
+	empty := false
+	for _, endpoint := range endpoints {
+		switch {
+		case endpoint == "":
+			empty = true
+		case !validEndpointURL(endpoint, schemes...):
+			problems = append(problems, fmt.Sprintf("%s entry %q must be an absolute %s URL", name, endpoint, strings.Join(schemes, " or ")))
+		}
+	}
+	if empty {
+		problems = append([]string{name + " cannot be empty"}, problems...)
+	}
+	return problems
+}
+
+// endpointSchemes returns the URL schemes endpoints must use with adapter,
+// or nil if the adapter does not address endpoints by URL, e.g. NATS
+// subjects or custom transports.
+func endpointSchemes(adapter HTTPAdapter) []string {
+	switch adapter.(type) {
+	case nil, *adapters.NetHTTPAdapter:
+		return []string{"http", "https"}
+	case *adapters.WebSocketAdapter:
+		return []string{"ws", "wss"}
+	default:
+		return nil
+	}
+}
+
+// validEndpointURL reports whether endpoint is an absolute URL with a host
+// and one of schemes. Any endpoint is valid if schemes is empty.
+func validEndpointURL(endpoint string, schemes ...string) bool {
+	if len(schemes) == 0 {
+		return true
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return slices.Contains(schemes, u.Scheme)
+}
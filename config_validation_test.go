@@ -0,0 +1,191 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestClientConfig_Validate(t *testing.T) {
+	t.Run("should accept a valid config", func(t *testing.T) {
+		if err := createTestConfig().Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should report every problem at once", func(t *testing.T) {
+		config := ClientConfig{
+			Endpoint:      "not a url",
+			FlushInterval: -time.Second,
+			MaxRetries:    -1,
+		}
+		err := config.Validate()
+
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Fatalf("expected *ConfigError, got %T", err)
+		}
+		expected := []string{
+			"api key is required",
+			`endpoint "not a url" must be an absolute http or https URL`,
+			"http adapter is required",
+			"storage adapter is required",
+			"flush interval must be a positive duration",
+			"max retries must be a non-negative number",
+		}
+		if strings.Join(configErr.Problems, "\n") != strings.Join(expected, "\n") {
+			t.Fatalf("unexpected problems:\n%s", strings.Join(configErr.Problems, "\n"))
+		}
+		if !strings.HasPrefix(err.Error(), "invalid client config (6 problems): api key is required; ") {
+			t.Fatalf("unexpected message: %v", err)
+		}
+	})
+
+	t.Run("should return a single problem as is", func(t *testing.T) {
+		config := createTestConfig()
+		config.APIKey = ""
+		if err := config.Validate(); err == nil || err.Error() != "api key is required" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should reject endpoints that are not http URLs", func(t *testing.T) {
+		for _, endpoint := range []string{"test.com/events", "ftp://test.com", "http://", "://bad"} {
+			config := createTestConfig()
+			config.HTTPAdapter = adapters.NewNetHTTPAdapter()
+			config.Endpoint = endpoint
+			if err := config.Validate(); err == nil {
+				t.Errorf("expected error for endpoint %q", endpoint)
+			}
+		}
+
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewNetHTTPAdapter()
+		config.Endpoint = ""
+		config.Endpoints = []string{"https://a.test/events", "b.test", ""}
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "endpoints cannot be empty") ||
+			!strings.Contains(err.Error(), `endpoints entry "b.test"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should check endpoint schemes for the adapter in use", func(t *testing.T) {
+		dial := func(ctx context.Context, endpoint string, headers map[string]string) (adapters.WebSocketConn, error) {
+			return nil, errors.New("unused")
+		}
+		wsAdapter, _ := adapters.NewWebSocketAdapter(dial)
+		natsAdapter, _ := adapters.NewJetStreamAdapter(func(ctx context.Context, subject string, data []byte) error {
+			return nil
+		})
+		tests := []struct {
+			adapter  HTTPAdapter
+			endpoint string
+			valid    bool
+		}{
+			{adapter: wsAdapter, endpoint: "wss://test.com/events", valid: true},
+			{adapter: wsAdapter, endpoint: "https://test.com/events", valid: false},
+			{adapter: natsAdapter, endpoint: "events.ingest", valid: true},
+			{adapter: &mockHTTPAdapter{}, endpoint: "custom-target", valid: true},
+		}
+		for _, tt := range tests {
+			config := createTestConfig()
+			config.HTTPAdapter = tt.adapter
+			config.Endpoint = tt.endpoint
+			if err := config.Validate(); (err == nil) != tt.valid {
+				t.Errorf("%T with endpoint %q: expected valid=%v, got %v", tt.adapter, tt.endpoint, tt.valid, err)
+			}
+		}
+	})
+
+	t.Run("should check the buffer size against the default batch size", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxBufferSize = defaultMaxBatchSize - 1
+		if err := config.Validate(); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should accept an auth provider instead of an api key", func(t *testing.T) {
+		config := createTestConfig()
+		config.APIKey = ""
+		config.AuthProvider = &mockAuthProvider{}
+		if err := config.Validate(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should reject headers the client sets itself", func(t *testing.T) {
+		config := createTestConfig()
+		config.Headers = map[string]string{"x-api-key": "k", "X-Batch-Id": "b", "X-Tenant-Id": "acme"}
+		var configErr *ConfigError
+		if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+			t.Fatalf("expected 2 problems, got %v", err)
+		}
+	})
+
+	t.Run("should require a priority, adapter and endpoint for fire-and-forget", func(t *testing.T) {
+		config := createTestConfig()
+		config.FireAndForget = &FireAndForget{}
+		var configErr *ConfigError
+		if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 3 {
+			t.Fatalf("expected 3 problems, got %v", err)
+		}
+	})
+
+	t.Run("should reject a malformed fire-and-forget endpoint", func(t *testing.T) {
+		config := createTestConfig()
+		config.EventPriority = func(Event) int { return 0 }
+		config.FireAndForget = &FireAndForget{HTTPAdapter: adapters.NewNetHTTPAdapter(), Endpoint: "udp.local:9000"}
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "fire-and-forget endpoint") {
+			t.Fatalf("expected a fire-and-forget endpoint error, got %v", err)
+		}
+	})
+
+	t.Run("should reject every setting NewClient cannot apply", func(t *testing.T) {
+		tests := map[string]func(*ClientConfig){
+			"NaN jitter":                 func(c *ClientConfig) { c.FlushIntervalJitter = math.NaN() },
+			"unknown retry mode":         func(c *ClientConfig) { c.RetryMode = RetryScheduled + 1 },
+			"unknown endpoint strategy":  func(c *ClientConfig) { c.EndpointStrategy = -1 },
+			"unknown ordering":           func(c *ClientConfig) { c.Ordering = OrderingPerKey + 1 },
+			"unknown delivery guarantee": func(c *ClientConfig) { c.DeliveryGuarantee = DeliveryAtLeastOnce + 1 },
+			"unknown storage eviction":   func(c *ClientConfig) { c.StorageEviction = StorageEvictLowestPriority + 1 },
+			"unknown rate limit mode":    func(c *ClientConfig) { c.RateLimitMode = RateLimitSpill + 1 },
+			"infinite request rate":      func(c *ClientConfig) { c.MaxRequestsPerSecond = math.Inf(1) },
+			"NaN import rate":            func(c *ClientConfig) { c.ImportRequestsPerSecond = math.NaN() },
+			"unknown budget degradation": func(c *ClientConfig) {
+				c.ResourceBudget = &ResourceBudget{Degradation: BudgetDegradeDropNewest + 1}
+			},
+		}
+		for name, modify := range tests {
+			config := createTestConfig()
+			modify(&config)
+			if err := config.Validate(); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+		}
+	})
+
+	t.Run("should reject empty and malformed event patterns", func(t *testing.T) {
+		config := createTestConfig()
+		config.AllowedEvents = []string{"checkout_*", ""}
+		config.DeniedEvents = []string{"debug_[a-"}
+		var configErr *ConfigError
+		if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 2 {
+			t.Fatalf("expected 2 problems, got %v", err)
+		}
+	})
+
+	t.Run("should be returned by NewClient", func(t *testing.T) {
+		client, err := NewClient(ClientConfig{})
+		var configErr *ConfigError
+		if client != nil || !errors.As(err, &configErr) || len(configErr.Problems) != 4 {
+			t.Fatalf("expected a config error with 4 problems, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package ripple
+
+import "context"
+
+// metadataContextKey is the context key for request-scoped metadata.
+type metadataContextKey struct{}
+
+// trackerContextKey is the context key for the client and its scoped
+// tracker.
+type trackerContextKey struct{}
+
+// WithClient returns a copy of ctx carrying client, so code several layers
+// deep can track events with FromContext or TrackerFromContext instead of a
+// global variable.
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, trackerContextKey{}, &Tracker{client: client})
+}
+
+// WithTracker returns a copy of ctx carrying tracker, e.g. a per-tenant
+// tracker created with Client.WithMetadata.
+func WithTracker(ctx context.Context, tracker *Tracker) context.Context {
+	return context.WithValue(ctx, trackerContextKey{}, tracker)
+}
+
+// FromContext returns the client stored in ctx with WithClient or
+// WithTracker, or nil.
+func FromContext(ctx context.Context) *Client {
+	tracker, _ := ctx.Value(trackerContextKey{}).(*Tracker)
+	if tracker == nil {
+		return nil
+	}
+	return tracker.client
+}
+
+// TrackerFromContext returns a Tracker for the client in ctx whose preset
+// metadata is that of the stored tracker, if any, overridden by the
+// request-scoped metadata in ctx. Returns nil if ctx has no client.
+func TrackerFromContext(ctx context.Context) *Tracker {
+	tracker, _ := ctx.Value(trackerContextKey{}).(*Tracker)
+	if tracker == nil {
+		return nil
+	}
+	return tracker.WithMetadata(MetadataFromContext(ctx))
+}
+
+// ContextWithMetadata returns a copy of ctx carrying metadata merged on top of
+// any request-scoped metadata already in ctx. Events tracked with
+// TrackContext include this metadata.
+func ContextWithMetadata(ctx context.Context, metadata map[string]any) context.Context {
+	merged := MetadataFromContext(ctx)
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, metadataContextKey{}, merged)
+}
+
+// MetadataFromContext returns a copy of the request-scoped metadata in ctx.
+// Returns an empty map if none is set.
+func MetadataFromContext(ctx context.Context) map[string]any {
+	stored, _ := ctx.Value(metadataContextKey{}).(map[string]any)
+	result := make(map[string]any, len(stored))
+	for k, v := range stored {
+		result[k] = v
+	}
+	return result
+}
+
+// MetadataCarrier is a detached snapshot of request-scoped metadata.
+// It holds no reference to the originating context, so it can be handed to
+// background goroutines or workers that outlive the request.
+type MetadataCarrier struct {
+	metadata map[string]any
+}
+
+// CaptureMetadata snapshots the request-scoped metadata in ctx.
+func CaptureMetadata(ctx context.Context) MetadataCarrier {
+	return MetadataCarrier{metadata: MetadataFromContext(ctx)}
+}
+
+// Metadata returns a copy of the captured metadata.
+func (c MetadataCarrier) Metadata() map[string]any {
+	result := make(map[string]any, len(c.metadata))
+	for k, v := range c.metadata {
+		result[k] = v
+	}
+	return result
+}
+
+// Context attaches the captured metadata to parent, typically a worker's
+// own context.
+func (c MetadataCarrier) Context(parent context.Context) context.Context {
+	return ContextWithMetadata(parent, c.metadata)
+}
+
+// DetachContext returns a context that carries a snapshot of the
+// request-scoped metadata and the client or tracker in ctx but is not
+// cancelled when ctx is.
+func DetachContext(ctx context.Context) context.Context {
+	detached := CaptureMetadata(ctx).Context(context.Background())
+	if tracker := TrackerFromContext(ctx); tracker != nil {
+		detached = WithTracker(detached, tracker)
+	}
+	return detached
+}
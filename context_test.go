@@ -0,0 +1,163 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextWithMetadata(t *testing.T) {
+	ctx := ContextWithMetadata(context.Background(), map[string]any{"requestId": "r1", "tenant": "a"})
+	ctx = ContextWithMetadata(ctx, map[string]any{"tenant": "b"})
+
+	metadata := MetadataFromContext(ctx)
+	if metadata["requestId"] != "r1" || metadata["tenant"] != "b" {
+		t.Fatalf("expected merged metadata, got %v", metadata)
+	}
+
+	metadata["requestId"] = "mutated"
+	if MetadataFromContext(ctx)["requestId"] != "r1" {
+		t.Error("expected MetadataFromContext to return a copy")
+	}
+
+	if len(MetadataFromContext(context.Background())) != 0 {
+		t.Error("expected empty metadata for bare context")
+	}
+}
+
+func TestMetadataCarrier(t *testing.T) {
+	reqCtx, cancel := context.WithCancel(ContextWithMetadata(context.Background(), map[string]any{"traceId": "t1"}))
+	carrier := CaptureMetadata(reqCtx)
+	detached := DetachContext(reqCtx)
+	cancel()
+
+	if detached.Err() != nil {
+		t.Fatal("expected detached context to survive parent cancellation")
+	}
+	if MetadataFromContext(detached)["traceId"] != "t1" {
+		t.Error("expected detached context to carry request metadata")
+	}
+
+	workerCtx := carrier.Context(context.Background())
+	if MetadataFromContext(workerCtx)["traceId"] != "t1" {
+		t.Error("expected carrier to attach metadata to worker context")
+	}
+	if carrier.Metadata()["traceId"] != "t1" {
+		t.Error("expected carrier metadata snapshot")
+	}
+}
+
+func TestClient_TrackContext(t *testing.T) {
+	client := createTestClient()
+	defer client.Dispose()
+
+	client.SetMetadata("service", "api")
+	client.SetMetadata("tenant", "shared")
+
+	ctx := ContextWithMetadata(context.Background(), map[string]any{"tenant": "ctx", "requestId": "r1"})
+	done := make(chan struct{})
+	go func(carrier MetadataCarrier) {
+		defer close(done)
+		client.TrackContext(carrier.Context(context.Background()), "job_done", nil, map[string]any{"requestId": "override"})
+	}(CaptureMetadata(ctx))
+	<-done
+
+	event, ok := client.dispatcher.queue.Dequeue()
+	if !ok {
+		t.Fatal("expected event to be enqueued")
+	}
+	if event.Metadata["service"] != "api" {
+		t.Errorf("expected shared metadata, got %v", event.Metadata)
+	}
+	if event.Metadata["tenant"] != "ctx" {
+		t.Errorf("expected context metadata to override shared, got %v", event.Metadata["tenant"])
+	}
+	if event.Metadata["requestId"] != "override" {
+		t.Errorf("expected event metadata to override context, got %v", event.Metadata["requestId"])
+	}
+}
+
+func TestWithClient(t *testing.T) {
+	t.Run("should round-trip the client", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		ctx := WithClient(context.Background(), client)
+		if FromContext(ctx) != client {
+			t.Fatal("expected client from context")
+		}
+		if FromContext(context.Background()) != nil {
+			t.Fatal("expected nil client for bare context")
+		}
+	})
+
+	t.Run("should track through a context tracker with request metadata", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+		client.SetMetadata("app", "api")
+
+		ctx := WithClient(context.Background(), client)
+		ctx = ContextWithMetadata(ctx, map[string]any{"requestId": "r1", "tenant": "a"})
+
+		tracker := TrackerFromContext(ctx)
+		if tracker == nil || tracker.Client() != client {
+			t.Fatal("expected tracker for the client")
+		}
+		if err := tracker.Track("deep_event", nil, map[string]any{"tenant": "b"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved := storage.getSaved()
+		if len(saved) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(saved))
+		}
+		metadata := saved[0].Metadata
+		if metadata["app"] != "api" || metadata["requestId"] != "r1" || metadata["tenant"] != "b" {
+			t.Fatalf("unexpected metadata: %v", metadata)
+		}
+	})
+
+	t.Run("should return nil tracker without a client", func(t *testing.T) {
+		if TrackerFromContext(context.Background()) != nil {
+			t.Fatal("expected nil tracker")
+		}
+	})
+
+	t.Run("should keep the client in detached contexts", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		ctx, cancel := context.WithCancel(WithClient(context.Background(), client))
+		detached := DetachContext(ctx)
+		cancel()
+
+		if FromContext(detached) != client {
+			t.Fatal("expected detached context to carry the client")
+		}
+	})
+
+	t.Run("should keep the tracker in detached contexts", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		tracker := client.WithMetadata(map[string]any{"tenant": "a"})
+		ctx, cancel := context.WithCancel(WithTracker(context.Background(), tracker))
+		detached := DetachContext(ctx)
+		cancel()
+
+		if err := TrackerFromContext(detached).Track("background_job", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Metadata["tenant"] != "a" {
+			t.Fatalf("expected the tracker's metadata, got %v", saved)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+)
+
+// stampEventID assigns a random UUID to event, unless it already carries one
+// (e.g. a restored event). Must be called with d.mu held.
+func (d *Dispatcher) stampEventID(event *Event) {
+	if !d.config.EventIDs || event.ID != "" {
+		return
+	}
+	event.ID = newUUID()
+}
+
+// dedupWindow remembers the IDs of delivered events for a fixed window so
+// that re-queued or restored copies are not sent again.
+type dedupWindow struct {
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+func newDedupWindow(window time.Duration) *dedupWindow {
+	return &dedupWindow{
+		window: window,
+		seen:   make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// record marks events as delivered and forgets IDs older than the window.
+func (w *dedupWindow) record(events []Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	for id, at := range w.seen {
+		if now.Sub(at) >= w.window {
+			delete(w.seen, id)
+		}
+	}
+	for _, event := range events {
+		if event.ID != "" {
+			w.seen[event.ID] = now
+		}
+	}
+}
+
+// filter returns events not delivered within the window, and the number of
+// duplicates removed.
+func (w *dedupWindow) filter(events []Event) ([]Event, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	kept := events[:0:0]
+	for _, event := range events {
+		if at, ok := w.seen[event.ID]; ok && event.ID != "" && now.Sub(at) < w.window {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept, len(events) - len(kept)
+}
+
+// dropDelivered removes events already delivered within the dedup window.
+func (d *Dispatcher) dropDelivered(events []Event) []Event {
+	if d.dedup == nil {
+		return events
+	}
+	kept, duplicates := d.dedup.filter(events)
+	if duplicates > 0 {
+		d.stats.duplicatesDropped(duplicates)
+		d.loggerAdapter.Debug("Dropped already delivered events", map[string]any{
+			"eventsCount": duplicates,
+		})
+	}
+	return kept
+}
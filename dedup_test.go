@@ -0,0 +1,137 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+)
+
+// withDedupWindow enables event IDs with a DedupWindow of window.
+func withDedupWindow(window time.Duration) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.EventIDs = true
+		c.DedupWindow = window
+	}
+}
+
+func TestDispatcher_EventIDs(t *testing.T) {
+	t.Run("should stamp every event with a unique ID", func(t *testing.T) {
+		storageAdapter := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storageAdapter, withDedupWindow(0))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+
+		saved := storageAdapter.getSaved()
+		if len(saved) != 2 || saved[0].ID == "" || saved[0].ID == saved[1].ID {
+			t.Fatalf("expected distinct IDs, got %+v", saved)
+		}
+	})
+
+	t.Run("should keep the ID of restored events", func(t *testing.T) {
+		storageAdapter := &mockStorageAdapter{loaded: []Event{{Name: "a", ID: "restored"}}}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storageAdapter, withDedupWindow(0))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "b"})
+
+		saved := storageAdapter.getSaved()
+		if len(saved) != 2 || saved[0].ID != "restored" {
+			t.Fatalf("expected restored ID to be kept, got %+v", saved)
+		}
+	})
+
+	t.Run("should not stamp IDs by default", func(t *testing.T) {
+		storageAdapter := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storageAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+
+		if saved := storageAdapter.getSaved(); saved[0].ID != "" {
+			t.Fatalf("expected no ID, got %q", saved[0].ID)
+		}
+	})
+}
+
+func TestDispatcher_DedupWindow(t *testing.T) {
+	t.Run("should skip copies of delivered events", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withDedupWindow(time.Minute))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a", ID: "dup"})
+		d.Flush()
+		d.Enqueue(Event{Name: "a", ID: "dup"})
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		stats := d.Stats()
+		if stats.EventsSent != 2 || stats.DuplicatesDropped != 1 {
+			t.Fatalf("expected duplicate to be skipped, got %+v", stats)
+		}
+		if httpAdapter.getCalls() != 2 {
+			t.Fatalf("expected 2 sends, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should not send a flush made only of duplicates", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withDedupWindow(time.Minute))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a", ID: "dup"})
+		d.Flush()
+		d.Enqueue(Event{Name: "a", ID: "dup"})
+		d.Flush()
+
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 send, got %d", httpAdapter.getCalls())
+		}
+	})
+}
+
+func TestDedupWindow(t *testing.T) {
+	t.Run("should forget IDs after the window", func(t *testing.T) {
+		now := time.Now()
+		w := newDedupWindow(time.Minute)
+		w.now = func() time.Time { return now }
+		w.record([]Event{{ID: "a"}})
+
+		if kept, dropped := w.filter([]Event{{ID: "a"}}); len(kept) != 0 || dropped != 1 {
+			t.Fatalf("expected duplicate within window, got %d kept", len(kept))
+		}
+
+		now = now.Add(time.Minute)
+		if kept, _ := w.filter([]Event{{ID: "a"}}); len(kept) != 1 {
+			t.Fatal("expected ID to be forgotten after the window")
+		}
+
+		w.record(nil)
+		if len(w.seen) != 0 {
+			t.Fatalf("expected expired IDs to be pruned, got %d", len(w.seen))
+		}
+	})
+
+	t.Run("should never drop events without an ID", func(t *testing.T) {
+		w := newDedupWindow(time.Minute)
+		w.record([]Event{{Name: "a"}})
+
+		if kept, _ := w.filter([]Event{{Name: "a"}}); len(kept) != 1 {
+			t.Fatal("expected events without ID to be kept")
+		}
+	})
+}
+
+func TestClient_DedupWindowRequiresEventIDs(t *testing.T) {
+	config := createTestConfig()
+	config.DedupWindow = time.Minute
+	if _, err := NewClient(config); err == nil {
+		t.Fatal("expected error when DedupWindow is set without EventIDs")
+	}
+}
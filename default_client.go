@@ -0,0 +1,50 @@
+package ripple
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoDefaultClient is returned by the package-level Track when no client
+// was set with SetDefault.
+var ErrNoDefaultClient = errors.New("no default client set")
+
+// defaultClient is the client the package-level helpers proxy to.
+var defaultClient atomic.Pointer[Client]
+
+// SetDefault makes client the target of the package-level Track, Flush and
+// SetMetadata, so small applications and libraries can emit events without
+// passing a client around. A nil client clears the default. The caller still
+// owns the client and must Init and Dispose it.
+func SetDefault(client *Client) {
+	defaultClient.Store(client)
+}
+
+// Default returns the client set with SetDefault, or nil.
+func Default() *Client {
+	return defaultClient.Load()
+}
+
+// Track tracks an event with the default client. Returns
+// ErrNoDefaultClient if none is set.
+func Track(name string, payload, metadata map[string]any) error {
+	client := Default()
+	if client == nil {
+		return ErrNoDefaultClient
+	}
+	return client.Track(name, payload, metadata)
+}
+
+// Flush flushes the default client, if one is set.
+func Flush() {
+	if client := Default(); client != nil {
+		client.Flush()
+	}
+}
+
+// SetMetadata sets shared metadata on the default client, if one is set.
+func SetMetadata(key string, value any) {
+	if client := Default(); client != nil {
+		client.SetMetadata(key, value)
+	}
+}
@@ -0,0 +1,46 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultClient(t *testing.T) {
+	t.Run("should report a missing default client", func(t *testing.T) {
+		SetDefault(nil)
+
+		if err := Track("a", nil, nil); !errors.Is(err, ErrNoDefaultClient) {
+			t.Fatalf("expected ErrNoDefaultClient, got %v", err)
+		}
+		// No-ops without a default client.
+		Flush()
+		SetMetadata("k", "v")
+	})
+
+	t.Run("should proxy to the default client", func(t *testing.T) {
+		config := createTestConfig()
+		httpAdapter := config.HTTPAdapter.(*mockHTTPAdapter)
+		client, _ := NewClient(config)
+		client.Init()
+		defer client.Dispose()
+		SetDefault(client)
+		defer SetDefault(nil)
+
+		if Default() != client {
+			t.Fatal("expected Default to return the client")
+		}
+		SetMetadata("app", "demo")
+		if err := Track("a", map[string]any{"x": 1}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		event := client.dispatcher.queue.ToSlice()[0]
+		if event.Name != "a" || event.Metadata["app"] != "demo" {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+		Flush()
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 send, got %d", httpAdapter.getCalls())
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package ripple
+
+import "fmt"
+
+// DeliveryGuarantee controls how strongly queued events are protected
+// against process crashes.
+type DeliveryGuarantee int
+
+const (
+	// DeliveryBestEffort checkpoints the queue according to
+	// PersistencePolicy. Events tracked since the last checkpoint are lost
+	// if the process crashes.
+	DeliveryBestEffort DeliveryGuarantee = iota
+
+	// DeliveryAtLeastOnce writes every event to storage before Track
+	// returns and removes it only once its batch is answered, so a crash
+	// never loses an accepted event. Events in flight during a crash are
+	// sent again after restart, so the backend must tolerate duplicates,
+	// e.g. by deduplicating on eventId.
+	DeliveryAtLeastOnce
+)
+
+// atLeastOnce reports whether storage is maintained as a journal of every
+// undelivered event rather than checkpointed from the queue.
+func (d *Dispatcher) atLeastOnce() bool {
+	return d.config.DeliveryGuarantee == DeliveryAtLeastOnce
+}
+
+// journalCommit gathers the events of concurrent appends, so that they are
+// written to the journal together.
+type journalCommit struct {
+	events []Event
+	done   chan struct{}
+	err    error
+}
+
+// journalAppend durably adds events to the journal. Appends made while the
+// journal is being written wait for that write and are then written in one
+// Save, by whichever of them gets to write first. The journal is left
+// unchanged if storage rejects the write.
+func (d *Dispatcher) journalAppend(events ...Event) error {
+	d.journalNextMu.Lock()
+	commit := d.journalNext
+	if commit == nil {
+		commit = &journalCommit{done: make(chan struct{})}
+		d.journalNext = commit
+	}
+	commit.events = append(commit.events, events...)
+	d.journalNextMu.Unlock()
+
+	d.journalMu.Lock()
+	d.journalNextMu.Lock()
+	leader := d.journalNext == commit
+	if leader {
+		d.journalNext = nil
+	}
+	d.journalNextMu.Unlock()
+	if !leader {
+		// Another append has written, or is about to write, these events.
+		d.journalMu.Unlock()
+		<-commit.done
+		return commit.err
+	}
+
+	defer d.journalMu.Unlock()
+	defer close(commit.done)
+	next := make([]Event, 0, len(d.journal)+len(commit.events))
+	next = append(next, d.journal...)
+	next = append(next, commit.events...)
+	if err := d.saveEvents(next); err != nil {
+		commit.err = fmt.Errorf("failed to persist event: %w", err)
+		return commit.err
+	}
+	d.journal = next
+	d.saveSequence()
+	return nil
+}
+
+// journalRemove removes events answered by the backend from the journal. If
+// the write fails they stay in storage and are sent again after a restart.
+func (d *Dispatcher) journalRemove(events []Event) error {
+	ids := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		ids[event.ID] = struct{}{}
+	}
+
+	d.journalMu.Lock()
+	defer d.journalMu.Unlock()
+
+	next := make([]Event, 0, len(d.journal))
+	for _, event := range d.journal {
+		if _, ok := ids[event.ID]; !ok {
+			next = append(next, event)
+		}
+	}
+	if len(next) == len(d.journal) {
+		return nil
+	}
+	if err := d.saveEvents(next); err != nil {
+		return err
+	}
+	d.journal = next
+	return nil
+}
+
+// journalReset replaces the journal with events restored from storage,
+// stamping IDs on events persisted without one so they can be removed once
+// delivered.
+func (d *Dispatcher) journalReset(events []Event) {
+	stamped := false
+	for i := range events {
+		if events[i].ID == "" {
+			events[i].ID = newUUID()
+			stamped = true
+		}
+	}
+
+	d.journalMu.Lock()
+	defer d.journalMu.Unlock()
+
+	d.journal = events
+	if stamped {
+		if err := d.saveEvents(events); err != nil {
+			d.logStorageError("Failed to persist restored event IDs", err, nil)
+		}
+	}
+}
+
+// releaseStored removes answered events from storage: from the journal
+// under DeliveryAtLeastOnce, otherwise by clearing the checkpoint. While
+// spilled, storage holds only events spilled after events were taken from
+// the queue, so it is left as is. Audit events are also removed from
+// AuditStorage.
+func (d *Dispatcher) releaseStored(events []Event) error {
+	if err := d.audit.remove(events); err != nil {
+		d.logStorageError("Failed to remove answered audit events", err, nil)
+	}
+	if d.atLeastOnce() {
+		return d.journalRemove(events)
+	}
+
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+	d.mu.Lock()
+	spilled := d.spilled
+	d.mu.Unlock()
+	if spilled {
+		return nil
+	}
+	return d.clearStorage()
+}
@@ -0,0 +1,233 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// nameFailingHTTPAdapter answers 500 to batches containing an event named
+// fail and 200 to all others.
+type nameFailingHTTPAdapter struct {
+	fail string
+}
+
+func (a *nameFailingHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *nameFailingHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	for _, event := range events {
+		if event.Name == a.fail {
+			return &HTTPResponse{Status: 500}, nil
+		}
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
+
+// gatedStorageAdapter counts saves. Its first save blocks until release is
+// closed.
+type gatedStorageAdapter struct {
+	mockStorageAdapter
+	saves   int
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedStorageAdapter) Save(events []Event) error {
+	g.once.Do(func() {
+		close(g.started)
+		<-g.release
+	})
+	g.mu.Lock()
+	g.saves++
+	g.mu.Unlock()
+	return g.mockStorageAdapter.Save(events)
+}
+
+// withAtLeastOnce selects DeliveryAtLeastOnce with single-event batches, no
+// retries and no periodic persistence.
+func withAtLeastOnce(c *DispatcherConfig) {
+	c.MaxBatchSize = 1
+	c.MaxRetries = 0
+	c.PersistencePolicy = PersistNever()
+	c.DeliveryGuarantee = DeliveryAtLeastOnce
+}
+
+func TestDispatcher_AtLeastOnce(t *testing.T) {
+	t.Run("should write events to storage before they are queued", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withAtLeastOnce)
+		d.setMaxBatchSize(10)
+		d.Restore()
+		defer d.Dispose()
+
+		if err := d.enqueue(Event{Name: "a"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Name != "a" || saved[0].ID == "" {
+			t.Fatalf("expected event journaled with an ID, got %+v", saved)
+		}
+	})
+
+	t.Run("should reject events that cannot be persisted", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withAtLeastOnce)
+		d.Restore()
+		defer d.Dispose()
+
+		storage.err = errors.New("disk full")
+		if err := d.enqueue(Event{Name: "a"}); err == nil {
+			t.Fatal("expected persist error")
+		}
+		if d.queue.Len() != 0 {
+			t.Fatal("expected rejected event not to be queued")
+		}
+	})
+
+	t.Run("should remove only answered batches from storage", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&nameFailingHTTPAdapter{fail: "b"}, storage, withAtLeastOnce)
+		d.setMaxBatchSize(10)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.setMaxBatchSize(1)
+		d.Flush()
+
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Name != "b" {
+			t.Fatalf("expected only the failed event to remain, got %+v", saved)
+		}
+		if d.queue.Len() != 1 {
+			t.Fatalf("expected failed event re-queued, got %d", d.queue.Len())
+		}
+	})
+
+	t.Run("should stamp IDs on restored events and remove them once delivered", func(t *testing.T) {
+		storage := &mockStorageAdapter{loaded: []Event{{Name: "a"}}}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withAtLeastOnce)
+		d.Restore()
+		defer d.Dispose()
+
+		if saved := storage.getSaved(); len(saved) != 1 || saved[0].ID == "" {
+			t.Fatalf("expected restored event re-saved with an ID, got %+v", saved)
+		}
+
+		d.Flush()
+		if saved := storage.getSaved(); len(saved) != 0 {
+			t.Fatalf("expected storage emptied after delivery, got %+v", saved)
+		}
+	})
+
+	t.Run("should write concurrent appends together", func(t *testing.T) {
+		storage := &gatedStorageAdapter{started: make(chan struct{}), release: make(chan struct{})}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withAtLeastOnce)
+
+		var wg sync.WaitGroup
+		appendEvent := func(name string) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = d.journalAppend(Event{Name: name})
+			}()
+		}
+		appendEvent("a")
+		<-storage.started
+		appendEvent("b")
+		appendEvent("c")
+
+		// Wait for b and c to join the next write before releasing a's.
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			d.journalNextMu.Lock()
+			waiting := 0
+			if d.journalNext != nil {
+				waiting = len(d.journalNext.events)
+			}
+			d.journalNextMu.Unlock()
+			if waiting == 2 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected 2 appends waiting, got %d", waiting)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		close(storage.release)
+		wg.Wait()
+
+		storage.mu.Lock()
+		saves := storage.saves
+		storage.mu.Unlock()
+		if saves != 2 {
+			t.Fatalf("expected b and c written in one save, got %d saves", saves)
+		}
+		if saved := storage.getSaved(); len(saved) != 3 {
+			t.Fatalf("expected 3 journaled events, got %+v", saved)
+		}
+	})
+
+	t.Run("should keep queued events in storage on dispose", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withAtLeastOnce)
+		d.setMaxBatchSize(10)
+		d.Restore()
+
+		d.Enqueue(Event{Name: "a"})
+		if _, persisted := d.dispose(false); persisted != 1 {
+			t.Fatalf("expected 1 event reported persisted, got %d", persisted)
+		}
+		if len(storage.getSaved()) != 1 {
+			t.Fatal("expected journal to survive dispose")
+		}
+	})
+}
+
+func TestClient_AtLeastOnce(t *testing.T) {
+	t.Run("should return persist errors from Track", func(t *testing.T) {
+		config := createTestConfig()
+		config.DeliveryGuarantee = DeliveryAtLeastOnce
+		config.StorageAdapter = &mockStorageAdapter{err: errors.New("disk full")}
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer client.Dispose()
+
+		if err := client.Track("a", nil, nil); err == nil {
+			t.Fatal("expected Track to report the persist error")
+		}
+	})
+
+	t.Run("should reject spilling", func(t *testing.T) {
+		config := createTestConfig()
+		config.DeliveryGuarantee = DeliveryAtLeastOnce
+		config.SpillThreshold = 100
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error combining at-least-once delivery with spilling")
+		}
+	})
+
+	t.Run("should reject limits that evict events", func(t *testing.T) {
+		for name, limit := range map[string]func(*ClientConfig){
+			"max buffer size":    func(c *ClientConfig) { c.MaxBufferSize = 100 },
+			"max storage events": func(c *ClientConfig) { c.MaxStorageEvents = 100 },
+			"max storage bytes":  func(c *ClientConfig) { c.MaxStorageBytes = 1 << 20 },
+		} {
+			config := createTestConfig()
+			config.DeliveryGuarantee = DeliveryAtLeastOnce
+			limit(&config)
+			if _, err := NewClient(config); err == nil {
+				t.Errorf("expected error combining at-least-once delivery with a %s", name)
+			}
+		}
+	})
+}
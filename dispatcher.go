@@ -3,14 +3,29 @@ package ripple
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
 )
 
 const (
-	maxBackoffDuration = 30 * time.Second
-	maxJitterMs        = 1000
+	flushSpanName = "ripple.flush"
+	sendSpanName  = "ripple.send"
+
+	// SentAtHeader carries the Unix millisecond timestamp of each delivery
+	// attempt, letting the backend correct for client-side queueing delay.
+	SentAtHeader = "X-Ripple-Sent-At"
+
+	// BatchIDHeader carries an ID that is unique per batch and stable across
+	// its retries, letting the backend deduplicate retried batches.
+	BatchIDHeader = "X-Batch-Id"
 )
 
 // Dispatcher manages event queuing, batching, flushing, and retry logic.
@@ -18,89 +33,403 @@ type Dispatcher struct {
 	config         DispatcherConfig
 	queue          *Queue
 	httpAdapter    HTTPAdapter
+	copyEvents     bool // httpAdapter may keep the events of a send
 	storageAdapter StorageAdapter
 	loggerAdapter  LoggerAdapter
+	tracer         TracerProvider
+	persistence    PersistencePolicy
+	backoff        BackoffPolicy
+	pendingPersist int
+	lastPersist    time.Time
+	dirty          bool
+	journal        []Event
+	journalMu      sync.Mutex     // held while writing the journal
+	journalNext    *journalCommit // appends waiting for the next journal write
+	journalNextMu  sync.Mutex
+	tap            eventTap
+	persistTicker  *persistTicker
 	headers        map[string]string
-	timer          *time.Timer
+	timer          Timer
+	scheduler      *flushScheduler // set by Manager; replaces timer for periodic flushes
+	clock          Clock
 	flushMu        sync.Mutex
+	flushes        flushCoalescer
+	acks           ackRegistry
+	audit          auditJournal
+	migrations     map[migrationKey]SchemaMigration
 	retryCancel    context.CancelFunc
+	flushRequeue   *[]Event // guarded by flushMu
+	retries        []retryBatch
+	retryTimer     Timer
 	disposed       bool
+	interrupted    bool // set by interruptFlush; stops retry rounds until Restore
 	mu             sync.Mutex
+	latency        latencyRecorder
+	stats          statsRecorder
+	selector       *EndpointSelector
+	pool           *endpointPool
+	dedup          *dedupWindow
+	memoryMonitor  *memoryMonitor
+	spilled        bool
+	storageMu      sync.Mutex // serializes storage writes that depend on spilled
+	rateLimiter    *tokenBucket
+	pausedUntil    time.Time
+	importLimiter  *tokenBucket
+	sizer          *batchSizer
+	maxBatchSize   atomic.Int64
+	sequence       uint64
+	savedSequence  uint64
+	held           []Event
+	resources      resourceMeter
 }
 
 // NewDispatcher creates a new Dispatcher instance.
+// It never panics: missing adapters are replaced with safe fallbacks and
+// non-positive sizes or intervals fall back to the client defaults.
 func NewDispatcher(config DispatcherConfig, httpAdapter HTTPAdapter, storageAdapter StorageAdapter, loggerAdapter LoggerAdapter) *Dispatcher {
-	return &Dispatcher{
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = defaultMaxBatchSize
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.APIKeyHeader == "" {
+		config.APIKeyHeader = defaultAPIKeyHeader
+	}
+	if config.DeliveryGuarantee == DeliveryAtLeastOnce {
+		// Journal entries are removed by event ID once delivered.
+		config.EventIDs = true
+	}
+	if config.SequenceNumbers && config.ProducerID == "" {
+		config.ProducerID = newProducerID()
+	}
+	if httpAdapter == nil {
+		httpAdapter = unconfiguredHTTPAdapter{}
+	}
+	if storageAdapter == nil {
+		storageAdapter = adapters.NewNoOpStorageAdapter()
+	}
+	if loggerAdapter == nil {
+		loggerAdapter = adapters.NewNoOpLoggerAdapter()
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+
+	d := &Dispatcher{
 		config:         config,
 		queue:          NewQueue(),
 		httpAdapter:    httpAdapter,
+		copyEvents:     retainsEvents(httpAdapter),
 		storageAdapter: storageAdapter,
 		loggerAdapter:  loggerAdapter,
-		headers: map[string]string{
-			config.APIKeyHeader: config.APIKey,
-			"Content-Type":      "application/json",
-		},
+		tracer:         TracerProvider(adapters.NewNoOpTracerProvider()),
+		persistence:    defaultPersistencePolicy(),
+		backoff:        defaultBackoffPolicy(),
+		clock:          config.Clock,
+		headers:        make(map[string]string, len(config.Headers)+2),
+		audit:          auditJournal{storage: config.AuditStorage},
+		migrations:     newMigrations(config.SchemaMigrations),
+	}
+	for name, value := range config.Headers {
+		if !d.reservedHeader(name) {
+			d.headers[name] = value
+		}
+	}
+	d.headers["Content-Type"] = "application/json"
+	if config.APIKey != "" {
+		d.headers[config.APIKeyHeader] = config.APIKey
 	}
+
+	d.maxBatchSize.Store(int64(config.MaxBatchSize))
+
+	if config.TracerProvider != nil {
+		d.tracer = config.TracerProvider
+	}
+	if config.PersistencePolicy != nil {
+		d.persistence = config.PersistencePolicy
+	}
+	if config.BackoffPolicy != nil {
+		d.backoff = config.BackoffPolicy
+	}
+
+	if len(config.RegionalEndpoints) > 0 {
+		d.selector = newEndpointSelector(config.RegionalEndpoints, config.EndpointProbeInterval, d.probeEndpoint)
+		d.selector.spawn = d.resources.spawn
+		d.selector.clock = d.clock
+	}
+	if config.DedupWindow > 0 {
+		d.dedup = newDedupWindow(config.DedupWindow)
+		d.dedup.now = d.clock.Now
+	}
+	if len(config.Endpoints) > 0 {
+		d.pool = newEndpointPool(config.Endpoints, config.EndpointStrategy, config.EndpointFailureThreshold, config.EndpointCooldown)
+		d.pool.now = d.clock.Now
+	}
+	if config.MaxRequestsPerSecond > 0 {
+		d.rateLimiter = newTokenBucket(config.MaxRequestsPerSecond, config.RateLimitBurst, d.clock)
+	}
+	if config.ImportBatchSize <= 0 {
+		d.config.ImportBatchSize = defaultImportBatchSize
+	}
+	if config.ImportRequestsPerSecond <= 0 {
+		d.config.ImportRequestsPerSecond = defaultImportRequestsPerSecond
+	}
+	d.importLimiter = newTokenBucket(d.config.ImportRequestsPerSecond, 1, d.clock)
+	if config.AdaptiveBatching != nil {
+		d.sizer = newBatchSizer(*config.AdaptiveBatching, config.MaxBatchSize)
+	}
+	if config.MemoryPressure != nil {
+		d.memoryMonitor = newMemoryMonitor(config.MemoryPressure, config.MemoryCheckInterval, func() {
+			d.spillToStorage("memory pressure")
+		})
+		d.memoryMonitor.spawn = d.resources.spawn
+		d.memoryMonitor.clock = d.clock
+	}
+	if config.PersistInterval > 0 {
+		d.persistTicker = newPersistTicker(config.PersistInterval, d.persistDirty)
+		d.persistTicker.spawn = d.resources.spawn
+		d.persistTicker.clock = d.clock
+	}
+	if config.ResourceBudget != nil {
+		d.queue.trackBytes()
+	}
+
+	return d
+}
+
+// errHTTPAdapterNotConfigured is returned for every send when a Dispatcher
+// was created without an HTTPAdapter.
+var errHTTPAdapterNotConfigured = errors.New("http adapter is not configured")
+
+// errNilHTTPResponse is reported when an HTTPAdapter returns neither a
+// response nor an error.
+var errNilHTTPResponse = errors.New("http adapter returned a nil response")
+
+// unconfiguredHTTPAdapter fails every request instead of panicking on a nil
+// adapter, so events are retained and retried like any network failure.
+type unconfiguredHTTPAdapter struct{}
+
+func (unconfiguredHTTPAdapter) Send(string, []Event, map[string]string) (*HTTPResponse, error) {
+	return nil, errHTTPAdapterNotConfigured
+}
+
+func (unconfiguredHTTPAdapter) SendWithContext(context.Context, string, []Event, map[string]string) (*HTTPResponse, error) {
+	return nil, errHTTPAdapterNotConfigured
+}
+
+func (unconfiguredHTTPAdapter) RetainsEvents() bool {
+	return false
 }
 
 // Enqueue adds an event to the queue.
 func (d *Dispatcher) Enqueue(event Event) {
+	_ = d.enqueue(event)
+}
+
+// enqueue implements Enqueue. Under DeliveryAtLeastOnce it returns an error,
+// and drops the event, if the event could not be written to storage.
+func (d *Dispatcher) enqueue(event Event) error {
 	d.mu.Lock()
 	if d.disposed {
 		d.mu.Unlock()
 		d.loggerAdapter.Warn("Cannot enqueue event: Dispatcher has been disposed")
-		return
+		d.acks.resolve([]Event{event}, discarded("client disposed"))
+		return nil
 	}
+	d.stampSequence(&event)
+	d.stampEventID(&event)
+	spilled := d.spilled
 	d.mu.Unlock()
 
+	if !d.admitWithinBudget(&event) {
+		d.acks.resolve([]Event{event}, discarded("queue byte budget exceeded"))
+		return nil
+	}
+
+	if d.atLeastOnce() {
+		if err := d.journalAppend(event); err != nil {
+			d.logStorageError("Failed to persist event before enqueue", err, nil)
+			return err
+		}
+	}
+	d.tap.publish(event)
+
+	if spilled {
+		// Keep the event in memory only if storage is unusable; never
+		// checkpoint the in-memory queue here as it would overwrite spilled events.
+		if !d.enqueueToStorage(event) {
+			d.queue.Enqueue(event)
+		}
+		d.stats.trackEvent()
+		d.scheduleFlush()
+		return nil
+	}
+
 	d.queue.Enqueue(event)
+	d.stats.trackEvent()
 
 	// Apply buffer limit and persist
-	eventsToSave := d.applyQueueLimit(d.queue.ToSlice())
-	if len(eventsToSave) < d.queue.Len() {
+	queued := d.queue.ToSlice()
+	eventsToSave := d.applyQueueLimit(queued)
+	if len(eventsToSave) < len(queued) {
+		d.acks.resolve(d.evicted(queued, eventsToSave), discarded("buffer full"))
 		d.queue.Clear()
 		d.queue.LoadFromSlice(eventsToSave)
 	}
 
-	if err := d.storageAdapter.Save(eventsToSave); err != nil {
-		d.logStorageError("Failed to persist events to storage", err, map[string]any{
-			"queueSize": d.queue.Len(),
-		})
+	d.checkpoint(PersistTriggerEnqueue, eventsToSave, 1)
+
+	overBudget := d.overQueueBudget()
+	if overBudget && d.config.ResourceBudget.Degradation == BudgetDegradeSpill {
+		d.spillToStorage("queue byte budget exceeded")
+		d.scheduleFlush()
+		return nil
+	}
+	if d.config.SpillThreshold > 0 && d.queue.Len() > d.config.SpillThreshold {
+		d.spillToStorage("spill threshold exceeded")
+		d.scheduleFlush()
+		return nil
 	}
 
-	if d.queue.Len() >= d.config.MaxBatchSize {
+	if d.queue.Len() >= d.batchSize() || overBudget {
 		d.Flush()
 	} else {
 		d.scheduleFlush()
 	}
+	return nil
 }
 
 // Flush immediately flushes all queued events.
 func (d *Dispatcher) Flush() {
+	d.FlushContext(context.Background())
+}
+
+// flush drains the queue and delivers it in batches, giving up on retries
+// once ctx is done.
+func (d *Dispatcher) flush(ctx context.Context) {
 	d.flushMu.Lock()
 	defer d.flushMu.Unlock()
 
 	d.stopTimer()
+	d.reloadSpilled()
 
-	if d.queue.IsEmpty() {
+	if d.queue.IsEmpty() || d.deferForBackoff() {
 		return
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := d.flushContext(ctx)
 	d.mu.Lock()
 	d.retryCancel = cancel
 	d.mu.Unlock()
 	defer cancel()
 
-	allEvents := d.queue.ToSlice()
-	d.queue.Clear()
+	buf := getFlushBuffer()
+	defer putFlushBuffer(buf)
+	*buf = d.queue.drainTo(*buf)
+	allEvents := d.dropExpired(d.dropDelivered(*buf))
+	if len(allEvents) == 0 {
+		return
+	}
+	allEvents = d.migrateEvents(allEvents)
 
-	for i := 0; i < len(allEvents); i += d.config.MaxBatchSize {
-		end := i + d.config.MaxBatchSize
-		if end > len(allEvents) {
-			end = len(allEvents)
+	ctx, span := d.tracer.Start(ctx, flushSpanName)
+	span.SetAttribute("events.count", len(allEvents))
+	defer span.End()
+
+	allEvents = d.sendFireAndForget(ctx, allEvents)
+	var batches [][]Event
+	for _, group := range d.groupByTenant(allEvents) {
+		batches = append(batches, splitBatches(group, d.batchSize(), d.config.MaxBatchBytes)...)
+	}
+
+	var deferred []Event
+	d.flushRequeue = &deferred
+	defer d.requeueDeferred(&deferred)
+
+	var gate *orderingGate
+	if d.config.Ordering != OrderingNone {
+		gate = newOrderingGate(d.config.Ordering, d.config.OrderingKey)
+	}
+	for i, batch := range batches {
+		if ctx.Err() != nil {
+			d.requeueIfActive(flattenBatches(batches[i:]))
+			break
+		}
+		if d.backoffRemaining() > 0 {
+			d.requeueEvents(flattenBatches(batches[i:]))
+			d.deferForBackoff()
+			break
+		}
+		if !d.acquireSendSlot(ctx) {
+			remaining := flattenBatches(batches[i:])
+			if ctx.Err() != nil {
+				d.requeueIfActive(remaining)
+				break
+			}
+			d.loggerAdapter.Warn("Rate limit reached, deferring remaining batches", map[string]any{
+				"eventsCount": len(remaining),
+			})
+			d.requeueEvents(remaining)
+			d.scheduleFlush()
+			break
 		}
-		d.sendWithRetry(ctx, allEvents[i:end], 0)
+		if gate == nil {
+			d.sendWithRetry(ctx, batch, newUUID(), 0)
+			continue
+		}
+		batch, held := gate.split(batch)
+		deferred = append(deferred, held...)
+		if len(batch) > 0 {
+			failed := len(deferred)
+			d.sendWithRetry(ctx, batch, newUUID(), 0)
+			gate.block(deferred[failed:])
+		}
+	}
+	d.logFlushTimeout(ctx)
+
+	d.stats.flushed(d.clock.Now())
+}
+
+// flushContext derives the context of a flush from parent, cancelled by
+// Dispose and bounded by FlushTimeout.
+func (d *Dispatcher) flushContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if d.config.FlushTimeout > 0 {
+		return context.WithTimeout(parent, d.config.FlushTimeout)
+	}
+	return context.WithCancel(parent)
+}
+
+// interruptFlush cancels the running flush, if any, and waits for it to
+// re-queue its undelivered events. Retry rounds stay stopped until Restore,
+// so the retry queue cannot start another flush ahead of Close.
+func (d *Dispatcher) interruptFlush() {
+	d.mu.Lock()
+	d.interrupted = true
+	if d.retryTimer != nil {
+		d.retryTimer.Stop()
+		d.retryTimer = nil
+	}
+	cancel := d.retryCancel
+	d.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+}
+
+// logFlushTimeout warns if a flush was cut short by FlushTimeout. Undelivered
+// events have been re-queued and checkpointed by then.
+func (d *Dispatcher) logFlushTimeout(ctx context.Context) {
+	if d.config.FlushTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		d.loggerAdapter.Warn("Flush timed out, remaining events re-queued", map[string]any{
+			"flushTimeout": d.config.FlushTimeout.String(),
+			"queueSize":    d.queue.Len(),
+		})
 	}
 }
 
@@ -108,18 +437,33 @@ func (d *Dispatcher) Flush() {
 func (d *Dispatcher) Restore() {
 	d.mu.Lock()
 	d.disposed = false
+	d.interrupted = false
+	d.spilled = false
 	d.mu.Unlock()
 
+	if d.selector != nil {
+		d.selector.Start()
+	}
+	if d.memoryMonitor != nil {
+		d.memoryMonitor.Start()
+	}
+	if d.persistTicker != nil {
+		d.persistTicker.Start()
+	}
+
 	events, err := d.storageAdapter.Load()
+	d.restoreSequence(events)
 	if err != nil {
-		d.loggerAdapter.Error("Failed to restore events from storage", map[string]any{
-			"error": err.Error(),
-		})
-		return
+		d.logStorageError("Failed to restore events from storage", err, nil)
+	} else {
+		limited := d.applyQueueLimit(events)
+		if d.atLeastOnce() {
+			d.journalReset(limited)
+		}
+		d.queue.LoadFromSlice(limited)
+		d.stats.setStorageSize(len(events))
 	}
-
-	limited := d.applyQueueLimit(events)
-	d.queue.LoadFromSlice(limited)
+	d.restoreAudit()
 
 	if d.queue.Len() > 0 {
 		d.scheduleFlush()
@@ -128,6 +472,14 @@ func (d *Dispatcher) Restore() {
 
 // Dispose cleans up resources: aborts retries, clears queue, releases mutex.
 func (d *Dispatcher) Dispose() {
+	d.dispose(false)
+}
+
+// dispose implements Dispose and returns the number of events left in the
+// queue and how many of them were persisted to storage. Held events, which
+// are never persisted, count as left. If force is true the queue is saved
+// regardless of the persistence policy.
+func (d *Dispatcher) dispose(force bool) (queued, persisted int) {
 	d.mu.Lock()
 	d.disposed = true
 	cancel := d.retryCancel
@@ -138,60 +490,553 @@ func (d *Dispatcher) Dispose() {
 	}
 
 	d.stopTimer()
+	d.reloadSpilled()
+	remaining := append(d.takeRetries(), d.queue.ToSlice()...)
+	if d.atLeastOnce() {
+		// Every queued event is already in the journal.
+		persisted = len(remaining)
+	} else if force {
+		if err := d.saveEvents(remaining); err != nil {
+			d.logStorageError("Failed to persist events on shutdown", err, nil)
+		} else {
+			persisted = len(remaining)
+		}
+	} else if d.checkpoint(PersistTriggerShutdown, remaining, 0) {
+		persisted = len(remaining)
+	}
+	d.saveSequence()
 	d.queue.Clear()
+	if held := d.takeHeld(nil); len(held) > 0 {
+		d.loggerAdapter.Warn("Discarding held events on dispose", map[string]any{
+			"eventsCount": len(held),
+		})
+		remaining = append(remaining, held...)
+	}
+	d.acks.resolveAll(discarded("client disposed"))
+
+	if d.selector != nil {
+		d.selector.Stop()
+	}
+	if d.memoryMonitor != nil {
+		d.memoryMonitor.Stop()
+	}
+	if d.persistTicker != nil {
+		d.persistTicker.Stop()
+	}
 
 	if err := d.storageAdapter.Close(); err != nil {
 		d.loggerAdapter.Error("failed to close storage adapter", map[string]any{
 			"error": err.Error(),
 		})
 	}
+	return len(remaining), persisted
+}
+
+// acquireSendSlot applies the outbound rate limit before sending a batch.
+// Returns false if the batch should be deferred instead.
+func (d *Dispatcher) acquireSendSlot(ctx context.Context) bool {
+	if d.rateLimiter == nil {
+		return true
+	}
+	if d.config.RateLimitMode == RateLimitSpill {
+		return d.rateLimiter.allow()
+	}
+	return d.rateLimiter.wait(ctx)
+}
+
+// DropQueue discards every queued and persisted event and returns the number
+// of in-memory events dropped. Pending acks of the dropped events, including
+// spilled ones, are resolved with ErrEventDiscarded.
+func (d *Dispatcher) DropQueue() int {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	spilled := d.spilled
+	d.spilled = false
+	d.mu.Unlock()
+
+	events := append(d.queue.drainTo(nil), d.takeHeld(nil)...)
+	events = append(events, d.takeRetries()...)
+	dropped := len(events)
+	if spilled {
+		if stored, err := d.storageAdapter.Load(); err == nil {
+			events = append(events, stored...)
+		}
+	}
+	d.acks.resolve(events, discarded("queue dropped"))
+
+	d.journalMu.Lock()
+	d.journal = nil
+	d.journalMu.Unlock()
+	if err := d.clearStorage(); err != nil {
+		d.loggerAdapter.Error("Failed to clear storage while dropping queue", map[string]any{
+			"error": err.Error(),
+		})
+	}
+	return dropped
+}
+
+// RotateStorage clears storage and rewrites it from the in-memory queue,
+// discarding stale persisted data.
+func (d *Dispatcher) RotateStorage() error {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.reloadSpilled()
+	events := d.queue.ToSlice()
+	if d.atLeastOnce() {
+		d.journalMu.Lock()
+		events = append([]Event(nil), d.journal...)
+		d.journalMu.Unlock()
+	}
+	if err := d.clearStorage(); err != nil {
+		return fmt.Errorf("failed to clear storage: %w", err)
+	}
+	if err := d.saveEvents(events); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+	return nil
+}
+
+// spillToStorage persists the in-memory queue and trims it, so that events
+// survive on disk rather than holding memory. New events go straight to
+// storage until the next flush. reason is logged to explain the spill.
+func (d *Dispatcher) spillToStorage(reason string) {
+	if d.atLeastOnce() {
+		// Spilling would overwrite the journal with the in-memory queue.
+		return
+	}
+
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	if d.disposed || d.spilled {
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+
+	events := d.queue.drainTo(nil)
+	if len(events) == 0 {
+		return
+	}
+
+	if err := d.saveEvents(events); err != nil {
+		d.queue.pushFront(events, 0)
+		d.logStorageError("Failed to spill events to storage", err, map[string]any{
+			"queueSize": len(events),
+			"reason":    reason,
+		})
+		return
+	}
+
+	d.mu.Lock()
+	d.spilled = true
+	d.mu.Unlock()
+	d.loggerAdapter.Warn("Spilled queue to storage", map[string]any{
+		"eventsCount": len(events),
+		"reason":      reason,
+	})
+}
+
+// enqueueToStorage appends an event directly to storage while spilled.
+// Returns false if storage could not be used, or the spilled events have
+// been reloaded meanwhile, and the event should be queued in memory instead.
+func (d *Dispatcher) enqueueToStorage(event Event) bool {
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	spilled := d.spilled
+	d.mu.Unlock()
+	if !spilled {
+		return false
+	}
+
+	stored, err := d.storageAdapter.Load()
+	if err != nil {
+		d.loggerAdapter.Error("Failed to load spilled events", map[string]any{"error": err.Error()})
+		return false
+	}
+
+	if err := d.saveEvents(d.evictOverLimit(append(stored, event))); err != nil {
+		d.logStorageError("Failed to persist spilled event", err, nil)
+		return false
+	}
+	d.saveSequence()
+	return true
+}
+
+// requeueToStorage puts the events of a failed batch back in storage ahead
+// of the spilled events, which were tracked after them. Returns false if not
+// spilled or storage could not be used, and the events should be re-queued
+// in memory instead.
+func (d *Dispatcher) requeueToStorage(events []Event) bool {
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	spilled := d.spilled
+	d.mu.Unlock()
+	if !spilled {
+		return false
+	}
+
+	stored, err := d.storageAdapter.Load()
+	if err != nil {
+		d.loggerAdapter.Error("Failed to load spilled events", map[string]any{"error": err.Error()})
+		return false
+	}
+
+	requeued := append(slices.Clone(events), stored...)
+	if err := d.saveEvents(d.evictOverLimit(requeued)); err != nil {
+		d.logStorageError("Failed to persist events after requeue", err, nil)
+		return false
+	}
+	d.saveSequence()
+	return true
+}
+
+// reloadSpilled moves spilled events from storage back into the queue,
+// merged with the events queued in memory meanwhile by the time they were
+// issued.
+func (d *Dispatcher) reloadSpilled() {
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	if !d.spilled {
+		d.mu.Unlock()
+		return
+	}
+	d.spilled = false
+	d.mu.Unlock()
+
+	stored, err := d.storageAdapter.Load()
+	if err != nil {
+		d.loggerAdapter.Error("Failed to reload spilled events", map[string]any{"error": err.Error()})
+		return
+	}
+	d.queue.LoadFromSlice(d.evictOverLimit(mergeByIssuedAt(stored, d.queue.ToSlice())))
+}
+
+// mergeByIssuedAt merges two queues, each in enqueue order, into one ordered
+// by IssuedAt. On a tie the event from first goes first.
+func mergeByIssuedAt(first, second []Event) []Event {
+	merged := make([]Event, 0, len(first)+len(second))
+	for len(first) > 0 && len(second) > 0 {
+		if second[0].IssuedAt < first[0].IssuedAt {
+			merged = append(merged, second[0])
+			second = second[1:]
+		} else {
+			merged = append(merged, first[0])
+			first = first[1:]
+		}
+	}
+	merged = append(merged, first...)
+	return append(merged, second...)
+}
+
+// evictOverLimit applies applyQueueLimit to events and resolves the acks of
+// the events it evicts.
+func (d *Dispatcher) evictOverLimit(events []Event) []Event {
+	kept := d.applyQueueLimit(events)
+	if len(kept) < len(events) {
+		d.acks.resolve(d.evicted(events, kept), discarded("buffer full"))
+	}
+	return kept
 }
 
 // applyQueueLimit applies the maxBufferSize limit using FIFO eviction.
+// Audit events are never evicted, so the result may exceed the limit.
 func (d *Dispatcher) applyQueueLimit(events []Event) []Event {
-	if d.config.MaxBufferSize > 0 && len(events) > d.config.MaxBufferSize {
-		return events[len(events)-d.config.MaxBufferSize:]
+	if d.config.MaxBufferSize <= 0 || len(events) <= d.config.MaxBufferSize {
+		return events
+	}
+	excess := len(events) - d.config.MaxBufferSize
+	if !d.audit.enabled() {
+		return events[excess:]
 	}
-	return events
+	kept := make([]Event, 0, d.config.MaxBufferSize)
+	for i, event := range events {
+		if excess == 0 {
+			return append(kept, events[i:]...)
+		}
+		if d.audit.contains(event) {
+			kept = append(kept, event)
+		} else {
+			excess--
+		}
+	}
+	return kept
+}
+
+// evicted returns the events of all that applyQueueLimit left out of kept.
+func (d *Dispatcher) evicted(all, kept []Event) []Event {
+	if !d.audit.enabled() {
+		return all[:len(all)-len(kept)]
+	}
+	dropped := make([]Event, 0, len(all)-len(kept))
+	i := 0
+	for _, event := range all {
+		if i < len(kept) && kept[i].ID == event.ID {
+			i++
+			continue
+		}
+		dropped = append(dropped, event)
+	}
+	return dropped
+}
+
+// Latency returns end-to-end delivery latency observed so far.
+func (d *Dispatcher) Latency() LatencyStats {
+	return d.latency.snapshot()
+}
+
+// Stats returns a snapshot of dispatcher activity.
+func (d *Dispatcher) Stats() Stats {
+	stats := d.stats.snapshot()
+	stats.QueueLength = d.queue.Len()
+	stats.HeldEvents = d.heldCount()
+	stats.PausedFor = d.backoffRemaining()
+	stats.PendingRetries = len(d.retryEvents())
+	stats.BatchSize = d.batchSize()
+	stats.Latency = d.latency.snapshot()
+	if d.pool != nil {
+		stats.Endpoints = d.pool.Health()
+	}
+	return stats
+}
+
+// clearStorage clears persisted events and resets the tracked storage size.
+func (d *Dispatcher) clearStorage() error {
+	if err := d.storageAdapter.Clear(); err != nil {
+		return err
+	}
+	// Events queued since the last checkpoint are no longer in storage.
+	d.mu.Lock()
+	d.dirty = true
+	d.mu.Unlock()
+	d.stats.setStorageSize(0)
+	d.saveSequence()
+	return nil
 }
 
 // sendWithRetry sends events with exponential backoff retry logic.
 // Note: This method never logs headers to prevent API key exposure.
-func (d *Dispatcher) sendWithRetry(ctx context.Context, events []Event, attempt int) {
-	resp, err := d.httpAdapter.SendWithContext(ctx, d.config.Endpoint, events, d.headers)
+func (d *Dispatcher) sendWithRetry(ctx context.Context, events []Event, batchID string, attempt int) {
+	sentAt := d.clock.Now()
+	spanCtx, span := d.tracer.Start(ctx, sendSpanName)
+	span.SetAttribute("batch.size", len(events))
+	span.SetAttribute("retry.attempt", attempt)
+	span.SetAttribute("batch.id", batchID)
+	endpoint, headers := d.route(events, sentAt, batchID)
+	resp, err := d.send(spanCtx, Batch{
+		ID:       batchID,
+		Endpoint: endpoint,
+		Events:   events,
+		Headers:  headers,
+		Attempt:  attempt,
+		SentAt:   sentAt,
+	})
+	d.recordEndpointResult(endpoint, resp, err)
+	d.adaptBatchSize(len(events), d.clock.Now().Sub(sentAt), resp, err)
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		span.SetAttribute("http.status", resp.Status)
+		if resp.Status >= 400 {
+			span.RecordError(attemptHTTPError(resp, endpoint, attempt))
+		}
+	}
+	span.End()
+
+	if err != nil {
+		d.handleNetworkError(ctx, err, events, batchID, attempt)
+	} else {
+		d.handleResponse(ctx, resp, endpoint, events, batchID, attempt, sentAt)
+	}
+}
+
+// endpoint returns the endpoint the next batch should be sent to.
+func (d *Dispatcher) endpoint() string {
+	if d.pool != nil {
+		return d.pool.Pick()
+	}
+	if d.selector != nil {
+		return d.selector.Current()
+	}
+	return d.config.Endpoint
+}
+
+// recordEndpointResult updates endpoint pool health after an attempt. Network
+// errors and 5xx responses count as failures; any other response shows the
+// endpoint is reachable.
+func (d *Dispatcher) recordEndpointResult(endpoint string, resp *HTTPResponse, err error) {
+	if d.pool == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	switch {
+	case err != nil:
+		d.pool.ReportFailure(endpoint, err)
+	case resp.Status >= 500:
+		d.pool.ReportFailure(endpoint, newHTTPError(resp))
+	default:
+		d.pool.ReportSuccess(endpoint)
+	}
+}
 
+// reportEndpointFailure lets the endpoint selector fail over after a batch
+// exhausted its retries against the current endpoint.
+func (d *Dispatcher) reportEndpointFailure() {
+	if d.selector != nil {
+		d.selector.ReportFailure(d.selector.Current())
+	}
+}
+
+// probeEndpoint measures round-trip latency by sending an empty batch.
+// Any response below 500 counts as healthy.
+func (d *Dispatcher) probeEndpoint(ctx context.Context, endpoint string) (time.Duration, error) {
+	start := d.clock.Now()
+	resp, err := d.httpAdapter.SendWithContext(ctx, endpoint, []Event{}, d.headers)
 	if err != nil {
-		d.handleNetworkError(ctx, err, events, attempt)
+		return 0, err
+	}
+	if resp == nil {
+		return 0, errNilHTTPResponse
+	}
+	if resp.Status >= 500 {
+		return 0, newHTTPError(resp)
+	}
+	return d.clock.Now().Sub(start), nil
+}
+
+// send delivers a batch through the HTTP adapter, using SendBatch when the
+// adapter implements BatchSender. A nil response is reported as an error.
+func (d *Dispatcher) send(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	if d.config.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.config.RequestTimeout)
+		defer cancel()
+	}
+
+	resp, err := d.sendAuthorized(ctx, batch)
+	if err == nil && resp.Backoff > 0 {
+		d.applyServerBackoff(batch.Endpoint, resp)
+	}
+	return resp, err
+}
+
+// sendAuthorized adds provided headers and the AuthProvider's token to a
+// batch and sends it, resending once with a fresh token after a 401.
+func (d *Dispatcher) sendAuthorized(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	if d.config.HeaderProvider != nil {
+		d.addProvidedHeaders(ctx, batch.Headers)
+	}
+	if d.config.AuthProvider == nil {
+		return d.sendOnce(ctx, batch)
+	}
+	if err := d.authorize(ctx, batch.Headers); err != nil {
+		return nil, err
+	}
+	resp, err := d.sendOnce(ctx, batch)
+	if err != nil || resp.Status != http.StatusUnauthorized || resp.Backoff > 0 {
+		return resp, err
+	}
+	if retry, authErr := d.reauthorize(ctx, batch.Headers); !retry {
+		return resp, nil
+	} else if authErr != nil {
+		return nil, authErr
+	}
+	return d.sendOnce(ctx, batch)
+}
+
+// sendOnce makes a single send through the HTTP adapter.
+func (d *Dispatcher) sendOnce(ctx context.Context, batch Batch) (*HTTPResponse, error) {
+	var resp *HTTPResponse
+	var err error
+	if d.copyEvents {
+		batch.Events = slices.Clone(batch.Events)
+	}
+	if sender, ok := d.httpAdapter.(BatchSender); ok {
+		resp, err = sender.SendBatch(ctx, batch)
 	} else {
-		d.handleResponse(ctx, resp, events, attempt)
+		resp, err = d.httpAdapter.SendWithContext(ctx, batch.Endpoint, batch.Events, batch.Headers)
+	}
+	if err == nil && resp == nil {
+		err = errNilHTTPResponse
+	}
+	return resp, err
+}
+
+// attemptHeaders returns the static headers plus the batch's BatchIDHeader
+// and a per-attempt SentAtHeader.
+func (d *Dispatcher) attemptHeaders(sentAt time.Time, batchID string) map[string]string {
+	headers := make(map[string]string, len(d.headers)+2)
+	for k, v := range d.headers {
+		headers[k] = v
 	}
+	headers[SentAtHeader] = strconv.FormatInt(sentAt.UnixMilli(), 10)
+	headers[BatchIDHeader] = batchID
+	return headers
 }
 
-func (d *Dispatcher) handleResponse(ctx context.Context, resp *HTTPResponse, events []Event, attempt int) {
+func (d *Dispatcher) handleResponse(ctx context.Context, resp *HTTPResponse, endpoint string, events []Event, batchID string, attempt int, sentAt time.Time) {
+	if d.handlePartialRejection(resp, endpoint, events, attempt, sentAt) {
+		return
+	}
 	if resp.Status >= 200 && resp.Status < 300 {
-		if err := d.storageAdapter.Clear(); err != nil {
+		d.latency.record(events, sentAt)
+		d.batchDelivered(events)
+		if err := d.releaseStored(events); err != nil {
 			d.loggerAdapter.Error("Failed to clear storage after successful send", map[string]any{
 				"error": err.Error(),
 			})
 		}
+	} else if resp.Backoff > 0 {
+		d.loggerAdapter.Warn("Server requested backoff, re-queueing events", map[string]any{
+			"status":      resp.Status,
+			"eventsCount": len(events),
+		})
+		d.requeueFailed(events)
+		if !d.deferForBackoff() {
+			d.scheduleFlush()
+		}
+	} else if d.tooLargeForBatch(resp.Status, events) {
+		d.loggerAdapter.Warn("Batch too large, re-queueing events in smaller batches", map[string]any{
+			"eventsCount": len(events),
+			"batchSize":   d.batchSize(),
+		})
+		d.requeueFailed(events)
+		d.scheduleFlush()
 	} else if resp.Status >= 400 && resp.Status < 500 {
+		httpErr := attemptHTTPError(resp, endpoint, attempt)
 		d.loggerAdapter.Warn("4xx client error, dropping events", map[string]any{
 			"status":      resp.Status,
 			"eventsCount": len(events),
+			"error":       httpErr.Error(),
 		})
-		if err := d.storageAdapter.Clear(); err != nil {
+		d.batchDropped(events, httpErr)
+		if err := d.releaseStored(events); err != nil {
 			d.loggerAdapter.Error("Failed to clear storage after 4xx error", map[string]any{
 				"error": err.Error(),
 			})
 		}
 	} else if resp.Status >= 500 {
-		d.handleServerError(ctx, resp.Status, events, attempt)
+		d.handleServerError(ctx, attemptHTTPError(resp, endpoint, attempt), events, batchID, attempt)
 	} else {
+		httpErr := attemptHTTPError(resp, endpoint, attempt)
 		d.loggerAdapter.Warn("Unexpected status code, dropping events", map[string]any{
 			"status":      resp.Status,
 			"eventsCount": len(events),
+			"error":       httpErr.Error(),
 		})
-		if err := d.storageAdapter.Clear(); err != nil {
+		d.batchDropped(events, httpErr)
+		if err := d.releaseStored(events); err != nil {
 			d.loggerAdapter.Error("Failed to clear storage after unexpected status", map[string]any{
 				"error": err.Error(),
 			})
@@ -199,29 +1044,39 @@ func (d *Dispatcher) handleResponse(ctx context.Context, resp *HTTPResponse, eve
 	}
 }
 
-func (d *Dispatcher) handleServerError(ctx context.Context, status int, events []Event, attempt int) {
+func (d *Dispatcher) handleServerError(ctx context.Context, httpErr *HTTPError, events []Event, batchID string, attempt int) {
 	if attempt < d.config.MaxRetries {
 		d.loggerAdapter.Warn("5xx server error, retrying", map[string]any{
-			"status":     status,
+			"status":     httpErr.Status,
 			"attempt":    attempt + 1,
 			"maxRetries": d.config.MaxRetries,
+			"error":      httpErr.Error(),
 		})
 
-		if !d.delay(ctx, d.calculateBackoff(attempt)) {
+		if d.config.RetryMode == RetryScheduled {
+			d.scheduleRetry(events, batchID, attempt+1, d.backoff.NextDelay(attempt))
+			return
+		}
+		if !d.delay(ctx, d.backoff.NextDelay(attempt)) {
+			d.notifyDelivery(events, ctx.Err())
+			d.requeueIfActive(events)
 			return
 		}
-		d.sendWithRetry(ctx, events, attempt+1)
+		d.sendWithRetry(ctx, events, batchID, attempt+1)
 	} else {
 		d.loggerAdapter.Error("5xx server error, max retries reached", map[string]any{
-			"status":      status,
+			"status":      httpErr.Status,
 			"maxRetries":  d.config.MaxRetries,
 			"eventsCount": len(events),
+			"error":       httpErr.Error(),
 		})
-		d.requeueEvents(events)
+		d.batchFailed(events, httpErr)
+		d.reportEndpointFailure()
+		d.requeueFailed(events)
 	}
 }
 
-func (d *Dispatcher) handleNetworkError(ctx context.Context, err error, events []Event, attempt int) {
+func (d *Dispatcher) handleNetworkError(ctx context.Context, err error, events []Event, batchID string, attempt int) {
 	d.loggerAdapter.Error("Network error occurred", map[string]any{"error": err.Error()})
 
 	if attempt < d.config.MaxRetries {
@@ -231,34 +1086,181 @@ func (d *Dispatcher) handleNetworkError(ctx context.Context, err error, events [
 			"error":      err.Error(),
 		})
 
-		if !d.delay(ctx, d.calculateBackoff(attempt)) {
+		if d.config.RetryMode == RetryScheduled {
+			d.scheduleRetry(events, batchID, attempt+1, d.backoff.NextDelay(attempt))
 			return
 		}
-		d.sendWithRetry(ctx, events, attempt+1)
+		if !d.delay(ctx, d.backoff.NextDelay(attempt)) {
+			d.notifyDelivery(events, ctx.Err())
+			d.requeueIfActive(events)
+			return
+		}
+		d.sendWithRetry(ctx, events, batchID, attempt+1)
 	} else {
 		d.loggerAdapter.Error("Network error, max retries reached", map[string]any{
 			"maxRetries":  d.config.MaxRetries,
 			"eventsCount": len(events),
 			"error":       err.Error(),
 		})
-		d.requeueEvents(events)
+		d.batchFailed(events, err)
+		d.reportEndpointFailure()
+		d.requeueFailed(events)
+	}
+}
+
+// batchDelivered records a successfully delivered batch.
+func (d *Dispatcher) batchDelivered(events []Event) {
+	d.stats.batchSent(len(events), d.clock.Now())
+	if d.dedup != nil {
+		d.dedup.record(events)
 	}
+	d.notifyDelivery(events, nil)
+	d.acks.resolve(events, nil)
 }
 
+// batchFailed records a batch that was dropped or gave up retrying.
+func (d *Dispatcher) batchFailed(events []Event, err error) {
+	d.stats.batchFailed(err)
+	d.notifyDelivery(events, err)
+}
+
+// batchDropped records a failed batch whose events will not be resent.
+func (d *Dispatcher) batchDropped(events []Event, err error) {
+	d.batchFailed(events, err)
+	d.acks.resolve(events, err)
+}
+
+// notifyDelivery invokes the OnDelivery callback, if configured.
+func (d *Dispatcher) notifyDelivery(events []Event, err error) {
+	if d.config.OnDelivery != nil {
+		d.config.OnDelivery(slices.Clone(events), err)
+	}
+}
+
+// requeueIfActive re-queues events interrupted by a cancelled flush, unless
+// the cancellation came from Dispose.
+func (d *Dispatcher) requeueIfActive(events []Event) {
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if !disposed && len(events) > 0 {
+		d.requeueFailed(events)
+	}
+}
+
+// requeueFailed re-queues the events of a failed batch. During a flush they
+// are collected instead and re-queued together once the flush ends, so that
+// several failed batches keep their original order.
+func (d *Dispatcher) requeueFailed(events []Event) {
+	if d.flushRequeue != nil {
+		*d.flushRequeue = append(*d.flushRequeue, events...)
+		return
+	}
+	d.requeueEvents(events)
+}
+
+// requeueDeferred re-queues the events collected by requeueFailed during a
+// flush, ahead of events tracked since the flush started.
+func (d *Dispatcher) requeueDeferred(deferred *[]Event) {
+	d.flushRequeue = nil
+	d.requeueIfActive(*deferred)
+}
+
+// requeueEvents puts events back at the front of the queue, ahead of events
+// tracked since they were taken, and checkpoints the queue. While spilled,
+// they go back to storage ahead of the spilled events instead.
 func (d *Dispatcher) requeueEvents(events []Event) {
-	currentQueue := d.queue.ToSlice()
-	events = append(events, currentQueue...)
-	limited := d.applyQueueLimit(events)
-	d.queue.Clear()
-	d.queue.LoadFromSlice(limited)
+	if d.requeueToStorage(events) {
+		return
+	}
+	if d.audit.enabled() {
+		// Evict with applyQueueLimit so audit events are kept.
+		queued := d.queue.pushFront(events, 0)
+		limited := d.applyQueueLimit(queued)
+		if len(limited) < len(queued) {
+			d.queue.Clear()
+			d.queue.LoadFromSlice(limited)
+		}
+		d.checkpoint(PersistTriggerFailure, limited, 0)
+		return
+	}
+	limited := d.queue.pushFront(events, d.config.MaxBufferSize)
+	d.checkpoint(PersistTriggerFailure, limited, 0)
+}
 
-	if err := d.storageAdapter.Save(limited); err != nil {
-		d.logStorageError("Failed to persist events after requeue", err, nil)
+// checkpoint saves events to storage if the persistence policy allows it for
+// the given trigger. added is the number of newly enqueued events.
+// Returns true if the events were saved.
+func (d *Dispatcher) checkpoint(trigger PersistTrigger, events []Event, added int) bool {
+	if d.atLeastOnce() {
+		// The journal already holds every undelivered event.
+		return false
+	}
+
+	d.storageMu.Lock()
+	defer d.storageMu.Unlock()
+
+	d.mu.Lock()
+	d.pendingPersist += added
+	d.dirty = true
+	if d.spilled {
+		// Storage holds the spilled events, which events would overwrite;
+		// they are merged back into the queue on the next flush.
+		d.mu.Unlock()
+		return false
 	}
+	state := PersistState{PendingEvents: d.pendingPersist, LastPersist: d.lastPersist, Now: d.clock.Now()}
+	d.mu.Unlock()
+
+	if !d.persistence.ShouldPersist(trigger, state) {
+		return false
+	}
+
+	if err := d.saveEvents(events); err != nil {
+		switch trigger {
+		case PersistTriggerFailure:
+			d.logStorageError("Failed to persist events after requeue", err, nil)
+		case PersistTriggerShutdown:
+			d.logStorageError("Failed to persist events on shutdown", err, nil)
+		default:
+			d.logStorageError("Failed to persist events to storage", err, map[string]any{
+				"queueSize": len(events),
+			})
+		}
+		return false
+	}
+
+	d.persisted()
+	return true
+}
+
+// persisted records a successful checkpoint of the queue.
+func (d *Dispatcher) persisted() {
+	d.mu.Lock()
+	d.pendingPersist = 0
+	d.lastPersist = d.clock.Now()
+	d.dirty = false
+	d.mu.Unlock()
+	d.saveSequence()
 }
 
 // scheduleFlush schedules a one-shot flush after the configured interval.
 func (d *Dispatcher) scheduleFlush() {
+	if d.scheduler == nil {
+		d.scheduleFlushIn(d.flushDelay())
+		return
+	}
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if !disposed {
+		d.scheduler.schedule(d, d.flushDelay())
+	}
+}
+
+// scheduleFlushIn schedules a flush after delay, unless one is already
+// scheduled.
+func (d *Dispatcher) scheduleFlushIn(delay time.Duration) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
@@ -266,15 +1268,30 @@ func (d *Dispatcher) scheduleFlush() {
 		return
 	}
 
-	d.timer = time.AfterFunc(d.config.FlushInterval, func() {
+	d.timer = d.clock.AfterFunc(delay, d.resources.wrap(func() {
 		d.mu.Lock()
 		d.timer = nil
 		d.mu.Unlock()
 		d.Flush()
-	})
+	}))
+}
+
+// flushDelay returns FlushInterval, randomized by up to FlushIntervalJitter
+// of its length in either direction.
+func (d *Dispatcher) flushDelay() time.Duration {
+	interval := d.config.FlushInterval
+	if d.config.FlushIntervalJitter <= 0 {
+		return interval
+	}
+	offset := (rand.Float64()*2 - 1) * d.config.FlushIntervalJitter
+	return interval + time.Duration(float64(interval)*offset)
 }
 
 func (d *Dispatcher) stopTimer() {
+	if d.scheduler != nil {
+		d.scheduler.cancel(d)
+	}
+
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
@@ -284,41 +1301,26 @@ func (d *Dispatcher) stopTimer() {
 	}
 }
 
-// logStorageError logs storage errors, using warn level for StorageQuotaExceededError.
+// logStorageError logs storage errors, using warn level for
+// StorageQuotaExceededError and StorageCorruptedError, which the dispatcher
+// recovers from.
 func (d *Dispatcher) logStorageError(message string, err error, extra map[string]any) {
 	args := map[string]any{"error": err.Error()}
 	for k, v := range extra {
 		args[k] = v
 	}
+	d.stats.storageFailed(err)
 	var quotaErr *StorageQuotaExceededError
-	if errors.As(err, &quotaErr) {
+	var corruptedErr *StorageCorruptedError
+	if errors.As(err, &quotaErr) || errors.As(err, &corruptedErr) {
 		d.loggerAdapter.Warn(message, args)
 	} else {
 		d.loggerAdapter.Error(message, args)
 	}
 }
 
-// calculateBackoff computes exponential backoff with jitter.
-// Formula: (2^attempt seconds) + random jitter, capped at 30s.
-// Example progression: 1s, 2s, 4s, 8s, 16s, 30s (capped).
-func (d *Dispatcher) calculateBackoff(attempt int) time.Duration {
-	// Exponential backoff: 2^attempt seconds
-	backoff := time.Duration(1<<attempt) * time.Second
-	if backoff > maxBackoffDuration {
-		backoff = maxBackoffDuration
-	}
-	// Add random jitter (0-1000ms) to prevent thundering herd
-	jitter := time.Duration(rand.Intn(maxJitterMs)) * time.Millisecond
-	return backoff + jitter
-}
-
 // delay waits for the given duration or until context is cancelled.
 // Returns true if the delay completed, false if cancelled.
 func (d *Dispatcher) delay(ctx context.Context, duration time.Duration) bool {
-	select {
-	case <-time.After(duration):
-		return true
-	case <-ctx.Done():
-		return false
-	}
+	return d.clock.Sleep(ctx, duration)
 }
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
 )
 
 type mockLogger struct {
@@ -52,6 +57,7 @@ type mockHTTPAdapter struct {
 	err          error
 	statusCode   int
 	networkError bool
+	lastHeaders  map[string]string
 }
 
 func (m *mockHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
@@ -61,6 +67,7 @@ func (m *mockHTTPAdapter) Send(endpoint string, events []Event, headers map[stri
 func (m *mockHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
 	m.mu.Lock()
 	m.calls++
+	m.lastHeaders = headers
 	fail := m.fail
 	err := m.err
 	statusCode := m.statusCode
@@ -93,6 +100,12 @@ func (m *mockHTTPAdapter) getCalls() int {
 	return m.calls
 }
 
+func (m *mockHTTPAdapter) getLastHeaders() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastHeaders
+}
+
 type mockStorageAdapter struct {
 	mu         sync.Mutex
 	saved      []Event
@@ -149,15 +162,21 @@ func (m *mockStorageAdapter) getSaved() []Event {
 	return result
 }
 
-func newTestDispatcher(httpAdapter *mockHTTPAdapter, storageAdapter *mockStorageAdapter) *Dispatcher {
-	return NewDispatcher(DispatcherConfig{
+// newTestDispatcher creates a dispatcher with a test configuration, adjusted
+// by options in order.
+func newTestDispatcher(httpAdapter HTTPAdapter, storageAdapter StorageAdapter, options ...func(*DispatcherConfig)) *Dispatcher {
+	config := DispatcherConfig{
 		APIKey:        "test-key",
 		APIKeyHeader:  "X-API-Key",
 		Endpoint:      "http://test.com",
 		FlushInterval: 10 * time.Second,
 		MaxBatchSize:  10,
 		MaxRetries:    3,
-	}, httpAdapter, storageAdapter, &mockLogger{})
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	return NewDispatcher(config, httpAdapter, storageAdapter, &mockLogger{})
 }
 
 func TestDispatcher_Enqueue(t *testing.T) {
@@ -632,7 +651,7 @@ func TestDispatcher_HandleUnexpectedStatusCode(t *testing.T) {
 	}
 }
 
-func TestDispatcher_CalculateBackoffCap(t *testing.T) {
+func TestDispatcher_DefaultBackoffCap(t *testing.T) {
 	d := NewDispatcher(DispatcherConfig{
 		APIKey:        "test-key",
 		APIKeyHeader:  "X-API-Key",
@@ -643,7 +662,7 @@ func TestDispatcher_CalculateBackoffCap(t *testing.T) {
 	}, &mockHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
 
 	// High attempt should cap at 30s
-	backoff := d.calculateBackoff(10)
+	backoff := d.backoff.NextDelay(10)
 	if backoff > 31*time.Second { // 30s + max jitter (1s)
 		t.Errorf("expected backoff <= 31s, got %v", backoff)
 	}
@@ -823,6 +842,55 @@ func TestDispatcher_RequeueWithStorageError(t *testing.T) {
 	}
 }
 
+func TestDispatcher_RequeueKeepsOrder(t *testing.T) {
+	t.Run("should re-queue failed batches in their original order", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 500, Times: 2},
+			adapters.Scenario{Status: 200},
+		)
+		storage := &mockStorageAdapter{loaded: []Event{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+		d := newTestDispatcher(httpAdapter, storage, withOrdering(OrderingNone))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Flush()
+
+		if got := queuedNames(d); !reflect.DeepEqual(got, []string{"a", "b"}) {
+			t.Fatalf("expected queue [a b], got %v", got)
+		}
+	})
+
+	t.Run("should not lose events tracked while re-queueing", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  1000,
+			MaxRetries:    0,
+		}, &mockHTTPAdapter{fail: true, statusCode: 500}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				d.Enqueue(Event{Name: "e"})
+			}
+		}()
+		for i := 0; i < 50; i++ {
+			d.Flush()
+		}
+		wg.Wait()
+
+		if d.queue.Len() != 200 {
+			t.Fatalf("expected 200 queued events, got %d", d.queue.Len())
+		}
+	})
+}
+
 func TestDispatcher_LogStorageErrorWithQuotaExceeded(t *testing.T) {
 	httpAdapter := &mockHTTPAdapter{}
 	storageAdapter := &mockStorageAdapter{err: &StorageQuotaExceededError{Message: "quota exceeded"}}
@@ -844,6 +912,27 @@ func TestDispatcher_LogStorageErrorWithQuotaExceeded(t *testing.T) {
 	}
 }
 
+func TestDispatcher_RestoreFromQuarantinedStorage(t *testing.T) {
+	storageAdapter := &mockStorageAdapter{err: &StorageCorruptedError{Path: "events.json", QuarantinePath: "events.json.1.corrupt"}}
+	logger := &mockLogger{}
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:        "test-key",
+		APIKeyHeader:  "X-API-Key",
+		Endpoint:      "http://test.com",
+		FlushInterval: 10 * time.Second,
+		MaxBatchSize:  10,
+		MaxRetries:    3,
+	}, &mockHTTPAdapter{}, storageAdapter, logger)
+
+	d.Restore()
+	defer d.Dispose()
+
+	// Quarantined storage is recovered from, so it is logged as a warning
+	if logger.warnCount == 0 || logger.errCount != 0 {
+		t.Errorf("expected a warning and no errors, got %d warnings and %d errors", logger.warnCount, logger.errCount)
+	}
+}
+
 func TestDispatcher_CloseStorageOnDispose(t *testing.T) {
 	t.Run("should call Close on storage adapter", func(t *testing.T) {
 		httpAdapter := &mockHTTPAdapter{}
@@ -891,3 +980,401 @@ func TestDispatcher_CloseStorageOnDispose(t *testing.T) {
 		}
 	})
 }
+
+func TestDispatcher_SentAtHeader(t *testing.T) {
+	httpAdapter := &mockHTTPAdapter{}
+	d := newTestDispatcher(httpAdapter, &mockStorageAdapter{})
+
+	d.Restore()
+	defer d.Dispose()
+
+	before := time.Now().UnixMilli()
+	d.Enqueue(Event{Name: "test", IssuedAt: before})
+	d.Flush()
+	after := time.Now().UnixMilli()
+
+	headers := httpAdapter.getLastHeaders()
+	sentAt, err := strconv.ParseInt(headers[SentAtHeader], 10, 64)
+	if err != nil {
+		t.Fatalf("expected numeric %s header, got %q", SentAtHeader, headers[SentAtHeader])
+	}
+	if sentAt < before || sentAt > after {
+		t.Errorf("expected sentAt within [%d, %d], got %d", before, after, sentAt)
+	}
+	if headers["X-API-Key"] != "test-key" {
+		t.Error("expected API key header to be preserved")
+	}
+	if _, ok := d.headers[SentAtHeader]; ok {
+		t.Error("expected static headers not to be mutated")
+	}
+}
+
+func TestDispatcher_Latency(t *testing.T) {
+	t.Run("records latency on success", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{})
+		d.Restore()
+		defer d.Dispose()
+
+		issuedAt := time.Now().Add(-2 * time.Second).UnixMilli()
+		d.Enqueue(Event{Name: "a", IssuedAt: issuedAt})
+		d.Enqueue(Event{Name: "b", IssuedAt: issuedAt})
+		d.Flush()
+
+		stats := d.Latency()
+		if stats.Count != 2 {
+			t.Fatalf("expected 2 recorded events, got %d", stats.Count)
+		}
+		if stats.Average < 2*time.Second || stats.Max < 2*time.Second {
+			t.Errorf("expected latency >= 2s, got avg=%v max=%v", stats.Average, stats.Max)
+		}
+	})
+
+	t.Run("does not record failed deliveries", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{fail: true, statusCode: 400}, &mockStorageAdapter{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a", IssuedAt: time.Now().UnixMilli()})
+		d.Flush()
+
+		if stats := d.Latency(); stats.Count != 0 {
+			t.Fatalf("expected no recorded latency, got %d", stats.Count)
+		}
+	})
+}
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	errs       []error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+
+func (s *recordedSpan) End() { s.ended = true }
+
+type mockTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (m *mockTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	span := &recordedSpan{name: name, attributes: map[string]any{}}
+	m.spans = append(m.spans, span)
+	return ctx, span
+}
+
+func (m *mockTracer) spansNamed(name string) []*recordedSpan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*recordedSpan
+	for _, span := range m.spans {
+		if span.name == name {
+			result = append(result, span)
+		}
+	}
+	return result
+}
+
+func TestDispatcher_Tracing(t *testing.T) {
+	t.Run("emits flush and send spans", func(t *testing.T) {
+		tracer := &mockTracer{}
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:         "test-key",
+			APIKeyHeader:   "X-API-Key",
+			Endpoint:       "http://test.com",
+			FlushInterval:  10 * time.Second,
+			MaxBatchSize:   2,
+			MaxRetries:     3,
+			TracerProvider: tracer,
+		}, &mockHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.LoadFromSlice([]Event{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+		d.Flush()
+
+		flushSpans := tracer.spansNamed(flushSpanName)
+		if len(flushSpans) != 1 {
+			t.Fatalf("expected 1 flush span, got %d", len(flushSpans))
+		}
+		if flushSpans[0].attributes["events.count"] != 3 || !flushSpans[0].ended {
+			t.Errorf("unexpected flush span: %+v", flushSpans[0])
+		}
+
+		sendSpans := tracer.spansNamed(sendSpanName)
+		if len(sendSpans) != 2 {
+			t.Fatalf("expected 2 send spans, got %d", len(sendSpans))
+		}
+		first := sendSpans[0]
+		if first.attributes["batch.size"] != 2 || first.attributes["retry.attempt"] != 0 || first.attributes["http.status"] != 200 {
+			t.Errorf("unexpected send span attributes: %v", first.attributes)
+		}
+		if len(first.errs) != 0 || !first.ended {
+			t.Errorf("expected ended span without errors, got %+v", first)
+		}
+	})
+
+	t.Run("records errors on failed attempts", func(t *testing.T) {
+		tracer := &mockTracer{}
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:         "test-key",
+			APIKeyHeader:   "X-API-Key",
+			Endpoint:       "http://test.com",
+			FlushInterval:  10 * time.Second,
+			MaxBatchSize:   10,
+			MaxRetries:     0,
+			TracerProvider: tracer,
+		}, &mockHTTPAdapter{err: errors.New("network error")}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		sendSpans := tracer.spansNamed(sendSpanName)
+		if len(sendSpans) != 1 || len(sendSpans[0].errs) != 1 {
+			t.Fatalf("expected one send span with a recorded error, got %+v", sendSpans)
+		}
+	})
+}
+
+func TestDispatcher_OnDelivery(t *testing.T) {
+	type delivery struct {
+		size int
+		err  error
+	}
+	newDispatcher := func(httpAdapter *mockHTTPAdapter, deliveries *[]delivery) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  2,
+			MaxRetries:    0,
+			OnDelivery: func(batch []Event, err error) {
+				*deliveries = append(*deliveries, delivery{size: len(batch), err: err})
+			},
+		}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("reports each successful batch", func(t *testing.T) {
+		var deliveries []delivery
+		d := newDispatcher(&mockHTTPAdapter{}, &deliveries)
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.LoadFromSlice([]Event{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+		d.Flush()
+
+		if len(deliveries) != 2 {
+			t.Fatalf("expected 2 deliveries, got %d", len(deliveries))
+		}
+		if deliveries[0].size != 2 || deliveries[1].size != 1 {
+			t.Errorf("unexpected batch sizes: %+v", deliveries)
+		}
+		for _, del := range deliveries {
+			if del.err != nil {
+				t.Errorf("expected nil error, got %v", del.err)
+			}
+		}
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		var deliveries []delivery
+		d := newDispatcher(&mockHTTPAdapter{err: errors.New("network error")}, &deliveries)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if len(deliveries) != 1 || deliveries[0].err == nil {
+			t.Fatalf("expected one failed delivery, got %+v", deliveries)
+		}
+	})
+
+	t.Run("reports 4xx drops", func(t *testing.T) {
+		var deliveries []delivery
+		d := newDispatcher(&mockHTTPAdapter{fail: true, statusCode: 422}, &deliveries)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		var httpErr *HTTPError
+		if len(deliveries) != 1 || !errors.As(deliveries[0].err, &httpErr) || httpErr.Status != 422 {
+			t.Fatalf("expected HTTPError 422, got %+v", deliveries)
+		}
+	})
+}
+
+type nilResponseHTTPAdapter struct{}
+
+func (nilResponseHTTPAdapter) Send(string, []Event, map[string]string) (*HTTPResponse, error) {
+	return nil, nil
+}
+
+func (nilResponseHTTPAdapter) SendWithContext(context.Context, string, []Event, map[string]string) (*HTTPResponse, error) {
+	return nil, nil
+}
+
+func TestDispatcher_NeverPanics(t *testing.T) {
+	t.Run("nil dependencies and zero config", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{Endpoint: "http://test.com"}, nil, nil, nil)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "test"})
+
+		done := make(chan struct{})
+		go func() {
+			d.config.MaxRetries = 0
+			d.Flush()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("flush did not complete with zero-value config")
+		}
+
+		if d.queue.Len() != 1 {
+			t.Fatal("expected event to be re-queued when no http adapter is configured")
+		}
+		if !errors.Is(d.Stats().LastError, errHTTPAdapterNotConfigured) {
+			t.Errorf("expected errHTTPAdapterNotConfigured, got %v", d.Stats().LastError)
+		}
+	})
+
+	t.Run("nil response from adapter", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  10,
+			MaxRetries:    0,
+		}, nilResponseHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "test"})
+		d.Flush()
+
+		if !errors.Is(d.Stats().LastError, errNilHTTPResponse) {
+			t.Errorf("expected errNilHTTPResponse, got %v", d.Stats().LastError)
+		}
+	})
+}
+
+func TestDispatcher_RequestTimeout(t *testing.T) {
+	httpAdapter := adapters.NewScriptedHTTPAdapter(
+		adapters.Scenario{Status: 200, Delay: time.Hour},
+		adapters.Scenario{Status: 200},
+	)
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:         "test-key",
+		Endpoint:       "http://test.com",
+		FlushInterval:  10 * time.Second,
+		MaxBatchSize:   10,
+		MaxRetries:     1,
+		BackoffPolicy:  ConstantBackoff(time.Millisecond),
+		RequestTimeout: 20 * time.Millisecond,
+	}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+
+	d.Enqueue(Event{Name: "test"})
+	d.Flush()
+
+	if httpAdapter.Calls() != 2 {
+		t.Fatalf("expected the timed-out attempt to be retried, got %d calls", httpAdapter.Calls())
+	}
+	if stats := d.Stats(); stats.EventsSent != 1 {
+		t.Fatalf("expected the retry to deliver the event, got %+v", stats)
+	}
+}
+
+func TestDispatcher_FlushTimeout(t *testing.T) {
+	httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+	storage := &mockStorageAdapter{}
+	logger := &mockLogger{}
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:        "test-key",
+		Endpoint:      "http://test.com",
+		FlushInterval: 10 * time.Second,
+		MaxBatchSize:  1,
+		MaxRetries:    5,
+		BackoffPolicy: ConstantBackoff(time.Hour),
+		FlushTimeout:  30 * time.Millisecond,
+	}, httpAdapter, storage, logger)
+	d.Restore()
+	defer d.Dispose()
+
+	d.queue.Enqueue(Event{Name: "a"})
+	d.queue.Enqueue(Event{Name: "b"})
+
+	start := time.Now()
+	d.Flush()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected flush to stop at the timeout, took %v", elapsed)
+	}
+
+	if httpAdapter.Calls() != 1 {
+		t.Fatalf("expected no further batches after the timeout, got %d calls", httpAdapter.Calls())
+	}
+	if d.queue.Len() != 2 {
+		t.Fatalf("expected both events re-queued, got %d", d.queue.Len())
+	}
+	if saved := storage.getSaved(); len(saved) != 2 {
+		t.Fatalf("expected re-queued events to be persisted, got %d", len(saved))
+	}
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	found := false
+	for _, warning := range logger.warnings {
+		if strings.Contains(warning, "Flush timed out") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a flush timeout warning, got %v", logger.warnings)
+	}
+}
+
+func TestDispatcher_FlushIntervalJitter(t *testing.T) {
+	t.Run("should wait exactly FlushInterval without jitter", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{})
+		if delay := d.flushDelay(); delay != d.config.FlushInterval {
+			t.Fatalf("expected %v, got %v", d.config.FlushInterval, delay)
+		}
+	})
+
+	t.Run("should randomize each delay within the jitter window", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:              "test-key",
+			Endpoint:            "http://test.com",
+			FlushInterval:       10 * time.Second,
+			FlushIntervalJitter: 0.2,
+		}, &mockHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
+
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 100; i++ {
+			delay := d.flushDelay()
+			if delay < 8*time.Second || delay > 12*time.Second {
+				t.Fatalf("expected delay within [8s, 12s], got %v", delay)
+			}
+			seen[delay] = true
+		}
+		if len(seen) < 2 {
+			t.Fatal("expected delays to vary")
+		}
+	})
+}
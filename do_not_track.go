@@ -0,0 +1,25 @@
+package ripple
+
+// SetDoNotTrack turns Do Not Track mode on or off. Turning it on purges
+// every queued, held, retrying and stored event, clears the identified
+// user, and drops every event tracked afterwards until it is turned off,
+// for example when a user withdraws consent. Unlike Disable, events
+// tracked before the switch are never delivered.
+func (c *Client) SetDoNotTrack(on bool) {
+	if !on {
+		if c.doNotTrack.Swap(false) {
+			c.loggerAdapter.Info("Do Not Track mode disabled")
+		}
+		return
+	}
+
+	c.doNotTrack.Store(true)
+	purged := c.dispatcher.DropQueue()
+	c.Reset()
+	c.loggerAdapter.Info("Do Not Track mode enabled, events purged", map[string]any{"eventsCount": purged})
+}
+
+// DoNotTrack reports whether Do Not Track mode is on.
+func (c *Client) DoNotTrack() bool {
+	return c.doNotTrack.Load()
+}
@@ -0,0 +1,204 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultEndpointFailureThreshold = 3
+	defaultEndpointCooldown         = 30 * time.Second
+)
+
+// EndpointStrategy controls how batches are spread across Endpoints.
+type EndpointStrategy int
+
+const (
+	// EndpointFailover sends every batch to the first healthy endpoint in
+	// list order, rolling over to the next one when it becomes unhealthy.
+	EndpointFailover EndpointStrategy = iota
+
+	// EndpointRoundRobin rotates batches across all healthy endpoints.
+	EndpointRoundRobin
+)
+
+// EndpointHealth is a point-in-time snapshot of one endpoint's delivery
+// health.
+type EndpointHealth struct {
+	// Endpoint is the endpoint URL.
+	Endpoint string
+
+	// Healthy is false while the endpoint is skipped after repeated failures.
+	Healthy bool
+
+	// ConsecutiveFailures is the number of failed attempts since the last
+	// success.
+	ConsecutiveFailures int
+
+	// Successes is the number of attempts answered with a non-5xx response.
+	Successes int64
+
+	// Failures is the number of attempts that hit a network error or 5xx.
+	Failures int64
+
+	// LastError is the error of the most recent failed attempt, or nil.
+	LastError error
+
+	// LastFailure is when the most recent failed attempt happened.
+	// Zero if the endpoint has never failed.
+	LastFailure time.Time
+
+	// PausedUntil is when a server-requested backoff of the endpoint ends.
+	// The endpoint is skipped until then.
+	PausedUntil time.Time
+}
+
+// endpointPool picks the endpoint for each delivery attempt from a fixed list
+// and tracks per-endpoint health. An endpoint is marked unhealthy after
+// threshold consecutive failures and is tried again once cooldown has passed.
+type endpointPool struct {
+	strategy  EndpointStrategy
+	threshold int
+	cooldown  time.Duration
+	endpoints []string
+	health    map[string]*EndpointHealth
+	next      int
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// newEndpointPool creates a pool over endpoints, all initially healthy.
+func newEndpointPool(endpoints []string, strategy EndpointStrategy, threshold int, cooldown time.Duration) *endpointPool {
+	if threshold <= 0 {
+		threshold = defaultEndpointFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultEndpointCooldown
+	}
+	health := make(map[string]*EndpointHealth, len(endpoints))
+	for _, endpoint := range endpoints {
+		health[endpoint] = &EndpointHealth{Endpoint: endpoint, Healthy: true}
+	}
+	return &endpointPool{
+		strategy:  strategy,
+		threshold: threshold,
+		cooldown:  cooldown,
+		endpoints: endpoints,
+		health:    health,
+		now:       time.Now,
+	}
+}
+
+// Pick returns the endpoint for the next delivery attempt. If every endpoint
+// is unhealthy, the one that failed longest ago is returned.
+func (p *endpointPool) Pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.endpoints)
+	start := 0
+	if p.strategy == EndpointRoundRobin {
+		start = p.next
+	}
+	for i := 0; i < n; i++ {
+		idx := (start + i) % n
+		if p.availableLocked(p.endpoints[idx]) {
+			if p.strategy == EndpointRoundRobin {
+				p.next = (idx + 1) % n
+			}
+			return p.endpoints[idx]
+		}
+	}
+
+	oldest := p.endpoints[0]
+	for _, endpoint := range p.endpoints[1:] {
+		if p.health[endpoint].LastFailure.Before(p.health[oldest].LastFailure) {
+			oldest = endpoint
+		}
+	}
+	return oldest
+}
+
+// availableLocked reports whether endpoint may be picked: it is healthy or
+// its cooldown has passed. Caller must hold p.mu.
+func (p *endpointPool) availableLocked(endpoint string) bool {
+	h := p.health[endpoint]
+	if p.now().Before(h.PausedUntil) {
+		return false
+	}
+	return h.Healthy || p.now().Sub(h.LastFailure) >= p.cooldown
+}
+
+// ReportSuccess records a successful attempt and marks endpoint healthy.
+func (p *endpointPool) ReportSuccess(endpoint string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h, ok := p.health[endpoint]
+	if !ok {
+		return
+	}
+	h.Successes++
+	h.ConsecutiveFailures = 0
+	h.Healthy = true
+}
+
+// ReportFailure records a failed attempt and marks endpoint unhealthy once it
+// reaches the failure threshold.
+func (p *endpointPool) ReportFailure(endpoint string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h, ok := p.health[endpoint]
+	if !ok {
+		return
+	}
+	h.Failures++
+	h.ConsecutiveFailures++
+	h.LastError = err
+	h.LastFailure = p.now()
+	if h.ConsecutiveFailures >= p.threshold {
+		h.Healthy = false
+	}
+}
+
+// Pause skips endpoint until the given time, as asked by its server.
+func (p *endpointPool) Pause(endpoint string, until time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if h, ok := p.health[endpoint]; ok && until.After(h.PausedUntil) {
+		h.PausedUntil = until
+	}
+}
+
+// PausedUntil returns when the first paused endpoint resumes if every
+// endpoint is paused, or the zero time if any can be picked.
+func (p *endpointPool) PausedUntil() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var earliest time.Time
+	for _, endpoint := range p.endpoints {
+		until := p.health[endpoint].PausedUntil
+		if !p.now().Before(until) {
+			return time.Time{}
+		}
+		if earliest.IsZero() || until.Before(earliest) {
+			earliest = until
+		}
+	}
+	return earliest
+}
+
+// Health returns a snapshot of every endpoint's health in list order.
+func (p *endpointPool) Health() []EndpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	health := make([]EndpointHealth, len(p.endpoints))
+	for i, endpoint := range p.endpoints {
+		health[i] = *p.health[endpoint]
+	}
+	return health
+}
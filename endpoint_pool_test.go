@@ -0,0 +1,208 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpointPool_Failover(t *testing.T) {
+	t.Run("should stay on the primary while it is healthy", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 2, time.Minute)
+		for i := 0; i < 3; i++ {
+			if got := pool.Pick(); got != "a" {
+				t.Fatalf("expected a, got %s", got)
+			}
+		}
+	})
+
+	t.Run("should roll over after the failure threshold", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 2, time.Minute)
+		pool.ReportFailure("a", errors.New("boom"))
+		if got := pool.Pick(); got != "a" {
+			t.Fatalf("expected a below threshold, got %s", got)
+		}
+		pool.ReportFailure("a", errors.New("boom"))
+		if got := pool.Pick(); got != "b" {
+			t.Fatalf("expected b after threshold, got %s", got)
+		}
+	})
+
+	t.Run("should reset consecutive failures on success", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 2, time.Minute)
+		pool.ReportFailure("a", errors.New("boom"))
+		pool.ReportSuccess("a")
+		pool.ReportFailure("a", errors.New("boom"))
+		if got := pool.Pick(); got != "a" {
+			t.Fatalf("expected a, got %s", got)
+		}
+	})
+
+	t.Run("should retry the primary after the cooldown", func(t *testing.T) {
+		now := time.Now()
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 1, time.Minute)
+		pool.now = func() time.Time { return now }
+		pool.ReportFailure("a", errors.New("boom"))
+		if got := pool.Pick(); got != "b" {
+			t.Fatalf("expected b, got %s", got)
+		}
+
+		now = now.Add(time.Minute)
+		if got := pool.Pick(); got != "a" {
+			t.Fatalf("expected a after cooldown, got %s", got)
+		}
+	})
+
+	t.Run("should skip paused endpoints until their backoff ends", func(t *testing.T) {
+		now := time.Now()
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 3, time.Minute)
+		pool.now = func() time.Time { return now }
+
+		pool.Pause("a", now.Add(10*time.Second))
+		if got := pool.Pick(); got != "b" {
+			t.Fatalf("expected b while a is paused, got %s", got)
+		}
+		if !pool.PausedUntil().IsZero() {
+			t.Fatal("expected no pool-wide pause while b is available")
+		}
+
+		pool.Pause("b", now.Add(5*time.Second))
+		if got := pool.PausedUntil(); !got.Equal(now.Add(5 * time.Second)) {
+			t.Fatalf("expected the pool to resume with b, got %v", got)
+		}
+
+		now = now.Add(10 * time.Second)
+		if got := pool.Pick(); got != "a" {
+			t.Fatalf("expected a after its backoff, got %s", got)
+		}
+	})
+
+	t.Run("should pick the endpoint that failed longest ago when all are unhealthy", func(t *testing.T) {
+		now := time.Now()
+		pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 1, time.Minute)
+		pool.now = func() time.Time { return now }
+		pool.ReportFailure("b", errors.New("boom"))
+		now = now.Add(time.Second)
+		pool.ReportFailure("a", errors.New("boom"))
+
+		if got := pool.Pick(); got != "b" {
+			t.Fatalf("expected b, got %s", got)
+		}
+	})
+}
+
+func TestEndpointPool_RoundRobin(t *testing.T) {
+	t.Run("should rotate across healthy endpoints", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b", "c"}, EndpointRoundRobin, 1, time.Minute)
+		var got []string
+		for i := 0; i < 4; i++ {
+			got = append(got, pool.Pick())
+		}
+		if want := []string{"a", "b", "c", "a"}; !slices.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("should skip unhealthy endpoints", func(t *testing.T) {
+		pool := newEndpointPool([]string{"a", "b", "c"}, EndpointRoundRobin, 1, time.Minute)
+		pool.ReportFailure("b", errors.New("boom"))
+		var got []string
+		for i := 0; i < 4; i++ {
+			got = append(got, pool.Pick())
+		}
+		if want := []string{"a", "c", "a", "c"}; !slices.Equal(got, want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestEndpointPool_Health(t *testing.T) {
+	pool := newEndpointPool([]string{"a", "b"}, EndpointFailover, 1, time.Minute)
+	failure := errors.New("boom")
+	pool.ReportSuccess("a")
+	pool.ReportFailure("b", failure)
+
+	health := pool.Health()
+	if len(health) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(health))
+	}
+	if a := health[0]; a.Endpoint != "a" || !a.Healthy || a.Successes != 1 || a.Failures != 0 {
+		t.Fatalf("unexpected health for a: %+v", a)
+	}
+	if b := health[1]; b.Endpoint != "b" || b.Healthy || b.Failures != 1 || b.ConsecutiveFailures != 1 || b.LastError != failure || b.LastFailure.IsZero() {
+		t.Fatalf("unexpected health for b: %+v", b)
+	}
+}
+
+// endpointHTTPAdapter fails every send to endpoints listed in down.
+type endpointHTTPAdapter struct {
+	mu   sync.Mutex
+	down map[string]bool
+	sent map[string]int
+}
+
+func (a *endpointHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *endpointHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sent[endpoint]++
+	if a.down[endpoint] {
+		return &HTTPResponse{Status: 503}, nil
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
+
+func TestClient_EndpointFailover(t *testing.T) {
+	t.Run("should deliver through a secondary when the primary is down", func(t *testing.T) {
+		httpAdapter := &endpointHTTPAdapter{down: map[string]bool{"http://primary": true}, sent: map[string]int{}}
+		config := createTestConfig()
+		config.Endpoint = ""
+		config.Endpoints = []string{"http://primary", "http://secondary"}
+		config.EndpointFailureThreshold = 2
+		config.MaxRetries = 3
+		config.HTTPAdapter = httpAdapter
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Flush()
+
+		stats := client.Stats()
+		if stats.EventsSent != 1 {
+			t.Fatalf("expected event delivered via secondary, got %+v", stats)
+		}
+		if httpAdapter.sent["http://primary"] != 2 || httpAdapter.sent["http://secondary"] != 1 {
+			t.Fatalf("unexpected sends: %v", httpAdapter.sent)
+		}
+		if len(stats.Endpoints) != 2 || stats.Endpoints[0].Healthy || !stats.Endpoints[1].Healthy {
+			t.Fatalf("unexpected endpoint health: %+v", stats.Endpoints)
+		}
+	})
+
+	t.Run("should reject combining endpoints with regional endpoints", func(t *testing.T) {
+		config := createTestConfig()
+		config.Endpoints = []string{"a"}
+		config.RegionalEndpoints = []string{"b"}
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should reject empty endpoints", func(t *testing.T) {
+		config := createTestConfig()
+		config.Endpoints = []string{"a", ""}
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package ripple
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEndpointProbeInterval = time.Minute
+	endpointProbeTimeout         = 5 * time.Second
+
+	// endpointSwitchThreshold is the minimum relative latency improvement
+	// required before the selector moves away from a healthy current endpoint.
+	endpointSwitchThreshold = 0.2
+)
+
+// endpointProbe measures the round-trip latency to an endpoint.
+type endpointProbe func(ctx context.Context, endpoint string) (time.Duration, error)
+
+// endpointState holds the last probe result for a single endpoint.
+type endpointState struct {
+	latency time.Duration
+	healthy bool
+}
+
+// EndpointSelector picks the lowest-latency healthy endpoint from a list of
+// regional endpoints. Selection is sticky: a healthy current endpoint is only
+// replaced when another endpoint is meaningfully faster or it fails.
+type EndpointSelector struct {
+	endpoints []string
+	probe     endpointProbe
+	interval  time.Duration
+	states    map[string]endpointState
+	current   string
+	stopCh    chan struct{}
+	spawn     func(func())
+	clock     Clock
+	mu        sync.RWMutex
+}
+
+// newEndpointSelector creates a selector starting at the first endpoint.
+func newEndpointSelector(endpoints []string, interval time.Duration, probe endpointProbe) *EndpointSelector {
+	if interval <= 0 {
+		interval = defaultEndpointProbeInterval
+	}
+	states := make(map[string]endpointState, len(endpoints))
+	for _, endpoint := range endpoints {
+		states[endpoint] = endpointState{healthy: true}
+	}
+	return &EndpointSelector{
+		endpoints: endpoints,
+		probe:     probe,
+		interval:  interval,
+		states:    states,
+		current:   endpoints[0],
+		spawn:     goSpawn,
+		clock:     systemClock{},
+	}
+}
+
+// Current returns the endpoint subsequent batches should be sent to.
+func (s *EndpointSelector) Current() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Start probes all endpoints in the background, immediately and then on
+// every interval until Stop. Until the first probe completes, Current keeps
+// returning the first endpoint, so Start never waits on slow endpoints.
+func (s *EndpointSelector) Start() {
+	s.mu.Lock()
+	if s.stopCh != nil {
+		s.mu.Unlock()
+		return
+	}
+	stopCh := make(chan struct{})
+	s.stopCh = stopCh
+	s.mu.Unlock()
+
+	s.spawn(func() {
+		s.ProbeAll()
+		ticker := s.clock.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				s.ProbeAll()
+			case <-stopCh:
+				return
+			}
+		}
+	})
+}
+
+// Stop halts periodic probing.
+func (s *EndpointSelector) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopCh != nil {
+		close(s.stopCh)
+		s.stopCh = nil
+	}
+}
+
+// ProbeAll probes every endpoint and re-evaluates the current selection.
+func (s *EndpointSelector) ProbeAll() {
+	results := make(map[string]endpointState, len(s.endpoints))
+	for _, endpoint := range s.endpoints {
+		ctx, cancel := context.WithTimeout(context.Background(), endpointProbeTimeout)
+		latency, err := s.probe(ctx, endpoint)
+		cancel()
+		results[endpoint] = endpointState{latency: latency, healthy: err == nil}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = results
+	s.selectLocked()
+}
+
+// ReportFailure marks an endpoint unhealthy until its next successful probe
+// and fails over to the best remaining endpoint.
+func (s *EndpointSelector) ReportFailure(endpoint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[endpoint]
+	if !ok {
+		return
+	}
+	state.healthy = false
+	s.states[endpoint] = state
+	s.selectLocked()
+}
+
+// selectLocked applies the sticky selection rule. Caller must hold s.mu.
+func (s *EndpointSelector) selectLocked() {
+	best := ""
+	for _, endpoint := range s.endpoints {
+		state := s.states[endpoint]
+		if !state.healthy {
+			continue
+		}
+		if best == "" || state.latency < s.states[best].latency {
+			best = endpoint
+		}
+	}
+	if best == "" || best == s.current {
+		return
+	}
+
+	current := s.states[s.current]
+	if current.healthy {
+		if current.latency == 0 {
+			return
+		}
+		improvement := float64(current.latency-s.states[best].latency) / float64(current.latency)
+		if improvement < endpointSwitchThreshold {
+			return
+		}
+	}
+	s.current = best
+}
@@ -0,0 +1,179 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeProbe struct {
+	mu        sync.Mutex
+	latencies map[string]time.Duration
+	failing   map[string]bool
+}
+
+func (f *fakeProbe) probe(ctx context.Context, endpoint string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing[endpoint] {
+		return 0, errors.New("unreachable")
+	}
+	return f.latencies[endpoint], nil
+}
+
+func (f *fakeProbe) set(endpoint string, latency time.Duration, failing bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latencies[endpoint] = latency
+	f.failing[endpoint] = failing
+}
+
+func newFakeProbe() *fakeProbe {
+	return &fakeProbe{latencies: map[string]time.Duration{}, failing: map[string]bool{}}
+}
+
+func TestEndpointSelector_PicksLowestLatency(t *testing.T) {
+	probe := newFakeProbe()
+	probe.set("http://eu", 100*time.Millisecond, false)
+	probe.set("http://us", 20*time.Millisecond, false)
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, time.Hour, probe.probe)
+	if s.Current() != "http://eu" {
+		t.Fatalf("expected first endpoint before probing, got %s", s.Current())
+	}
+
+	s.ProbeAll()
+
+	if s.Current() != "http://us" {
+		t.Fatalf("expected lowest-latency endpoint, got %s", s.Current())
+	}
+}
+
+func TestEndpointSelector_Sticky(t *testing.T) {
+	probe := newFakeProbe()
+	probe.set("http://eu", 100*time.Millisecond, false)
+	probe.set("http://us", 95*time.Millisecond, false)
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, time.Hour, probe.probe)
+	s.ProbeAll()
+
+	if s.Current() != "http://eu" {
+		t.Fatalf("expected to stay on current endpoint for marginal improvement, got %s", s.Current())
+	}
+}
+
+func TestEndpointSelector_SkipsUnhealthy(t *testing.T) {
+	probe := newFakeProbe()
+	probe.set("http://eu", 0, true)
+	probe.set("http://us", 200*time.Millisecond, false)
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, time.Hour, probe.probe)
+	s.ProbeAll()
+
+	if s.Current() != "http://us" {
+		t.Fatalf("expected failover to healthy endpoint, got %s", s.Current())
+	}
+}
+
+func TestEndpointSelector_ReportFailure(t *testing.T) {
+	probe := newFakeProbe()
+	probe.set("http://eu", 10*time.Millisecond, false)
+	probe.set("http://us", 50*time.Millisecond, false)
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, time.Hour, probe.probe)
+	s.ProbeAll()
+	s.ReportFailure("http://eu")
+
+	if s.Current() != "http://us" {
+		t.Fatalf("expected failover after reported failure, got %s", s.Current())
+	}
+
+	// Next successful probe restores the faster endpoint
+	s.ProbeAll()
+	if s.Current() != "http://eu" {
+		t.Fatalf("expected recovery to faster endpoint, got %s", s.Current())
+	}
+}
+
+func TestEndpointSelector_StartStop(t *testing.T) {
+	probe := newFakeProbe()
+	probe.set("http://eu", 100*time.Millisecond, false)
+	probe.set("http://us", 10*time.Millisecond, false)
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, 10*time.Millisecond, probe.probe)
+	s.Start()
+	defer s.Stop()
+
+	waitForEndpoint(t, s, "http://us")
+
+	probe.set("http://eu", 1*time.Millisecond, false)
+	probe.set("http://us", 0, true)
+
+	waitForEndpoint(t, s, "http://eu")
+}
+
+func TestEndpointSelector_StartDoesNotWaitForProbes(t *testing.T) {
+	release := make(chan struct{})
+	probe := func(ctx context.Context, endpoint string) (time.Duration, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		if endpoint == "http://us" {
+			return time.Millisecond, nil
+		}
+		return time.Second, nil
+	}
+
+	s := newEndpointSelector([]string{"http://eu", "http://us"}, time.Hour, probe)
+	start := time.Now()
+	s.Start()
+	defer s.Stop()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected Start to return without waiting for probes, took %v", elapsed)
+	}
+	if s.Current() != "http://eu" {
+		t.Fatalf("expected the first endpoint until probes complete, got %s", s.Current())
+	}
+
+	close(release)
+	waitForEndpoint(t, s, "http://us")
+}
+
+// waitForEndpoint polls s until it selects endpoint.
+func waitForEndpoint(t *testing.T, s *EndpointSelector, endpoint string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Current() != endpoint {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected endpoint %s, got %s", endpoint, s.Current())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatcher_RegionalEndpoints(t *testing.T) {
+	httpAdapter := &mockHTTPAdapter{}
+	client, err := NewClient(ClientConfig{
+		APIKey:                "test-key",
+		RegionalEndpoints:     []string{"http://eu", "http://us"},
+		EndpointProbeInterval: time.Hour,
+		HTTPAdapter:           httpAdapter,
+		StorageAdapter:        &mockStorageAdapter{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.config.Endpoint != "http://eu" {
+		t.Fatalf("expected first regional endpoint as default, got %s", client.config.Endpoint)
+	}
+
+	client.Init()
+	defer client.Dispose()
+
+	// Init probes every regional endpoint in the background
+	waitForCalls(t, httpAdapter, 2)
+}
@@ -0,0 +1,126 @@
+package ripple
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const serverPlatformType = "server"
+
+// Kubernetes downward API variables read for KubernetesInfo, along with the
+// service account namespace file.
+const (
+	envPodName        = "POD_NAME"
+	envPodNamespace   = "POD_NAMESPACE"
+	envNodeName       = "NODE_NAME"
+	envKubernetesHost = "KUBERNETES_SERVICE_HOST"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// containerIDPattern matches the 64-character hex IDs used by Docker,
+// containerd and CRI-O in cgroup and mount paths.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// detectedPlatform caches the host details, which do not change while the
+// process runs.
+var detectedPlatform = sync.OnceValue(func() Platform {
+	return detectPlatform(platformProbe{
+		getenv:   os.Getenv,
+		hostname: os.Hostname,
+		open: func(name string) (io.ReadCloser, error) {
+			return os.Open(name)
+		},
+	})
+})
+
+// platformProbe abstracts the process environment for detectPlatform.
+type platformProbe struct {
+	getenv   func(string) string
+	hostname func() (string, error)
+	open     func(string) (io.ReadCloser, error)
+}
+
+// detectPlatform describes the host, container and pod the process runs in.
+// Undetectable details are left empty.
+func detectPlatform(probe platformProbe) Platform {
+	platform := Platform{
+		Type: serverPlatformType,
+		OS:   runtime.GOOS,
+		Arch: runtime.GOARCH,
+	}
+	platform.Hostname, _ = probe.hostname()
+
+	for _, name := range []string{"/proc/self/cgroup", "/proc/self/mountinfo"} {
+		if id := probe.containerID(name); id != "" {
+			platform.ContainerID = id
+			break
+		}
+	}
+
+	if probe.getenv(envKubernetesHost) != "" {
+		k8s := &KubernetesInfo{
+			Pod:       probe.getenv(envPodName),
+			Namespace: probe.getenv(envPodNamespace),
+			Node:      probe.getenv(envNodeName),
+		}
+		if k8s.Pod == "" {
+			// A pod's hostname is its name unless overridden in the spec.
+			k8s.Pod = platform.Hostname
+		}
+		if k8s.Namespace == "" {
+			k8s.Namespace = probe.firstLine(serviceAccountNamespaceFile)
+		}
+		platform.Kubernetes = k8s
+	}
+	return platform
+}
+
+// containerID returns the first container ID found in the named file.
+func (p platformProbe) containerID(name string) string {
+	f, err := p.open(name)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id := containerIDPattern.FindString(scanner.Text()); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// firstLine returns the trimmed first line of the named file.
+func (p platformProbe) firstLine(name string) string {
+	f, err := p.open(name)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		return strings.TrimSpace(scanner.Text())
+	}
+	return ""
+}
+
+// newPlatform returns the platform stamped onto every event of a client:
+// the detected host details plus the optional app info and process details.
+func newPlatform(app *AppInfo, enrich bool) *Platform {
+	platform := detectedPlatform()
+	platform.App = app
+	if enrich {
+		platform.PID = os.Getpid()
+		platform.Runtime = runtime.Version()
+	}
+	return &platform
+}
@@ -0,0 +1,113 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestClient_Enrichment(t *testing.T) {
+	t.Run("should stamp the detected host details by default", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		_ = client.Track("a", nil, nil)
+		platform := client.dispatcher.queue.ToSlice()[0].Platform
+
+		hostname, _ := os.Hostname()
+		if platform.Type != "server" || platform.OS != runtime.GOOS || platform.Arch != runtime.GOARCH || platform.Hostname != hostname {
+			t.Fatalf("unexpected platform: %+v", platform)
+		}
+		if platform.PID != 0 || platform.Runtime != "" || platform.App != nil {
+			t.Fatalf("expected no process details without enrichment, got %+v", platform)
+		}
+	})
+
+	t.Run("should stamp app info and process details", func(t *testing.T) {
+		app := &AppInfo{Name: "checkout", Version: "1.4.2", Region: "eu-west-1"}
+		config := createTestConfig()
+		config.App = app
+		config.Enrichment = true
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		app.Name = "changed"
+		_ = client.Track("a", nil, nil)
+		platform := client.dispatcher.queue.ToSlice()[0].Platform
+
+		if platform.PID != os.Getpid() || platform.Runtime != runtime.Version() {
+			t.Fatalf("unexpected process details: %+v", platform)
+		}
+		if platform.App == nil || platform.App.Name != "checkout" || platform.App.Region != "eu-west-1" {
+			t.Fatalf("unexpected app info: %+v", platform.App)
+		}
+	})
+
+	t.Run("should omit unset fields on the wire", func(t *testing.T) {
+		data, _ := json.Marshal(Platform{Type: "server", App: &AppInfo{Name: "checkout"}})
+		if string(data) != `{"type":"server","app":{"name":"checkout"}}` {
+			t.Fatalf("unexpected platform JSON: %s", data)
+		}
+	})
+}
+
+func TestDetectPlatform(t *testing.T) {
+	const containerID = "3f4e8c1d9a7b6e5f4d3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e"
+
+	probe := func(env map[string]string, files map[string]string) platformProbe {
+		return platformProbe{
+			getenv:   func(name string) string { return env[name] },
+			hostname: func() (string, error) { return "worker-1", nil },
+			open: func(name string) (io.ReadCloser, error) {
+				content, ok := files[name]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+				return io.NopCloser(strings.NewReader(content)), nil
+			},
+		}
+	}
+
+	t.Run("should detect the container and pod", func(t *testing.T) {
+		platform := detectPlatform(probe(
+			map[string]string{envKubernetesHost: "10.0.0.1", envPodName: "api-7d9f", envNodeName: "node-a"},
+			map[string]string{
+				"/proc/self/cgroup":         "0::/kubepods/burstable/pod1/cri-containerd-" + containerID + ".scope\n",
+				serviceAccountNamespaceFile: "payments\n",
+			},
+		))
+
+		if platform.Hostname != "worker-1" || platform.ContainerID != containerID {
+			t.Fatalf("unexpected host details: %+v", platform)
+		}
+		k8s := platform.Kubernetes
+		if k8s == nil || k8s.Pod != "api-7d9f" || k8s.Namespace != "payments" || k8s.Node != "node-a" {
+			t.Fatalf("unexpected kubernetes info: %+v", k8s)
+		}
+	})
+
+	t.Run("should fall back to mountinfo and the hostname", func(t *testing.T) {
+		platform := detectPlatform(probe(
+			map[string]string{envKubernetesHost: "10.0.0.1"},
+			map[string]string{
+				"/proc/self/cgroup":    "0::/\n",
+				"/proc/self/mountinfo": "612 598 0:52 /var/lib/docker/containers/" + containerID + "/hostname /etc/hostname rw\n",
+			},
+		))
+
+		if platform.ContainerID != containerID || platform.Kubernetes.Pod != "worker-1" || platform.Kubernetes.Namespace != "" {
+			t.Fatalf("unexpected platform: %+v", platform)
+		}
+	})
+
+	t.Run("should leave container details empty outside containers", func(t *testing.T) {
+		platform := detectPlatform(probe(nil, nil))
+		if platform.ContainerID != "" || platform.Kubernetes != nil {
+			t.Fatalf("unexpected platform: %+v", platform)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package ripple
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvAPIKey          = "RIPPLE_API_KEY"
+	EnvAPIKeyHeader    = "RIPPLE_API_KEY_HEADER"
+	EnvEndpoint        = "RIPPLE_ENDPOINT"
+	EnvEndpoints       = "RIPPLE_ENDPOINTS"
+	EnvFlushInterval   = "RIPPLE_FLUSH_INTERVAL"
+	EnvMaxBatchSize    = "RIPPLE_MAX_BATCH_SIZE"
+	EnvMaxBufferSize   = "RIPPLE_MAX_BUFFER_SIZE"
+	EnvMaxRetries      = "RIPPLE_MAX_RETRIES"
+	EnvFlushTimeout    = "RIPPLE_FLUSH_TIMEOUT"
+	EnvRequestTimeout  = "RIPPLE_REQUEST_TIMEOUT"
+	EnvShutdownTimeout = "RIPPLE_SHUTDOWN_TIMEOUT"
+	EnvSamplingRate    = "RIPPLE_SAMPLING_RATE"
+	EnvStoragePath     = "RIPPLE_STORAGE_PATH"
+	EnvLogLevel        = "RIPPLE_LOG_LEVEL"
+)
+
+// NewClientFromEnv creates a client configured from RIPPLE_* environment
+// variables, then applies opts on top. Durations use time.ParseDuration
+// syntax ("5s"), RIPPLE_ENDPOINTS is comma-separated, RIPPLE_STORAGE_PATH
+// selects a FileStorageAdapter and RIPPLE_LOG_LEVEL a PrintLoggerAdapter
+// level (debug, info, warn, error or none). Other defaults are those of
+// NewClientWithOptions.
+//
+// Malformed variables are reported together with any other configuration
+// problem in a single *ConfigError.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	config, problems := configFromEnv(os.LookupEnv)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&config)
+		}
+	}
+	if len(problems) > 0 {
+		if err := config.Validate(); err != nil {
+			problems = append(problems, err.(*ConfigError).Problems...)
+		}
+		return nil, &ConfigError{Problems: problems}
+	}
+	return NewClient(config)
+}
+
+// configFromEnv builds a config from the variables found by lookup, returning
+// a problem for each malformed value.
+func configFromEnv(lookup func(string) (string, bool)) (ClientConfig, []string) {
+	config := defaultOptionsConfig()
+	var problems []string
+
+	str := func(name string, set func(string)) {
+		if value, ok := lookup(name); ok && value != "" {
+			set(value)
+		}
+	}
+	duration := func(name string, field *time.Duration) {
+		str(name, func(value string) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid duration %q", name, value))
+				return
+			}
+			*field = d
+		})
+	}
+	integer := func(name string, field *int) {
+		str(name, func(value string) {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid integer %q", name, value))
+				return
+			}
+			*field = n
+		})
+	}
+
+	str(EnvAPIKey, func(value string) { config.APIKey = value })
+	str(EnvAPIKeyHeader, func(value string) { config.APIKeyHeader = &value })
+	str(EnvEndpoint, func(value string) { config.Endpoint = value })
+	str(EnvEndpoints, func(value string) {
+		for _, endpoint := range strings.Split(value, ",") {
+			config.Endpoints = append(config.Endpoints, strings.TrimSpace(endpoint))
+		}
+	})
+	duration(EnvFlushInterval, &config.FlushInterval)
+	integer(EnvMaxBatchSize, &config.MaxBatchSize)
+	integer(EnvMaxBufferSize, &config.MaxBufferSize)
+	integer(EnvMaxRetries, &config.MaxRetries)
+	duration(EnvFlushTimeout, &config.FlushTimeout)
+	duration(EnvRequestTimeout, &config.RequestTimeout)
+	duration(EnvShutdownTimeout, &config.ShutdownTimeout)
+	str(EnvSamplingRate, func(value string) {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid number %q", EnvSamplingRate, value))
+			return
+		}
+		config.SamplingRate = rate
+	})
+	str(EnvStoragePath, func(value string) {
+		config.StorageAdapter = adapters.NewFileStorageAdapter(value)
+	})
+	str(EnvLogLevel, func(value string) {
+		level := LogLevel(strings.ToUpper(value))
+		if !validLogLevel(level) {
+			problems = append(problems, fmt.Sprintf("%s: unknown log level %q", EnvLogLevel, value))
+			return
+		}
+		config.LoggerAdapter = adapters.NewPrintLoggerAdapter(level)
+	})
+
+	return config, problems
+}
@@ -0,0 +1,72 @@
+package ripple
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Run("should read the config from the environment", func(t *testing.T) {
+		storagePath := filepath.Join(t.TempDir(), "events.json")
+		t.Setenv(EnvAPIKey, "env-key")
+		t.Setenv(EnvEndpoints, "http://a.test/events, http://b.test/events")
+		t.Setenv(EnvFlushInterval, "30s")
+		t.Setenv(EnvMaxBatchSize, "25")
+		t.Setenv(EnvMaxRetries, "5")
+		t.Setenv(EnvSamplingRate, "0.5")
+		t.Setenv(EnvStoragePath, storagePath)
+		t.Setenv(EnvLogLevel, "error")
+
+		client, err := NewClientFromEnv(WithHTTPAdapter(&mockHTTPAdapter{}), WithMaxBatchSize(40))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer client.Dispose()
+
+		config := client.config
+		if config.APIKey != "env-key" || config.FlushInterval != 30*time.Second ||
+			config.MaxRetries != 5 || config.SamplingRate != 0.5 {
+			t.Fatalf("environment not applied: %+v", config)
+		}
+		if config.MaxBatchSize != 40 {
+			t.Fatalf("expected options to override the environment, got %d", config.MaxBatchSize)
+		}
+		if len(config.Endpoints) != 2 || config.Endpoints[1] != "http://b.test/events" || config.Endpoint != "http://a.test/events" {
+			t.Fatalf("unexpected endpoints: %q %q", config.Endpoint, config.Endpoints)
+		}
+		if _, ok := config.StorageAdapter.(*adapters.FileStorageAdapter); !ok {
+			t.Fatalf("expected FileStorageAdapter, got %T", config.StorageAdapter)
+		}
+		if _, ok := config.LoggerAdapter.(*adapters.PrintLoggerAdapter); !ok {
+			t.Fatalf("expected PrintLoggerAdapter, got %T", config.LoggerAdapter)
+		}
+	})
+
+	t.Run("should report malformed variables with other problems", func(t *testing.T) {
+		t.Setenv(EnvAPIKey, "")
+		t.Setenv(EnvEndpoint, "http://test.com")
+		t.Setenv(EnvFlushInterval, "soon")
+		t.Setenv(EnvMaxRetries, "many")
+		t.Setenv(EnvLogLevel, "loud")
+
+		client, err := NewClientFromEnv()
+		var configErr *ConfigError
+		if client != nil || !errors.As(err, &configErr) {
+			t.Fatalf("expected a config error, got %v", err)
+		}
+		expected := []string{
+			`RIPPLE_FLUSH_INTERVAL: invalid duration "soon"`,
+			`RIPPLE_MAX_RETRIES: invalid integer "many"`,
+			`RIPPLE_LOG_LEVEL: unknown log level "loud"`,
+			"api key is required",
+		}
+		if strings.Join(configErr.Problems, "\n") != strings.Join(expected, "\n") {
+			t.Fatalf("unexpected problems:\n%s", strings.Join(configErr.Problems, "\n"))
+		}
+	})
+}
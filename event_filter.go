@@ -0,0 +1,114 @@
+package ripple
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// nameMatcher matches event names against exact names and path.Match glob
+// patterns.
+type nameMatcher struct {
+	exact    map[string]struct{}
+	patterns []string
+}
+
+// newNameMatcher creates a nameMatcher. Entries containing glob
+// metacharacters are treated as patterns; they must be valid (see
+// validateEventPatterns).
+func newNameMatcher(names []string) nameMatcher {
+	m := nameMatcher{exact: make(map[string]struct{}, len(names))}
+	for _, name := range names {
+		if isGlobPattern(name) {
+			m.patterns = append(m.patterns, name)
+		} else {
+			m.exact[name] = struct{}{}
+		}
+	}
+	return m
+}
+
+// empty reports whether the matcher has no entries.
+func (m nameMatcher) empty() bool {
+	return len(m.exact) == 0 && len(m.patterns) == 0
+}
+
+// matches reports whether name equals an entry or matches a pattern.
+func (m nameMatcher) matches(name string) bool {
+	if _, ok := m.exact[name]; ok {
+		return true
+	}
+	for _, pattern := range m.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// eventNameFilter decides which event names Track accepts. An event is
+// dropped if it matches DeniedEvents, or if AllowedEvents is set and it
+// matches none of them.
+type eventNameFilter struct {
+	allowed nameMatcher
+	denied  nameMatcher
+}
+
+// allows reports whether events named name are tracked.
+func (f *eventNameFilter) allows(name string) bool {
+	if f.denied.matches(name) {
+		return false
+	}
+	return f.allowed.empty() || f.allowed.matches(name)
+}
+
+// isGlobPattern reports whether name contains path.Match metacharacters.
+func isGlobPattern(name string) bool {
+	return strings.ContainsAny(name, `*?[\`)
+}
+
+// validateEventPatterns reports empty entries and malformed patterns in
+// names, the value of the named setting.
+func validateEventPatterns(setting string, names []string) []string {
+	var problems []string
+	for _, name := range names {
+		if name == "" {
+			problems = append(problems, setting+" cannot contain an empty name")
+			continue
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("%s pattern %q is malformed", setting, name))
+		}
+	}
+	return problems
+}
+
+// setEventFilter replaces the allowed and/or denied event names. Nil
+// arguments keep the current list; empty ones clear it.
+func (c *Client) setEventFilter(allowed, denied []string) {
+	c.filterMu.Lock()
+	defer c.filterMu.Unlock()
+
+	next := &eventNameFilter{}
+	if current := c.eventFilter.Load(); current != nil {
+		*next = *current
+	}
+	if allowed != nil {
+		next.allowed = newNameMatcher(allowed)
+	}
+	if denied != nil {
+		next.denied = newNameMatcher(denied)
+	}
+	if next.allowed.empty() && next.denied.empty() {
+		c.eventFilter.Store(nil)
+		return
+	}
+	c.eventFilter.Store(next)
+}
+
+// eventFiltered reports whether events named name are dropped by
+// AllowedEvents or DeniedEvents.
+func (c *Client) eventFiltered(name string) bool {
+	filter := c.eventFilter.Load()
+	return filter != nil && !filter.allows(name)
+}
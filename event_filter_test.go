@@ -0,0 +1,61 @@
+package ripple
+
+import "testing"
+
+func TestEventNameFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		denied  []string
+		event   string
+		want    bool
+	}{
+		{name: "no lists", event: "a", want: true},
+		{name: "exact deny", denied: []string{"a"}, event: "a", want: false},
+		{name: "glob deny", denied: []string{"debug_*"}, event: "debug_cache", want: false},
+		{name: "deny miss", denied: []string{"debug_*"}, event: "checkout", want: true},
+		{name: "exact allow", allowed: []string{"checkout"}, event: "checkout", want: true},
+		{name: "glob allow", allowed: []string{"checkout_?"}, event: "checkout_1", want: true},
+		{name: "allow miss", allowed: []string{"checkout_*"}, event: "page_view", want: false},
+		{name: "deny wins", allowed: []string{"checkout_*"}, denied: []string{"checkout_test"}, event: "checkout_test", want: false},
+		{name: "character class", denied: []string{"v[12]_*"}, event: "v2_login", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &eventNameFilter{allowed: newNameMatcher(tt.allowed), denied: newNameMatcher(tt.denied)}
+			if got := filter.allows(tt.event); got != tt.want {
+				t.Fatalf("allows(%q) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_EventFilter(t *testing.T) {
+	t.Run("should drop events outside the allowlist", func(t *testing.T) {
+		config := createTestConfig()
+		config.AllowedEvents = []string{"checkout_*"}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.Track("checkout_started", nil, nil)
+		_ = client.Track("page_view", nil, nil)
+		if client.dispatcher.queue.Len() != 1 || client.Stats().EventsFiltered != 1 {
+			t.Fatalf("expected 1 queued and 1 filtered event, got %+v", client.Stats())
+		}
+	})
+
+	t.Run("should keep the other list when one is updated", func(t *testing.T) {
+		config := createTestConfig()
+		config.AllowedEvents = []string{"checkout_*"}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.UpdateConfig(ConfigUpdate{DeniedEvents: []string{"checkout_test"}})
+		_ = client.Track("checkout_test", nil, nil)
+		_ = client.Track("page_view", nil, nil)
+		_ = client.Track("checkout_done", nil, nil)
+		if client.dispatcher.queue.Len() != 1 || client.Stats().EventsFiltered != 2 {
+			t.Fatalf("expected 1 queued and 2 filtered events, got %+v", client.Stats())
+		}
+	})
+}
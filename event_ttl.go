@@ -0,0 +1,39 @@
+package ripple
+
+// dropExpired removes events issued more than EventTTL ago, except audit
+// events. Under
+// DeliveryAtLeastOnce they are also removed from the journal so they are not
+// restored after a restart.
+func (d *Dispatcher) dropExpired(events []Event) []Event {
+	if d.config.EventTTL <= 0 {
+		return events
+	}
+	cutoff := d.clock.Now().Add(-d.config.EventTTL).UnixMilli()
+	kept := events[:0:0]
+	var expired []Event
+	for _, event := range events {
+		if event.IssuedAt > 0 && event.IssuedAt < cutoff && !d.audit.contains(event) {
+			expired = append(expired, event)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if len(expired) == 0 {
+		return events
+	}
+
+	d.stats.eventsExpired(len(expired))
+	d.acks.resolve(expired, discarded("older than EventTTL"))
+	d.loggerAdapter.Warn("Dropped events older than EventTTL", map[string]any{
+		"eventsCount": len(expired),
+		"ttl":         d.config.EventTTL.String(),
+	})
+	if d.atLeastOnce() {
+		if err := d.journalRemove(expired); err != nil {
+			d.loggerAdapter.Error("Failed to remove expired events from storage", map[string]any{
+				"error": err.Error(),
+			})
+		}
+	}
+	return kept
+}
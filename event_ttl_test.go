@@ -0,0 +1,102 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+)
+
+// withEventTTL sets an EventTTL of an hour and guarantee.
+func withEventTTL(guarantee DeliveryGuarantee) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.EventTTL = time.Hour
+		c.DeliveryGuarantee = guarantee
+	}
+}
+
+func TestDispatcher_EventTTL(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should drop events older than the TTL", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withEventTTL(DeliveryBestEffort))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "stale", IssuedAt: now.Add(-2 * time.Hour).UnixMilli()})
+		d.Enqueue(Event{Name: "fresh", IssuedAt: now.UnixMilli()})
+		d.Flush()
+
+		stats := d.Stats()
+		if stats.EventsSent != 1 || stats.EventsExpired != 1 {
+			t.Fatalf("expected 1 sent and 1 expired, got %+v", stats)
+		}
+	})
+
+	t.Run("should not send a flush made only of expired events", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withEventTTL(DeliveryBestEffort))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "stale", IssuedAt: now.Add(-2 * time.Hour).UnixMilli()})
+		d.Flush()
+
+		if httpAdapter.getCalls() != 0 {
+			t.Fatalf("expected no sends, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should drop expired events restored from storage", func(t *testing.T) {
+		storageAdapter := &mockStorageAdapter{loaded: []Event{
+			{Name: "stale", IssuedAt: now.Add(-2 * time.Hour).UnixMilli()},
+		}}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storageAdapter, withEventTTL(DeliveryBestEffort))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Flush()
+
+		if stats := d.Stats(); stats.EventsSent != 0 || stats.EventsExpired != 1 {
+			t.Fatalf("expected restored event to expire, got %+v", stats)
+		}
+	})
+
+	t.Run("should remove expired events from the journal", func(t *testing.T) {
+		storageAdapter := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{fail: true}, storageAdapter, withEventTTL(DeliveryAtLeastOnce))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "stale", IssuedAt: now.Add(-2 * time.Hour).UnixMilli()})
+		if saved := storageAdapter.getSaved(); len(saved) != 1 {
+			t.Fatalf("expected event to be journaled, got %+v", saved)
+		}
+		d.Flush()
+
+		if saved := storageAdapter.getSaved(); len(saved) != 0 {
+			t.Fatalf("expected journal to be empty, got %+v", saved)
+		}
+	})
+
+	t.Run("should keep events without IssuedAt", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{}, withEventTTL(DeliveryBestEffort))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if stats := d.Stats(); stats.EventsSent != 1 || stats.EventsExpired != 0 {
+			t.Fatalf("expected event to be sent, got %+v", stats)
+		}
+	})
+}
+
+func TestClient_EventTTLValidation(t *testing.T) {
+	config := createTestConfig()
+	config.EventTTL = -time.Second
+
+	if _, err := NewClient(config); err == nil {
+		t.Fatal("expected error for negative EventTTL")
+	}
+}
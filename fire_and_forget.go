@@ -0,0 +1,107 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FireAndForget sends low-priority events once through a separate adapter,
+// typically an adapters.UDPAdapter, instead of the reliable path. They are
+// never retried, re-queued or kept in storage after their flush.
+type FireAndForget struct {
+	// HTTPAdapter sends fire-and-forget batches, e.g.
+	// adapters.NewUDPAdapter(adapters.UDPConfig{}).
+	//
+	// Required.
+	HTTPAdapter HTTPAdapter
+
+	// Endpoint is passed to HTTPAdapter, e.g. "127.0.0.1:8125" for UDP.
+	//
+	// Required.
+	Endpoint string
+
+	// MaxPriority selects the events sent fire-and-forget: those ranked at
+	// or below it by ClientConfig.EventPriority.
+	//
+	// Default: 0.
+	MaxPriority int
+}
+
+// validate reports configuration problems.
+func (f *FireAndForget) validate(priority EventPriorityFunc) []string {
+	var problems []string
+	if f.HTTPAdapter == nil {
+		problems = append(problems, "fire-and-forget http adapter is required")
+	}
+	if f.Endpoint == "" {
+		problems = append(problems, "fire-and-forget endpoint is required")
+	} else if schemes := endpointSchemes(f.HTTPAdapter); f.HTTPAdapter != nil && !validEndpointURL(f.Endpoint, schemes...) {
+		problems = append(problems, fmt.Sprintf("fire-and-forget endpoint %q must be an absolute %s URL", f.Endpoint, strings.Join(schemes, " or ")))
+	}
+	if priority == nil {
+		problems = append(problems, "fire-and-forget requires an event priority function")
+	}
+	return problems
+}
+
+// sendFireAndForget sends the events selected by FireAndForget once and
+// returns the rest for regular delivery. Outcomes are recorded in Stats and
+// reported to OnDelivery like any batch.
+func (d *Dispatcher) sendFireAndForget(ctx context.Context, events []Event) []Event {
+	ff := d.config.FireAndForget
+	if ff == nil || d.config.EventPriority == nil {
+		return events
+	}
+
+	var reliable, lossy []Event
+	for _, event := range events {
+		if d.config.EventPriority(event) <= ff.MaxPriority && !d.audit.contains(event) {
+			lossy = append(lossy, event)
+		} else {
+			reliable = append(reliable, event)
+		}
+	}
+	if len(lossy) == 0 {
+		return events
+	}
+
+	for _, batch := range splitBatches(lossy, d.batchSize(), d.config.MaxBatchBytes) {
+		d.sendLossy(ctx, ff, batch)
+	}
+	return reliable
+}
+
+// sendLossy makes a single attempt at delivering a fire-and-forget batch.
+func (d *Dispatcher) sendLossy(ctx context.Context, ff *FireAndForget, events []Event) {
+	sentAt := d.clock.Now()
+	resp, err := ff.HTTPAdapter.SendWithContext(ctx, ff.Endpoint, events, d.attemptHeaders(sentAt, newUUID()))
+	if err == nil && resp == nil {
+		err = errNilHTTPResponse
+	}
+	if err == nil && d.handlePartialRejection(resp, ff.Endpoint, events, 0, sentAt) {
+		return
+	}
+
+	if err == nil && resp.Status >= 200 && resp.Status < 300 {
+		d.latency.record(events, sentAt)
+		d.batchDelivered(events)
+	} else {
+		if err == nil {
+			err = attemptHTTPError(resp, ff.Endpoint, 0)
+		}
+		if !errors.Is(err, context.Canceled) {
+			d.loggerAdapter.Debug("Fire-and-forget batch lost", map[string]any{
+				"eventsCount": len(events),
+				"error":       err.Error(),
+			})
+		}
+		d.batchDropped(events, err)
+	}
+	if err := d.releaseStored(events); err != nil {
+		d.loggerAdapter.Error("Failed to clear storage after fire-and-forget send", map[string]any{
+			"error": err.Error(),
+		})
+	}
+}
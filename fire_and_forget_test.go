@@ -0,0 +1,78 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestDispatcher_FireAndForget(t *testing.T) {
+	newDispatcher := func(reliable HTTPAdapter, lossy *adapters.ScriptedHTTPAdapter) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  10,
+			MaxRetries:    3,
+			EventPriority: func(e Event) int {
+				if e.Name == "metric" {
+					return 0
+				}
+				return 1
+			},
+			FireAndForget: &FireAndForget{
+				HTTPAdapter: lossy,
+				Endpoint:    "127.0.0.1:8125",
+			},
+		}, reliable, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should route low-priority events to the fire-and-forget adapter", func(t *testing.T) {
+		reliable := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		lossy := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newDispatcher(reliable, lossy)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "metric"})
+		d.Enqueue(Event{Name: "purchase"})
+		d.Flush()
+
+		sent := lossy.Requests()
+		if len(sent) != 1 || sent[0].Endpoint != "127.0.0.1:8125" || len(sent[0].Events) != 1 || sent[0].Events[0].Name != "metric" {
+			t.Fatalf("unexpected fire-and-forget requests %+v", sent)
+		}
+		if got := reliable.Requests(); len(got) != 1 || len(got[0].Events) != 1 || got[0].Events[0].Name != "purchase" {
+			t.Fatalf("unexpected reliable requests %+v", got)
+		}
+		if stats := d.Stats(); stats.EventsSent != 2 {
+			t.Fatalf("expected 2 events sent, got %+v", stats)
+		}
+	})
+
+	t.Run("should not retry or re-queue failed fire-and-forget batches", func(t *testing.T) {
+		reliable := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		lossy := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		d := newDispatcher(reliable, lossy)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "metric"})
+		d.Flush()
+
+		if got := len(lossy.Requests()); got != 1 {
+			t.Fatalf("expected a single attempt, got %d", got)
+		}
+		if len(reliable.Requests()) != 0 {
+			t.Fatal("expected no reliable sends")
+		}
+		if d.queue.Len() != 0 {
+			t.Fatalf("expected nothing re-queued, got %d", d.queue.Len())
+		}
+		if stats := d.Stats(); stats.BatchesFailed != 1 {
+			t.Fatalf("expected 1 failed batch, got %+v", stats)
+		}
+	})
+}
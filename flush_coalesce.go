@@ -0,0 +1,129 @@
+package ripple
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// flushAndWaitPoll is how often FlushAndWait retries while events remain,
+// e.g. because a batch was re-queued or a rate limit deferred it.
+const flushAndWaitPoll = 100 * time.Millisecond
+
+// flushRound is one flush, shared by every caller that joined it.
+type flushRound struct {
+	done chan struct{}
+
+	// ctx and cancel are set for queued rounds. ctx is cancelled once every
+	// caller waiting on the round has given up, and replaced if another
+	// caller joins before the round starts. Guarded by flushCoalescer.mu.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// flushCoalescer merges overlapping flush requests. While a flush runs,
+// further requests join a single queued round that starts once it ends, so
+// at most one flush runs and one waits, however many callers pile up. The
+// queued round drains the queue after every waiter asked for it, so each
+// still sees the events it tracked before calling flushed.
+type flushCoalescer struct {
+	mu      sync.Mutex
+	current *flushRound
+	queued  *flushRound
+}
+
+// FlushContext flushes all queued events, giving up on retries once ctx is
+// done. Events not delivered by then are re-queued and checkpointed.
+//
+// Calls overlapping a running flush are coalesced into one follow-up flush,
+// which runs until it completes or every caller that joined it is done.
+// Each caller returns once the follow-up completes or its own ctx is done.
+func (d *Dispatcher) FlushContext(ctx context.Context) {
+	c := &d.flushes
+	c.mu.Lock()
+	if c.current == nil {
+		round := &flushRound{done: make(chan struct{})}
+		c.current = round
+		c.mu.Unlock()
+		d.runFlushRound(ctx, round)
+		return
+	}
+
+	round := c.queued
+	if round == nil {
+		round = &flushRound{done: make(chan struct{})}
+		c.queued = round
+		running := c.current
+		d.resources.spawn(func() {
+			<-running.done
+			c.mu.Lock()
+			roundCtx := round.ctx
+			c.mu.Unlock()
+			d.runFlushRound(roundCtx, round)
+		})
+	}
+	if round.waiters == 0 {
+		round.ctx, round.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+	round.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-round.done:
+	case <-ctx.Done():
+		c.mu.Lock()
+		round.waiters--
+		if round.waiters == 0 {
+			round.cancel()
+		}
+		c.mu.Unlock()
+	}
+}
+
+// runFlushRound flushes, then hands over to the queued round, if any. A
+// round whose context is already done, as all its callers gave up before it
+// started, does not flush.
+func (d *Dispatcher) runFlushRound(ctx context.Context, round *flushRound) {
+	defer func() {
+		c := &d.flushes
+		c.mu.Lock()
+		c.current, c.queued = c.queued, nil
+		cancel := round.cancel
+		c.mu.Unlock()
+		close(round.done)
+		if cancel != nil {
+			cancel()
+		}
+	}()
+	if ctx.Err() == nil {
+		d.flush(ctx)
+	}
+}
+
+// FlushAndWait flushes repeatedly until the queue and the scheduled retries
+// have drained, returning nil, or until ctx is done, returning its error.
+// Events dropped after failing count as drained; held events are ignored.
+// Between attempts it waits out server-requested backoff.
+func (d *Dispatcher) FlushAndWait(ctx context.Context) error {
+	for {
+		d.FlushContext(ctx)
+		if d.drained() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.clock.Sleep(ctx, max(d.backoffRemaining(), flushAndWaitPoll)) {
+			return ctx.Err()
+		}
+	}
+}
+
+// drained reports whether no queued, spilled or retrying events remain.
+func (d *Dispatcher) drained() bool {
+	d.mu.Lock()
+	spilled := d.spilled
+	d.mu.Unlock()
+	return !spilled && d.queue.IsEmpty() && len(d.retryEvents()) == 0
+}
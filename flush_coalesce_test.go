@@ -0,0 +1,161 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// withOneSendPerFlush allows batches of up to 100 events and disables
+// retries, so each flush of a few events makes a single send.
+func withOneSendPerFlush(c *DispatcherConfig) {
+	c.MaxBatchSize = 100
+	c.MaxRetries = 0
+}
+
+func TestDispatcher_FlushCoalescing(t *testing.T) {
+	t.Run("should coalesce flushes overlapping a running one", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200, Delay: 50 * time.Millisecond})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		go d.Flush()
+		time.Sleep(10 * time.Millisecond)
+
+		d.Enqueue(Event{Name: "b"})
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.Flush()
+			}()
+		}
+		wg.Wait()
+
+		if got := httpAdapter.Calls(); got != 2 {
+			t.Fatalf("expected the running flush plus one coalesced flush, got %d sends", got)
+		}
+		if d.queue.Len() != 0 {
+			t.Fatalf("expected every waiter's events to be flushed, got %d queued", d.queue.Len())
+		}
+	})
+
+	t.Run("should return when the caller's context is done", func(t *testing.T) {
+		httpAdapter := newGatedHTTPAdapter()
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		go d.Flush()
+		<-httpAdapter.started
+		d.Enqueue(Event{Name: "b"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		d.FlushContext(ctx)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("expected FlushContext to return at its deadline, took %v", elapsed)
+		}
+
+		close(httpAdapter.release)
+		d.Flush()
+		if got := httpAdapter.delivered(); !slices.Equal(got, []string{"a", "b"}) {
+			t.Fatalf("expected a and b delivered, got %v", got)
+		}
+	})
+
+	t.Run("should keep the coalesced flush for callers still waiting", func(t *testing.T) {
+		httpAdapter := newGatedHTTPAdapter()
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		go d.Flush()
+		<-httpAdapter.started
+		d.Enqueue(Event{Name: "b"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelled := make(chan struct{})
+		go func() {
+			d.FlushContext(ctx)
+			close(cancelled)
+		}()
+		waited := make(chan struct{})
+		go func() {
+			d.FlushContext(context.Background())
+			close(waited)
+		}()
+
+		cancel()
+		<-cancelled
+		close(httpAdapter.release)
+		<-waited
+		if got := httpAdapter.delivered(); !slices.Equal(got, []string{"a", "b"}) {
+			t.Fatalf("expected a and b delivered, got %v", got)
+		}
+	})
+}
+
+func TestDispatcher_FlushAndWait(t *testing.T) {
+	t.Run("should keep flushing until re-queued events are delivered", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503, Times: 2},
+			adapters.Scenario{Status: 200},
+		)
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := d.FlushAndWait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats := d.Stats(); stats.EventsSent != 1 || stats.QueueLength != 0 {
+			t.Fatalf("expected the event delivered, got %+v", stats)
+		}
+	})
+
+	t.Run("should return the context error while events remain", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+		if err := d.FlushAndWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if d.queue.Len() != 1 {
+			t.Fatalf("expected the event to stay queued, got %d", d.queue.Len())
+		}
+	})
+
+	t.Run("should return immediately when the queue is empty", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter()
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withOneSendPerFlush)
+		d.Restore()
+		defer d.Dispose()
+
+		if err := d.FlushAndWait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if httpAdapter.Calls() != 0 {
+			t.Fatal("expected no sends")
+		}
+	})
+}
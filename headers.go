@@ -0,0 +1,41 @@
+package ripple
+
+import (
+	"context"
+	"net/http"
+)
+
+// HeaderProvider returns headers added to each delivery attempt, such as
+// trace IDs or rotating tokens. ctx is the flush's context, or the caller's
+// for TrackNow. It is called once per attempt and must be safe for
+// concurrent use.
+type HeaderProvider func(ctx context.Context) map[string]string
+
+// reservedHeader reports whether name is a header the dispatcher sets
+// itself, which custom headers cannot replace.
+func (d *Dispatcher) reservedHeader(name string) bool {
+	return isReservedHeader(name, d.config.APIKeyHeader, d.config.AuthProvider != nil)
+}
+
+// isReservedHeader reports whether name, compared case-insensitively, is the
+// API key header, Content-Type, SentAtHeader, BatchIDHeader, or
+// AuthorizationHeader when an AuthProvider sets it.
+func isReservedHeader(name, apiKeyHeader string, auth bool) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case http.CanonicalHeaderKey(apiKeyHeader), "Content-Type", http.CanonicalHeaderKey(SentAtHeader), http.CanonicalHeaderKey(BatchIDHeader):
+		return true
+	case AuthorizationHeader:
+		return auth
+	}
+	return false
+}
+
+// addProvidedHeaders sets the HeaderProvider's headers on an attempt,
+// skipping reserved ones.
+func (d *Dispatcher) addProvidedHeaders(ctx context.Context, headers map[string]string) {
+	for name, value := range d.config.HeaderProvider(ctx) {
+		if !d.reservedHeader(name) {
+			headers[name] = value
+		}
+	}
+}
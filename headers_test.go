@@ -0,0 +1,73 @@
+package ripple
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestDispatcher_Headers(t *testing.T) {
+	newDispatcher := func(httpAdapter HTTPAdapter, headers map[string]string, provider HeaderProvider) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:         "test-key",
+			APIKeyHeader:   "X-API-Key",
+			Endpoint:       "http://test.com",
+			FlushInterval:  10 * time.Second,
+			MaxBatchSize:   10,
+			Headers:        headers,
+			HeaderProvider: provider,
+		}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should add static and provided headers to every request", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		calls := 0
+		d := newDispatcher(httpAdapter, map[string]string{"X-Tenant-Id": "acme", "X-Trace-Id": "static"}, func(ctx context.Context) map[string]string {
+			calls++
+			return map[string]string{"X-Trace-Id": "trace-" + strconv.Itoa(calls)}
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		requests := httpAdapter.Requests()
+		if len(requests) != 2 {
+			t.Fatalf("expected 2 requests, got %d", len(requests))
+		}
+		for i, req := range requests {
+			if req.Headers["X-Tenant-Id"] != "acme" {
+				t.Errorf("request %d: expected static header, got %v", i, req.Headers)
+			}
+			if want := "trace-" + strconv.Itoa(i+1); req.Headers["X-Trace-Id"] != want {
+				t.Errorf("request %d: expected provided header %q to win, got %q", i, want, req.Headers["X-Trace-Id"])
+			}
+		}
+	})
+
+	t.Run("should not let custom headers replace reserved ones", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newDispatcher(httpAdapter, map[string]string{"x-api-key": "other"}, func(ctx context.Context) map[string]string {
+			return map[string]string{"X-Batch-Id": "forged", "X-Region": "eu"}
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		headers := httpAdapter.Requests()[0].Headers
+		if headers["X-API-Key"] != "test-key" || headers["x-api-key"] != "" {
+			t.Fatalf("expected the api key header to be kept, got %v", headers)
+		}
+		if headers[BatchIDHeader] == "forged" || headers["X-Region"] != "eu" {
+			t.Fatalf("unexpected headers %v", headers)
+		}
+	})
+}
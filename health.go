@@ -0,0 +1,139 @@
+package ripple
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Health states reported in HealthStatus.Status.
+const (
+	// HealthOK means the client is running and nothing is failing.
+	HealthOK = "ok"
+
+	// HealthDegraded means the client is running but storage is failing or
+	// an endpoint is marked unhealthy.
+	HealthDegraded = "degraded"
+
+	// HealthDown means the client is not initialized or has been disposed.
+	HealthDown = "down"
+)
+
+// HealthStatus is a JSON-friendly snapshot of client health, served by
+// HealthHandler.
+type HealthStatus struct {
+	// Status is HealthOK, HealthDegraded or HealthDown.
+	Status string `json:"status"`
+
+	// Initialized reports whether Init has run and the client is not disposed.
+	Initialized bool `json:"initialized"`
+
+	// QueueLength is the number of events waiting to be sent.
+	QueueLength int `json:"queueLength"`
+
+	// HeldEvents is the number of events held by HoldEvents.
+	HeldEvents int `json:"heldEvents"`
+
+	// LastFlush is when the last non-empty flush completed, if any.
+	LastFlush *time.Time `json:"lastFlush,omitempty"`
+
+	// LastDelivery is when a batch was last delivered successfully, if any.
+	LastDelivery *time.Time `json:"lastDelivery,omitempty"`
+
+	// LastError is the error of the most recent failed batch, if any.
+	LastError string `json:"lastError,omitempty"`
+
+	// Storage reports the state of the StorageAdapter.
+	Storage StorageHealth `json:"storage"`
+
+	// Endpoints reports per-endpoint health when Endpoints is configured.
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
+}
+
+// StorageHealth reports the state of the StorageAdapter in HealthStatus.
+type StorageHealth struct {
+	// Healthy is false if the most recent storage operation failed.
+	Healthy bool `json:"healthy"`
+
+	// Events is the number of events last persisted to storage.
+	Events int `json:"events"`
+
+	// LastError is the error of the most recent failed storage operation.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// EndpointStatus is the JSON form of EndpointHealth in HealthStatus.
+type EndpointStatus struct {
+	Endpoint            string `json:"endpoint"`
+	Healthy             bool   `json:"healthy"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	LastError           string `json:"lastError,omitempty"`
+}
+
+// Health returns a snapshot of the client's health.
+func (c *Client) Health() HealthStatus {
+	c.initMu.Lock()
+	running := c.initialized && !c.disposed
+	c.initMu.Unlock()
+
+	stats := c.Stats()
+	health := HealthStatus{
+		Status:      HealthOK,
+		Initialized: running,
+		QueueLength: stats.QueueLength,
+		HeldEvents:  stats.HeldEvents,
+		LastError:   errorString(stats.LastError),
+		Storage: StorageHealth{
+			Healthy:   stats.StorageError == nil,
+			Events:    stats.StorageSize,
+			LastError: errorString(stats.StorageError),
+		},
+	}
+	if !stats.LastFlushTime.IsZero() {
+		health.LastFlush = &stats.LastFlushTime
+	}
+	if !stats.LastDeliveryTime.IsZero() {
+		health.LastDelivery = &stats.LastDeliveryTime
+	}
+	if !health.Storage.Healthy {
+		health.Status = HealthDegraded
+	}
+	for _, endpoint := range stats.Endpoints {
+		health.Endpoints = append(health.Endpoints, EndpointStatus{
+			Endpoint:            endpoint.Endpoint,
+			Healthy:             endpoint.Healthy,
+			ConsecutiveFailures: endpoint.ConsecutiveFailures,
+			LastError:           errorString(endpoint.LastError),
+		})
+		if !endpoint.Healthy {
+			health.Status = HealthDegraded
+		}
+	}
+	if !running {
+		health.Status = HealthDown
+	}
+	return health
+}
+
+// HealthHandler returns an http.Handler serving client.Health() as JSON,
+// e.g. mounted at /healthz/ripple. It responds 503 Service Unavailable
+// while the client is down and 200 OK otherwise, including when degraded.
+func HealthHandler(client *Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		health := client.Health()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if health.Status == HealthDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(health)
+	})
+}
+
+// errorString returns err's message, or "" if err is nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,78 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serveHealth(t *testing.T, client *Client) (int, HealthStatus) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	HealthHandler(client).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/ripple", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected JSON content type, got %q", ct)
+	}
+	var health HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	return rec.Code, health
+}
+
+func TestHealthHandler(t *testing.T) {
+	t.Run("should report down before Init", func(t *testing.T) {
+		client := createTestClient()
+
+		code, health := serveHealth(t, client)
+		if code != http.StatusServiceUnavailable || health.Status != HealthDown || health.Initialized {
+			t.Fatalf("unexpected response %d: %+v", code, health)
+		}
+	})
+
+	t.Run("should report queue depth and last delivery", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		code, health := serveHealth(t, client)
+		if code != http.StatusOK || health.Status != HealthOK || health.QueueLength != 1 || health.LastDelivery != nil {
+			t.Fatalf("unexpected response %d: %+v", code, health)
+		}
+
+		client.Flush()
+		_, health = serveHealth(t, client)
+		if health.QueueLength != 0 || health.LastDelivery == nil || health.LastFlush == nil {
+			t.Fatalf("expected delivery to be reported, got %+v", health)
+		}
+	})
+
+	t.Run("should report degraded storage", func(t *testing.T) {
+		config := createTestConfig()
+		config.StorageAdapter = &mockStorageAdapter{err: errors.New("disk full")}
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		code, health := serveHealth(t, client)
+		if code != http.StatusOK || health.Status != HealthDegraded || health.Storage.Healthy || health.Storage.LastError != "disk full" {
+			t.Fatalf("unexpected response %d: %+v", code, health)
+		}
+	})
+
+	t.Run("should report down after Dispose", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+		client.Dispose()
+
+		if code, health := serveHealth(t, client); code != http.StatusServiceUnavailable || health.Status != HealthDown {
+			t.Fatalf("unexpected response %d: %+v", code, health)
+		}
+	})
+}
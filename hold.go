@@ -0,0 +1,151 @@
+package ripple
+
+// EventFilter reports whether a queued event matches an operator selection.
+type EventFilter func(event Event) bool
+
+// EventNameFilter matches events with any of the given names.
+func EventNameFilter(names ...string) EventFilter {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return func(event Event) bool {
+		_, ok := set[event.Name]
+		return ok
+	}
+}
+
+// Hold moves queued events and batches waiting for a scheduled retry that
+// match filter out of the send path. Held events are kept in memory only,
+// are excluded from flushes and storage, and are discarded on Dispose unless
+// released first. Returns the number of events held.
+func (d *Dispatcher) Hold(filter EventFilter) int {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.reloadSpilled()
+
+	kept, held := splitEvents(d.queue.ToSlice(), filter)
+	d.mu.Lock()
+	retries := d.retries[:0]
+	for _, retry := range d.retries {
+		var heldRetry []Event
+		retry.events, heldRetry = splitEvents(retry.events, filter)
+		held = append(held, heldRetry...)
+		if len(retry.events) > 0 {
+			retries = append(retries, retry)
+		}
+	}
+	clear(d.retries[len(retries):])
+	d.retries = retries
+	d.armRetryTimerLocked()
+	d.held = append(d.held, held...)
+	d.mu.Unlock()
+	if len(held) == 0 {
+		return 0
+	}
+
+	d.queue.Clear()
+	d.queue.LoadFromSlice(kept)
+
+	// Rewrite storage so held events are not restored and sent after a restart.
+	if d.atLeastOnce() {
+		if err := d.journalRemove(held); err != nil {
+			d.logStorageError("Failed to persist queue after holding events", err, nil)
+		}
+	} else if err := d.saveEvents(append(d.retryEvents(), kept...)); err != nil {
+		d.logStorageError("Failed to persist queue after holding events", err, nil)
+	}
+	return len(held)
+}
+
+// Release returns held events matching filter to the front of the queue,
+// ahead of events tracked since. A nil filter releases every held event.
+// Returns the number of events released.
+func (d *Dispatcher) Release(filter EventFilter) int {
+	released := d.takeHeld(filter)
+	if len(released) == 0 {
+		return 0
+	}
+
+	d.flushMu.Lock()
+	if d.atLeastOnce() {
+		if err := d.journalAppend(released...); err != nil {
+			d.logStorageError("Failed to persist released events", err, nil)
+		}
+	}
+	d.requeueEvents(released)
+	d.flushMu.Unlock()
+	d.scheduleFlush()
+	return len(released)
+}
+
+// Purge discards held events matching filter. A nil filter purges every held
+// event. Returns the number of events purged.
+func (d *Dispatcher) Purge(filter EventFilter) int {
+	purged := d.takeHeld(filter)
+	d.acks.resolve(purged, discarded("purged"))
+	if err := d.audit.remove(purged); err != nil {
+		d.logStorageError("Failed to remove purged audit events", err, nil)
+	}
+	return len(purged)
+}
+
+// splitEvents splits events into those not matching filter and those
+// matching it.
+func splitEvents(events []Event, filter EventFilter) (kept, matched []Event) {
+	for _, event := range events {
+		if filter(event) {
+			matched = append(matched, event)
+		} else {
+			kept = append(kept, event)
+		}
+	}
+	return kept, matched
+}
+
+// takeHeld removes and returns held events matching filter.
+func (d *Dispatcher) takeHeld(filter EventFilter) []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var taken, remaining []Event
+	for _, event := range d.held {
+		if filter == nil || filter(event) {
+			taken = append(taken, event)
+		} else {
+			remaining = append(remaining, event)
+		}
+	}
+	d.held = remaining
+	return taken
+}
+
+// heldCount returns the number of held events.
+func (d *Dispatcher) heldCount() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.held)
+}
+
+// HoldEvents quarantines queued events matching filter so they are not sent
+// until released with ReleaseEvents or discarded with PurgeHeldEvents.
+// Returns the number of events held.
+func (c *Client) HoldEvents(filter EventFilter) int {
+	if filter == nil {
+		return 0
+	}
+	return c.dispatcher.Hold(filter)
+}
+
+// ReleaseEvents re-queues held events matching filter for delivery. A nil
+// filter releases every held event. Returns the number of events released.
+func (c *Client) ReleaseEvents(filter EventFilter) int {
+	return c.dispatcher.Release(filter)
+}
+
+// PurgeHeldEvents discards held events matching filter. A nil filter purges
+// every held event. Returns the number of events purged.
+func (c *Client) PurgeHeldEvents(filter EventFilter) int {
+	return c.dispatcher.Purge(filter)
+}
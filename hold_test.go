@@ -0,0 +1,165 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestClient_HoldEvents(t *testing.T) {
+	t.Run("should exclude held events from flush and storage", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("bad", nil, nil)
+		client.Track("good", nil, nil)
+		client.Track("bad", nil, nil)
+
+		if held := client.HoldEvents(EventNameFilter("bad")); held != 2 {
+			t.Fatalf("expected 2 held events, got %d", held)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Name != "good" {
+			t.Fatalf("expected only the good event in storage, got %+v", saved)
+		}
+
+		stats := client.Stats()
+		if stats.QueueLength != 1 || stats.HeldEvents != 2 {
+			t.Fatalf("expected 1 queued and 2 held, got %d and %d", stats.QueueLength, stats.HeldEvents)
+		}
+
+		client.Flush()
+		if client.Stats().HeldEvents != 2 {
+			t.Fatal("expected held events to survive flush")
+		}
+	})
+
+	t.Run("should release held events to the front of the queue", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("bad", nil, nil)
+		client.HoldEvents(EventNameFilter("bad"))
+		client.Track("later", nil, nil)
+
+		if released := client.ReleaseEvents(nil); released != 1 {
+			t.Fatalf("expected 1 released event, got %d", released)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 2 || saved[0].Name != "bad" || saved[1].Name != "later" {
+			t.Fatalf("expected [bad later], got %+v", saved)
+		}
+		if client.Stats().HeldEvents != 0 {
+			t.Fatal("expected no held events")
+		}
+	})
+
+	t.Run("should purge only matching held events", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+		client.HoldEvents(EventNameFilter("a", "b"))
+
+		if purged := client.PurgeHeldEvents(EventNameFilter("a")); purged != 1 {
+			t.Fatalf("expected 1 purged event, got %d", purged)
+		}
+		if client.Stats().HeldEvents != 1 {
+			t.Fatalf("expected 1 held event, got %d", client.Stats().HeldEvents)
+		}
+	})
+
+	t.Run("should hold events waiting for a scheduled retry", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		config.RetryMode = RetryScheduled
+		config.BackoffPolicy = ConstantBackoff(time.Hour)
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("bad", nil, nil)
+		client.Track("good", nil, nil)
+		client.Flush()
+		if client.Stats().PendingRetries != 2 {
+			t.Fatalf("expected the batch to wait for a retry, got %d pending", client.Stats().PendingRetries)
+		}
+
+		if held := client.HoldEvents(EventNameFilter("bad")); held != 1 {
+			t.Fatalf("expected 1 held event, got %d", held)
+		}
+		if pending := client.Stats().PendingRetries; pending != 1 {
+			t.Fatalf("expected only the good event left to retry, got %d", pending)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Name != "good" {
+			t.Fatalf("expected only the good event in storage, got %+v", saved)
+		}
+	})
+
+	t.Run("should count held events as dropped on close", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+
+		client.Track("bad", nil, nil)
+		client.Track("good", nil, nil)
+		client.HoldEvents(EventNameFilter("bad"))
+
+		summary, _ := client.Close(context.Background())
+		if summary != (CloseSummary{Sent: 1, Dropped: 1}) {
+			t.Fatalf("expected the held event dropped, got %+v", summary)
+		}
+	})
+
+	t.Run("should ignore a nil hold filter", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		if held := client.HoldEvents(nil); held != 0 {
+			t.Fatalf("expected 0 held events, got %d", held)
+		}
+	})
+}
+
+func TestClient_Command_HoldEvents(t *testing.T) {
+	t.Run("should hold, release and purge by event name", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+
+		if err := client.Command(AdminCommand{Op: AdminHoldEvents}); err == nil {
+			t.Fatal("expected error for hold without event names")
+		}
+		if err := client.Command(AdminCommand{Op: AdminHoldEvents, EventNames: []string{"a", "b"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.Command(AdminCommand{Op: AdminReleaseEvents, EventNames: []string{"a"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats := client.Stats(); stats.QueueLength != 1 || stats.HeldEvents != 1 {
+			t.Fatalf("expected 1 queued and 1 held, got %d and %d", stats.QueueLength, stats.HeldEvents)
+		}
+		if err := client.Command(AdminCommand{Op: AdminPurgeHeld}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Stats().HeldEvents != 0 {
+			t.Fatal("expected no held events after purge")
+		}
+	})
+}
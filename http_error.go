@@ -0,0 +1,116 @@
+package ripple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// maxErrorMessageLength bounds the length of a plain-text response body
+// used as an HTTPError message.
+const maxErrorMessageLength = 256
+
+// IsRetryable reports whether err is a delivery failure the dispatcher
+// retries: an *HTTPError with a 5xx status, or a network error. Context
+// cancellation is not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsClientError reports whether err is an *HTTPError with a 4xx status,
+// meaning the server refused the batch and resending it will not help.
+func IsClientError(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Status >= 400 && httpErr.Status < 500
+}
+
+// attemptHTTPError builds the HTTPError of a failed delivery attempt to
+// endpoint, where attempt is zero-based.
+func attemptHTTPError(resp *HTTPResponse, endpoint string, attempt int) *HTTPError {
+	httpErr := newHTTPError(resp)
+	httpErr.Endpoint = endpoint
+	httpErr.Attempts = attempt + 1
+	return httpErr
+}
+
+// newHTTPError builds an HTTPError from a non-2xx response, parsing a
+// structured error from its body. JSON bodies may carry "code", "message"
+// (or "error") and "rejectedEvents" fields, either at the top level or
+// nested under "error"; a plain-text body becomes the message.
+func newHTTPError(resp *HTTPResponse) *HTTPError {
+	httpErr := &HTTPError{Status: resp.Status, Body: resp.Data}
+	switch data := resp.Data.(type) {
+	case map[string]any:
+		parseServerError(httpErr, data)
+	case string:
+		httpErr.Message = truncateMessage(strings.TrimSpace(data))
+	}
+	if len(resp.RejectedEvents) > 0 {
+		httpErr.RejectedEvents = resp.RejectedEvents
+	}
+	return httpErr
+}
+
+// parseServerError copies the error fields found in data into httpErr.
+func parseServerError(httpErr *HTTPError, data map[string]any) {
+	if nested, ok := data["error"].(map[string]any); ok {
+		parseServerError(httpErr, nested)
+	}
+	if code, ok := data["code"]; ok && code != nil {
+		httpErr.Code = fmt.Sprint(code)
+	}
+	if message, ok := data["message"].(string); ok {
+		httpErr.Message = truncateMessage(message)
+	} else if message, ok := data["error"].(string); ok {
+		httpErr.Message = truncateMessage(message)
+	}
+	if rejected, ok := data["rejectedEvents"].([]any); ok {
+		httpErr.RejectedEvents = parseIndices(rejected)
+	}
+}
+
+// parseIndices converts JSON numbers to non-negative integer indices,
+// skipping anything else.
+func parseIndices(values []any) []int {
+	var indices []int
+	for _, value := range values {
+		if n, ok := value.(float64); ok && n >= 0 && n == float64(int(n)) {
+			indices = append(indices, int(n))
+		}
+	}
+	return indices
+}
+
+// bodySnippet renders a response body without a recognized error message,
+// truncated for use in HTTPError messages.
+func bodySnippet(body any) string {
+	if body == nil {
+		return ""
+	}
+	if text, ok := body.(string); ok {
+		return truncateMessage(strings.TrimSpace(text))
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	return truncateMessage(string(data))
+}
+
+func truncateMessage(message string) string {
+	if len(message) > maxErrorMessageLength {
+		return message[:maxErrorMessageLength] + "..."
+	}
+	return message
+}
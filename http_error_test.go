@@ -0,0 +1,132 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPError(t *testing.T) {
+	t.Run("should parse a top-level structured error", func(t *testing.T) {
+		httpErr := newHTTPError(&HTTPResponse{Status: 422, Data: map[string]any{
+			"code":           "invalid_event",
+			"message":        "payload is required",
+			"rejectedEvents": []any{float64(0), float64(2), "x", float64(-1)},
+		}})
+		if httpErr.Code != "invalid_event" || httpErr.Message != "payload is required" {
+			t.Fatalf("unexpected error fields: %+v", httpErr)
+		}
+		if len(httpErr.RejectedEvents) != 2 || httpErr.RejectedEvents[0] != 0 || httpErr.RejectedEvents[1] != 2 {
+			t.Fatalf("expected rejected events [0 2], got %v", httpErr.RejectedEvents)
+		}
+		expected := "HTTP request failed with status 422 Unprocessable Entity: invalid_event: payload is required (rejected events [0 2])"
+		if httpErr.Error() != expected {
+			t.Fatalf("expected %q, got %q", expected, httpErr.Error())
+		}
+	})
+
+	t.Run("should parse an error nested under error", func(t *testing.T) {
+		httpErr := newHTTPError(&HTTPResponse{Status: 400, Data: map[string]any{
+			"error": map[string]any{"code": float64(1001), "message": "bad schema"},
+		}})
+		if httpErr.Code != "1001" || httpErr.Message != "bad schema" {
+			t.Fatalf("unexpected error fields: %+v", httpErr)
+		}
+	})
+
+	t.Run("should use an error string as the message", func(t *testing.T) {
+		httpErr := newHTTPError(&HTTPResponse{Status: 413, Data: map[string]any{"error": "request body too large"}})
+		if httpErr.Message != "request body too large" || httpErr.Error() != "HTTP request failed with status 413 Request Entity Too Large: request body too large" {
+			t.Fatalf("unexpected error: %+v", httpErr)
+		}
+	})
+
+	t.Run("should use a truncated plain-text body as the message", func(t *testing.T) {
+		httpErr := newHTTPError(&HTTPResponse{Status: 502, Data: strings.Repeat("x", 300)})
+		if len(httpErr.Message) != maxErrorMessageLength+3 || httpErr.Body == nil {
+			t.Fatalf("expected a truncated message and the raw body, got %+v", httpErr)
+		}
+	})
+
+	t.Run("should keep the status only without a body", func(t *testing.T) {
+		httpErr := newHTTPError(&HTTPResponse{Status: 500})
+		if httpErr.Error() != "HTTP request failed with status 500 Internal Server Error" {
+			t.Fatalf("unexpected error: %q", httpErr.Error())
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		client    bool
+	}{
+		{"nil", nil, false, false},
+		{"server error", &HTTPError{Status: 503}, true, false},
+		{"wrapped server error", fmt.Errorf("send: %w", &HTTPError{Status: 500}), true, false},
+		{"client error", &HTTPError{Status: 422}, false, true},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true, false},
+		{"cancelled", context.Canceled, false, false},
+		{"other error", errors.New("boom"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if IsRetryable(tt.err) != tt.retryable {
+				t.Errorf("expected IsRetryable %v", tt.retryable)
+			}
+			if IsClientError(tt.err) != tt.client {
+				t.Errorf("expected IsClientError %v", tt.client)
+			}
+		})
+	}
+}
+
+func TestDispatcher_StructuredServerError(t *testing.T) {
+	httpAdapter := &bodyHTTPAdapter{status: 400, data: map[string]any{
+		"code":    "invalid_api_key",
+		"message": "api key revoked",
+	}}
+	var delivered error
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:        "test-key",
+		Endpoint:      "http://test.com",
+		FlushInterval: 10 * time.Second,
+		OnDelivery:    func(_ []Event, err error) { delivered = err },
+	}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+
+	d.Enqueue(Event{Name: "test"})
+	d.Flush()
+
+	var httpErr *HTTPError
+	if !errors.As(delivered, &httpErr) || httpErr.Code != "invalid_api_key" || httpErr.Message != "api key revoked" {
+		t.Fatalf("expected the structured error in OnDelivery, got %v", delivered)
+	}
+	if !errors.As(d.Stats().LastError, &httpErr) || httpErr.Code != "invalid_api_key" {
+		t.Fatalf("expected the structured error in Stats, got %v", d.Stats().LastError)
+	}
+	if httpErr.Endpoint != "http://test.com" || httpErr.Attempts != 1 {
+		t.Fatalf("expected endpoint and attempt info, got %+v", httpErr)
+	}
+}
+
+// bodyHTTPAdapter answers every send with a fixed status, body and rejected
+// event indices.
+type bodyHTTPAdapter struct {
+	status   int
+	data     any
+	rejected []int
+}
+
+func (a *bodyHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return &HTTPResponse{Status: a.status, Data: a.data, RejectedEvents: a.rejected}, nil
+}
+
+func (a *bodyHTTPAdapter) SendWithContext(_ context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.Send(endpoint, events, headers)
+}
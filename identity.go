@@ -0,0 +1,130 @@
+package ripple
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	// userIDMetadataKey carries the identified user ID on every event.
+	userIDMetadataKey = "userId"
+
+	// traitsMetadataKey carries the identified user's traits on every event.
+	traitsMetadataKey = "traits"
+)
+
+// identity holds the identified user of a client.
+type identity struct {
+	mu     sync.RWMutex
+	userID string
+	traits map[string]any
+}
+
+// set records userID and traits. Traits are merged when the user is
+// unchanged and replaced otherwise.
+func (i *identity) set(userID string, traits map[string]any) (string, map[string]any) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	merged := make(map[string]any, len(traits))
+	if userID == i.userID {
+		for k, v := range i.traits {
+			merged[k] = v
+		}
+	}
+	for k, v := range traits {
+		merged[k] = v
+	}
+	i.userID = userID
+	i.traits = merged
+	return userID, copyMap(merged)
+}
+
+// restore sets the identity unless one was already set in this process.
+func (i *identity) restore(userID string, traits map[string]any) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.userID == "" {
+		i.userID = userID
+		i.traits = copyMap(traits)
+	}
+}
+
+func (i *identity) clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.userID = ""
+	i.traits = nil
+}
+
+// metadata returns the identity as an event metadata layer, or nil if no
+// user is identified.
+func (i *identity) metadata() map[string]any {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.userID == "" {
+		return nil
+	}
+	layer := map[string]any{userIDMetadataKey: i.userID}
+	if len(i.traits) > 0 {
+		layer[traitsMetadataKey] = copyMap(i.traits)
+	}
+	return layer
+}
+
+func copyMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
+
+// Identify associates subsequent events with userID, stamping the "userId"
+// and "traits" metadata keys onto every event. Calling it again for the same
+// user merges traits; a different user replaces them. The identity is
+// persisted when the StorageAdapter implements IdentityStore.
+func (c *Client) Identify(userID string, traits map[string]any) error {
+	if userID == "" {
+		return errors.New("user id cannot be empty")
+	}
+
+	userID, traits = c.identity.set(userID, traits)
+	if store, ok := c.config.StorageAdapter.(IdentityStore); ok {
+		if err := store.SaveIdentity(userID, traits); err != nil {
+			c.loggerAdapter.Error("Failed to persist identity", map[string]any{"error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// Reset forgets the identified user, e.g. on logout, including any persisted
+// identity.
+func (c *Client) Reset() {
+	c.identity.clear()
+	if store, ok := c.config.StorageAdapter.(IdentityStore); ok {
+		if err := store.ClearIdentity(); err != nil {
+			c.loggerAdapter.Error("Failed to clear persisted identity", map[string]any{"error": err.Error()})
+		}
+	}
+}
+
+// restoreIdentity loads the persisted identity, if the StorageAdapter
+// supports it.
+func (c *Client) restoreIdentity() {
+	store, ok := c.config.StorageAdapter.(IdentityStore)
+	if !ok {
+		return
+	}
+	userID, traits, err := store.LoadIdentity()
+	if err != nil {
+		c.loggerAdapter.Error("Failed to restore identity", map[string]any{"error": err.Error()})
+		return
+	}
+	if userID != "" {
+		c.identity.restore(userID, traits)
+	}
+}
@@ -0,0 +1,136 @@
+package ripple
+
+import (
+	"sync"
+	"testing"
+)
+
+// identityStorageAdapter is a mockStorageAdapter that also implements IdentityStore.
+type identityStorageAdapter struct {
+	mockStorageAdapter
+	idMu   sync.Mutex
+	userID string
+	traits map[string]any
+}
+
+func (s *identityStorageAdapter) LoadIdentity() (string, map[string]any, error) {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+	return s.userID, s.traits, nil
+}
+
+func (s *identityStorageAdapter) SaveIdentity(userID string, traits map[string]any) error {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+	s.userID, s.traits = userID, traits
+	return nil
+}
+
+func (s *identityStorageAdapter) ClearIdentity() error {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+	s.userID, s.traits = "", nil
+	return nil
+}
+
+func TestClient_Identify(t *testing.T) {
+	t.Run("should stamp the identity onto events", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if err := client.Identify("user-1", map[string]any{"plan": "pro"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Track("test_event", nil, nil)
+
+		event, _ := client.dispatcher.queue.Dequeue()
+		if event.Metadata["userId"] != "user-1" {
+			t.Errorf("expected userId user-1, got %v", event.Metadata["userId"])
+		}
+		traits, _ := event.Metadata["traits"].(map[string]any)
+		if traits["plan"] != "pro" {
+			t.Errorf("expected plan trait, got %v", event.Metadata["traits"])
+		}
+	})
+
+	t.Run("should merge traits for the same user and replace them for another", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.Identify("user-1", map[string]any{"plan": "pro"})
+		client.Identify("user-1", map[string]any{"country": "IR"})
+		if traits := client.identity.metadata()["traits"].(map[string]any); len(traits) != 2 {
+			t.Fatalf("expected merged traits, got %v", traits)
+		}
+
+		client.Identify("user-2", map[string]any{"plan": "free"})
+		traits := client.identity.metadata()["traits"].(map[string]any)
+		if len(traits) != 1 || traits["plan"] != "free" {
+			t.Fatalf("expected replaced traits, got %v", traits)
+		}
+	})
+
+	t.Run("should let event metadata override the identity", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("userId", "shared")
+		client.Identify("user-1", nil)
+		client.Track("test_event", nil, map[string]any{"userId": "explicit"})
+		client.Track("test_event", nil, nil)
+
+		first, _ := client.dispatcher.queue.Dequeue()
+		second, _ := client.dispatcher.queue.Dequeue()
+		if first.Metadata["userId"] != "explicit" {
+			t.Errorf("expected event metadata to win, got %v", first.Metadata["userId"])
+		}
+		if second.Metadata["userId"] != "user-1" {
+			t.Errorf("expected identity to win over shared metadata, got %v", second.Metadata["userId"])
+		}
+	})
+
+	t.Run("should reject an empty user id", func(t *testing.T) {
+		client := createTestClient()
+		if err := client.Identify("", nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should forget the identity on Reset", func(t *testing.T) {
+		storage := &identityStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Identify("user-1", nil)
+		client.Reset()
+		client.Track("test_event", nil, nil)
+
+		event, _ := client.dispatcher.queue.Dequeue()
+		if _, ok := event.Metadata["userId"]; ok {
+			t.Errorf("expected no userId after Reset, got %v", event.Metadata["userId"])
+		}
+		if userID, _, _ := storage.LoadIdentity(); userID != "" {
+			t.Errorf("expected persisted identity to be cleared, got %q", userID)
+		}
+	})
+
+	t.Run("should persist and restore the identity via IdentityStore", func(t *testing.T) {
+		storage := &identityStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		client.Identify("user-1", map[string]any{"plan": "pro"})
+		client.Dispose()
+
+		restarted, _ := NewClient(config)
+		defer restarted.Dispose()
+		restarted.Track("test_event", nil, nil)
+
+		event, _ := restarted.dispatcher.queue.Dequeue()
+		if event.Metadata["userId"] != "user-1" {
+			t.Errorf("expected restored userId, got %v", event.Metadata["userId"])
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	defaultImportBatchSize         = 100
+	defaultImportRequestsPerSecond = 2
+)
+
+// ImportSummary reports the outcome of ImportEvents.
+type ImportSummary struct {
+	// Sent is the number of events delivered. Events are sent in order, so
+	// a failed import can be resumed from events[Sent:].
+	Sent int
+
+	// Batches is the number of requests that were delivered.
+	Batches int
+}
+
+// ImportEvents sends historical events, such as a backfill, outside the
+// queue: they are not persisted, sampled or passed to BeforeSend hooks, and
+// do not trigger or wait for flushes. Events keep their IssuedAt and are
+// sent in order in batches of ImportBatchSize (and MaxBatchBytes), limited to
+// ImportRequestsPerSecond. Failed requests are retried with the client's
+// BackoffPolicy up to MaxRetries times.
+//
+// Like Track, ImportEvents drops the events without error while tracking is
+// disabled or Do Not Track mode is on. Every event is validated before
+// anything is sent; an invalid event fails the whole import. On a delivery
+// failure or when ctx is done, the import stops and the summary reports how
+// many events were sent.
+func (c *Client) ImportEvents(ctx context.Context, events []Event) (ImportSummary, error) {
+	if c.disposed {
+		return ImportSummary{}, errDisposed
+	}
+	if !c.enabled.Load() || c.doNotTrack.Load() {
+		return ImportSummary{}, nil
+	}
+	for i := range events {
+		if err := validateImportEvent(&events[i]); err != nil {
+			return ImportSummary{}, fmt.Errorf("invalid event at index %d: %w", i, err)
+		}
+	}
+
+	c.Init()
+	imported := make([]Event, len(events))
+	for i, event := range events {
+		if event.Platform == nil {
+			event.Platform = c.platform
+		}
+		if c.config.Redactor != nil {
+			c.config.Redactor.apply(&event)
+		}
+		imported[i] = event
+	}
+
+	c.loggerAdapter.Info("Importing events", map[string]any{"eventsCount": len(imported)})
+	return c.dispatcher.Import(ctx, imported)
+}
+
+// validateImportEvent checks the fields an imported event must carry.
+func validateImportEvent(event *Event) error {
+	if event.Name == "" {
+		return errors.New("event name cannot be empty")
+	}
+	if event.IssuedAt <= 0 {
+		return fmt.Errorf("event %q has no issuedAt", event.Name)
+	}
+	return nil
+}
+
+// Import sends events in order through the import path, bypassing the queue.
+// It stops at the first batch that cannot be delivered.
+func (d *Dispatcher) Import(ctx context.Context, events []Event) (ImportSummary, error) {
+	var summary ImportSummary
+
+	d.mu.Lock()
+	if d.disposed {
+		d.mu.Unlock()
+		return summary, errDisposed
+	}
+	for i := range events {
+		d.stampEventID(&events[i])
+	}
+	d.mu.Unlock()
+
+	for _, batch := range splitBatches(events, d.config.ImportBatchSize, d.config.MaxBatchBytes) {
+		if err := d.importBatch(ctx, batch); err != nil {
+			d.loggerAdapter.Error("Import stopped", map[string]any{
+				"error":       err.Error(),
+				"eventsCount": summary.Sent,
+			})
+			return summary, err
+		}
+		summary.Sent += len(batch)
+		summary.Batches++
+	}
+	return summary, nil
+}
+
+// importBatch delivers one import batch, retrying retryable failures.
+func (d *Dispatcher) importBatch(ctx context.Context, events []Event) error {
+	batchID := newUUID()
+	for attempt := 0; ; attempt++ {
+		if !d.importLimiter.wait(ctx) {
+			return ctx.Err()
+		}
+
+		sentAt := d.clock.Now()
+		endpoint, headers := d.route(events, sentAt, batchID)
+		resp, err := d.send(ctx, Batch{
+			ID:       batchID,
+			Endpoint: endpoint,
+			Events:   events,
+			Headers:  headers,
+			Attempt:  attempt,
+			SentAt:   sentAt,
+		})
+		d.recordEndpointResult(endpoint, resp, err)
+		// Network errors and 5xx responses are retried, as for queued batches.
+		retryable := err != nil
+		if err == nil && (resp.Status < 200 || resp.Status >= 300 || len(rejectedEvents(resp, len(events))) > 0) {
+			err = attemptHTTPError(resp, endpoint, attempt)
+			retryable = resp.Status >= 500
+		}
+		if err == nil {
+			d.batchDelivered(events)
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !retryable || attempt >= d.config.MaxRetries {
+			d.batchFailed(events, err)
+			return err
+		}
+		if !d.clock.Sleep(ctx, d.backoff.NextDelay(attempt)) {
+			return ctx.Err()
+		}
+	}
+}
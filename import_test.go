@@ -0,0 +1,163 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func historicalEvents(n int) []Event {
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = Event{Name: "legacy", IssuedAt: int64(1_600_000_000_000 + i)}
+	}
+	return events
+}
+
+func TestClient_ImportEvents(t *testing.T) {
+	t.Run("should send events in order in import batches", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter()
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.ImportBatchSize = 2
+		config.ImportRequestsPerSecond = 1000
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		events := historicalEvents(5)
+		summary, err := client.ImportEvents(context.Background(), events)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Sent != 5 || summary.Batches != 3 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+
+		var issuedAt []int64
+		for _, req := range httpAdapter.Requests() {
+			for _, event := range req.Events {
+				issuedAt = append(issuedAt, event.IssuedAt)
+				if event.Platform == nil {
+					t.Fatal("expected imported events to get the client platform")
+				}
+			}
+		}
+		for i, at := range issuedAt {
+			if at != events[i].IssuedAt {
+				t.Fatalf("expected events in order with their IssuedAt, got %v", issuedAt)
+			}
+		}
+		if events[0].Platform != nil {
+			t.Fatal("expected the caller's events to be unchanged")
+		}
+		if client.dispatcher.queue.Len() != 0 {
+			t.Fatal("expected imports to bypass the queue")
+		}
+	})
+
+	t.Run("should reject an invalid event before sending anything", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter()
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		events := historicalEvents(3)
+		events[1].IssuedAt = 0
+		if _, err := client.ImportEvents(context.Background(), events); err == nil {
+			t.Fatal("expected error")
+		}
+		if httpAdapter.Calls() != 0 {
+			t.Fatal("expected nothing to be sent")
+		}
+	})
+
+	t.Run("should retry server errors and stop at a client error", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 200},
+			adapters.Scenario{Status: 503},
+			adapters.Scenario{Status: 200},
+			adapters.Scenario{Status: 400},
+		)
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.ImportBatchSize = 1
+		config.ImportRequestsPerSecond = 1000
+		config.BackoffPolicy = ConstantBackoff(time.Millisecond)
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		summary, err := client.ImportEvents(context.Background(), historicalEvents(4))
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != 400 {
+			t.Fatalf("expected the 400 error, got %v", err)
+		}
+		if summary.Sent != 2 || httpAdapter.Calls() != 4 {
+			t.Fatalf("expected two events sent in four calls, got %+v and %d calls", summary, httpAdapter.Calls())
+		}
+	})
+
+	t.Run("should be limited by its own rate", func(t *testing.T) {
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewScriptedHTTPAdapter()
+		config.ImportBatchSize = 1
+		config.ImportRequestsPerSecond = 20
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		start := time.Now()
+		if _, err := client.ImportEvents(context.Background(), historicalEvents(3)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+			t.Fatalf("expected three requests at 20/s to take about 100ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("should stop when the context is done", func(t *testing.T) {
+		config := createTestConfig()
+		config.HTTPAdapter = adapters.NewScriptedHTTPAdapter()
+		config.ImportBatchSize = 1
+		config.ImportRequestsPerSecond = 1
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		summary, err := client.ImportEvents(ctx, historicalEvents(3))
+		if !errors.Is(err, context.DeadlineExceeded) || summary.Sent != 1 {
+			t.Fatalf("expected to stop after the first event, got %+v, %v", summary, err)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		gate func(c *Client)
+	}{
+		{"while tracking is disabled", (*Client).Disable},
+		{"in Do Not Track mode", func(c *Client) { c.SetDoNotTrack(true) }},
+	} {
+		t.Run("should drop events "+tc.name, func(t *testing.T) {
+			httpAdapter := adapters.NewScriptedHTTPAdapter()
+			config := createTestConfig()
+			config.HTTPAdapter = httpAdapter
+			client, _ := NewClient(config)
+			defer client.Dispose()
+
+			tc.gate(client)
+			summary, err := client.ImportEvents(context.Background(), historicalEvents(3))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if summary != (ImportSummary{}) {
+				t.Fatalf("expected an empty summary, got %+v", summary)
+			}
+			if httpAdapter.Calls() != 0 {
+				t.Fatal("expected nothing to be sent")
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+)
+
+func eventNames(prefix string, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s_%d", prefix, i)
+	}
+	return names
+}
+
+func trackAll(t *testing.T, client *ripple.Client, names []string) {
+	t.Helper()
+	for _, name := range names {
+		if err := client.Track(name, map[string]any{"n": name}, nil); err != nil {
+			t.Fatalf("track %s: %v", name, err)
+		}
+	}
+}
+
+func TestDelivery_EndToEnd(t *testing.T) {
+	collector := NewCollector(t)
+	client := NewClient(t, collector, NewFileStorage(t), nil)
+	defer client.Dispose()
+
+	names := eventNames("e2e", 12)
+	trackAll(t, client, names)
+	client.Flush()
+
+	collector.WaitForNames(t, 5*time.Second, names...)
+	if got := len(collector.Received()); got != len(names) {
+		t.Fatalf("expected exactly %d events, got %d", len(names), got)
+	}
+}
+
+func TestDelivery_RejectsWrongAPIKey(t *testing.T) {
+	collector := NewCollector(t)
+	var results []error
+	client := NewClient(t, collector, NewFileStorage(t), func(c *ripple.ClientConfig) {
+		c.APIKey = "wrong"
+		c.OnDelivery = func(_ []ripple.Event, err error) { results = append(results, err) }
+	})
+	defer client.Dispose()
+
+	client.Track("unauthorized", nil, nil)
+	client.Flush()
+
+	if len(collector.Received()) != 0 {
+		t.Fatal("expected collector to reject the batch")
+	}
+	if len(results) != 1 || results[0] == nil {
+		t.Fatalf("expected one failed delivery, got %v", results)
+	}
+}
+
+func TestDelivery_ConnectionDroppedMidBatch(t *testing.T) {
+	collector := NewCollector(t)
+	collector.DropConnectionNext(1)
+	client := NewClient(t, collector, NewFileStorage(t), nil)
+	defer client.Dispose()
+
+	names := eventNames("dropped", 5)
+	trackAll(t, client, names)
+
+	collector.WaitForNames(t, 10*time.Second, names...)
+}
+
+func TestDelivery_AtLeastOnceWhenResponseLost(t *testing.T) {
+	collector := NewCollector(t)
+	collector.DropResponseNext(1)
+	client := NewClient(t, collector, NewFileStorage(t), nil)
+	defer client.Dispose()
+
+	names := eventNames("lost_response", 5)
+	trackAll(t, client, names)
+
+	collector.WaitForNames(t, 10*time.Second, names...)
+	// The first delivery was accepted but not acknowledged, so the retry
+	// duplicates it: delivery is at-least-once, never at-most-once.
+	if got := len(collector.Received()); got < 2*len(names) {
+		t.Fatalf("expected the unacknowledged batch to be redelivered, got %d events", got)
+	}
+}
+
+func TestDelivery_ServerErrorsThenRecovery(t *testing.T) {
+	collector := NewCollector(t)
+	collector.FailNext(2, http.StatusServiceUnavailable)
+	client := NewClient(t, collector, NewFileStorage(t), nil)
+	defer client.Dispose()
+
+	names := eventNames("recovered", 5)
+	trackAll(t, client, names)
+
+	collector.WaitForNames(t, 15*time.Second, names...)
+}
+
+func TestDelivery_PersistsAcrossRestart(t *testing.T) {
+	collector := NewCollector(t)
+	storage := NewFileStorage(t)
+
+	names := eventNames("restart", 3)
+	first := NewClient(t, collector, storage, func(c *ripple.ClientConfig) {
+		c.FlushInterval = time.Hour
+		c.MaxBatchSize = 100
+	})
+	trackAll(t, first, names)
+	first.Dispose()
+
+	if len(collector.Received()) != 0 {
+		t.Fatal("expected nothing delivered before restart")
+	}
+
+	second := NewClient(t, collector, storage, nil)
+	defer second.Dispose()
+	second.Init()
+
+	collector.WaitForNames(t, 5*time.Second, names...)
+}
+
+func TestDelivery_CorruptStorage(t *testing.T) {
+	collector := NewCollector(t)
+	storage := NewFileStorage(t)
+	if err := storage.Corrupt(); err != nil {
+		t.Fatalf("failed to corrupt storage: %v", err)
+	}
+
+	client := NewClient(t, collector, storage, nil)
+	defer client.Dispose()
+
+	names := eventNames("after_corruption", 3)
+	trackAll(t, client, names)
+	client.Flush()
+
+	collector.WaitForNames(t, 5*time.Second, names...)
+	for _, event := range collector.Received() {
+		if event.Platform == nil || event.Platform.Type != "server" {
+			t.Fatalf("unexpected event from corrupt storage: %+v", event)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package integration holds end-to-end tests that run the SDK against the
+// embeddable collector over real HTTP, with fault injection for dropped
+// connections, server errors, and corrupt storage.
+//
+// The tests are excluded from the default build; run them with:
+//
+//	go test -tags integration ./integration/...
+package integration
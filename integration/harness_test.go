@@ -0,0 +1,238 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+	"github.com/Tap30/ripple-go/server"
+)
+
+const testAPIKey = "integration-key"
+
+// Collector is an embeddable ripple server with a recording sink and
+// fault injection.
+type Collector struct {
+	URL string
+
+	mu       sync.Mutex
+	received []adapters.Event
+	faults   []fault
+	server   *httptest.Server
+}
+
+// fault is applied to the next request instead of handling it.
+type fault struct {
+	status     int
+	dropBefore bool // close the connection before reading the batch
+	dropAfter  bool // close the connection after the sink accepted the batch
+}
+
+// NewCollector starts a collector that is closed when the test ends.
+func NewCollector(t *testing.T) *Collector {
+	t.Helper()
+	c := &Collector{}
+
+	sink := server.SinkFunc(func(_ context.Context, events []adapters.Event) error {
+		c.mu.Lock()
+		c.received = append(c.received, events...)
+		c.mu.Unlock()
+		return nil
+	})
+	handler, err := server.New(server.Config{
+		Sink:       sink,
+		APIKeys:    []string{testAPIKey},
+		Middleware: []server.Middleware{c.injectFaults},
+	})
+	if err != nil {
+		t.Fatalf("failed to create collector: %v", err)
+	}
+
+	c.server = httptest.NewServer(handler)
+	c.URL = c.server.URL
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+// FailNext responds to the next n requests with status.
+func (c *Collector) FailNext(n, status int) {
+	c.addFaults(n, fault{status: status})
+}
+
+// DropConnectionNext closes the connection of the next n requests before
+// the batch is accepted.
+func (c *Collector) DropConnectionNext(n int) {
+	c.addFaults(n, fault{dropBefore: true})
+}
+
+// DropResponseNext accepts the next n batches but closes the connection
+// before responding, so the client cannot tell the batch was delivered.
+func (c *Collector) DropResponseNext(n int) {
+	c.addFaults(n, fault{dropAfter: true})
+}
+
+func (c *Collector) addFaults(n int, f fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < n; i++ {
+		c.faults = append(c.faults, f)
+	}
+}
+
+func (c *Collector) nextFault() (fault, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.faults) == 0 {
+		return fault{}, false
+	}
+	f := c.faults[0]
+	c.faults = c.faults[1:]
+	return f, true
+}
+
+func (c *Collector) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := c.nextFault()
+		switch {
+		case !ok:
+			next.ServeHTTP(w, r)
+		case f.dropBefore:
+			hijackAndClose(w)
+		case f.dropAfter:
+			next.ServeHTTP(httptest.NewRecorder(), r)
+			hijackAndClose(w)
+		default:
+			w.WriteHeader(f.status)
+		}
+	})
+}
+
+func hijackAndClose(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err == nil {
+		_ = conn.Close()
+	}
+}
+
+// Received returns a copy of every event accepted by the collector.
+func (c *Collector) Received() []adapters.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]adapters.Event(nil), c.received...)
+}
+
+// WaitForNames waits until every name has been received at least once.
+func (c *Collector) WaitForNames(t *testing.T, timeout time.Duration, names ...string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		seen := make(map[string]bool)
+		for _, event := range c.Received() {
+			seen[event.Name] = true
+		}
+		missing := 0
+		for _, name := range names {
+			if !seen[name] {
+				missing++
+			}
+		}
+		if missing == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d of %d events", missing, len(names))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// FileStorage is a JSON file StorageAdapter that survives client restarts
+// and can be corrupted on demand.
+type FileStorage struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStorage creates file storage in a temporary directory.
+func NewFileStorage(t *testing.T) *FileStorage {
+	return &FileStorage{path: filepath.Join(t.TempDir(), "events.json")}
+}
+
+func (f *FileStorage) Save(events []adapters.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, data, 0o600)
+}
+
+func (f *FileStorage) Load() ([]adapters.Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var events []adapters.Event
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (f *FileStorage) Clear() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *FileStorage) Close() error {
+	return nil
+}
+
+// Corrupt overwrites the stored events with invalid JSON.
+func (f *FileStorage) Corrupt() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return os.WriteFile(f.path, []byte(`[{"name":`), 0o600)
+}
+
+// NewClient creates a client wired to the collector over real HTTP.
+func NewClient(t *testing.T, collector *Collector, storage adapters.StorageAdapter, configure func(*ripple.ClientConfig)) *ripple.Client {
+	t.Helper()
+	config := ripple.ClientConfig{
+		APIKey:         testAPIKey,
+		Endpoint:       collector.URL,
+		HTTPAdapter:    adapters.NewNetHTTPAdapter(),
+		StorageAdapter: storage,
+		LoggerAdapter:  adapters.NewNoOpLoggerAdapter(),
+		FlushInterval:  50 * time.Millisecond,
+		MaxBatchSize:   5,
+	}
+	if configure != nil {
+		configure(&config)
+	}
+	client, err := ripple.NewClient(config)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
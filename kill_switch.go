@@ -0,0 +1,25 @@
+package ripple
+
+// Disable turns tracking into a no-op: Track, TrackNow and the other tracking
+// methods drop events without error until Enable is called. Events queued
+// before Disable are kept and delivered as usual, and Flush, Dispose and
+// Close are unaffected. It is safe to call concurrently with Track.
+//
+// Use it to gate analytics behind user consent or a feature flag.
+func (c *Client) Disable() {
+	if c.enabled.Swap(false) {
+		c.loggerAdapter.Info("Tracking disabled")
+	}
+}
+
+// Enable resumes tracking after Disable.
+func (c *Client) Enable() {
+	if !c.enabled.Swap(true) {
+		c.loggerAdapter.Info("Tracking enabled")
+	}
+}
+
+// Enabled reports whether tracked events are accepted.
+func (c *Client) Enabled() bool {
+	return c.enabled.Load()
+}
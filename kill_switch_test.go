@@ -0,0 +1,63 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClient_KillSwitch(t *testing.T) {
+	t.Run("should drop events while disabled and keep queued ones", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.Track("before", nil, nil)
+		client.Disable()
+		if client.Enabled() {
+			t.Fatal("expected client to report disabled")
+		}
+		if err := client.Track("during", nil, nil); err != nil {
+			t.Fatalf("expected a silent no-op, got %v", err)
+		}
+		if err := client.TrackNow(context.Background(), "during", nil, nil); err != nil {
+			t.Fatalf("expected a silent no-op, got %v", err)
+		}
+		if client.dispatcher.queue.Len() != 1 {
+			t.Fatalf("expected the queued event to be kept, got %d", client.dispatcher.queue.Len())
+		}
+
+		client.Flush()
+		httpAdapter.mu.Lock()
+		calls := httpAdapter.calls
+		httpAdapter.mu.Unlock()
+		if calls != 1 {
+			t.Fatalf("expected queued events to flush while disabled, got %d calls", calls)
+		}
+
+		client.Enable()
+		_ = client.Track("after", nil, nil)
+		if client.dispatcher.queue.Len() != 1 {
+			t.Fatal("expected tracking to resume after Enable")
+		}
+	})
+
+	t.Run("should start disabled when configured", func(t *testing.T) {
+		client, err := NewClientWithOptions(
+			WithAPIKey("test-key"),
+			WithEndpoint("http://test.com"),
+			WithHTTPAdapter(&mockHTTPAdapter{}),
+			WithEnabled(false),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer client.Dispose()
+
+		_ = client.Track("a", nil, nil)
+		if client.Enabled() || client.dispatcher.queue.Len() != 0 {
+			t.Fatal("expected the client to start disabled")
+		}
+	})
+}
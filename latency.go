@@ -0,0 +1,49 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyRecorder accumulates end-to-end delivery latency, measured from an
+// event's IssuedAt to the sentAt of the attempt that delivered it.
+type latencyRecorder struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	max   time.Duration
+}
+
+// record adds the latency of each delivered event.
+func (l *latencyRecorder) record(events []Event, sentAt time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sentAtMs := sentAt.UnixMilli()
+	for _, event := range events {
+		if event.IssuedAt <= 0 {
+			continue
+		}
+		latency := time.Duration(sentAtMs-event.IssuedAt) * time.Millisecond
+		if latency < 0 {
+			latency = 0
+		}
+		l.count++
+		l.total += latency
+		if latency > l.max {
+			l.max = latency
+		}
+	}
+}
+
+// snapshot returns the current latency summary.
+func (l *latencyRecorder) snapshot() LatencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := LatencyStats{Count: l.count, Max: l.max}
+	if l.count > 0 {
+		stats.Average = l.total / time.Duration(l.count)
+	}
+	return stats
+}
@@ -0,0 +1,113 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// logRateWindow is how long LogRateLimit counts messages for.
+const logRateWindow = time.Minute
+
+// logWindow counts the messages of one category in the current window.
+type logWindow struct {
+	start      time.Time
+	logged     int
+	suppressed int
+	warn       bool
+}
+
+// rateLimitedLogger logs at most limit Warn and Error messages per minute for
+// each message, i.e. per format string, so a retry loop cannot flood the
+// application log. Once a category's window has passed, the number of
+// messages suppressed in it is logged before the next Warn or Error. Debug
+// and Info messages are passed through.
+type rateLimitedLogger struct {
+	LoggerAdapter
+	limit int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*logWindow
+}
+
+// newRateLimitedLogger wraps inner with a LogRateLimit of limit. The result
+// implements adapters.LevelSetter if inner does.
+func newRateLimitedLogger(inner LoggerAdapter, limit int, clock Clock) LoggerAdapter {
+	limited := &rateLimitedLogger{
+		LoggerAdapter: inner,
+		limit:         limit,
+		now:           clock.Now,
+		windows:       make(map[string]*logWindow),
+	}
+	if setter, ok := inner.(adapters.LevelSetter); ok {
+		return struct {
+			*rateLimitedLogger
+			adapters.LevelSetter
+		}{limited, setter}
+	}
+	return limited
+}
+
+func (l *rateLimitedLogger) Warn(message string, args ...any) {
+	if l.allow(message, true) {
+		l.LoggerAdapter.Warn(message, args...)
+	}
+}
+
+func (l *rateLimitedLogger) Error(message string, args ...any) {
+	if l.allow(message, false) {
+		l.LoggerAdapter.Error(message, args...)
+	}
+}
+
+// allow counts message and reports whether it should be logged. It first
+// logs summaries for categories whose window has passed.
+func (l *rateLimitedLogger) allow(message string, warn bool) bool {
+	now := l.now()
+
+	l.mu.Lock()
+	var summaries []logSummary
+	for category, window := range l.windows {
+		if now.Sub(window.start) < logRateWindow {
+			continue
+		}
+		if window.suppressed > 0 {
+			summaries = append(summaries, logSummary{category, window.suppressed, window.warn})
+		}
+		delete(l.windows, category)
+	}
+	window, ok := l.windows[message]
+	if !ok {
+		window = &logWindow{start: now, warn: warn}
+		l.windows[message] = window
+	}
+	allowed := window.logged < l.limit
+	if allowed {
+		window.logged++
+	} else {
+		window.suppressed++
+	}
+	l.mu.Unlock()
+
+	for _, s := range summaries {
+		s.log(l.LoggerAdapter)
+	}
+	return allowed
+}
+
+// logSummary reports the messages suppressed in a passed window.
+type logSummary struct {
+	message string
+	count   int
+	warn    bool
+}
+
+func (s logSummary) log(logger LoggerAdapter) {
+	if s.warn {
+		logger.Warn("Suppressed %d similar messages: %s", s.count, s.message)
+	} else {
+		logger.Error("Suppressed %d similar messages: %s", s.count, s.message)
+	}
+}
@@ -0,0 +1,99 @@
+package ripple
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestRateLimitedLogger(t *testing.T) {
+	newLogger := func(limit int) (*rateLimitedLogger, *mockLogger, *time.Time) {
+		inner := &mockLogger{}
+		now := time.Unix(1700000000, 0)
+		logger := newRateLimitedLogger(inner, limit, systemClock{}).(*rateLimitedLogger)
+		logger.now = func() time.Time { return now }
+		return logger, inner, &now
+	}
+
+	t.Run("should limit each message per minute", func(t *testing.T) {
+		logger, inner, _ := newLogger(2)
+		for range 5 {
+			logger.Warn("5xx server error, retrying", map[string]any{"attempt": 1})
+		}
+		logger.Warn("Network error, retrying")
+		logger.Error("Failed to load spilled events")
+
+		if inner.warnCount != 3 || inner.errCount != 1 {
+			t.Fatalf("expected 3 warnings and 1 error, got %d and %d", inner.warnCount, inner.errCount)
+		}
+	})
+
+	t.Run("should summarize suppressed messages after the window", func(t *testing.T) {
+		logger, inner, now := newLogger(1)
+		for range 4 {
+			logger.Error("Network error occurred")
+		}
+		*now = now.Add(time.Minute)
+		logger.Warn("Network error, retrying")
+
+		if inner.errCount != 2 || !strings.Contains(inner.errs[1], "Suppressed 3 similar messages: Network error occurred") {
+			t.Fatalf("expected a summary of 3 suppressed errors, got %v", inner.errs)
+		}
+		if inner.warnCount != 1 {
+			t.Fatalf("expected the new warning to be logged, got %v", inner.warnings)
+		}
+
+		logger.Error("Network error occurred")
+		if inner.errCount != 3 {
+			t.Fatal("expected a new window to start after the summary")
+		}
+	})
+
+	t.Run("should pass debug and info messages through", func(t *testing.T) {
+		logger, inner, _ := newLogger(1)
+		for range 3 {
+			logger.Debug("Flushing events")
+			logger.Info("Flushing events")
+		}
+		if len(inner.debugs) != 3 || len(inner.infos) != 3 {
+			t.Fatalf("expected every message, got %d debug and %d info", len(inner.debugs), len(inner.infos))
+		}
+	})
+
+	t.Run("should keep the inner logger's level setter", func(t *testing.T) {
+		inner := adapters.NewPrintLoggerAdapter(adapters.LogLevelWarn)
+		if _, ok := newRateLimitedLogger(inner, 1, systemClock{}).(adapters.LevelSetter); !ok {
+			t.Fatal("expected a LevelSetter")
+		}
+		if _, ok := newRateLimitedLogger(&mockLogger{}, 1, systemClock{}).(adapters.LevelSetter); ok {
+			t.Fatal("expected no LevelSetter")
+		}
+	})
+}
+
+func TestClientLogRateLimit(t *testing.T) {
+	logger := &mockLogger{}
+	config := createTestConfig()
+	config.LoggerAdapter = logger
+	config.LogRateLimit = 1
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Dispose()
+	for range 3 {
+		_ = client.Track("event", nil, nil)
+	}
+
+	if logger.warnCount != 1 {
+		t.Fatalf("expected 1 warning, got %d: %v", logger.warnCount, logger.warnings)
+	}
+
+	config.LogRateLimit = -1
+	if _, err := NewClient(config); err == nil {
+		t.Fatal("expected a validation error")
+	}
+}
@@ -0,0 +1,230 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+var (
+	errManagerDisposed = errors.New("manager is disposed")
+	errClientNameEmpty = errors.New("client name cannot be empty")
+)
+
+// ManagerConfig configures a Manager.
+type ManagerConfig struct {
+	// HTTPAdapter is shared by every client registered without one, so they
+	// reuse a single connection pool.
+	//
+	// Default: adapters.NewNetHTTPAdapter().
+	HTTPAdapter HTTPAdapter
+
+	// Clock drives the shared flush scheduler and is used by every client
+	// registered without one.
+	//
+	// Default: the system clock.
+	Clock Clock
+}
+
+// Manager owns multiple named clients, e.g. one per product or endpoint.
+// Its clients share one flush scheduler and, unless they set their own
+// HTTPAdapter, one HTTP adapter.
+type Manager struct {
+	httpAdapter HTTPAdapter
+	clock       Clock
+	scheduler   *flushScheduler
+
+	mu       sync.RWMutex
+	clients  map[string]*Client
+	disposed bool
+}
+
+// NewManager creates a Manager with no clients.
+func NewManager(config ManagerConfig) *Manager {
+	if config.HTTPAdapter == nil {
+		config.HTTPAdapter = adapters.NewNetHTTPAdapter()
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &Manager{
+		httpAdapter: config.HTTPAdapter,
+		clock:       config.Clock,
+		scheduler:   newFlushScheduler(config.Clock),
+		clients:     make(map[string]*Client),
+	}
+}
+
+// Register creates a client from config under name. The client is returned
+// uninitialized; call Init before tracking, as with NewClient. A config
+// without an HTTPAdapter or Clock uses the manager's. It returns an error if
+// the name is empty or taken, the config is invalid, or the config sets a
+// Clock other than the manager's, which the shared scheduler could not
+// follow.
+func (m *Manager) Register(name string, config ClientConfig) (*Client, error) {
+	if name == "" {
+		return nil, errClientNameEmpty
+	}
+	if config.HTTPAdapter == nil {
+		config.HTTPAdapter = m.httpAdapter
+	}
+	if config.Clock == nil {
+		config.Clock = m.clock
+	} else if config.Clock != m.clock {
+		return nil, fmt.Errorf("client %q: clock must be set on the manager", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.disposed {
+		return nil, errManagerDisposed
+	}
+	if _, ok := m.clients[name]; ok {
+		return nil, fmt.Errorf("client %q is already registered", name)
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("client %q: %w", name, err)
+	}
+	client.dispatcher.scheduler = m.scheduler
+	m.clients[name] = client
+	return client, nil
+}
+
+// Client returns the client registered under name, or nil if there is none.
+func (m *Manager) Client(name string) *Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clients[name]
+}
+
+// Names returns the names of the registered clients, sorted.
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// DisposeAll disposes every client concurrently and stops the shared
+// scheduler. Further Register calls fail.
+func (m *Manager) DisposeAll() {
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*Client)
+	m.disposed = true
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Dispose()
+		}()
+	}
+	wg.Wait()
+	m.scheduler.stop()
+}
+
+// flushScheduler runs the periodic flushes of several dispatchers on a
+// single timer, armed for the earliest one due.
+type flushScheduler struct {
+	clock Clock
+
+	mu         sync.Mutex
+	due        map[*Dispatcher]time.Time
+	timer      Timer
+	next       time.Time
+	generation int
+	stopped    bool
+}
+
+func newFlushScheduler(clock Clock) *flushScheduler {
+	return &flushScheduler{clock: clock, due: make(map[*Dispatcher]time.Time)}
+}
+
+// schedule flushes d after delay, unless a flush is already scheduled.
+func (s *flushScheduler) schedule(d *Dispatcher, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.due[d]; ok || s.stopped {
+		return
+	}
+	at := s.clock.Now().Add(delay)
+	s.due[d] = at
+	if s.timer == nil || at.Before(s.next) {
+		s.arm(at)
+	}
+}
+
+// cancel removes d's scheduled flush, if any.
+func (s *flushScheduler) cancel(d *Dispatcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.due, d)
+}
+
+// stop cancels every scheduled flush.
+func (s *flushScheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	clear(s.due)
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// arm replaces the timer with one firing at at. Must hold mu.
+func (s *flushScheduler) arm(at time.Time) {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.generation++
+	generation := s.generation
+	s.next = at
+	s.timer = s.clock.AfterFunc(at.Sub(s.clock.Now()), func() { s.run(generation) })
+}
+
+// run flushes every dispatcher that is due and re-arms the timer for the
+// next one. Calls from a replaced timer are ignored.
+func (s *flushScheduler) run(generation int) {
+	s.mu.Lock()
+	if generation != s.generation || s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.timer = nil
+	now := s.clock.Now()
+	var ready []*Dispatcher
+	var next time.Time
+	for d, at := range s.due {
+		if !at.After(now) {
+			ready = append(ready, d)
+			delete(s.due, d)
+		} else if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	if !next.IsZero() {
+		s.arm(next)
+	}
+	s.mu.Unlock()
+
+	// Flush concurrently so a slow endpoint does not delay other clients.
+	for _, d := range ready {
+		d.resources.spawn(d.Flush)
+	}
+}
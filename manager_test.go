@@ -0,0 +1,139 @@
+package ripple
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// managerTestConfig returns a client config without an HTTP adapter, so the
+// manager's is used.
+func managerTestConfig(flushInterval time.Duration) ClientConfig {
+	config := createTestConfig()
+	config.HTTPAdapter = nil
+	config.FlushInterval = flushInterval
+	return config
+}
+
+// waitForCalls polls adapter until it has at least n calls.
+func waitForCalls(t *testing.T, adapter *mockHTTPAdapter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for adapter.getCalls() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d calls, got %d", n, adapter.getCalls())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Run("should register and look up named clients", func(t *testing.T) {
+		shared := &mockHTTPAdapter{}
+		m := NewManager(ManagerConfig{HTTPAdapter: shared})
+		defer m.DisposeAll()
+
+		checkout, err := m.Register("checkout", managerTestConfig(0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		own := &mockHTTPAdapter{}
+		config := managerTestConfig(0)
+		config.HTTPAdapter = own
+		search, _ := m.Register("search", config)
+
+		if m.Client("checkout") != checkout || m.Client("search") != search || m.Client("missing") != nil {
+			t.Fatal("unexpected lookup result")
+		}
+		if names := m.Names(); !slices.Equal(names, []string{"checkout", "search"}) {
+			t.Fatalf("unexpected names: %v", names)
+		}
+		if checkout.config.HTTPAdapter != shared || search.config.HTTPAdapter != own {
+			t.Fatal("expected the shared adapter only for clients without one")
+		}
+	})
+
+	t.Run("should reject invalid registrations", func(t *testing.T) {
+		m := NewManager(ManagerConfig{HTTPAdapter: &mockHTTPAdapter{}})
+		defer m.DisposeAll()
+
+		if _, err := m.Register("", managerTestConfig(0)); !errors.Is(err, errClientNameEmpty) {
+			t.Fatalf("expected errClientNameEmpty, got %v", err)
+		}
+		_, _ = m.Register("a", managerTestConfig(0))
+		if _, err := m.Register("a", managerTestConfig(0)); err == nil {
+			t.Fatal("expected an error for a duplicate name")
+		}
+		config := managerTestConfig(0)
+		config.APIKey = ""
+		var configErr *ConfigError
+		if _, err := m.Register("b", config); !errors.As(err, &configErr) {
+			t.Fatalf("expected a ConfigError, got %v", err)
+		}
+		config = managerTestConfig(0)
+		config.Clock = &fakeManagerClock{}
+		if _, err := m.Register("c", config); err == nil {
+			t.Fatal("expected an error for a client clock")
+		}
+	})
+
+	t.Run("should flush each client at its own interval on the shared scheduler", func(t *testing.T) {
+		m := NewManager(ManagerConfig{})
+		defer m.DisposeAll()
+
+		fastAdapter, slowAdapter := &mockHTTPAdapter{}, &mockHTTPAdapter{}
+		fastConfig := managerTestConfig(20 * time.Millisecond)
+		fastConfig.HTTPAdapter = fastAdapter
+		slowConfig := managerTestConfig(time.Hour)
+		slowConfig.HTTPAdapter = slowAdapter
+		fast, _ := m.Register("fast", fastConfig)
+		slow, _ := m.Register("slow", slowConfig)
+		fast.Init()
+		slow.Init()
+
+		_ = slow.Track("a", nil, nil)
+		_ = fast.Track("b", nil, nil)
+
+		waitForCalls(t, fastAdapter, 1)
+		if slowAdapter.getCalls() != 0 {
+			t.Fatal("expected the slow client not to flush yet")
+		}
+		if fast.dispatcher.timer != nil || slow.dispatcher.timer != nil {
+			t.Fatal("expected no per-client flush timers")
+		}
+
+		_ = fast.Track("c", nil, nil)
+		waitForCalls(t, fastAdapter, 2)
+	})
+
+	t.Run("should dispose all clients and refuse new ones", func(t *testing.T) {
+		m := NewManager(ManagerConfig{HTTPAdapter: &mockHTTPAdapter{}})
+		a, _ := m.Register("a", managerTestConfig(time.Hour))
+		b, _ := m.Register("b", managerTestConfig(time.Hour))
+		a.Init()
+		b.Init()
+		_ = a.Track("x", nil, nil)
+		_ = b.Track("y", nil, nil)
+
+		m.DisposeAll()
+
+		for _, client := range []*Client{a, b} {
+			storage := client.config.StorageAdapter.(*mockStorageAdapter)
+			if len(storage.getSaved()) != 1 {
+				t.Fatalf("expected each client to persist its event on dispose, got %v", storage.getSaved())
+			}
+		}
+		if m.Client("a") != nil || len(m.Names()) != 0 {
+			t.Fatal("expected no clients after DisposeAll")
+		}
+		if _, err := m.Register("c", managerTestConfig(0)); !errors.Is(err, errManagerDisposed) {
+			t.Fatalf("expected errManagerDisposed, got %v", err)
+		}
+	})
+}
+
+// fakeManagerClock is a Clock distinct from the manager's.
+type fakeManagerClock struct {
+	systemClock
+}
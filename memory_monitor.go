@@ -0,0 +1,114 @@
+package ripple
+
+import (
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMemoryCheckInterval = 5 * time.Second
+
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+)
+
+// MemoryPressureFunc reports whether the host process is close to its memory
+// limit. When it returns true, the dispatcher persists its in-memory queue to
+// storage and keeps new events on disk until the next flush.
+type MemoryPressureFunc func() bool
+
+// HeapAboveBytes reports memory pressure once live heap objects exceed limit
+// bytes.
+func HeapAboveBytes(limit uint64) MemoryPressureFunc {
+	return func() bool {
+		return heapObjectBytes() > limit
+	}
+}
+
+// HeapAboveMemoryLimitFraction reports memory pressure once live heap objects
+// exceed fraction of the runtime soft memory limit (GOMEMLIMIT or
+// debug.SetMemoryLimit). It never reports pressure when no limit is set.
+func HeapAboveMemoryLimitFraction(fraction float64) MemoryPressureFunc {
+	return func() bool {
+		limit := debug.SetMemoryLimit(-1)
+		if limit <= 0 || limit == math.MaxInt64 {
+			return false
+		}
+		return float64(heapObjectBytes()) > fraction*float64(limit)
+	}
+}
+
+// heapObjectBytes reads live heap object bytes without stopping the world.
+func heapObjectBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// memoryMonitor periodically polls a MemoryPressureFunc and invokes onPressure
+// whenever it reports true.
+type memoryMonitor struct {
+	check      MemoryPressureFunc
+	interval   time.Duration
+	onPressure func()
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+	spawn      func(func())
+	clock      Clock
+	mu         sync.Mutex
+}
+
+func newMemoryMonitor(check MemoryPressureFunc, interval time.Duration, onPressure func()) *memoryMonitor {
+	if interval <= 0 {
+		interval = defaultMemoryCheckInterval
+	}
+	return &memoryMonitor{check: check, interval: interval, onPressure: onPressure, spawn: goSpawn, clock: systemClock{}}
+}
+
+// Start begins polling until Stop is called.
+func (m *memoryMonitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopCh != nil {
+		return
+	}
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	m.stopCh = stopCh
+	m.doneCh = doneCh
+
+	m.spawn(func() {
+		defer close(doneCh)
+		ticker := m.clock.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if m.check() {
+					m.onPressure()
+				}
+			case <-stopCh:
+				return
+			}
+		}
+	})
+}
+
+// Stop halts polling and waits for an in-flight check to finish, so that
+// onPressure is never invoked after Stop returns.
+func (m *memoryMonitor) Stop() {
+	m.mu.Lock()
+	stopCh, doneCh := m.stopCh, m.doneCh
+	m.stopCh, m.doneCh = nil, nil
+	m.mu.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+		<-doneCh
+	}
+}
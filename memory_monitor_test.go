@@ -0,0 +1,313 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestHeapAboveBytes(t *testing.T) {
+	if !HeapAboveBytes(0)() {
+		t.Error("expected pressure with a zero-byte limit")
+	}
+	if HeapAboveBytes(1 << 62)() {
+		t.Error("expected no pressure with a huge limit")
+	}
+}
+
+func TestHeapAboveMemoryLimitFraction_NoLimit(t *testing.T) {
+	// Tests run without GOMEMLIMIT, so no pressure is reported
+	if HeapAboveMemoryLimitFraction(0)() {
+		t.Error("expected no pressure without a memory limit")
+	}
+}
+
+func TestMemoryMonitor_StartStop(t *testing.T) {
+	var calls atomic.Int32
+	m := newMemoryMonitor(func() bool { return true }, 5*time.Millisecond, func() { calls.Add(1) })
+	m.Start()
+	m.Start() // idempotent
+	time.Sleep(30 * time.Millisecond)
+	m.Stop()
+
+	seen := calls.Load()
+	if seen == 0 {
+		t.Fatal("expected onPressure to be called")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if calls.Load() != seen {
+		t.Error("expected no calls after Stop")
+	}
+}
+
+func TestDispatcher_SpillUnderMemoryPressure(t *testing.T) {
+	storage := &mockStorageAdapter{}
+	httpAdapter := &mockHTTPAdapter{}
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:            "test-key",
+		APIKeyHeader:      "X-API-Key",
+		Endpoint:          "http://test.com",
+		FlushInterval:     10 * time.Second,
+		MaxBatchSize:      100,
+		MaxRetries:        3,
+		PersistencePolicy: PersistNever(),
+		MemoryPressure:    func() bool { return false },
+	}, httpAdapter, storage, &mockLogger{})
+	d.Restore()
+	defer d.Dispose()
+
+	d.Enqueue(Event{Name: "a"})
+	d.Enqueue(Event{Name: "b"})
+	d.spillToStorage("memory pressure")
+
+	if d.queue.Len() != 0 {
+		t.Fatalf("expected in-memory queue to be trimmed, got %d", d.queue.Len())
+	}
+	if len(storage.getSaved()) != 2 {
+		t.Fatalf("expected 2 spilled events in storage, got %d", len(storage.getSaved()))
+	}
+
+	// While spilled, new events go straight to storage
+	storage.loaded = storage.getSaved()
+	d.Enqueue(Event{Name: "c"})
+	if d.queue.Len() != 0 {
+		t.Fatal("expected new event to bypass the in-memory queue")
+	}
+	saved := storage.getSaved()
+	if len(saved) != 3 || saved[2].Name != "c" {
+		t.Fatalf("expected event appended to storage, got %v", saved)
+	}
+
+	// Flush reloads spilled events and sends them
+	storage.loaded = saved
+	d.Flush()
+	if httpAdapter.getCalls() != 1 {
+		t.Fatalf("expected spilled events to be sent, got %d calls", httpAdapter.getCalls())
+	}
+	if d.queue.Len() != 0 {
+		t.Error("expected queue to be drained after flush")
+	}
+}
+
+func TestDispatcher_SpillThreshold(t *testing.T) {
+	storage := &mockStorageAdapter{}
+	httpAdapter := &mockHTTPAdapter{}
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:            "test-key",
+		APIKeyHeader:      "X-API-Key",
+		Endpoint:          "http://test.com",
+		FlushInterval:     10 * time.Second,
+		MaxBatchSize:      100,
+		MaxRetries:        3,
+		PersistencePolicy: PersistNever(),
+		SpillThreshold:    2,
+	}, httpAdapter, storage, &mockLogger{})
+	d.Restore()
+	defer d.Dispose()
+
+	d.Enqueue(Event{Name: "a"})
+	d.Enqueue(Event{Name: "b"})
+	if d.queue.Len() != 2 || len(storage.getSaved()) != 0 {
+		t.Fatalf("expected events to stay in memory up to the threshold, got %d queued", d.queue.Len())
+	}
+
+	d.Enqueue(Event{Name: "c"})
+	if d.queue.Len() != 0 {
+		t.Fatalf("expected queue to spill past the threshold, got %d queued", d.queue.Len())
+	}
+	saved := storage.getSaved()
+	if len(saved) != 3 {
+		t.Fatalf("expected 3 spilled events in storage, got %d", len(saved))
+	}
+
+	storage.loaded = saved
+	d.Flush()
+	if httpAdapter.getCalls() != 1 {
+		t.Fatalf("expected spilled events to be sent, got %d calls", httpAdapter.getCalls())
+	}
+	if stats := d.Stats(); stats.EventsSent != 3 {
+		t.Fatalf("expected 3 events sent, got %d", stats.EventsSent)
+	}
+}
+
+// gatedHTTPAdapter records the names of delivered events. Its first send
+// blocks until release is closed, and then fails with a 503 if fail is set.
+type gatedHTTPAdapter struct {
+	mu      sync.Mutex
+	names   []string
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+	fail    bool
+}
+
+func newGatedHTTPAdapter() *gatedHTTPAdapter {
+	return &gatedHTTPAdapter{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (g *gatedHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return g.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (g *gatedHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	first := false
+	g.once.Do(func() {
+		first = true
+		close(g.started)
+	})
+	if first {
+		<-g.release
+		if g.fail {
+			return &HTTPResponse{Status: 503}, nil
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, event := range events {
+		g.names = append(g.names, event.Name)
+	}
+	return &HTTPResponse{Status: 200}, nil
+}
+
+func (g *gatedHTTPAdapter) delivered() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.names)
+}
+
+// testSpillDuringFlush starts a flush that blocks in flight, calls spill to
+// track e0-e4 and spill them, then releases the flush and checks that every
+// event is delivered exactly once.
+func testSpillDuringFlush(t *testing.T, config DispatcherConfig, spill func(d *Dispatcher)) {
+	t.Helper()
+	config.APIKey = "test-key"
+	config.Endpoint = "http://test.com"
+	config.FlushInterval = 10 * time.Second
+	config.MaxBatchSize = 100
+
+	httpAdapter := newGatedHTTPAdapter()
+	storage := adapters.NewFileStorageAdapter(filepath.Join(t.TempDir(), "events.json"))
+	d := NewDispatcher(config, httpAdapter, storage, &mockLogger{})
+	d.Restore()
+	defer d.Dispose()
+
+	d.Enqueue(Event{Name: "in-flight"})
+	done := make(chan struct{})
+	go func() {
+		d.Flush()
+		close(done)
+	}()
+	<-httpAdapter.started
+
+	spill(d)
+	d.mu.Lock()
+	spilled := d.spilled
+	d.mu.Unlock()
+	if !spilled {
+		t.Fatal("expected the queue to be spilled during the flush")
+	}
+
+	close(httpAdapter.release)
+	<-done
+	d.Flush()
+
+	want := []string{"in-flight"}
+	for i := range 5 {
+		want = append(want, fmt.Sprintf("e%d", i))
+	}
+	if got := httpAdapter.delivered(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v delivered, got %v", want, got)
+	}
+}
+
+func TestDispatcher_SpillDuringFlush(t *testing.T) {
+	config := DispatcherConfig{MemoryPressure: func() bool { return false }}
+	testSpillDuringFlush(t, config, func(d *Dispatcher) {
+		for i := range 3 {
+			d.Enqueue(Event{Name: fmt.Sprintf("e%d", i)})
+		}
+		d.spillToStorage("memory pressure")
+		d.Enqueue(Event{Name: "e3"})
+		d.Enqueue(Event{Name: "e4"})
+	})
+}
+
+func TestDispatcher_SpillThresholdDuringFlush(t *testing.T) {
+	testSpillDuringFlush(t, DispatcherConfig{SpillThreshold: 2}, func(d *Dispatcher) {
+		for i := range 5 {
+			d.Enqueue(Event{Name: fmt.Sprintf("e%d", i)})
+		}
+	})
+}
+
+func TestDispatcher_SpillRequeuesFailures(t *testing.T) {
+	httpAdapter := newGatedHTTPAdapter()
+	httpAdapter.fail = true
+	storage := adapters.NewFileStorageAdapter(filepath.Join(t.TempDir(), "events.json"))
+	d := NewDispatcher(DispatcherConfig{
+		APIKey:         "test-key",
+		Endpoint:       "http://test.com",
+		FlushInterval:  10 * time.Second,
+		MaxBatchSize:   100,
+		MaxBufferSize:  4,
+		SpillThreshold: 2,
+	}, httpAdapter, storage, &mockLogger{})
+	d.Restore()
+	defer d.Dispose()
+
+	ack, _ := d.EnqueueWithAck(Event{Name: "failed"})
+	done := make(chan struct{})
+	go func() {
+		d.Flush()
+		close(done)
+	}()
+	<-httpAdapter.started
+
+	for i := range 3 {
+		d.Enqueue(Event{Name: fmt.Sprintf("e%d", i)})
+	}
+	close(httpAdapter.release)
+	<-done
+
+	stored, _ := storage.Load()
+	var names []string
+	for _, event := range stored {
+		names = append(names, event.Name)
+	}
+	if want := []string{"failed", "e0", "e1", "e2"}; !slices.Equal(names, want) {
+		t.Fatalf("expected the failed batch stored ahead of the spilled events %v, got %v", want, names)
+	}
+
+	// A fifth event exceeds MaxBufferSize and evicts the failed one.
+	d.Enqueue(Event{Name: "e3"})
+	if err := awaitAck(t, ack); !errors.Is(err, ErrEventDiscarded) {
+		t.Fatalf("expected the evicted event's ack to be discarded, got %v", err)
+	}
+
+	d.Flush()
+	if got, want := httpAdapter.delivered(), []string{"e0", "e1", "e2", "e3"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v delivered, got %v", want, got)
+	}
+}
+
+func TestMergeByIssuedAt(t *testing.T) {
+	stored := []Event{{Name: "a", IssuedAt: 1}, {Name: "c", IssuedAt: 3}}
+	queued := []Event{{Name: "b", IssuedAt: 2}, {Name: "d", IssuedAt: 3}}
+
+	var names []string
+	for _, event := range mergeByIssuedAt(stored, queued) {
+		names = append(names, event.Name)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(names, want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+}
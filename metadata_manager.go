@@ -22,6 +22,34 @@ func (m *MetadataManager) Set(key string, value any) {
 	m.metadata[key] = value
 }
 
+// SetAll sets multiple metadata values, keeping other existing keys
+func (m *MetadataManager) SetAll(values map[string]any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range values {
+		m.metadata[k] = v
+	}
+}
+
+// Replace replaces all metadata with a copy of values
+func (m *MetadataManager) Replace(values map[string]any) {
+	metadata := make(map[string]any, len(values))
+	for k, v := range values {
+		metadata[k] = v
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metadata = metadata
+}
+
+// Delete removes a metadata value
+func (m *MetadataManager) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.metadata, key)
+}
+
 // GetAll returns all metadata as a copy
 func (m *MetadataManager) GetAll() map[string]any {
 	m.mu.RLock()
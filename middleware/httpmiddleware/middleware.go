@@ -0,0 +1,148 @@
+// Package httpmiddleware tracks an event for every request served by a
+// net/http handler and makes the client and request-scoped metadata
+// available to downstream handlers through the request context.
+package httpmiddleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+)
+
+const (
+	// DefaultEventName is the name of tracked request events.
+	DefaultEventName = "http_request"
+
+	// DefaultRequestIDHeader is the header read into the "requestId"
+	// metadata key.
+	DefaultRequestIDHeader = "X-Request-Id"
+)
+
+// Options configures Handler. The zero value is usable.
+type Options struct {
+	// EventName is the name of the event tracked per request.
+	// Default: DefaultEventName.
+	EventName string
+
+	// RequestIDHeader is copied into the "requestId" metadata of the request
+	// context when present. Default: DefaultRequestIDHeader.
+	RequestIDHeader string
+
+	// Metadata returns extra request-scoped metadata, e.g. a tenant ID. It is
+	// added to the request context, so downstream TrackContext calls and the
+	// request event both carry it.
+	Metadata func(r *http.Request) map[string]any
+
+	// Skip excludes requests (e.g. health checks) from tracking. Skipped
+	// requests still get the client and metadata in their context.
+	Skip func(r *http.Request) bool
+}
+
+// ContextWithClient returns a copy of ctx carrying client. It is equivalent
+// to ripple.WithClient.
+func ContextWithClient(ctx context.Context, client *ripple.Client) context.Context {
+	return ripple.WithClient(ctx, client)
+}
+
+// ClientFromContext returns the client injected by Handler, or nil. It is
+// equivalent to ripple.FromContext.
+func ClientFromContext(ctx context.Context) *ripple.Client {
+	return ripple.FromContext(ctx)
+}
+
+// RequestTracker tracks events carrying the request-scoped metadata of one
+// request.
+type RequestTracker = ripple.Tracker
+
+// TrackerFromContext returns a tracker for the client and request-scoped
+// metadata in ctx, or nil if ctx has no client. It is equivalent to
+// ripple.TrackerFromContext.
+func TrackerFromContext(ctx context.Context) *RequestTracker {
+	return ripple.TrackerFromContext(ctx)
+}
+
+// Handler returns middleware that tracks one event per request with its
+// method, path, status and latency, and injects client and request-scoped
+// metadata (method, path, request ID, plus Options.Metadata) into the request
+// context for downstream TrackContext calls.
+func Handler(client *ripple.Client, opts Options) func(http.Handler) http.Handler {
+	if opts.EventName == "" {
+		opts.EventName = DefaultEventName
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ctx := ContextWithClient(r.Context(), client)
+			ctx = ripple.ContextWithMetadata(ctx, RequestMetadata(r, opts))
+			r = r.WithContext(ctx)
+
+			if opts.Skip != nil && opts.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			_ = client.TrackContext(ctx, opts.EventName, map[string]any{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"status":     rec.status,
+				"durationMs": time.Since(start).Milliseconds(),
+			}, nil)
+		})
+	}
+}
+
+// RequestMetadata returns the request-scoped metadata Handler attaches to r:
+// method, path, requestId (from Options.RequestIDHeader, if present) and the
+// result of Options.Metadata.
+func RequestMetadata(r *http.Request, opts Options) map[string]any {
+	header := opts.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+
+	metadata := map[string]any{
+		"method": r.Method,
+		"path":   r.URL.Path,
+	}
+	if id := r.Header.Get(header); id != "" {
+		metadata["requestId"] = id
+	}
+	if opts.Metadata != nil {
+		for k, v := range opts.Metadata(r) {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// statusRecorder captures the response status written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
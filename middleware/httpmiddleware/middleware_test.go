@@ -0,0 +1,186 @@
+package httpmiddleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+type recordingAdapter struct {
+	mu     sync.Mutex
+	events []ripple.Event
+}
+
+func (a *recordingAdapter) Send(endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *recordingAdapter) SendWithContext(ctx context.Context, endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, events...)
+	return &ripple.HTTPResponse{Status: 200}, nil
+}
+
+func (a *recordingAdapter) sent() []ripple.Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ripple.Event(nil), a.events...)
+}
+
+func newTestClient(t *testing.T) (*ripple.Client, *recordingAdapter) {
+	t.Helper()
+	httpAdapter := &recordingAdapter{}
+	client, err := ripple.NewClient(ripple.ClientConfig{
+		APIKey:         "test-key",
+		Endpoint:       "http://test.com",
+		HTTPAdapter:    httpAdapter,
+		StorageAdapter: adapters.NewNoOpStorageAdapter(),
+		LoggerAdapter:  adapters.NewNoOpLoggerAdapter(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Init()
+	t.Cleanup(client.Dispose)
+	return client, httpAdapter
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("should track an event per request", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		handler := Handler(client, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("X-Request-Id", "req-1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		client.Flush()
+
+		events := httpAdapter.sent()
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		event := events[0]
+		if event.Name != DefaultEventName {
+			t.Fatalf("expected event %q, got %q", DefaultEventName, event.Name)
+		}
+		if event.Payload["method"] != "POST" || event.Payload["path"] != "/orders" || event.Payload["status"] != http.StatusCreated {
+			t.Fatalf("unexpected payload: %+v", event.Payload)
+		}
+		if _, ok := event.Payload["durationMs"]; !ok {
+			t.Fatal("expected durationMs in payload")
+		}
+		if event.Metadata["requestId"] != "req-1" {
+			t.Fatalf("expected requestId metadata, got %+v", event.Metadata)
+		}
+	})
+
+	t.Run("should default the status to 200", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		handler := Handler(client, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		client.Flush()
+
+		if events := httpAdapter.sent(); len(events) != 1 || events[0].Payload["status"] != http.StatusOK {
+			t.Fatalf("expected status 200, got %+v", events)
+		}
+	})
+
+	t.Run("should inject the client and metadata for downstream tracking", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		opts := Options{
+			EventName: "request",
+			Metadata: func(r *http.Request) map[string]any {
+				return map[string]any{"tenant": r.Header.Get("X-Tenant")}
+			},
+		}
+		handler := Handler(client, opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tracker := ClientFromContext(r.Context())
+			if tracker != client {
+				t.Error("expected client in request context")
+				return
+			}
+			tracker.TrackContext(r.Context(), "order_created", nil, nil)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", "acme")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		client.Flush()
+
+		events := httpAdapter.sent()
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		for _, event := range events {
+			if event.Metadata["tenant"] != "acme" || event.Metadata["path"] != "/" {
+				t.Fatalf("expected request metadata on %q, got %+v", event.Name, event.Metadata)
+			}
+		}
+		if events[1].Name != "request" {
+			t.Fatalf("expected custom event name, got %q", events[1].Name)
+		}
+	})
+
+	t.Run("should skip excluded requests", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		called := false
+		handler := Handler(client, Options{
+			Skip: func(r *http.Request) bool { return r.URL.Path == "/healthz" },
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = ClientFromContext(r.Context()) == client
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		client.Flush()
+
+		if !called {
+			t.Fatal("expected skipped request to still reach the handler with the client")
+		}
+		if events := httpAdapter.sent(); len(events) != 0 {
+			t.Fatalf("expected no events, got %d", len(events))
+		}
+	})
+}
+
+func TestClientFromContext(t *testing.T) {
+	if ClientFromContext(context.Background()) != nil {
+		t.Fatal("expected nil client")
+	}
+}
+
+func TestTrackerFromContext(t *testing.T) {
+	t.Run("should track with request metadata", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		ctx := ContextWithClient(context.Background(), client)
+		ctx = ripple.ContextWithMetadata(ctx, map[string]any{"route": "/orders/:id"})
+
+		tracker := TrackerFromContext(ctx)
+		if tracker == nil || tracker.Client() != client {
+			t.Fatal("expected tracker for the client")
+		}
+		tracker.Track("order_viewed", nil, map[string]any{"extra": true})
+		client.Flush()
+
+		events := httpAdapter.sent()
+		if len(events) != 1 || events[0].Metadata["route"] != "/orders/:id" || events[0].Metadata["extra"] != true {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("should return nil without a client", func(t *testing.T) {
+		if TrackerFromContext(context.Background()) != nil {
+			t.Fatal("expected nil tracker")
+		}
+	})
+}
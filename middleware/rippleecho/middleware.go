@@ -0,0 +1,68 @@
+// Package rippleecho integrates Ripple with the Echo web framework. It is a
+// separate module so the SDK itself does not depend on Echo.
+package rippleecho
+
+import (
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/middleware/httpmiddleware"
+	"github.com/labstack/echo/v4"
+)
+
+// Options configures Middleware. It is shared with httpmiddleware.Handler.
+type Options = httpmiddleware.Options
+
+// Middleware tracks one event per request with its method, route, path,
+// status and latency. Request-scoped metadata (method, path, route,
+// userAgent, requestId and Options.Metadata) is attached to the request
+// context, so handlers can track through Tracker.
+//
+// Errors returned by the handler are passed to Echo's error handler first,
+// so the tracked status matches the response sent.
+func Middleware(client *ripple.Client, opts Options) echo.MiddlewareFunc {
+	eventName := opts.EventName
+	if eventName == "" {
+		eventName = httpmiddleware.DefaultEventName
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			metadata := httpmiddleware.RequestMetadata(req, opts)
+			metadata["route"] = c.Path()
+			metadata["userAgent"] = req.UserAgent()
+
+			ctx := httpmiddleware.ContextWithClient(req.Context(), client)
+			ctx = ripple.ContextWithMetadata(ctx, metadata)
+			req = req.WithContext(ctx)
+			c.SetRequest(req)
+
+			if opts.Skip != nil && opts.Skip(req) {
+				return next(c)
+			}
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			_ = client.TrackContext(ctx, eventName, map[string]any{
+				"method":     req.Method,
+				"route":      c.Path(),
+				"path":       req.URL.Path,
+				"status":     c.Response().Status,
+				"durationMs": time.Since(start).Milliseconds(),
+			}, nil)
+			return err
+		}
+	}
+}
+
+// Tracker returns a tracker carrying the request-scoped metadata of c, or nil
+// if Middleware is not installed.
+func Tracker(c echo.Context) *httpmiddleware.RequestTracker {
+	return httpmiddleware.TrackerFromContext(c.Request().Context())
+}
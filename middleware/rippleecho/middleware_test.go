@@ -0,0 +1,112 @@
+package rippleecho
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+	"github.com/labstack/echo/v4"
+)
+
+type recordingAdapter struct {
+	mu     sync.Mutex
+	events []ripple.Event
+}
+
+func (a *recordingAdapter) Send(endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *recordingAdapter) SendWithContext(ctx context.Context, endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, events...)
+	return &ripple.HTTPResponse{Status: 200}, nil
+}
+
+func (a *recordingAdapter) sent() []ripple.Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ripple.Event(nil), a.events...)
+}
+
+func newTestClient(t *testing.T) (*ripple.Client, *recordingAdapter) {
+	t.Helper()
+	httpAdapter := &recordingAdapter{}
+	client, err := ripple.NewClient(ripple.ClientConfig{
+		APIKey:         "test-key",
+		Endpoint:       "http://test.com",
+		HTTPAdapter:    httpAdapter,
+		StorageAdapter: adapters.NewNoOpStorageAdapter(),
+		LoggerAdapter:  adapters.NewNoOpLoggerAdapter(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Init()
+	t.Cleanup(client.Dispose)
+	return client, httpAdapter
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should track requests with route, status and user agent", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		e := echo.New()
+		e.Use(Middleware(client, Options{}))
+		e.GET("/orders/:id", func(c echo.Context) error {
+			Tracker(c).Track("order_viewed", nil, nil)
+			return c.NoContent(http.StatusAccepted)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+		req.Header.Set("User-Agent", "test-agent")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+		client.Flush()
+
+		events := httpAdapter.sent()
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		for _, event := range events {
+			if event.Metadata["route"] != "/orders/:id" || event.Metadata["userAgent"] != "test-agent" {
+				t.Fatalf("expected request metadata on %q, got %+v", event.Name, event.Metadata)
+			}
+		}
+		request := events[1]
+		if request.Name != "http_request" || request.Payload["status"] != http.StatusAccepted || request.Payload["route"] != "/orders/:id" {
+			t.Fatalf("unexpected request event: %+v", request)
+		}
+	})
+
+	t.Run("should track the status of handler errors", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		e := echo.New()
+		e.Use(Middleware(client, Options{}))
+		e.GET("/missing", func(c echo.Context) error {
+			return echo.NewHTTPError(http.StatusNotFound, "not found")
+		})
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		client.Flush()
+
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 response, got %d", rec.Code)
+		}
+		if events := httpAdapter.sent(); len(events) != 1 || events[0].Payload["status"] != http.StatusNotFound {
+			t.Fatalf("expected tracked 404, got %+v", events)
+		}
+	})
+}
+
+func TestTracker(t *testing.T) {
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	if Tracker(c) != nil {
+		t.Fatal("expected nil tracker without middleware")
+	}
+}
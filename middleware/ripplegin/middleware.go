@@ -0,0 +1,58 @@
+// Package ripplegin integrates Ripple with the Gin web framework. It is a
+// separate module so the SDK itself does not depend on Gin.
+package ripplegin
+
+import (
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/middleware/httpmiddleware"
+	"github.com/gin-gonic/gin"
+)
+
+// Options configures Middleware. It is shared with httpmiddleware.Handler.
+type Options = httpmiddleware.Options
+
+// Middleware tracks one event per request with its method, route, path,
+// status and latency. Request-scoped metadata (method, path, route,
+// userAgent, requestId and Options.Metadata) is attached to the request
+// context, so handlers can track through Tracker.
+func Middleware(client *ripple.Client, opts Options) gin.HandlerFunc {
+	eventName := opts.EventName
+	if eventName == "" {
+		eventName = httpmiddleware.DefaultEventName
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		metadata := httpmiddleware.RequestMetadata(c.Request, opts)
+		metadata["route"] = c.FullPath()
+		metadata["userAgent"] = c.Request.UserAgent()
+
+		ctx := httpmiddleware.ContextWithClient(c.Request.Context(), client)
+		ctx = ripple.ContextWithMetadata(ctx, metadata)
+		c.Request = c.Request.WithContext(ctx)
+
+		if opts.Skip != nil && opts.Skip(c.Request) {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		_ = client.TrackContext(ctx, eventName, map[string]any{
+			"method":     c.Request.Method,
+			"route":      c.FullPath(),
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"durationMs": time.Since(start).Milliseconds(),
+		}, nil)
+	}
+}
+
+// Tracker returns a tracker carrying the request-scoped metadata of c, or nil
+// if Middleware is not installed.
+func Tracker(c *gin.Context) *httpmiddleware.RequestTracker {
+	return httpmiddleware.TrackerFromContext(c.Request.Context())
+}
@@ -0,0 +1,110 @@
+package ripplegin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+	"github.com/gin-gonic/gin"
+)
+
+type recordingAdapter struct {
+	mu     sync.Mutex
+	events []ripple.Event
+}
+
+func (a *recordingAdapter) Send(endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *recordingAdapter) SendWithContext(ctx context.Context, endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, events...)
+	return &ripple.HTTPResponse{Status: 200}, nil
+}
+
+func (a *recordingAdapter) sent() []ripple.Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ripple.Event(nil), a.events...)
+}
+
+func newTestClient(t *testing.T) (*ripple.Client, *recordingAdapter) {
+	t.Helper()
+	httpAdapter := &recordingAdapter{}
+	client, err := ripple.NewClient(ripple.ClientConfig{
+		APIKey:         "test-key",
+		Endpoint:       "http://test.com",
+		HTTPAdapter:    httpAdapter,
+		StorageAdapter: adapters.NewNoOpStorageAdapter(),
+		LoggerAdapter:  adapters.NewNoOpLoggerAdapter(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Init()
+	t.Cleanup(client.Dispose)
+	return client, httpAdapter
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("should track requests with route, status and user agent", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		router := gin.New()
+		router.Use(Middleware(client, Options{}))
+		router.GET("/orders/:id", func(c *gin.Context) {
+			Tracker(c).Track("order_viewed", nil, nil)
+			c.Status(http.StatusAccepted)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+		req.Header.Set("User-Agent", "test-agent")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+		client.Flush()
+
+		events := httpAdapter.sent()
+		if len(events) != 2 {
+			t.Fatalf("expected 2 events, got %d", len(events))
+		}
+		for _, event := range events {
+			if event.Metadata["route"] != "/orders/:id" || event.Metadata["userAgent"] != "test-agent" {
+				t.Fatalf("expected request metadata on %q, got %+v", event.Name, event.Metadata)
+			}
+		}
+		request := events[1]
+		if request.Name != "http_request" || request.Payload["status"] != http.StatusAccepted || request.Payload["route"] != "/orders/:id" {
+			t.Fatalf("unexpected request event: %+v", request)
+		}
+	})
+
+	t.Run("should skip excluded requests", func(t *testing.T) {
+		client, httpAdapter := newTestClient(t)
+		router := gin.New()
+		router.Use(Middleware(client, Options{
+			Skip: func(r *http.Request) bool { return r.URL.Path == "/healthz" },
+		}))
+		router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		client.Flush()
+
+		if events := httpAdapter.sent(); len(events) != 0 {
+			t.Fatalf("expected no events, got %d", len(events))
+		}
+	})
+}
+
+func TestTracker(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if Tracker(c) != nil {
+		t.Fatal("expected nil tracker without middleware")
+	}
+}
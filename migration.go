@@ -0,0 +1,120 @@
+package ripple
+
+import (
+	"fmt"
+	"maps"
+)
+
+// SchemaMigration upgrades the payload of an event from one schema version
+// to the next. Migrations run at flush time, so events queued or persisted
+// under an old schema are upgraded before they are sent, even after a
+// restart with newer code.
+type SchemaMigration struct {
+	// Event is the name of the events to migrate.
+	Event string
+
+	// From is the "schemaVersion" metadata value of the events to migrate.
+	// An empty From matches events without a schema version.
+	From string
+
+	// To is the schema version stamped on migrated events.
+	To string
+
+	// Migrate returns the payload in the To version. It must not modify
+	// payload; return a new map instead. If it returns an error the event is
+	// sent unchanged and a warning is logged.
+	Migrate func(payload map[string]any) (map[string]any, error)
+}
+
+// migrationKey identifies the migration for an event name and version.
+type migrationKey struct {
+	event   string
+	version string
+}
+
+// validateMigrations reports every invalid migration.
+func validateMigrations(migrations []SchemaMigration) []string {
+	var problems []string
+	seen := make(map[migrationKey]bool, len(migrations))
+	for _, m := range migrations {
+		if m.Event == "" {
+			problems = append(problems, "schema migration event name cannot be empty")
+			continue
+		}
+		if m.To == "" || m.To == m.From {
+			problems = append(problems, fmt.Sprintf("schema migration for %q from %q must target a different version", m.Event, m.From))
+		}
+		if m.Migrate == nil {
+			problems = append(problems, fmt.Sprintf("schema migration for %q from %q requires a migrate function", m.Event, m.From))
+		}
+		key := migrationKey{m.Event, m.From}
+		if seen[key] {
+			problems = append(problems, fmt.Sprintf("schema migration for %q from %q is registered twice", m.Event, m.From))
+		}
+		seen[key] = true
+	}
+	return problems
+}
+
+// newMigrations indexes migrations by event name and source version.
+func newMigrations(migrations []SchemaMigration) map[migrationKey]SchemaMigration {
+	if len(migrations) == 0 {
+		return nil
+	}
+	index := make(map[migrationKey]SchemaMigration, len(migrations))
+	for _, m := range migrations {
+		index[migrationKey{m.Event, m.From}] = m
+	}
+	return index
+}
+
+// migrateEvents upgrades events in place through every applicable
+// migration, chaining them until no migration matches the event's version.
+func (d *Dispatcher) migrateEvents(events []Event) []Event {
+	if len(d.migrations) == 0 {
+		return events
+	}
+	migrated := 0
+	for i := range events {
+		if d.migrateEvent(&events[i]) {
+			migrated++
+		}
+	}
+	if migrated > 0 {
+		d.stats.eventsMigrated(migrated)
+	}
+	return events
+}
+
+// migrateEvent applies the migration chain to event and reports whether it
+// changed. A chain is at most as long as the number of migrations, so a
+// cycle cannot loop forever.
+func (d *Dispatcher) migrateEvent(event *Event) bool {
+	changed := false
+	for range len(d.migrations) {
+		version, _ := event.Metadata[schemaVersionMetadataKey].(string)
+		m, ok := d.migrations[migrationKey{event.Name, version}]
+		if !ok {
+			break
+		}
+		payload, err := m.Migrate(event.Payload)
+		if err != nil {
+			d.loggerAdapter.Warn("Schema migration failed, sending event unchanged", map[string]any{
+				"event": event.Name,
+				"from":  m.From,
+				"to":    m.To,
+				"error": err.Error(),
+			})
+			break
+		}
+		metadata := maps.Clone(event.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]any, 1)
+		}
+		metadata[schemaVersionMetadataKey] = m.To
+		event.Payload = payload
+		event.Metadata = metadata
+		changed = true
+	}
+	return changed
+}
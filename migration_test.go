@@ -0,0 +1,129 @@
+package ripple
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// withMigrations sets the SchemaMigrations.
+func withMigrations(migrations ...SchemaMigration) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) { c.SchemaMigrations = migrations }
+}
+
+// renameField returns a migration function renaming from to to.
+func renameField(from, to string) func(map[string]any) (map[string]any, error) {
+	return func(payload map[string]any) (map[string]any, error) {
+		migrated := maps.Clone(payload)
+		migrated[to] = migrated[from]
+		delete(migrated, from)
+		return migrated, nil
+	}
+}
+
+func TestDispatcher_SchemaMigrations(t *testing.T) {
+	t.Run("should chain migrations for events persisted under an old schema", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		storage := &mockStorageAdapter{loaded: []Event{
+			{Name: "purchase", Payload: map[string]any{"amt": 5}, Metadata: map[string]any{"schemaVersion": "1"}},
+			{Name: "purchase", Payload: map[string]any{"amount": 7}, Metadata: map[string]any{"schemaVersion": "2"}},
+			{Name: "signup", Payload: map[string]any{"amt": 1}, Metadata: map[string]any{"schemaVersion": "1"}},
+		}}
+		d := newTestDispatcher(httpAdapter, storage, withMigrations(
+			SchemaMigration{Event: "purchase", From: "1", To: "2", Migrate: renameField("amt", "amount")},
+			SchemaMigration{Event: "purchase", From: "2", To: "3", Migrate: renameField("amount", "total")},
+		))
+		d.Restore()
+		defer d.Dispose()
+		d.Flush()
+
+		sent := httpAdapter.Requests()[0].Events
+		for _, event := range sent[:2] {
+			if event.Metadata["schemaVersion"] != "3" || event.Payload["total"] == nil || len(event.Payload) != 1 {
+				t.Fatalf("expected the purchase to be migrated to version 3, got %+v", event)
+			}
+		}
+		if sent[2].Metadata["schemaVersion"] != "1" || sent[2].Payload["amt"] != 1 {
+			t.Fatalf("expected other events to be unchanged, got %+v", sent[2])
+		}
+		if d.Stats().EventsMigrated != 2 {
+			t.Fatalf("expected 2 migrated events, got %d", d.Stats().EventsMigrated)
+		}
+	})
+
+	t.Run("should migrate events without a version from the empty version", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withMigrations(
+			SchemaMigration{Event: "purchase", To: "1", Migrate: renameField("amt", "amount")},
+		))
+		d.Restore()
+		defer d.Dispose()
+
+		payload := map[string]any{"amt": 5}
+		d.Enqueue(Event{Name: "purchase", Payload: payload})
+		d.Flush()
+
+		event := httpAdapter.Requests()[0].Events[0]
+		if event.Metadata["schemaVersion"] != "1" || event.Payload["amount"] != 5 {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+		if payload["amt"] != 5 {
+			t.Fatal("expected the original payload to be unchanged")
+		}
+	})
+
+	t.Run("should send the event unchanged if a migration fails", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withMigrations(
+			SchemaMigration{Event: "purchase", From: "1", To: "2", Migrate: func(map[string]any) (map[string]any, error) {
+				return nil, errors.New("bad payload")
+			}},
+		))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "purchase", Payload: map[string]any{"amt": 5}, Metadata: map[string]any{"schemaVersion": "1"}})
+		d.Flush()
+
+		event := httpAdapter.Requests()[0].Events[0]
+		if event.Metadata["schemaVersion"] != "1" || event.Payload["amt"] != 5 {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("should stop at a migration cycle", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 200})
+		identity := func(payload map[string]any) (map[string]any, error) { return payload, nil }
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withMigrations(
+			SchemaMigration{Event: "e", From: "1", To: "2", Migrate: identity},
+			SchemaMigration{Event: "e", From: "2", To: "1", Migrate: identity},
+		))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "e", Metadata: map[string]any{"schemaVersion": "1"}})
+		d.Flush()
+
+		if httpAdapter.Calls() != 1 {
+			t.Fatal("expected the event to be sent")
+		}
+	})
+}
+
+func TestValidateMigrations(t *testing.T) {
+	noop := func(payload map[string]any) (map[string]any, error) { return payload, nil }
+	config := createTestConfig()
+	config.SchemaMigrations = []SchemaMigration{
+		{From: "1", To: "2", Migrate: noop},
+		{Event: "a", From: "1", To: "1", Migrate: noop},
+		{Event: "b", From: "1", To: "2"},
+		{Event: "c", From: "1", To: "2", Migrate: noop},
+		{Event: "c", From: "1", To: "3", Migrate: noop},
+	}
+	var configErr *ConfigError
+	if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 4 {
+		t.Fatalf("expected 4 problems, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package ripple
+
+import (
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// Option sets a ClientConfig field. Options are an alternative to filling in
+// ClientConfig directly; any field without a dedicated option can be set with
+// an Option literal:
+//
+//	ripple.Option(func(c *ripple.ClientConfig) { c.EventIDs = true })
+type Option func(*ClientConfig)
+
+// NewClientWithOptions creates a client from options. Unless overridden, the
+// client sends events with NetHTTPAdapter and does not persist them
+// (NoOpStorageAdapter). Validation is the same as NewClient's.
+func NewClientWithOptions(opts ...Option) (*Client, error) {
+	config := defaultOptionsConfig()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&config)
+		}
+	}
+	return NewClient(config)
+}
+
+// defaultOptionsConfig returns the base config that options are applied to.
+func defaultOptionsConfig() ClientConfig {
+	return ClientConfig{
+		HTTPAdapter:    adapters.NewNetHTTPAdapter(),
+		StorageAdapter: adapters.NewNoOpStorageAdapter(),
+	}
+}
+
+// WithAPIKey sets ClientConfig.APIKey.
+func WithAPIKey(apiKey string) Option {
+	return func(c *ClientConfig) { c.APIKey = apiKey }
+}
+
+// WithEndpoint sets ClientConfig.Endpoint.
+func WithEndpoint(endpoint string) Option {
+	return func(c *ClientConfig) { c.Endpoint = endpoint }
+}
+
+// WithEndpoints sets ClientConfig.Endpoints for failover or load balancing.
+func WithEndpoints(endpoints ...string) Option {
+	return func(c *ClientConfig) { c.Endpoints = endpoints }
+}
+
+// WithAPIKeyHeader sets ClientConfig.APIKeyHeader.
+func WithAPIKeyHeader(header string) Option {
+	return func(c *ClientConfig) { c.APIKeyHeader = &header }
+}
+
+// WithAuthProvider sets ClientConfig.AuthProvider.
+func WithAuthProvider(provider AuthProvider) Option {
+	return func(c *ClientConfig) { c.AuthProvider = provider }
+}
+
+// WithHeaders sets ClientConfig.Headers.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *ClientConfig) { c.Headers = headers }
+}
+
+// WithHeaderProvider sets ClientConfig.HeaderProvider.
+func WithHeaderProvider(provider HeaderProvider) Option {
+	return func(c *ClientConfig) { c.HeaderProvider = provider }
+}
+
+// WithHTTPAdapter sets ClientConfig.HTTPAdapter.
+func WithHTTPAdapter(adapter HTTPAdapter) Option {
+	return func(c *ClientConfig) { c.HTTPAdapter = adapter }
+}
+
+// WithStorageAdapter sets ClientConfig.StorageAdapter.
+func WithStorageAdapter(adapter StorageAdapter) Option {
+	return func(c *ClientConfig) { c.StorageAdapter = adapter }
+}
+
+// WithLoggerAdapter sets ClientConfig.LoggerAdapter.
+func WithLoggerAdapter(adapter LoggerAdapter) Option {
+	return func(c *ClientConfig) { c.LoggerAdapter = adapter }
+}
+
+// WithFlushInterval sets ClientConfig.FlushInterval.
+func WithFlushInterval(interval time.Duration) Option {
+	return func(c *ClientConfig) { c.FlushInterval = interval }
+}
+
+// WithMaxBatchSize sets ClientConfig.MaxBatchSize.
+func WithMaxBatchSize(size int) Option {
+	return func(c *ClientConfig) { c.MaxBatchSize = size }
+}
+
+// WithMaxBufferSize sets ClientConfig.MaxBufferSize.
+func WithMaxBufferSize(size int) Option {
+	return func(c *ClientConfig) { c.MaxBufferSize = size }
+}
+
+// WithMaxRetries sets ClientConfig.MaxRetries.
+func WithMaxRetries(retries int) Option {
+	return func(c *ClientConfig) { c.MaxRetries = retries }
+}
+
+// WithBackoffPolicy sets ClientConfig.BackoffPolicy.
+func WithBackoffPolicy(policy BackoffPolicy) Option {
+	return func(c *ClientConfig) { c.BackoffPolicy = policy }
+}
+
+// WithRetryMode sets ClientConfig.RetryMode.
+func WithRetryMode(mode RetryMode) Option {
+	return func(c *ClientConfig) { c.RetryMode = mode }
+}
+
+// WithFlushTimeout sets ClientConfig.FlushTimeout.
+func WithFlushTimeout(timeout time.Duration) Option {
+	return func(c *ClientConfig) { c.FlushTimeout = timeout }
+}
+
+// WithRequestTimeout sets ClientConfig.RequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *ClientConfig) { c.RequestTimeout = timeout }
+}
+
+// WithShutdownTimeout sets ClientConfig.ShutdownTimeout.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(c *ClientConfig) { c.ShutdownTimeout = timeout }
+}
+
+// WithSamplingRate sets ClientConfig.SamplingRate.
+func WithSamplingRate(rate float64) Option {
+	return func(c *ClientConfig) { c.SamplingRate = rate }
+}
+
+// WithAllowedEvents sets ClientConfig.AllowedEvents.
+func WithAllowedEvents(names ...string) Option {
+	return func(c *ClientConfig) { c.AllowedEvents = names }
+}
+
+// WithDeniedEvents sets ClientConfig.DeniedEvents.
+func WithDeniedEvents(names ...string) Option {
+	return func(c *ClientConfig) { c.DeniedEvents = names }
+}
+
+// WithBeforeSend appends hooks to ClientConfig.BeforeSend.
+func WithBeforeSend(hooks ...BeforeSendHook) Option {
+	return func(c *ClientConfig) { c.BeforeSend = append(c.BeforeSend, hooks...) }
+}
+
+// WithOnDelivery sets ClientConfig.OnDelivery.
+func WithOnDelivery(callback DeliveryCallback) Option {
+	return func(c *ClientConfig) { c.OnDelivery = callback }
+}
+
+// WithClock sets ClientConfig.Clock.
+func WithClock(clock Clock) Option {
+	return func(c *ClientConfig) { c.Clock = clock }
+}
+
+// WithEnabled sets ClientConfig.Enabled.
+func WithEnabled(enabled bool) Option {
+	return func(c *ClientConfig) { c.Enabled = &enabled }
+}
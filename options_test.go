@@ -0,0 +1,53 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Run("should apply options over the defaults", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		client, err := NewClientWithOptions(
+			WithAPIKey("test-key"),
+			WithEndpoint("http://test.com"),
+			WithHTTPAdapter(httpAdapter),
+			WithLoggerAdapter(adapters.NewNoOpLoggerAdapter()),
+			WithMaxBatchSize(50),
+			WithFlushInterval(time.Minute),
+			WithAPIKeyHeader("Authorization"),
+			Option(func(c *ClientConfig) { c.EventIDs = true }),
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer client.Dispose()
+
+		if client.config.MaxBatchSize != 50 || client.config.FlushInterval != time.Minute || !client.config.EventIDs {
+			t.Fatalf("options not applied: %+v", client.config)
+		}
+		if client.config.MaxRetries != defaultMaxRetries {
+			t.Fatalf("expected default max retries, got %d", client.config.MaxRetries)
+		}
+		if _, ok := client.config.StorageAdapter.(*adapters.NoOpStorageAdapter); !ok {
+			t.Fatalf("expected NoOpStorageAdapter by default, got %T", client.config.StorageAdapter)
+		}
+
+		_ = client.Track("a", nil, nil)
+		client.Flush()
+		httpAdapter.mu.Lock()
+		defer httpAdapter.mu.Unlock()
+		if httpAdapter.calls != 1 || httpAdapter.lastHeaders["Authorization"] != "test-key" {
+			t.Fatalf("unexpected calls: %d, headers %v", httpAdapter.calls, httpAdapter.lastHeaders)
+		}
+	})
+
+	t.Run("should validate the resulting config", func(t *testing.T) {
+		if _, err := NewClientWithOptions(WithAPIKey("test-key")); err == nil || err.Error() != "endpoint is required" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
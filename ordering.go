@@ -0,0 +1,131 @@
+package ripple
+
+import "fmt"
+
+// OrderingMode controls whether events may overtake a failed batch.
+type OrderingMode int
+
+const (
+	// OrderingNone keeps sending later batches while a failed batch is
+	// re-queued, so retried events can arrive after newer ones.
+	OrderingNone OrderingMode = iota
+
+	// OrderingGlobal holds every event behind a failed batch until the next
+	// flush, so events are delivered in the order they were tracked.
+	OrderingGlobal
+
+	// OrderingPerKey holds only events sharing an ordering key (see
+	// ClientConfig.OrderingKey) with an event of a failed batch. Events for
+	// other keys, and events without the key, are still sent.
+	OrderingPerKey
+)
+
+// String returns the name of the mode.
+func (m OrderingMode) String() string {
+	switch m {
+	case OrderingNone:
+		return "none"
+	case OrderingGlobal:
+		return "global"
+	case OrderingPerKey:
+		return "per-key"
+	default:
+		return fmt.Sprintf("OrderingMode(%d)", int(m))
+	}
+}
+
+// sessionOrderingKey orders events by their session ID.
+const sessionOrderingKey = "sessionId"
+
+// validOrderingKey reports whether path is "sessionId" or a field path under
+// payload or metadata, e.g. "payload.userId".
+func validOrderingKey(path string) bool {
+	if path == sessionOrderingKey {
+		return true
+	}
+	root, fields := splitRedactionPath(path)
+	return (root == "payload" || root == "metadata") && len(fields) > 0
+}
+
+// orderingKey returns the value of path in event, formatted as a string.
+// Events without the field have no key.
+func orderingKey(event Event, path string) (string, bool) {
+	if path == sessionOrderingKey {
+		if event.SessionID == nil {
+			return "", false
+		}
+		return *event.SessionID, true
+	}
+
+	root, fields := splitRedactionPath(path)
+	var value any
+	switch root {
+	case "payload":
+		value = event.Payload
+	case "metadata":
+		value = event.Metadata
+	}
+	for _, field := range fields {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[field]; !ok {
+			return "", false
+		}
+	}
+	if value == nil {
+		return "", false
+	}
+	return fmt.Sprint(value), true
+}
+
+// orderingGate holds back events of a flush that must not overtake a failed
+// batch.
+type orderingGate struct {
+	mode    OrderingMode
+	path    string
+	blocked bool
+	keys    map[string]struct{}
+}
+
+func newOrderingGate(mode OrderingMode, path string) *orderingGate {
+	return &orderingGate{mode: mode, path: path, keys: make(map[string]struct{})}
+}
+
+// block records the events of a failed batch.
+func (g *orderingGate) block(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	if g.mode == OrderingGlobal {
+		g.blocked = true
+		return
+	}
+	for _, event := range events {
+		if key, ok := orderingKey(event, g.path); ok {
+			g.keys[key] = struct{}{}
+		}
+	}
+}
+
+// split separates batch into events that may be sent and events held behind
+// a failed batch, preserving their order.
+func (g *orderingGate) split(batch []Event) (send, held []Event) {
+	if g.blocked {
+		return nil, batch
+	}
+	if len(g.keys) == 0 {
+		return batch, nil
+	}
+	for _, event := range batch {
+		if key, ok := orderingKey(event, g.path); ok {
+			if _, blocked := g.keys[key]; blocked {
+				held = append(held, event)
+				continue
+			}
+		}
+		send = append(send, event)
+	}
+	return send, held
+}
@@ -0,0 +1,167 @@
+package ripple
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// withOrdering selects mode, keyed by the userId payload field, and sends one
+// event per batch without retries. Tests restore their events from storage
+// because enqueueing a full batch flushes it right away.
+func withOrdering(mode OrderingMode) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.MaxBatchSize = 1
+		c.MaxRetries = 0
+		c.Ordering = mode
+		c.OrderingKey = "payload.userId"
+	}
+}
+
+func orderingEvent(name, userID string) Event {
+	return Event{Name: name, Payload: map[string]any{"userId": userID}}
+}
+
+func sentNames(requests []adapters.ScriptedRequest) []string {
+	var names []string
+	for _, request := range requests {
+		for _, event := range request.Events {
+			names = append(names, event.Name)
+		}
+	}
+	return names
+}
+
+func queuedNames(d *Dispatcher) []string {
+	var names []string
+	for _, event := range d.queue.ToSlice() {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func TestDispatcher_Ordering(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   OrderingMode
+		sent   []string
+		queued []string
+	}{
+		{name: "none sends past a failed batch", mode: OrderingNone, sent: []string{"a", "b", "c"}, queued: []string{"a"}},
+		{name: "global holds every later event", mode: OrderingGlobal, sent: []string{"a"}, queued: []string{"a", "b", "c"}},
+		{name: "per key holds events with the failed key", mode: OrderingPerKey, sent: []string{"a", "b"}, queued: []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpAdapter := adapters.NewScriptedHTTPAdapter(
+				adapters.Scenario{Status: 500},
+				adapters.Scenario{Status: 200},
+			)
+			storage := &mockStorageAdapter{loaded: []Event{orderingEvent("a", "u1"), orderingEvent("b", "u2"), orderingEvent("c", "u1")}}
+			d := newTestDispatcher(httpAdapter, storage, withOrdering(tt.mode))
+			d.Restore()
+			defer d.Dispose()
+
+			d.Flush()
+
+			if got := sentNames(httpAdapter.Requests()); !reflect.DeepEqual(got, tt.sent) {
+				t.Fatalf("expected sends %v, got %v", tt.sent, got)
+			}
+			if got := queuedNames(d); !reflect.DeepEqual(got, tt.queued) {
+				t.Fatalf("expected queue %v, got %v", tt.queued, got)
+			}
+		})
+	}
+
+	t.Run("should deliver held events in order on the next flush", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 500},
+			adapters.Scenario{Status: 200},
+		)
+		storage := &mockStorageAdapter{loaded: []Event{orderingEvent("a", "u1"), orderingEvent("b", "u2")}}
+		d := newTestDispatcher(httpAdapter, storage, withOrdering(OrderingGlobal))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Flush()
+		d.Enqueue(orderingEvent("c", "u1"))
+		d.Flush()
+
+		want := []string{"a", "a", "b", "c"}
+		if got := sentNames(httpAdapter.Requests()); !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected sends %v, got %v", want, got)
+		}
+	})
+
+	t.Run("should not hold events without the ordering key", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 500},
+			adapters.Scenario{Status: 200},
+		)
+		storage := &mockStorageAdapter{loaded: []Event{orderingEvent("a", "u1"), {Name: "b"}}}
+		d := newTestDispatcher(httpAdapter, storage, withOrdering(OrderingPerKey))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Flush()
+
+		if got := queuedNames(d); !reflect.DeepEqual(got, []string{"a"}) {
+			t.Fatalf("expected only the failed event to be queued, got %v", got)
+		}
+	})
+}
+
+func TestOrderingKey(t *testing.T) {
+	session := "s1"
+	event := Event{
+		Payload:   map[string]any{"user": map[string]any{"id": 42}},
+		Metadata:  map[string]any{"device": "d1"},
+		SessionID: &session,
+	}
+
+	tests := []struct {
+		path string
+		key  string
+		ok   bool
+	}{
+		{path: "sessionId", key: "s1", ok: true},
+		{path: "payload.user.id", key: "42", ok: true},
+		{path: "metadata.device", key: "d1", ok: true},
+		{path: "payload.missing", ok: false},
+		{path: "metadata.device.id", ok: false},
+	}
+	for _, tt := range tests {
+		key, ok := orderingKey(event, tt.path)
+		if key != tt.key || ok != tt.ok {
+			t.Errorf("orderingKey(%q) = %q, %v; want %q, %v", tt.path, key, ok, tt.key, tt.ok)
+		}
+	}
+}
+
+func TestClient_OrderingValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*ClientConfig)
+	}{
+		{name: "per key without key", modify: func(c *ClientConfig) { c.Ordering = OrderingPerKey }},
+		{name: "per key with invalid key", modify: func(c *ClientConfig) {
+			c.Ordering = OrderingPerKey
+			c.OrderingKey = "userId"
+		}},
+		{name: "key without per key", modify: func(c *ClientConfig) { c.OrderingKey = "sessionId" }},
+		{name: "scheduled retries", modify: func(c *ClientConfig) {
+			c.Ordering = OrderingGlobal
+			c.RetryMode = RetryScheduled
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			tt.modify(&config)
+			if _, err := NewClient(config); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package ripple
+
+import (
+	"net/http"
+	"time"
+)
+
+// rejectedEvents returns the distinct, in-range indices of the events in a
+// batch of n that resp reports as rejected, taken from
+// HTTPResponse.RejectedEvents or else from a "rejectedEvents" body field.
+func rejectedEvents(resp *HTTPResponse, n int) []int {
+	indices := resp.RejectedEvents
+	if len(indices) == 0 {
+		indices = newHTTPError(resp).RejectedEvents
+	}
+
+	seen := make(map[int]bool, len(indices))
+	var valid []int
+	for _, i := range indices {
+		if i >= 0 && i < n && !seen[i] {
+			seen[i] = true
+			valid = append(valid, i)
+		}
+	}
+	return valid
+}
+
+// acceptsPartially reports whether status may carry per-event acceptance:
+// any 2xx, such as 207 Multi-Status, or 422 Unprocessable Entity.
+func acceptsPartially(status int) bool {
+	return status >= 200 && status < 300 || status == http.StatusUnprocessableEntity
+}
+
+// handlePartialRejection delivers the accepted events of a batch the server
+// partially rejected and drops the rejected ones, reporting each group to
+// OnDelivery separately. Neither group is resent. Returns false, leaving the
+// response to the regular status handling, if no event was rejected.
+func (d *Dispatcher) handlePartialRejection(resp *HTTPResponse, endpoint string, events []Event, attempt int, sentAt time.Time) bool {
+	if !acceptsPartially(resp.Status) {
+		return false
+	}
+	rejected := rejectedEvents(resp, len(events))
+	if len(rejected) == 0 {
+		return false
+	}
+
+	isRejected := make(map[int]bool, len(rejected))
+	for _, i := range rejected {
+		isRejected[i] = true
+	}
+	var accepted, dropped []Event
+	for i, event := range events {
+		if isRejected[i] {
+			dropped = append(dropped, event)
+		} else {
+			accepted = append(accepted, event)
+		}
+	}
+
+	httpErr := attemptHTTPError(resp, endpoint, attempt)
+	httpErr.RejectedEvents = rejected
+	d.loggerAdapter.Warn("Server rejected events, dropping them", map[string]any{
+		"status":        resp.Status,
+		"rejectedCount": len(dropped),
+		"acceptedCount": len(accepted),
+		"error":         httpErr.Error(),
+	})
+
+	if len(accepted) > 0 {
+		d.latency.record(accepted, sentAt)
+		d.batchDelivered(accepted)
+	}
+	d.stats.eventsRejected(len(dropped))
+	d.batchDropped(dropped, httpErr)
+	if err := d.releaseStored(events); err != nil {
+		d.loggerAdapter.Error("Failed to clear storage after partial rejection", map[string]any{
+			"error": err.Error(),
+		})
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+type delivery struct {
+	names []string
+	err   error
+}
+
+// recordDeliveries returns an option setting an OnDelivery callback that
+// records each batch, and a function returning the recorded deliveries.
+func recordDeliveries() (func(*DispatcherConfig), func() []delivery) {
+	var mu sync.Mutex
+	var deliveries []delivery
+	record := func(c *DispatcherConfig) {
+		c.OnDelivery = func(batch []Event, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			var names []string
+			for _, event := range batch {
+				names = append(names, event.Name)
+			}
+			deliveries = append(deliveries, delivery{names: names, err: err})
+		}
+	}
+	return record, func() []delivery {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]delivery(nil), deliveries...)
+	}
+}
+
+func TestRejectedEvents(t *testing.T) {
+	t.Run("should prefer the response field over the body", func(t *testing.T) {
+		resp := &HTTPResponse{Status: 207, RejectedEvents: []int{1}, Data: map[string]any{"rejectedEvents": []any{float64(0)}}}
+		if got := rejectedEvents(resp, 3); len(got) != 1 || got[0] != 1 {
+			t.Fatalf("expected [1], got %v", got)
+		}
+	})
+
+	t.Run("should drop duplicate and out-of-range indices", func(t *testing.T) {
+		resp := &HTTPResponse{Status: 422, Data: map[string]any{"rejectedEvents": []any{float64(2), float64(2), float64(5)}}}
+		if got := rejectedEvents(resp, 3); len(got) != 1 || got[0] != 2 {
+			t.Fatalf("expected [2], got %v", got)
+		}
+	})
+}
+
+func TestDispatcher_PartialRejection(t *testing.T) {
+	t.Run("should deliver accepted events and drop rejected ones on 207", func(t *testing.T) {
+		httpAdapter := &bodyHTTPAdapter{status: 207, data: map[string]any{"rejectedEvents": []any{float64(1)}}}
+		storage := &mockStorageAdapter{}
+		record, deliveries := recordDeliveries()
+		d := newTestDispatcher(httpAdapter, storage, record)
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.Enqueue(Event{Name: "c"})
+		d.Flush()
+
+		got := deliveries()
+		if len(got) != 2 {
+			t.Fatalf("expected accepted and rejected deliveries, got %+v", got)
+		}
+		if got[0].err != nil || len(got[0].names) != 2 || got[0].names[0] != "a" || got[0].names[1] != "c" {
+			t.Fatalf("expected a and c delivered, got %+v", got[0])
+		}
+		var httpErr *HTTPError
+		if !errors.As(got[1].err, &httpErr) || len(got[1].names) != 1 || got[1].names[0] != "b" {
+			t.Fatalf("expected b rejected with an HTTPError, got %+v", got[1])
+		}
+		if len(httpErr.RejectedEvents) != 1 || httpErr.RejectedEvents[0] != 1 {
+			t.Fatalf("expected rejected index 1, got %v", httpErr.RejectedEvents)
+		}
+
+		stats := d.Stats()
+		if stats.EventsSent != 2 || stats.EventsRejected != 1 || stats.QueueLength != 0 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+		if storage.clearCalls != 1 {
+			t.Fatalf("expected storage to be released, got %d clear calls", storage.clearCalls)
+		}
+	})
+
+	t.Run("should not resend accepted events on 422", func(t *testing.T) {
+		record, deliveries := recordDeliveries()
+		d := newTestDispatcher(&bodyHTTPAdapter{status: 422, rejected: []int{0}}, &mockStorageAdapter{}, record)
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		got := deliveries()
+		if len(got) != 2 || got[0].names[0] != "b" || got[1].names[0] != "a" {
+			t.Fatalf("expected b delivered and a rejected, got %+v", got)
+		}
+		if stats := d.Stats(); stats.EventsSent != 1 || stats.EventsRejected != 1 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("should drop the whole batch on 422 without indices", func(t *testing.T) {
+		record, deliveries := recordDeliveries()
+		d := newTestDispatcher(&bodyHTTPAdapter{status: 422}, &mockStorageAdapter{}, record)
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		got := deliveries()
+		if len(got) != 1 || len(got[0].names) != 2 || got[0].err == nil {
+			t.Fatalf("expected the whole batch dropped, got %+v", got)
+		}
+		if stats := d.Stats(); stats.EventsRejected != 0 {
+			t.Fatalf("expected no individually rejected events, got %d", stats.EventsRejected)
+		}
+	})
+
+	t.Run("should fail SendNow when its event is rejected", func(t *testing.T) {
+		d := newTestDispatcher(&bodyHTTPAdapter{status: 207, rejected: []int{0}}, &mockStorageAdapter{})
+
+		var httpErr *HTTPError
+		if err := d.SendNow(context.Background(), Event{Name: "a"}); !errors.As(err, &httpErr) || httpErr.Status != 207 {
+			t.Fatalf("expected an HTTPError, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+)
+
+// PersistTrigger identifies the dispatcher event that may cause the queue to
+// be checkpointed to storage.
+type PersistTrigger int
+
+const (
+	// PersistTriggerEnqueue fires after an event is added to the queue.
+	PersistTriggerEnqueue PersistTrigger = iota
+
+	// PersistTriggerFailure fires after a batch exhausts its retries and is
+	// re-queued.
+	PersistTriggerFailure
+
+	// PersistTriggerShutdown fires when the dispatcher is disposed.
+	PersistTriggerShutdown
+)
+
+// PersistState describes the queue since the last successful checkpoint.
+type PersistState struct {
+	// PendingEvents is the number of events enqueued since the last checkpoint.
+	PendingEvents int
+
+	// LastPersist is when the queue was last checkpointed.
+	// Zero if it has not been checkpointed yet.
+	LastPersist time.Time
+
+	// Now is the current time according to the dispatcher's Clock.
+	// Zero means the wall clock.
+	Now time.Time
+}
+
+// PersistencePolicy decides when queued events are checkpointed to storage,
+// trading write amplification against durability.
+type PersistencePolicy interface {
+	// ShouldPersist reports whether the queue should be saved for trigger.
+	ShouldPersist(trigger PersistTrigger, state PersistState) bool
+}
+
+// PersistencePolicyFunc adapts a function to the PersistencePolicy interface.
+type PersistencePolicyFunc func(trigger PersistTrigger, state PersistState) bool
+
+// ShouldPersist calls f(trigger, state).
+func (f PersistencePolicyFunc) ShouldPersist(trigger PersistTrigger, state PersistState) bool {
+	return f(trigger, state)
+}
+
+// PersistNever never checkpoints the queue. Events live only in memory.
+func PersistNever() PersistencePolicy {
+	return PersistencePolicyFunc(func(PersistTrigger, PersistState) bool {
+		return false
+	})
+}
+
+// PersistOnShutdown checkpoints the queue only when the client is disposed.
+func PersistOnShutdown() PersistencePolicy {
+	return PersistencePolicyFunc(func(trigger PersistTrigger, _ PersistState) bool {
+		return trigger == PersistTriggerShutdown
+	})
+}
+
+// PersistOnFailure checkpoints the queue whenever a batch fails delivery.
+func PersistOnFailure() PersistencePolicy {
+	return PersistencePolicyFunc(func(trigger PersistTrigger, _ PersistState) bool {
+		return trigger == PersistTriggerFailure
+	})
+}
+
+// PersistEveryN checkpoints the queue once n events have been enqueued since
+// the last checkpoint. Values below 1 are treated as 1.
+func PersistEveryN(n int) PersistencePolicy {
+	if n < 1 {
+		n = 1
+	}
+	return PersistencePolicyFunc(func(trigger PersistTrigger, state PersistState) bool {
+		return trigger == PersistTriggerEnqueue && state.PendingEvents >= n
+	})
+}
+
+// PersistEvery checkpoints the queue on enqueue when at least interval has
+// passed since the last checkpoint.
+func PersistEvery(interval time.Duration) PersistencePolicy {
+	return PersistencePolicyFunc(func(trigger PersistTrigger, state PersistState) bool {
+		now := state.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		return trigger == PersistTriggerEnqueue && now.Sub(state.LastPersist) >= interval
+	})
+}
+
+// PersistAny checkpoints the queue when any of the given policies would.
+func PersistAny(policies ...PersistencePolicy) PersistencePolicy {
+	return PersistencePolicyFunc(func(trigger PersistTrigger, state PersistState) bool {
+		for _, policy := range policies {
+			if policy.ShouldPersist(trigger, state) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// defaultPersistencePolicy persists on every enqueue and on every failure.
+func defaultPersistencePolicy() PersistencePolicy {
+	return PersistAny(PersistEveryN(1), PersistOnFailure())
+}
+
+// persistTicker calls persist every interval until stopped, checkpointing
+// queue changes that PersistencePolicy left unsaved.
+type persistTicker struct {
+	interval time.Duration
+	persist  func()
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	spawn    func(func())
+	clock    Clock
+	mu       sync.Mutex
+}
+
+func newPersistTicker(interval time.Duration, persist func()) *persistTicker {
+	return &persistTicker{interval: interval, persist: persist, spawn: goSpawn, clock: systemClock{}}
+}
+
+// Start begins ticking until Stop is called.
+func (p *persistTicker) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopCh != nil {
+		return
+	}
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	p.stopCh = stopCh
+	p.doneCh = doneCh
+
+	p.spawn(func() {
+		defer close(doneCh)
+		ticker := p.clock.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				p.persist()
+			case <-stopCh:
+				return
+			}
+		}
+	})
+}
+
+// Stop halts ticking and waits for an in-flight checkpoint to finish.
+func (p *persistTicker) Stop() {
+	p.mu.Lock()
+	stopCh, doneCh := p.stopCh, p.doneCh
+	p.stopCh, p.doneCh = nil, nil
+	p.mu.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+		<-doneCh
+	}
+}
+
+// persistDirty checkpoints the queue if it changed since it was last saved.
+// It is skipped while spilled, as storage then holds the queue, and while a
+// flush is in progress, as the flush clears or checkpoints storage itself.
+func (d *Dispatcher) persistDirty() {
+	if !d.flushMu.TryLock() {
+		return
+	}
+	defer d.flushMu.Unlock()
+
+	d.mu.Lock()
+	if d.disposed || d.spilled || !d.dirty || d.atLeastOnce() {
+		d.mu.Unlock()
+		return
+	}
+	d.dirty = false
+	d.mu.Unlock()
+
+	events := d.queue.ToSlice()
+	if len(events) == 0 {
+		return
+	}
+	if err := d.saveEvents(events); err != nil {
+		d.mu.Lock()
+		d.dirty = true
+		d.mu.Unlock()
+		d.logStorageError("Failed to persist events on interval", err, map[string]any{
+			"queueSize": len(events),
+		})
+		return
+	}
+	d.persisted()
+}
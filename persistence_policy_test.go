@@ -0,0 +1,180 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPersistencePolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  PersistencePolicy
+		trigger PersistTrigger
+		state   PersistState
+		want    bool
+	}{
+		{"never on enqueue", PersistNever(), PersistTriggerEnqueue, PersistState{PendingEvents: 100}, false},
+		{"never on shutdown", PersistNever(), PersistTriggerShutdown, PersistState{}, false},
+		{"shutdown on shutdown", PersistOnShutdown(), PersistTriggerShutdown, PersistState{}, true},
+		{"shutdown on enqueue", PersistOnShutdown(), PersistTriggerEnqueue, PersistState{PendingEvents: 1}, false},
+		{"failure on failure", PersistOnFailure(), PersistTriggerFailure, PersistState{}, true},
+		{"failure on enqueue", PersistOnFailure(), PersistTriggerEnqueue, PersistState{PendingEvents: 1}, false},
+		{"every N below threshold", PersistEveryN(3), PersistTriggerEnqueue, PersistState{PendingEvents: 2}, false},
+		{"every N at threshold", PersistEveryN(3), PersistTriggerEnqueue, PersistState{PendingEvents: 3}, true},
+		{"every N clamps to 1", PersistEveryN(0), PersistTriggerEnqueue, PersistState{PendingEvents: 1}, true},
+		{"every T elapsed", PersistEvery(time.Second), PersistTriggerEnqueue, PersistState{LastPersist: time.Now().Add(-2 * time.Second)}, true},
+		{"every T not elapsed", PersistEvery(time.Minute), PersistTriggerEnqueue, PersistState{LastPersist: time.Now()}, false},
+		{"any matches", PersistAny(PersistNever(), PersistOnShutdown()), PersistTriggerShutdown, PersistState{}, true},
+		{"any none match", PersistAny(PersistNever(), PersistOnShutdown()), PersistTriggerFailure, PersistState{}, false},
+		{"default on enqueue", defaultPersistencePolicy(), PersistTriggerEnqueue, PersistState{PendingEvents: 1}, true},
+		{"default on failure", defaultPersistencePolicy(), PersistTriggerFailure, PersistState{}, true},
+		{"default on shutdown", defaultPersistencePolicy(), PersistTriggerShutdown, PersistState{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ShouldPersist(tt.trigger, tt.state); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDispatcher_PersistencePolicy(t *testing.T) {
+	newDispatcher := func(policy PersistencePolicy, storage *mockStorageAdapter) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:            "test-key",
+			APIKeyHeader:      "X-API-Key",
+			Endpoint:          "http://test.com",
+			FlushInterval:     10 * time.Second,
+			MaxBatchSize:      100,
+			MaxRetries:        3,
+			PersistencePolicy: policy,
+		}, &mockHTTPAdapter{}, storage, &mockLogger{})
+	}
+
+	t.Run("every N batches writes", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(PersistEveryN(3), storage)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected no checkpoint before 3 events")
+		}
+
+		d.Enqueue(Event{Name: "c"})
+		if len(storage.getSaved()) != 3 {
+			t.Fatalf("expected checkpoint of 3 events, got %d", len(storage.getSaved()))
+		}
+
+		d.Enqueue(Event{Name: "d"})
+		if len(storage.getSaved()) != 3 {
+			t.Fatal("expected pending counter to reset after checkpoint")
+		}
+	})
+
+	t.Run("shutdown only persists on dispose", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(PersistOnShutdown(), storage)
+		d.Restore()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected no checkpoint before dispose")
+		}
+
+		d.Dispose()
+		if len(storage.getSaved()) != 2 {
+			t.Fatalf("expected 2 events persisted on dispose, got %d", len(storage.getSaved()))
+		}
+	})
+
+	t.Run("never persists", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(PersistNever(), storage)
+		d.Restore()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Dispose()
+
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected no events persisted")
+		}
+	})
+}
+
+func TestDispatcher_PersistInterval(t *testing.T) {
+	newDispatcher := func(storage *mockStorageAdapter, interval time.Duration) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:            "test-key",
+			APIKeyHeader:      "X-API-Key",
+			Endpoint:          "http://test.com",
+			FlushInterval:     10 * time.Second,
+			MaxBatchSize:      100,
+			MaxRetries:        3,
+			PersistencePolicy: PersistNever(),
+			PersistInterval:   interval,
+		}, &mockHTTPAdapter{}, storage, &mockLogger{})
+	}
+
+	t.Run("should checkpoint unsaved events on each tick", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(storage, 10*time.Millisecond)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+
+		deadline := time.Now().Add(time.Second)
+		for len(storage.getSaved()) != 2 {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected 2 events checkpointed, got %d", len(storage.getSaved()))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	t.Run("should only save when the queue changed", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(storage, time.Hour)
+		d.Restore()
+		defer d.Dispose()
+
+		d.persistDirty()
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected no checkpoint of an unchanged queue")
+		}
+
+		d.Enqueue(Event{Name: "a"})
+		d.persistDirty()
+		if len(storage.getSaved()) != 1 {
+			t.Fatalf("expected 1 event checkpointed, got %d", len(storage.getSaved()))
+		}
+
+		_ = storage.Clear()
+		d.persistDirty()
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected no second checkpoint without changes")
+		}
+	})
+
+	t.Run("should clear storage after successful delivery", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(storage, time.Hour)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.persistDirty()
+		d.Flush()
+
+		if len(storage.getSaved()) != 0 {
+			t.Fatalf("expected storage cleared after delivery, got %d events", len(storage.getSaved()))
+		}
+	})
+}
@@ -30,7 +30,7 @@ func main() {
 		MaxBatchSize:   5,
 		MaxRetries:     3,
 		HTTPAdapter:    httpAdapter,
-		StorageAdapter: NewFileStorageAdapter("ripple_events.json"),
+		StorageAdapter: adapters.NewFileStorageAdapter("ripple_events.json"),
 		LoggerAdapter:  adapters.NewPrintLoggerAdapter(adapters.LogLevelDebug),
 	})
 
@@ -216,7 +216,7 @@ func testInvalidEndpoint() {
 		MaxBatchSize:   5,
 		MaxRetries:     2,
 		HTTPAdapter:    adapters.NewNetHTTPAdapter(),
-		StorageAdapter: NewFileStorageAdapter("error_events.json"),
+		StorageAdapter: adapters.NewFileStorageAdapter("error_events.json"),
 		LoggerAdapter:  adapters.NewPrintLoggerAdapter(adapters.LogLevelWarn),
 	})
 
@@ -0,0 +1,80 @@
+package ripple
+
+import "fmt"
+
+// PropertyType is the JSON type a payload property must have.
+type PropertyType string
+
+const (
+	// PropertyAny accepts a value of any type.
+	PropertyAny PropertyType = ""
+
+	PropertyString  PropertyType = "string"
+	PropertyNumber  PropertyType = "number"
+	PropertyInteger PropertyType = "integer"
+	PropertyBoolean PropertyType = "boolean"
+	PropertyObject  PropertyType = "object"
+	PropertyArray   PropertyType = "array"
+)
+
+// PropertySpec describes one top-level payload property for
+// RegisterProperties.
+type PropertySpec struct {
+	// Name is the payload key.
+	Name string
+
+	// Type is the JSON type the value must have. Go numbers count as
+	// "number", and as "integer" if they have no fractional part.
+	Type PropertyType
+
+	// Required rejects events whose payload lacks the property.
+	Required bool
+}
+
+// RegisterProperties adds or replaces the schema for an event name and
+// version, like Register, from a list of property specs instead of a JSON
+// Schema. Properties not listed are allowed. For example:
+//
+//	validator.RegisterProperties("purchase_completed", "",
+//		ripple.PropertySpec{Name: "orderId", Type: ripple.PropertyString, Required: true},
+//		ripple.PropertySpec{Name: "amount", Type: ripple.PropertyNumber, Required: true},
+//		ripple.PropertySpec{Name: "coupon", Type: ripple.PropertyString},
+//	)
+func (v *SchemaValidator) RegisterProperties(eventName, version string, specs ...PropertySpec) error {
+	if eventName == "" {
+		return fmt.Errorf("event name cannot be empty")
+	}
+
+	schema := &jsonSchema{
+		Type:       schemaTypes{"object"},
+		Properties: make(map[string]*jsonSchema, len(specs)),
+	}
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return fmt.Errorf("invalid properties for %q: property name cannot be empty", eventName)
+		}
+		if _, ok := schema.Properties[spec.Name]; ok {
+			return fmt.Errorf("invalid properties for %q: property %q is listed twice", eventName, spec.Name)
+		}
+		prop := &jsonSchema{}
+		switch spec.Type {
+		case PropertyAny:
+		case PropertyString, PropertyNumber, PropertyInteger, PropertyBoolean, PropertyObject, PropertyArray:
+			prop.Type = schemaTypes{string(spec.Type)}
+		default:
+			return fmt.Errorf("invalid properties for %q: property %q has unknown type %q", eventName, spec.Name, spec.Type)
+		}
+		schema.Properties[spec.Name] = prop
+		if spec.Required {
+			schema.Required = append(schema.Required, spec.Name)
+		}
+	}
+	if err := schema.compile(); err != nil {
+		return fmt.Errorf("invalid properties for %q: %w", eventName, err)
+	}
+
+	v.mu.Lock()
+	v.schemas[schemaKey{eventName, version}] = schema
+	v.mu.Unlock()
+	return nil
+}
@@ -1,26 +1,47 @@
 package ripple
 
 import (
-	"container/list"
+	"slices"
 	"sync"
 )
 
+const (
+	// minQueueCapacity is the smallest ring buffer a Queue allocates.
+	minQueueCapacity = 16
+
+	// maxRetainedQueueCapacity is the largest ring buffer a Queue keeps for
+	// reuse once emptied; larger ones, grown during a spike, are released.
+	maxRetainedQueueCapacity = 1024
+)
+
 // Queue represents a thread-safe FIFO queue for Event items.
+//
+// Events are stored by value in a ring buffer that grows by doubling, so
+// enqueueing does not allocate once the buffer has reached its working size.
 type Queue struct {
-	mu   sync.Mutex
-	list *list.List
+	mu    sync.Mutex
+	buf   []Event
+	head  int
+	count int
+	sized bool
+	bytes int64
 }
 
 // NewQueue creates and returns a new empty Queue.
 func NewQueue() *Queue {
-	return &Queue{list: list.New()}
+	return &Queue{}
 }
 
 // Enqueue adds an Event to the end of the queue.
 func (q *Queue) Enqueue(event Event) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.list.PushBack(event)
+	q.grow(q.count + 1)
+	q.buf[(q.head+q.count)%len(q.buf)] = event
+	q.count++
+	if q.sized {
+		q.bytes += eventBytes(event)
+	}
 }
 
 // Dequeue removes and returns the front Event in the queue.
@@ -28,52 +49,184 @@ func (q *Queue) Enqueue(event Event) {
 func (q *Queue) Dequeue() (Event, bool) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	if q.list.Len() == 0 {
+	if q.count == 0 {
 		return Event{}, false
 	}
-	front := q.list.Front()
-	q.list.Remove(front)
-	return front.Value.(Event), true
+	event := q.popFront()
+	if q.sized {
+		q.bytes -= eventBytes(event)
+	}
+	return event, true
 }
 
 // IsEmpty reports whether the queue has no elements.
 func (q *Queue) IsEmpty() bool {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return q.list.Len() == 0
+	return q.count == 0
 }
 
 // Len returns the number of Events currently in the queue.
 func (q *Queue) Len() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	return q.list.Len()
+	return q.count
 }
 
 // Clear removes all Events from the queue.
 func (q *Queue) Clear() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.list.Init()
+	q.reset()
 }
 
 // ToSlice returns all Events in the queue as a slice, preserving order.
 func (q *Queue) ToSlice() []Event {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	events := make([]Event, 0, q.list.Len())
-	for e := q.list.Front(); e != nil; e = e.Next() {
-		events = append(events, e.Value.(Event))
-	}
-	return events
+	return q.contents()
 }
 
 // LoadFromSlice replaces the queue contents with Events from the provided slice.
 func (q *Queue) LoadFromSlice(events []Event) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
-	q.list.Init()
-	for _, event := range events {
-		q.list.PushBack(event)
+	q.reset()
+	q.grow(len(events))
+	copy(q.buf, events)
+	q.count = len(events)
+	if q.sized {
+		for _, event := range events {
+			q.bytes += eventBytes(event)
+		}
+	}
+}
+
+// drainTo atomically appends the queued Events to dst in order and empties
+// the queue.
+func (q *Queue) drainTo(dst []Event) []Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(dst)
+	dst = slices.Grow(dst, q.count)[:n+q.count]
+	q.copyTo(dst[n:])
+	q.reset()
+	return dst
+}
+
+// pushFront atomically inserts events ahead of the queued Events, keeping
+// their order. If limit is positive, the oldest Events are dropped to keep at
+// most limit. Returns the resulting queue contents.
+func (q *Queue) pushFront(events []Event, limit int) []Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.grow(q.count + len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		q.head = (q.head - 1 + len(q.buf)) % len(q.buf)
+		q.buf[q.head] = events[i]
+		q.count++
+		if q.sized {
+			q.bytes += eventBytes(events[i])
+		}
+	}
+	for limit > 0 && q.count > limit {
+		event := q.popFront()
+		if q.sized {
+			q.bytes -= eventBytes(event)
+		}
+	}
+	return q.contents()
+}
+
+// popFront removes and returns the front Event, clearing its slot so the
+// buffer does not keep its payload alive. Caller must hold q.mu and ensure
+// the queue is not empty.
+func (q *Queue) popFront() Event {
+	event := q.buf[q.head]
+	q.buf[q.head] = Event{}
+	q.head = (q.head + 1) % len(q.buf)
+	q.count--
+	if q.count == 0 {
+		q.head = 0
+	}
+	return event
+}
+
+// grow ensures the buffer can hold n Events, moving them to the start of a
+// larger buffer if needed. Caller must hold q.mu.
+func (q *Queue) grow(n int) {
+	if n <= len(q.buf) {
+		return
+	}
+	size := max(len(q.buf)*2, minQueueCapacity)
+	for size < n {
+		size *= 2
+	}
+	buf := make([]Event, size)
+	q.copyTo(buf)
+	q.buf = buf
+	q.head = 0
+}
+
+// contents returns a copy of the queued Events in order. Caller must hold
+// q.mu.
+func (q *Queue) contents() []Event {
+	events := make([]Event, q.count)
+	q.copyTo(events)
+	return events
+}
+
+// copyTo copies the queued Events in order to the start of dst. Caller must
+// hold q.mu.
+func (q *Queue) copyTo(dst []Event) {
+	if q.count == 0 {
+		return
+	}
+	n := copy(dst, q.buf[q.head:min(q.head+q.count, len(q.buf))])
+	copy(dst[n:], q.buf[:q.count-n])
+}
+
+// reset empties the queue, keeping its buffer for reuse unless it is larger
+// than maxRetainedQueueCapacity. Caller must hold q.mu.
+func (q *Queue) reset() {
+	if len(q.buf) > maxRetainedQueueCapacity {
+		q.buf = nil
+	} else {
+		clear(q.buf)
+	}
+	q.head = 0
+	q.count = 0
+	q.bytes = 0
+}
+
+// trackBytes enables incremental accounting of the queue's serialized size,
+// making Bytes O(1) at the cost of encoding each event as it is queued.
+func (q *Queue) trackBytes() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sized = true
+	q.bytes = 0
+	for _, event := range q.contents() {
+		q.bytes += eventBytes(event)
 	}
 }
+
+// Bytes returns the serialized size of the queued Events.
+func (q *Queue) Bytes() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.sized {
+		return q.bytes
+	}
+	var total int64
+	for _, event := range q.contents() {
+		total += eventBytes(event)
+	}
+	return total
+}
+
+// eventBytes returns the serialized size of event, or 0 if it cannot be encoded.
+func eventBytes(event Event) int64 {
+	size, _ := eventSize(&event)
+	return int64(size)
+}
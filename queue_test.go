@@ -77,3 +77,80 @@ func TestQueue_DequeueEmpty(t *testing.T) {
 		t.Fatal("expected dequeue to fail on empty queue")
 	}
 }
+
+func TestQueue_PushFront(t *testing.T) {
+	q := NewQueue()
+	q.Enqueue(Event{Name: "c"})
+
+	contents := q.pushFront([]Event{{Name: "a"}, {Name: "b"}}, 0)
+	if len(contents) != 3 || contents[0].Name != "a" || contents[1].Name != "b" || contents[2].Name != "c" {
+		t.Fatalf("expected a, b, c, got %+v", contents)
+	}
+
+	contents = q.pushFront([]Event{{Name: "z"}}, 2)
+	if len(contents) != 2 || contents[0].Name != "b" || q.Len() != 2 {
+		t.Fatalf("expected oldest events to be dropped, got %+v", contents)
+	}
+}
+
+func TestQueue_WrapAround(t *testing.T) {
+	q := NewQueue()
+	next := 0
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 12; i++ {
+			q.Enqueue(Event{Sequence: uint64(next)})
+			next++
+		}
+		for i := 0; i < 7; i++ {
+			q.Dequeue()
+		}
+	}
+
+	events := q.ToSlice()
+	if len(events) != q.Len() || len(events) != 25 {
+		t.Fatalf("expected 25 events, got %d", len(events))
+	}
+	for i, event := range events {
+		if want := uint64(35 + i); event.Sequence != want {
+			t.Fatalf("expected event %d to have sequence %d, got %d", i, want, event.Sequence)
+		}
+	}
+}
+
+func BenchmarkQueue_Enqueue(b *testing.B) {
+	q := NewQueue()
+	event := Event{Name: "bench", Payload: map[string]any{"k": "v"}}
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		q.Enqueue(event)
+		if i%1000 == 999 {
+			q.Clear()
+		}
+	}
+}
+
+func BenchmarkQueue_EnqueueParallel(b *testing.B) {
+	q := NewQueue()
+	event := Event{Name: "bench", Payload: map[string]any{"k": "v"}}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Enqueue(event)
+			q.Dequeue()
+		}
+	})
+}
+
+func TestQueue_DrainTo(t *testing.T) {
+	q := NewQueue()
+	q.Enqueue(Event{Name: "b"})
+	q.Enqueue(Event{Name: "c"})
+
+	events := q.drainTo([]Event{{Name: "a"}})
+	if len(events) != 3 || events[0].Name != "a" || events[2].Name != "c" {
+		t.Fatalf("expected a, b, c, got %+v", events)
+	}
+	if !q.IsEmpty() {
+		t.Fatal("expected queue to be empty after drain")
+	}
+}
@@ -0,0 +1,80 @@
+package ripple
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitMode controls what happens to a batch when the outbound rate
+// limit has no capacity left.
+type RateLimitMode int
+
+const (
+	// RateLimitWait blocks the flush until capacity is available.
+	RateLimitWait RateLimitMode = iota
+
+	// RateLimitSpill re-queues the remaining batches, persists them, and
+	// schedules another flush instead of waiting.
+	RateLimitSpill
+)
+
+// tokenBucket is a token-bucket rate limiter refilled at rate tokens per
+// second up to burst tokens.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  Clock
+}
+
+// newTokenBucket creates a full bucket. A burst below 1 defaults to
+// max(1, ceil(rate)).
+func newTokenBucket(rate float64, burst int, clock Clock) *tokenBucket {
+	b := float64(burst)
+	if burst < 1 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: clock.Now(), clock: clock}
+}
+
+// refillLocked adds tokens accrued since the last refill. Caller must hold b.mu.
+func (b *tokenBucket) refillLocked(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// allow takes a token if one is available without waiting.
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(b.clock.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+	return false
+}
+
+// wait blocks until a token is available or ctx is done.
+// Returns false if ctx was cancelled first.
+func (b *tokenBucket) wait(ctx context.Context) bool {
+	for {
+		b.mu.Lock()
+		b.refillLocked(b.clock.Now())
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return true
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if !b.clock.Sleep(ctx, delay) {
+			return false
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Allow(t *testing.T) {
+	b := newTokenBucket(1, 2, systemClock{})
+	if !b.allow() || !b.allow() {
+		t.Fatal("expected burst of 2 to be allowed")
+	}
+	if b.allow() {
+		t.Fatal("expected third request to be rejected")
+	}
+}
+
+func TestTokenBucket_DefaultBurst(t *testing.T) {
+	b := newTokenBucket(2.5, 0, systemClock{})
+	if b.burst != 3 {
+		t.Fatalf("expected burst 3, got %v", b.burst)
+	}
+	b = newTokenBucket(0.5, 0, systemClock{})
+	if b.burst != 1 {
+		t.Fatalf("expected burst 1, got %v", b.burst)
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	b := newTokenBucket(50, 1, systemClock{})
+	b.allow()
+
+	start := time.Now()
+	if !b.wait(context.Background()) {
+		t.Fatal("expected wait to succeed")
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("expected wait for refill, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if b.wait(ctx) {
+		t.Fatal("expected cancelled wait to fail")
+	}
+}
+
+func TestDispatcher_RateLimit(t *testing.T) {
+	newDispatcher := func(httpAdapter *mockHTTPAdapter, storage *mockStorageAdapter, mode RateLimitMode) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:               "test-key",
+			APIKeyHeader:         "X-API-Key",
+			Endpoint:             "http://test.com",
+			FlushInterval:        10 * time.Second,
+			MaxBatchSize:         1,
+			MaxRetries:           3,
+			MaxRequestsPerSecond: 20,
+			RateLimitBurst:       1,
+			RateLimitMode:        mode,
+		}, httpAdapter, storage, &mockLogger{})
+	}
+
+	t.Run("wait mode throttles batches", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newDispatcher(httpAdapter, &mockStorageAdapter{}, RateLimitWait)
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.LoadFromSlice([]Event{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+
+		start := time.Now()
+		d.Flush()
+
+		if httpAdapter.getCalls() != 3 {
+			t.Fatalf("expected all 3 batches sent, got %d", httpAdapter.getCalls())
+		}
+		if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+			t.Errorf("expected flush to be throttled, took %v", elapsed)
+		}
+	})
+
+	t.Run("spill mode defers excess batches", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		storage := &mockStorageAdapter{}
+		d := newDispatcher(httpAdapter, storage, RateLimitSpill)
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.LoadFromSlice([]Event{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+		d.Flush()
+
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 batch sent within burst, got %d", httpAdapter.getCalls())
+		}
+		if d.queue.Len() != 2 {
+			t.Fatalf("expected 2 deferred events in queue, got %d", d.queue.Len())
+		}
+		if len(storage.getSaved()) != 2 {
+			t.Fatalf("expected deferred events to be persisted, got %d", len(storage.getSaved()))
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package ripple
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+const defaultRedactionMask = "[REDACTED]"
+
+// RedactAction is what a RedactionRule does to the value at its path.
+type RedactAction int
+
+const (
+	// RedactMask replaces the value with Redactor.Mask.
+	RedactMask RedactAction = iota
+
+	// RedactHash replaces the value with the hex SHA-256 of its string form,
+	// or its HMAC-SHA256 if Redactor.HashKey is set, so events can still be
+	// correlated by the hidden value.
+	RedactHash
+
+	// RedactDrop removes the field.
+	RedactDrop
+)
+
+// RedactionRule redacts one field of every event.
+type RedactionRule struct {
+	// Path is the dot-separated path of the field, rooted at "payload" or
+	// "metadata", such as "payload.user.email" or "metadata.ip". Missing
+	// fields are ignored.
+	Path string
+
+	// Action is what to do with the value.
+	Action RedactAction
+}
+
+// Redactor removes personal data from events before they are queued, so it
+// never reaches storage or the network. Rules run after BeforeSend hooks and
+// schema validation, and never modify the maps passed to Track.
+type Redactor struct {
+	// Rules are applied in order.
+	Rules []RedactionRule
+
+	// Mask replaces values redacted with RedactMask.
+	//
+	// Default: "[REDACTED]".
+	Mask string
+
+	// HashKey keys the HMAC used by RedactHash. Without it, low-entropy
+	// values such as phone numbers can be recovered by brute force.
+	//
+	// Optional: If not set, values are hashed with plain SHA-256.
+	HashKey []byte
+}
+
+// validate reports every invalid rule.
+func (r *Redactor) validate() []string {
+	var problems []string
+	for _, rule := range r.Rules {
+		root, path := splitRedactionPath(rule.Path)
+		if (root != "payload" && root != "metadata") || len(path) == 0 {
+			problems = append(problems, fmt.Sprintf("redaction path %q must start with payload. or metadata.", rule.Path))
+		}
+		if rule.Action < RedactMask || rule.Action > RedactDrop {
+			problems = append(problems, fmt.Sprintf("redaction action for %q is unknown", rule.Path))
+		}
+	}
+	return problems
+}
+
+// apply redacts the event in place, copying any map it changes.
+func (r *Redactor) apply(event *Event) {
+	for _, rule := range r.Rules {
+		root, path := splitRedactionPath(rule.Path)
+		switch root {
+		case "payload":
+			event.Payload = r.redactPath(event.Payload, path, rule.Action)
+		case "metadata":
+			event.Metadata = r.redactPath(event.Metadata, path, rule.Action)
+		}
+	}
+}
+
+// redactPath returns m with the value at path redacted. Every map along the
+// path is copied, so m itself is never modified.
+func (r *Redactor) redactPath(m map[string]any, path []string, action RedactAction) map[string]any {
+	value, ok := m[path[0]]
+	if !ok {
+		return m
+	}
+	if len(path) > 1 {
+		child, ok := value.(map[string]any)
+		if !ok {
+			return m
+		}
+		copied := maps.Clone(m)
+		copied[path[0]] = r.redactPath(child, path[1:], action)
+		return copied
+	}
+
+	copied := maps.Clone(m)
+	switch action {
+	case RedactDrop:
+		delete(copied, path[0])
+	case RedactHash:
+		copied[path[0]] = r.hash(value)
+	default:
+		copied[path[0]] = r.mask()
+	}
+	return copied
+}
+
+func (r *Redactor) mask() string {
+	if r.Mask != "" {
+		return r.Mask
+	}
+	return defaultRedactionMask
+}
+
+// hash returns the hex SHA-256, or HMAC-SHA256 with HashKey, of value's
+// string form.
+func (r *Redactor) hash(value any) string {
+	data := []byte(fmt.Sprint(value))
+	if len(r.HashKey) > 0 {
+		mac := hmac.New(sha256.New, r.HashKey)
+		mac.Write(data)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// splitRedactionPath splits "payload.user.email" into "payload" and
+// ["user", "email"].
+func splitRedactionPath(path string) (string, []string) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[1:] {
+		if part == "" {
+			return parts[0], nil
+		}
+	}
+	return parts[0], parts[1:]
+}
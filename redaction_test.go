@@ -0,0 +1,122 @@
+package ripple
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Run("should mask, hash and drop fields without touching the caller's maps", func(t *testing.T) {
+		config := createTestConfig()
+		config.Redactor = &Redactor{Rules: []RedactionRule{
+			{Path: "payload.user.email", Action: RedactMask},
+			{Path: "payload.phone", Action: RedactHash},
+			{Path: "metadata.ip", Action: RedactDrop},
+			{Path: "payload.missing.field", Action: RedactDrop},
+		}}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		user := map[string]any{"email": "a@example.com", "name": "A"}
+		payload := map[string]any{"user": user, "phone": "+100", "plan": "pro"}
+		metadata := map[string]any{"ip": "10.0.0.1", "source": "web"}
+		_ = client.Track("signup", payload, metadata)
+
+		event := client.dispatcher.queue.ToSlice()[0]
+		redactedUser := event.Payload["user"].(map[string]any)
+		if redactedUser["email"] != "[REDACTED]" || redactedUser["name"] != "A" {
+			t.Fatalf("unexpected user: %v", redactedUser)
+		}
+		sum := sha256.Sum256([]byte("+100"))
+		if event.Payload["phone"] != hex.EncodeToString(sum[:]) || event.Payload["plan"] != "pro" {
+			t.Fatalf("unexpected payload: %v", event.Payload)
+		}
+		if _, ok := event.Metadata["ip"]; ok || event.Metadata["source"] != "web" {
+			t.Fatalf("unexpected metadata: %v", event.Metadata)
+		}
+
+		if user["email"] != "a@example.com" || payload["phone"] != "+100" || metadata["ip"] != "10.0.0.1" {
+			t.Fatal("expected the caller's maps to be unchanged")
+		}
+	})
+
+	t.Run("should use the custom mask and hash key", func(t *testing.T) {
+		r := &Redactor{
+			Rules: []RedactionRule{
+				{Path: "payload.a", Action: RedactMask},
+				{Path: "payload.b", Action: RedactHash},
+			},
+			Mask:    "***",
+			HashKey: []byte("secret"),
+		}
+		event := &Event{Payload: map[string]any{"a": 1, "b": 2}}
+		r.apply(event)
+
+		plain := sha256.Sum256([]byte("2"))
+		if event.Payload["a"] != "***" || event.Payload["b"] == hex.EncodeToString(plain[:]) || len(event.Payload["b"].(string)) != 64 {
+			t.Fatalf("unexpected payload: %v", event.Payload)
+		}
+	})
+
+	t.Run("should reject invalid rules", func(t *testing.T) {
+		for _, rule := range []RedactionRule{
+			{Path: "email"},
+			{Path: "payload."},
+			{Path: "context.ip"},
+			{Path: "payload.a", Action: RedactAction(9)},
+		} {
+			config := createTestConfig()
+			config.Redactor = &Redactor{Rules: []RedactionRule{rule}}
+			if err := config.Validate(); err == nil {
+				t.Errorf("expected error for rule %+v", rule)
+			}
+		}
+	})
+}
+
+func TestClient_DoNotTrack(t *testing.T) {
+	t.Run("should purge events and drop new ones until turned off", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.Identify("user-1", nil)
+		_ = client.Track("a", nil, nil)
+		client.SetDoNotTrack(true)
+
+		if !client.DoNotTrack() || client.dispatcher.queue.Len() != 0 || len(storage.getSaved()) != 0 {
+			t.Fatal("expected queued and stored events to be purged")
+		}
+		if len(client.identity.metadata()) != 0 {
+			t.Fatal("expected the identity to be cleared")
+		}
+		_ = client.Track("b", nil, nil)
+		if client.dispatcher.queue.Len() != 0 {
+			t.Fatal("expected events to be dropped in Do Not Track mode")
+		}
+
+		client.SetDoNotTrack(false)
+		_ = client.Track("c", nil, nil)
+		if client.dispatcher.queue.Len() != 1 {
+			t.Fatal("expected tracking to resume")
+		}
+	})
+
+	t.Run("should purge stored events on Init instead of restoring them", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		_ = storage.Save([]Event{{Name: "stored"}})
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		config.DoNotTrack = true
+		client, _ := NewClient(config)
+		client.Init()
+		defer client.Dispose()
+
+		if client.dispatcher.queue.Len() != 0 || len(storage.getSaved()) != 0 {
+			t.Fatal("expected stored events to be purged")
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package ripple
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRemoteConfigInterval = time.Minute
+
+	// maxRemoteConfigBytes bounds how much of a config response is read.
+	maxRemoteConfigBytes = 1 << 20
+)
+
+// RemoteConfig enables the control-plane mode, in which the client fetches
+// RemoteSettings on Init and then every Interval, and applies them with
+// UpdateConfig. Invalid settings and failed fetches are logged and leave the
+// current configuration unchanged.
+type RemoteConfig struct {
+	// URL is the config endpoint. It is fetched with GET, sending the API
+	// key header, and must respond with RemoteSettings as JSON. ETags are
+	// honored: a 304 response leaves the settings unchanged.
+	//
+	// Required unless Source is set.
+	URL string
+
+	// Interval is the time between fetches.
+	//
+	// Default: 1 minute.
+	Interval time.Duration
+
+	// Source fetches the settings instead of the built-in HTTP fetcher.
+	//
+	// Optional.
+	Source RemoteConfigSource
+}
+
+// RemoteSettings is the document served by a config endpoint. Absent fields
+// leave the corresponding setting unchanged.
+type RemoteSettings struct {
+	// SamplingRate replaces the global sampling rate.
+	SamplingRate *float64 `json:"samplingRate,omitempty"`
+
+	// SamplingRules replaces the per-event sampling rates.
+	SamplingRules map[string]float64 `json:"samplingRules,omitempty"`
+
+	// DisabledEvents replaces the names of events that Track drops. An empty
+	// list re-enables every event.
+	DisabledEvents []string `json:"disabledEvents,omitempty"`
+
+	// AllowedEvents replaces the names and patterns of events that Track
+	// accepts. An empty list allows every event.
+	AllowedEvents []string `json:"allowedEvents,omitempty"`
+
+	// DeniedEvents replaces the names and patterns of events that Track
+	// drops. An empty list denies none.
+	DeniedEvents []string `json:"deniedEvents,omitempty"`
+
+	// MaxBatchSize replaces the number of events per batch.
+	MaxBatchSize *int `json:"maxBatchSize,omitempty"`
+}
+
+// RemoteConfigSource fetches remote settings.
+type RemoteConfigSource interface {
+	// FetchConfig returns the current settings, or nil if they are unchanged
+	// since the previous fetch.
+	FetchConfig(ctx context.Context) (*RemoteSettings, error)
+}
+
+// RemoteConfigSourceFunc adapts a function to the RemoteConfigSource
+// interface.
+type RemoteConfigSourceFunc func(ctx context.Context) (*RemoteSettings, error)
+
+// FetchConfig calls f(ctx).
+func (f RemoteConfigSourceFunc) FetchConfig(ctx context.Context) (*RemoteSettings, error) {
+	return f(ctx)
+}
+
+// httpConfigSource fetches RemoteSettings with net/http.
+type httpConfigSource struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	mu      sync.Mutex
+	etag    string
+}
+
+func newHTTPConfigSource(url string, headers map[string]string) *httpConfigSource {
+	return &httpConfigSource{url: url, headers: headers, client: &http.Client{}}
+}
+
+// FetchConfig implements RemoteConfigSource.
+func (s *httpConfigSource) FetchConfig(ctx context.Context) (*RemoteSettings, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Accept", "application/json")
+	s.mu.Lock()
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	s.mu.Unlock()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &HTTPError{Status: resp.StatusCode, Endpoint: s.url}
+	}
+
+	var settings RemoteSettings
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteConfigBytes)).Decode(&settings); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.etag = resp.Header.Get("ETag")
+	s.mu.Unlock()
+	return &settings, nil
+}
+
+// remoteConfigPoller fetches remote settings immediately and then on every
+// interval until stopped.
+type remoteConfigPoller struct {
+	source   RemoteConfigSource
+	interval time.Duration
+	apply    func(*RemoteSettings)
+	onError  func(error)
+	spawn    func(func())
+	clock    Clock
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	doneCh   chan struct{}
+}
+
+func newRemoteConfigPoller(source RemoteConfigSource, interval time.Duration, apply func(*RemoteSettings), onError func(error)) *remoteConfigPoller {
+	if interval <= 0 {
+		interval = defaultRemoteConfigInterval
+	}
+	return &remoteConfigPoller{
+		source:   source,
+		interval: interval,
+		apply:    apply,
+		onError:  onError,
+		spawn:    goSpawn,
+		clock:    systemClock{},
+	}
+}
+
+// Start begins polling until Stop is called.
+func (p *remoteConfigPoller) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	doneCh := make(chan struct{})
+	p.cancel = cancel
+	p.doneCh = doneCh
+
+	p.spawn(func() {
+		defer close(doneCh)
+		p.poll(ctx)
+		ticker := p.clock.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				p.poll(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// Stop halts polling, cancelling an in-flight fetch, and waits for the
+// poller to exit.
+func (p *remoteConfigPoller) Stop() {
+	p.mu.Lock()
+	cancel, doneCh := p.cancel, p.doneCh
+	p.cancel, p.doneCh = nil, nil
+	p.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-doneCh
+	}
+}
+
+// poll fetches and applies the settings once. Each fetch is bounded by the
+// polling interval.
+func (p *remoteConfigPoller) poll(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+
+	settings, err := p.source.FetchConfig(ctx)
+	if err != nil {
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			p.onError(err)
+		}
+		return
+	}
+	if settings != nil {
+		p.apply(settings)
+	}
+}
+
+// applyRemoteSettings applies fetched settings, ignoring them as a whole if
+// any field is invalid.
+func (c *Client) applyRemoteSettings(settings *RemoteSettings) {
+	update := ConfigUpdate{
+		SamplingRate:   settings.SamplingRate,
+		SamplingRules:  settings.SamplingRules,
+		DisabledEvents: settings.DisabledEvents,
+		AllowedEvents:  settings.AllowedEvents,
+		DeniedEvents:   settings.DeniedEvents,
+		MaxBatchSize:   settings.MaxBatchSize,
+	}
+	if update.SamplingRate == nil && update.SamplingRules == nil && update.DisabledEvents == nil &&
+		update.AllowedEvents == nil && update.DeniedEvents == nil && update.MaxBatchSize == nil {
+		return
+	}
+	if err := c.UpdateConfig(update); err != nil {
+		c.loggerAdapter.Warn("Ignoring invalid remote config", map[string]any{
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RemoteConfig(t *testing.T) {
+	waitFor := func(t *testing.T, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("condition not met in time")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("should fetch and apply settings over HTTP", func(t *testing.T) {
+		var requests atomic.Int32
+		var notModified atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if r.Method != http.MethodGet || r.Header.Get("X-API-Key") != "test-key" {
+				t.Errorf("unexpected request: %s %v", r.Method, r.Header)
+			}
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"disabledEvents": ["noisy"], "samplingRules": {"heartbeat": 0}, "maxBatchSize": 2}`))
+		}))
+		defer server.Close()
+
+		config := createTestConfig()
+		config.RemoteConfig = &RemoteConfig{URL: server.URL, Interval: 10 * time.Millisecond}
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		waitFor(t, func() bool { return client.Stats().BatchSize == 2 })
+		waitFor(t, func() bool { return notModified.Load() > 0 })
+
+		_ = client.Track("noisy", nil, nil)
+		_ = client.Track("heartbeat", nil, nil)
+		stats := client.Stats()
+		if stats.EventsDisabled != 1 || stats.EventsSampledOut != 1 || client.dispatcher.queue.Len() != 0 {
+			t.Fatalf("expected remote settings applied, got %+v", stats)
+		}
+		if stats.BatchSize != 2 {
+			t.Fatalf("expected a 304 to keep the settings, got batch size %d", stats.BatchSize)
+		}
+	})
+
+	t.Run("should keep the current settings on invalid or failed fetches", func(t *testing.T) {
+		logger := &mockLogger{}
+		var calls atomic.Int32
+		config := createTestConfig()
+		config.LoggerAdapter = logger
+		config.RemoteConfig = &RemoteConfig{
+			Interval: 10 * time.Millisecond,
+			Source: RemoteConfigSourceFunc(func(ctx context.Context) (*RemoteSettings, error) {
+				if calls.Add(1) == 1 {
+					size := -1
+					return &RemoteSettings{MaxBatchSize: &size, DisabledEvents: []string{"a"}}, nil
+				}
+				return nil, errors.New("control plane down")
+			}),
+		}
+		client, _ := NewClient(config)
+		client.Init()
+
+		hasWarning := func(text string) bool {
+			logger.mu.Lock()
+			defer logger.mu.Unlock()
+			for _, warning := range logger.warnings {
+				if strings.Contains(warning, text) {
+					return true
+				}
+			}
+			return false
+		}
+		waitFor(t, func() bool { return hasWarning("Failed to fetch remote config") })
+		client.Dispose()
+
+		if !hasWarning("Ignoring invalid remote config") {
+			t.Fatal("expected invalid settings to be reported")
+		}
+		if client.Stats().BatchSize != defaultMaxBatchSize || client.eventDisabled("a") {
+			t.Fatal("expected invalid settings to be ignored as a whole")
+		}
+	})
+
+	t.Run("should stop polling on dispose", func(t *testing.T) {
+		var mu sync.Mutex
+		calls := 0
+		config := createTestConfig()
+		config.RemoteConfig = &RemoteConfig{
+			Interval: time.Millisecond,
+			Source: RemoteConfigSourceFunc(func(ctx context.Context) (*RemoteSettings, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				return nil, nil
+			}),
+		}
+		client, _ := NewClient(config)
+		client.Init()
+		client.Dispose()
+
+		mu.Lock()
+		before := calls
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if calls != before {
+			t.Fatalf("expected no fetches after dispose, got %d more", calls-before)
+		}
+	})
+
+	t.Run("should require a valid url without a source", func(t *testing.T) {
+		config := createTestConfig()
+		config.RemoteConfig = &RemoteConfig{URL: "config"}
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package ripple
+
+import (
+	"sync/atomic"
+)
+
+// BudgetDegradation decides what the dispatcher does when the queue exceeds
+// ResourceBudget.MaxQueueBytes.
+type BudgetDegradation int
+
+const (
+	// BudgetDegradeFlush flushes the queue immediately to free memory.
+	BudgetDegradeFlush BudgetDegradation = iota
+
+	// BudgetDegradeSpill persists the queue to storage and keeps new events
+	// on disk until the next flush, as under memory pressure.
+	BudgetDegradeSpill
+
+	// BudgetDegradeDropNewest drops incoming events that would exceed the
+	// budget.
+	BudgetDegradeDropNewest
+)
+
+// ResourceBudget sets hard limits on the resources a client may hold.
+// A zero limit is unlimited; an empty budget only enables accounting.
+type ResourceBudget struct {
+	// MaxQueueBytes caps the serialized size of the in-memory queue.
+	MaxQueueBytes int64
+
+	// MaxStorageBytesWritten caps the cumulative bytes handed to the storage
+	// adapter. Once exhausted, persistence stops and events are kept in
+	// memory only.
+	MaxStorageBytesWritten int64
+
+	// Degradation is applied when MaxQueueBytes is exceeded.
+	Degradation BudgetDegradation
+}
+
+// ResourceUsage is a snapshot of the resources held by a client.
+type ResourceUsage struct {
+	// Goroutines is the number of SDK background goroutines running now.
+	Goroutines int64
+
+	// GoroutinesStarted is the number of SDK background goroutines spawned.
+	GoroutinesStarted int64
+
+	// QueueBytes is the serialized size of the in-memory queue, a proxy for
+	// the heap it holds.
+	QueueBytes int64
+
+	// StorageBytesWritten is the cumulative serialized size of events saved
+	// to storage. Only metered when a ResourceBudget is configured.
+	StorageBytesWritten int64
+
+	// OpenFiles is the number of file descriptors held by adapters
+	// implementing OpenFilesReporter, or -1 if none do.
+	OpenFiles int
+
+	// EventsDroppedByBudget is the number of events dropped by
+	// BudgetDegradeDropNewest.
+	EventsDroppedByBudget int64
+}
+
+// resourceMeter accounts for goroutines and storage writes of a dispatcher.
+type resourceMeter struct {
+	goroutines        atomic.Int64
+	goroutinesStarted atomic.Int64
+	storageBytes      atomic.Int64
+	droppedByBudget   atomic.Int64
+}
+
+// spawn runs f on a new, accounted goroutine.
+func (m *resourceMeter) spawn(f func()) {
+	go m.wrap(f)()
+}
+
+// wrap returns f accounted as a goroutine, for callbacks such as
+// time.AfterFunc that run on goroutines the runtime starts.
+func (m *resourceMeter) wrap(f func()) func() {
+	return func() {
+		m.goroutinesStarted.Add(1)
+		m.goroutines.Add(1)
+		defer m.goroutines.Add(-1)
+		f()
+	}
+}
+
+// goSpawn runs f on a new, unaccounted goroutine.
+func goSpawn(f func()) {
+	go f()
+}
+
+// saveEvents saves events to storage, enforcing the storage quota and the
+// storage write budget, and records the resulting storage size.
+func (d *Dispatcher) saveEvents(events []Event) error {
+	events = d.applyStorageQuota(events)
+	budget := d.config.ResourceBudget
+	if budget == nil {
+		if err := d.storageAdapter.Save(events); err != nil {
+			return err
+		}
+		d.stats.setStorageSize(len(events))
+		return nil
+	}
+
+	size := int64(batchEnvelopeBytes)
+	for i := range events {
+		n, _ := eventSize(&events[i])
+		size += int64(n)
+	}
+	if budget.MaxStorageBytesWritten > 0 && d.resources.storageBytes.Load()+size > budget.MaxStorageBytesWritten {
+		return &StorageQuotaExceededError{Message: "storage write budget exhausted"}
+	}
+
+	if err := d.storageAdapter.Save(events); err != nil {
+		return err
+	}
+	d.resources.storageBytes.Add(size)
+	d.stats.setStorageSize(len(events))
+	return nil
+}
+
+// admitWithinBudget reports whether event fits in the queue byte budget
+// under BudgetDegradeDropNewest, counting the event as dropped otherwise.
+func (d *Dispatcher) admitWithinBudget(event *Event) bool {
+	budget := d.config.ResourceBudget
+	if budget == nil || budget.MaxQueueBytes == 0 || budget.Degradation != BudgetDegradeDropNewest {
+		return true
+	}
+	if d.audit.contains(*event) {
+		return true
+	}
+	size, _ := eventSize(event)
+	if d.queue.Bytes()+int64(size) <= budget.MaxQueueBytes {
+		return true
+	}
+	d.resources.droppedByBudget.Add(1)
+	d.loggerAdapter.Warn("Queue byte budget exceeded, dropping event", map[string]any{
+		"event": event.Name,
+	})
+	return false
+}
+
+// overQueueBudget reports whether the queue exceeds MaxQueueBytes.
+func (d *Dispatcher) overQueueBudget() bool {
+	budget := d.config.ResourceBudget
+	return budget != nil && budget.MaxQueueBytes > 0 && d.queue.Bytes() > budget.MaxQueueBytes
+}
+
+// ResourceUsage returns a snapshot of the resources held by the dispatcher.
+func (d *Dispatcher) ResourceUsage() ResourceUsage {
+	return ResourceUsage{
+		Goroutines:            d.resources.goroutines.Load(),
+		GoroutinesStarted:     d.resources.goroutinesStarted.Load(),
+		QueueBytes:            d.queue.Bytes(),
+		StorageBytesWritten:   d.resources.storageBytes.Load(),
+		OpenFiles:             d.openFiles(),
+		EventsDroppedByBudget: d.resources.droppedByBudget.Load(),
+	}
+}
+
+// openFiles sums descriptors reported by the adapters, or -1 if none report.
+func (d *Dispatcher) openFiles() int {
+	total, reported := 0, false
+	for _, adapter := range []any{d.httpAdapter, d.storageAdapter} {
+		if reporter, ok := adapter.(OpenFilesReporter); ok {
+			total += reporter.OpenFiles()
+			reported = true
+		}
+	}
+	if !reported {
+		return -1
+	}
+	return total
+}
+
+// ResourceUsage returns a snapshot of the resources held by the client:
+// background goroutines, queue bytes, storage bytes written, and open files.
+func (c *Client) ResourceUsage() ResourceUsage {
+	return c.dispatcher.ResourceUsage()
+}
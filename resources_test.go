@@ -0,0 +1,191 @@
+package ripple
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type openFilesStorage struct {
+	mockStorageAdapter
+}
+
+func (o *openFilesStorage) OpenFiles() int { return 2 }
+
+// withResourceBudget sets budget and allows batches of up to 1000 events, so
+// enqueueing never triggers a flush.
+func withResourceBudget(budget *ResourceBudget) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.MaxBatchSize = 1000
+		c.ResourceBudget = budget
+	}
+}
+
+func TestQueue_Bytes(t *testing.T) {
+	t.Run("should match on-demand and tracked accounting", func(t *testing.T) {
+		untracked := NewQueue()
+		tracked := NewQueue()
+		tracked.trackBytes()
+
+		for _, q := range []*Queue{untracked, tracked} {
+			q.Enqueue(Event{Name: "a"})
+			q.Enqueue(Event{Name: "bb", Payload: map[string]any{"k": "v"}})
+			q.Dequeue()
+			q.Enqueue(Event{Name: "c"})
+		}
+		if untracked.Bytes() != tracked.Bytes() || tracked.Bytes() == 0 {
+			t.Fatalf("expected equal non-zero sizes, got %d and %d", untracked.Bytes(), tracked.Bytes())
+		}
+
+		tracked.Clear()
+		if tracked.Bytes() != 0 {
+			t.Fatalf("expected 0 bytes after clear, got %d", tracked.Bytes())
+		}
+	})
+}
+
+func TestDispatcher_ResourceUsage(t *testing.T) {
+	t.Run("should report queue bytes and unknown open files", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{}, withResourceBudget(nil))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+
+		usage := d.ResourceUsage()
+		if usage.QueueBytes != eventBytes(Event{Name: "a"}) {
+			t.Errorf("expected queue bytes %d, got %d", eventBytes(Event{Name: "a"}), usage.QueueBytes)
+		}
+		if usage.OpenFiles != -1 {
+			t.Errorf("expected -1 open files, got %d", usage.OpenFiles)
+		}
+		if usage.StorageBytesWritten != 0 {
+			t.Errorf("expected unmetered storage writes, got %d", usage.StorageBytesWritten)
+		}
+	})
+
+	t.Run("should meter storage writes and open files", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &openFilesStorage{}, withResourceBudget(&ResourceBudget{}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+
+		usage := d.ResourceUsage()
+		if usage.StorageBytesWritten == 0 {
+			t.Error("expected storage writes to be metered")
+		}
+		if usage.OpenFiles != 2 {
+			t.Errorf("expected 2 open files, got %d", usage.OpenFiles)
+		}
+	})
+
+	t.Run("should count background goroutines", func(t *testing.T) {
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			Endpoint:      "http://test.com",
+			FlushInterval: 10 * time.Millisecond,
+			MaxBatchSize:  100,
+		}, &mockHTTPAdapter{}, &mockStorageAdapter{}, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		time.Sleep(50 * time.Millisecond)
+
+		usage := d.ResourceUsage()
+		if usage.GoroutinesStarted != 1 || usage.Goroutines != 0 {
+			t.Fatalf("expected 1 finished goroutine, got started=%d running=%d", usage.GoroutinesStarted, usage.Goroutines)
+		}
+	})
+}
+
+func TestDispatcher_ResourceBudget(t *testing.T) {
+	size := eventBytes(Event{Name: "a"})
+
+	t.Run("should flush when the queue byte budget is exceeded", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withResourceBudget(&ResourceBudget{MaxQueueBytes: size}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		if httpAdapter.getCalls() != 0 {
+			t.Fatal("expected no flush within budget")
+		}
+		d.Enqueue(Event{Name: "a"})
+		if httpAdapter.getCalls() != 1 || d.queue.Len() != 0 {
+			t.Fatalf("expected a flush over budget, got %d calls and %d queued", httpAdapter.getCalls(), d.queue.Len())
+		}
+	})
+
+	t.Run("should spill when configured", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withResourceBudget(&ResourceBudget{MaxQueueBytes: size, Degradation: BudgetDegradeSpill}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "a"})
+
+		if d.queue.Len() != 0 || len(storage.getSaved()) != 2 {
+			t.Fatalf("expected queue spilled to storage, got %d queued and %d saved", d.queue.Len(), len(storage.getSaved()))
+		}
+	})
+
+	t.Run("should deliver events spilled during a flush", func(t *testing.T) {
+		budget := &ResourceBudget{MaxQueueBytes: 2 * eventBytes(Event{Name: "e0"}), Degradation: BudgetDegradeSpill}
+		testSpillDuringFlush(t, DispatcherConfig{ResourceBudget: budget}, func(d *Dispatcher) {
+			for i := range 5 {
+				d.Enqueue(Event{Name: fmt.Sprintf("e%d", i)})
+			}
+		})
+	})
+
+	t.Run("should drop newest events when configured", func(t *testing.T) {
+		d := newTestDispatcher(&mockHTTPAdapter{}, &mockStorageAdapter{}, withResourceBudget(&ResourceBudget{MaxQueueBytes: size, Degradation: BudgetDegradeDropNewest}))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "a"})
+
+		if d.queue.Len() != 1 || d.ResourceUsage().EventsDroppedByBudget != 1 {
+			t.Fatalf("expected 1 queued and 1 dropped, got %d and %d", d.queue.Len(), d.ResourceUsage().EventsDroppedByBudget)
+		}
+	})
+
+	t.Run("should stop persisting when the storage write budget is exhausted", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		logger := &mockLogger{}
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:         "test-key",
+			Endpoint:       "http://test.com",
+			FlushInterval:  time.Hour,
+			MaxBatchSize:   1000,
+			ResourceBudget: &ResourceBudget{MaxStorageBytesWritten: size + int64(batchEnvelopeBytes)},
+		}, &mockHTTPAdapter{}, storage, logger)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "a"})
+
+		if len(storage.getSaved()) != 1 {
+			t.Fatalf("expected only the first write to be persisted, got %d events", len(storage.getSaved()))
+		}
+		if d.queue.Len() != 2 {
+			t.Fatalf("expected events kept in memory, got %d", d.queue.Len())
+		}
+	})
+}
+
+func TestClient_ResourceBudgetValidation(t *testing.T) {
+	t.Run("should reject negative limits", func(t *testing.T) {
+		config := createTestConfig()
+		config.ResourceBudget = &ResourceBudget{MaxQueueBytes: -1}
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package ripple
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// RetryMode controls how a failed batch waits out its backoff.
+type RetryMode int
+
+const (
+	// RetryInline waits on the flush path between attempts, so a flush
+	// returns once every batch is delivered or out of retries, and later
+	// batches wait behind a failing one.
+	RetryInline RetryMode = iota
+
+	// RetryScheduled moves a failed batch to a retry queue stamped with its
+	// next attempt time and carries on with the remaining batches. A timer
+	// resends each batch once its backoff has elapsed.
+	RetryScheduled
+)
+
+// retryBatch is a failed batch waiting in the retry queue.
+type retryBatch struct {
+	events  []Event
+	batchID string
+	attempt int
+	due     time.Time
+}
+
+// scheduleRetry adds a failed batch to the retry queue, to be sent as attempt
+// once delay has elapsed, and checkpoints it with the queue.
+func (d *Dispatcher) scheduleRetry(events []Event, batchID string, attempt int, delay time.Duration) {
+	d.mu.Lock()
+	if d.disposed {
+		d.mu.Unlock()
+		d.notifyDelivery(events, context.Canceled)
+		return
+	}
+	d.retries = append(d.retries, retryBatch{
+		events:  slices.Clone(events),
+		batchID: batchID,
+		attempt: attempt,
+		due:     d.clock.Now().Add(delay),
+	})
+	d.armRetryTimerLocked()
+	d.mu.Unlock()
+
+	d.checkpoint(PersistTriggerFailure, append(d.retryEvents(), d.queue.ToSlice()...), 0)
+}
+
+// armRetryTimerLocked schedules sendDueRetries for the earliest retry, if any.
+// Caller must hold d.mu.
+func (d *Dispatcher) armRetryTimerLocked() {
+	if d.retryTimer != nil {
+		d.retryTimer.Stop()
+		d.retryTimer = nil
+	}
+	if d.disposed || d.interrupted || len(d.retries) == 0 {
+		return
+	}
+
+	next := d.retries[0].due
+	for _, retry := range d.retries[1:] {
+		if retry.due.Before(next) {
+			next = retry.due
+		}
+	}
+	d.retryTimer = d.clock.AfterFunc(next.Sub(d.clock.Now()), d.resources.wrap(d.sendDueRetries))
+}
+
+// sendDueRetries sends every retry whose backoff has elapsed. A batch that
+// fails again is rescheduled or, once out of retries, re-queued, as are the
+// due batches left unsent when the round is cut short.
+func (d *Dispatcher) sendDueRetries() {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.mu.Lock()
+	d.retryTimer = nil
+	if d.disposed || d.interrupted {
+		d.mu.Unlock()
+		return
+	}
+	now := d.clock.Now()
+	var due, pending []retryBatch
+	for _, retry := range d.retries {
+		if retry.due.After(now) {
+			pending = append(pending, retry)
+		} else {
+			due = append(due, retry)
+		}
+	}
+	d.retries = pending
+
+	ctx, cancel := d.flushContext(context.Background())
+	d.retryCancel = cancel
+	d.mu.Unlock()
+	defer cancel()
+
+	var deferred []Event
+	d.flushRequeue = &deferred
+	defer d.requeueDeferred(&deferred)
+
+	for i, retry := range due {
+		if ctx.Err() != nil {
+			var remaining []Event
+			for _, rest := range due[i:] {
+				remaining = append(remaining, rest.events...)
+			}
+			d.requeueIfActive(remaining)
+			break
+		}
+		d.sendWithRetry(ctx, retry.events, retry.batchID, retry.attempt)
+	}
+	d.logFlushTimeout(ctx)
+
+	d.mu.Lock()
+	d.armRetryTimerLocked()
+	d.mu.Unlock()
+}
+
+// retryEvents returns the events waiting in the retry queue, in the order
+// they failed.
+func (d *Dispatcher) retryEvents() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.retryEventsLocked()
+}
+
+// retryEventsLocked implements retryEvents. Caller must hold d.mu.
+func (d *Dispatcher) retryEventsLocked() []Event {
+	var events []Event
+	for _, retry := range d.retries {
+		events = append(events, retry.events...)
+	}
+	return events
+}
+
+// takeRetries empties the retry queue, stops its timer and returns the
+// waiting events.
+func (d *Dispatcher) takeRetries() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	events := d.retryEventsLocked()
+	d.retries = nil
+	if d.retryTimer != nil {
+		d.retryTimer.Stop()
+		d.retryTimer = nil
+	}
+	return events
+}
@@ -0,0 +1,154 @@
+package ripple
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// withScheduledRetries selects RetryScheduled.
+func withScheduledRetries(c *DispatcherConfig) {
+	c.RetryMode = RetryScheduled
+}
+
+// waitForStats polls the dispatcher's stats until cond holds.
+func waitForStats(t *testing.T, d *Dispatcher, cond func(Stats) bool) Stats {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := d.Stats()
+		if cond(stats) {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time, stats: %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDispatcher_ScheduledRetry(t *testing.T) {
+	t.Run("should send later batches while a failed batch backs off", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503},
+			adapters.Scenario{Status: 200},
+		)
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withScheduledRetries, func(c *DispatcherConfig) {
+			c.MaxBatchSize = 1
+			c.MaxRetries = 3
+			c.BackoffPolicy = ConstantBackoff(50 * time.Millisecond)
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.Enqueue(Event{Name: "a"})
+		d.queue.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		if httpAdapter.Calls() != 2 {
+			t.Fatalf("expected both batches to be attempted without waiting, got %d calls", httpAdapter.Calls())
+		}
+		if stats := d.Stats(); stats.PendingRetries != 1 || stats.EventsSent != 1 {
+			t.Fatalf("expected one pending retry and one sent event, got %+v", stats)
+		}
+
+		waitForStats(t, d, func(s Stats) bool { return s.EventsSent == 2 })
+		requests := httpAdapter.Requests()
+		if len(requests) != 3 || requests[2].Events[0].Name != "a" {
+			t.Fatalf("expected the failed batch to be retried last, got %+v", requests)
+		}
+		if requests[2].Headers[BatchIDHeader] != requests[0].Headers[BatchIDHeader] {
+			t.Error("expected the retry to keep its batch ID")
+		}
+		if stats := d.Stats(); stats.PendingRetries != 0 {
+			t.Fatalf("expected the retry queue to be empty, got %d", stats.PendingRetries)
+		}
+	})
+
+	t.Run("should re-queue a batch once it runs out of retries", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withScheduledRetries, func(c *DispatcherConfig) {
+			c.MaxBatchSize = 10
+			c.MaxRetries = 1
+			c.BackoffPolicy = ConstantBackoff(10 * time.Millisecond)
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		stats := waitForStats(t, d, func(s Stats) bool { return s.BatchesFailed == 1 })
+		if stats.QueueLength != 1 || stats.PendingRetries != 0 {
+			t.Fatalf("expected the batch back in the queue, got %+v", stats)
+		}
+		if httpAdapter.Calls() != 2 {
+			t.Fatalf("expected 2 attempts, got %d", httpAdapter.Calls())
+		}
+	})
+
+	t.Run("should persist pending retries on dispose", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(adapters.Scenario{Status: 503})
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(httpAdapter, storage, withScheduledRetries, func(c *DispatcherConfig) {
+			c.MaxBatchSize = 10
+			c.MaxRetries = 3
+			c.BackoffPolicy = ConstantBackoff(time.Hour)
+			c.PersistencePolicy = PersistOnShutdown()
+		})
+		d.Restore()
+
+		d.queue.Enqueue(Event{Name: "a"})
+		d.Flush()
+		d.Enqueue(Event{Name: "b"})
+		queued, persisted := d.dispose(false)
+
+		if queued != 2 || persisted != 2 {
+			t.Fatalf("expected 2 queued and persisted events, got %d and %d", queued, persisted)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 2 || saved[0].Name != "a" || saved[1].Name != "b" {
+			t.Fatalf("expected the pending retry saved ahead of the queue, got %+v", saved)
+		}
+		if d.Stats().PendingRetries != 0 {
+			t.Error("expected the retry queue to be emptied")
+		}
+	})
+
+	t.Run("should re-queue due retries left unsent when the round times out", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503, Times: 2},
+			adapters.Scenario{Status: 200, Delay: time.Second},
+		)
+		d := newTestDispatcher(httpAdapter, &mockStorageAdapter{}, withScheduledRetries, func(c *DispatcherConfig) {
+			c.MaxBatchSize = 1
+			c.MaxRetries = 3
+			c.BackoffPolicy = ConstantBackoff(time.Hour)
+			c.FlushTimeout = 50 * time.Millisecond
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.queue.Enqueue(Event{Name: "a"})
+		d.queue.Enqueue(Event{Name: "b"})
+		d.Flush()
+		d.mu.Lock()
+		for i := range d.retries {
+			d.retries[i].due = d.clock.Now()
+		}
+		d.mu.Unlock()
+		d.sendDueRetries()
+
+		if got := queuedNames(d); !reflect.DeepEqual(got, []string{"b"}) {
+			t.Fatalf("expected the unsent retry back in the queue, got %v", got)
+		}
+		if stats := d.Stats(); stats.PendingRetries != 1 {
+			t.Fatalf("expected the timed-out retry to be rescheduled, got %+v", stats)
+		}
+		if httpAdapter.Calls() != 3 {
+			t.Fatalf("expected only the first retry to be sent, got %d calls", httpAdapter.Calls())
+		}
+	})
+}
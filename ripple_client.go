@@ -1,17 +1,22 @@
 package ripple
 
 import (
+	"context"
 	"errors"
-	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Tap30/ripple-go/adapters"
 )
 
-var (
-	// serverPlatform is a shared pointer used by all events.
-	serverPlatform = &Platform{Type: "server"}
+const (
+	defaultFlushInterval   = 5 * time.Second
+	defaultMaxBatchSize    = 10
+	defaultMaxRetries      = 3
+	defaultAPIKeyHeader    = "X-API-Key"
+	defaultShutdownTimeout = 10 * time.Second
 )
 
 type Client struct {
@@ -19,53 +24,57 @@ type Client struct {
 	metadataManager *MetadataManager
 	dispatcher      *Dispatcher
 	loggerAdapter   LoggerAdapter
+	sampler         *sampler
+	sampledOut      atomic.Int64
+	disabledOut     atomic.Int64
+	disabledEvents  atomic.Pointer[map[string]struct{}]
+	filteredOut     atomic.Int64
+	eventFilter     atomic.Pointer[eventNameFilter]
+	filterMu        sync.Mutex
+	enabled         atomic.Bool
+	doNotTrack      atomic.Bool
+	identity        identity
+	stopSignals     func()
+	ignoredSignals  map[os.Signal]bool // shutdown signals ignored before HandleSignals
+	remoteConfig    *remoteConfigPoller
+	platform        *Platform
 	initialized     bool
 	disposed        bool
 	initMu          sync.Mutex
 }
 
-// NewClient creates a new Ripple client
+// NewClient creates a new Ripple client.
+// It never panics: every invalid configuration is reported as an error.
 func NewClient(config ClientConfig) (*Client, error) {
-	// Validate required fields
-	if config.APIKey == "" {
-		return nil, errors.New("api key is required")
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
-	if config.Endpoint == "" {
-		return nil, errors.New("endpoint is required")
+	if config.App != nil {
+		app := *config.App
+		config.App = &app
 	}
-	if config.HTTPAdapter == nil {
-		return nil, errors.New("http adapter is required")
+	if config.Endpoint == "" && len(config.RegionalEndpoints) > 0 {
+		config.Endpoint = config.RegionalEndpoints[0]
 	}
-	if config.StorageAdapter == nil {
-		return nil, errors.New("storage adapter is required")
-	}
-
-	// Validate numeric config values
-	if config.FlushInterval < 0 || (config.FlushInterval > 0 && config.FlushInterval < time.Millisecond) {
-		return nil, errors.New("flush interval must be a positive duration")
-	}
-	if config.MaxBatchSize < 0 {
-		return nil, errors.New("max batch size must be a positive number")
-	}
-	if config.MaxRetries < 0 {
-		return nil, errors.New("max retries must be a non-negative number")
-	}
-	if config.MaxBufferSize < 0 {
-		return nil, errors.New("max buffer size must be a positive number")
+	if config.Endpoint == "" && len(config.Endpoints) > 0 {
+		config.Endpoint = config.Endpoints[0]
 	}
 
 	// Set defaults
 	if config.FlushInterval == 0 {
-		config.FlushInterval = 5 * time.Second
+		config.FlushInterval = defaultFlushInterval
 	}
 	if config.MaxBatchSize == 0 {
-		config.MaxBatchSize = 10
+		config.MaxBatchSize = defaultMaxBatchSize
 	}
 	if config.MaxRetries == 0 {
-		config.MaxRetries = 3
+		config.MaxRetries = defaultMaxRetries
+	}
+	if config.ShutdownTimeout == 0 {
+		config.ShutdownTimeout = defaultShutdownTimeout
 	}
 
-	apiKeyHeader := "X-API-Key"
+	apiKeyHeader := defaultAPIKeyHeader
 	if config.APIKeyHeader != nil {
 		apiKeyHeader = *config.APIKeyHeader
 	}
@@ -74,20 +83,69 @@ func NewClient(config ClientConfig) (*Client, error) {
 	if config.LoggerAdapter != nil {
 		loggerAdapter = config.LoggerAdapter
 	}
+	if config.LogRateLimit > 0 {
+		clock := config.Clock
+		if clock == nil {
+			clock = systemClock{}
+		}
+		loggerAdapter = newRateLimitedLogger(loggerAdapter, config.LogRateLimit, clock)
+	}
 
 	dispatcherConfig := DispatcherConfig{
-		APIKey:        config.APIKey,
-		APIKeyHeader:  apiKeyHeader,
-		Endpoint:      config.Endpoint,
-		FlushInterval: config.FlushInterval,
-		MaxBatchSize:  config.MaxBatchSize,
-		MaxRetries:    config.MaxRetries,
-		MaxBufferSize: config.MaxBufferSize,
-	}
+		APIKey:              config.APIKey,
+		APIKeyHeader:        apiKeyHeader,
+		AuthProvider:        config.AuthProvider,
+		Headers:             config.Headers,
+		HeaderProvider:      config.HeaderProvider,
+		Endpoint:            config.Endpoint,
+		FlushInterval:       config.FlushInterval,
+		FlushIntervalJitter: config.FlushIntervalJitter,
+		MaxBatchSize:        config.MaxBatchSize,
+		MaxRetries:          config.MaxRetries,
+		BackoffPolicy:       config.BackoffPolicy,
+		RetryMode:           config.RetryMode,
+		Ordering:            config.Ordering,
+		OrderingKey:         config.OrderingKey,
+		FlushTimeout:        config.FlushTimeout,
+		RequestTimeout:      config.RequestTimeout,
+		AdaptiveBatching:    config.AdaptiveBatching,
+		MaxBufferSize:       config.MaxBufferSize,
 
-	// Validate buffer vs batch
-	if config.MaxBufferSize > 0 && config.MaxBufferSize < config.MaxBatchSize {
-		return nil, fmt.Errorf("max buffer size (%d) must be greater than or equal to max batch size (%d)", config.MaxBufferSize, config.MaxBatchSize)
+		RegionalEndpoints:        config.RegionalEndpoints,
+		EndpointProbeInterval:    config.EndpointProbeInterval,
+		Endpoints:                config.Endpoints,
+		EndpointStrategy:         config.EndpointStrategy,
+		EndpointFailureThreshold: config.EndpointFailureThreshold,
+		EndpointCooldown:         config.EndpointCooldown,
+		TracerProvider:           config.TracerProvider,
+		PersistencePolicy:        config.PersistencePolicy,
+		PersistInterval:          config.PersistInterval,
+		DeliveryGuarantee:        config.DeliveryGuarantee,
+		AuditStorage:             config.AuditStorage,
+		SchemaMigrations:         config.SchemaMigrations,
+		MaxStorageEvents:         config.MaxStorageEvents,
+		MaxStorageBytes:          config.MaxStorageBytes,
+		StorageEviction:          config.StorageEviction,
+		EventPriority:            config.EventPriority,
+		FireAndForget:            config.FireAndForget,
+		OnDelivery:               config.OnDelivery,
+		MemoryPressure:           config.MemoryPressure,
+		MemoryCheckInterval:      config.MemoryCheckInterval,
+		SpillThreshold:           config.SpillThreshold,
+		MaxRequestsPerSecond:     config.MaxRequestsPerSecond,
+		RateLimitBurst:           config.RateLimitBurst,
+		RateLimitMode:            config.RateLimitMode,
+		ImportBatchSize:          config.ImportBatchSize,
+		ImportRequestsPerSecond:  config.ImportRequestsPerSecond,
+		MaxBatchBytes:            config.MaxBatchBytes,
+		SequenceNumbers:          config.SequenceNumbers,
+		ProducerID:               config.ProducerID,
+		EventIDs:                 config.EventIDs,
+		DedupWindow:              config.DedupWindow,
+		EventTTL:                 config.EventTTL,
+		TenantResolver:           config.TenantResolver,
+		ResourceBudget:           config.ResourceBudget,
+		Clock:                    config.Clock,
 	}
 
 	dispatcher := NewDispatcher(dispatcherConfig, config.HTTPAdapter, config.StorageAdapter, loggerAdapter)
@@ -97,6 +155,21 @@ func NewClient(config ClientConfig) (*Client, error) {
 		metadataManager: NewMetadataManager(),
 		dispatcher:      dispatcher,
 		loggerAdapter:   loggerAdapter,
+		sampler:         newSampler(config.SamplingRate, config.SamplingRules),
+	}
+	client.enabled.Store(config.Enabled == nil || *config.Enabled)
+	client.doNotTrack.Store(config.DoNotTrack)
+	client.setEventFilter(config.AllowedEvents, config.DeniedEvents)
+	if remote := config.RemoteConfig; remote != nil {
+		source := remote.Source
+		if source == nil {
+			source = newHTTPConfigSource(remote.URL, map[string]string{apiKeyHeader: config.APIKey})
+		}
+		client.remoteConfig = newRemoteConfigPoller(source, remote.Interval, client.applyRemoteSettings, func(err error) {
+			loggerAdapter.Warn("Failed to fetch remote config", map[string]any{"error": err.Error()})
+		})
+		client.remoteConfig.spawn = dispatcher.resources.spawn
+		client.remoteConfig.clock = dispatcher.clock
 	}
 
 	return client, nil
@@ -110,7 +183,19 @@ func (c *Client) Init() {
 		return
 	}
 
+	if c.doNotTrack.Load() {
+		c.dispatcher.DropQueue()
+		c.Reset()
+	}
 	c.dispatcher.Restore()
+	c.restoreIdentity()
+	c.platform = newPlatform(c.config.App, c.config.Enrichment)
+	if c.remoteConfig != nil {
+		c.remoteConfig.Start()
+	}
+	if c.config.HandleSignals {
+		c.stopSignals = c.handleSignals()
+	}
 	c.disposed = false
 	c.initialized = true
 	c.loggerAdapter.Info("Client initialized successfully")
@@ -120,60 +205,286 @@ func (c *Client) SetMetadata(key string, value any) {
 	c.metadataManager.Set(key, value)
 }
 
+// SetMetadataMap sets multiple shared metadata values at once, keeping other
+// existing keys. Either every value is set or, if any key is empty, none is.
+func (c *Client) SetMetadataMap(values map[string]any) error {
+	if err := validateMetadataKeys(values); err != nil {
+		return err
+	}
+	c.metadataManager.SetAll(values)
+	return nil
+}
+
+// ReplaceMetadata atomically replaces all shared metadata with values, e.g.
+// when the signed-in user changes. A nil or empty map clears the metadata.
+func (c *Client) ReplaceMetadata(values map[string]any) error {
+	if err := validateMetadataKeys(values); err != nil {
+		return err
+	}
+	c.metadataManager.Replace(values)
+	return nil
+}
+
+// DeleteMetadata removes a shared metadata key. Deleting a missing key is a
+// no-op.
+func (c *Client) DeleteMetadata(key string) {
+	c.metadataManager.Delete(key)
+}
+
+// validateMetadataKeys rejects empty metadata keys.
+func validateMetadataKeys(values map[string]any) error {
+	for key := range values {
+		if key == "" {
+			return errors.New("metadata key cannot be empty")
+		}
+	}
+	return nil
+}
+
 func (c *Client) GetMetadata() map[string]any {
 	return c.metadataManager.GetAll()
 }
 
+// GetSessionId returns the current session ID from the configured
+// SessionProvider, or nil if none is configured. Like tracking an event, this
+// counts as session activity for IdleSessionProvider.
 func (c *Client) GetSessionId() *string {
-	return nil
+	return c.sessionID()
+}
+
+// sessionID returns the session ID for a new event.
+func (c *Client) sessionID() *string {
+	if c.config.SessionProvider == nil {
+		return nil
+	}
+	return c.config.SessionProvider.SessionID()
 }
 
 // Track tracks an event with optional payload and metadata.
 // Automatically initializes the client if not already initialized.
 // If the client is disposed, events are silently dropped.
 //
+// The event's metadata is a new map holding a snapshot of the shared
+// metadata (see SetMetadata) and the identified user (see Identify), overlaid
+// with metadata; on key conflicts the event-specific value wins. Neither
+// source map is modified.
+//
 // Parameters:
 //   - name: Event name/identifier (required, cannot be empty)
 //   - payload: Event data payload (optional, pass nil if not needed)
 //   - metadata: Event-specific metadata (optional, pass nil if not needed)
 func (c *Client) Track(name string, payload, metadata map[string]any) error {
+	return c.track(name, payload, nil, metadata)
+}
+
+// TrackContext tracks an event like Track, additionally attaching the
+// request-scoped metadata carried by ctx (see ContextWithMetadata).
+// Metadata precedence: shared < context < event-specific.
+func (c *Client) TrackContext(ctx context.Context, name string, payload, metadata map[string]any) error {
+	return c.track(name, payload, MetadataFromContext(ctx), metadata)
+}
+
+// TrackWithContext tracks an event like Track, merging contextOverrides
+// (e.g. trace or tenant IDs) on top of the shared metadata for this call only,
+// without mutating the client's shared metadata.
+// Metadata precedence: shared < contextOverrides < event-specific.
+func (c *Client) TrackWithContext(name string, payload, metadata, contextOverrides map[string]any) error {
+	return c.track(name, payload, contextOverrides, metadata)
+}
+
+// track builds and enqueues an event, layering metadata sources from lowest
+// to highest precedence.
+func (c *Client) track(name string, payload map[string]any, layers ...map[string]any) error {
+	event, err := c.buildEvent(name, payload, layers...)
+	if event == nil {
+		return err
+	}
+
+	c.loggerAdapter.Debug("Tracking event: %s", name)
+	return c.dispatcher.enqueue(*event)
+}
+
+// buildEvent runs the tracking pipeline shared by Track and TrackNow:
+// sampling, metadata merging, BeforeSend hooks, transformers, schema
+// validation, redaction and size limits. It returns a nil event if the event
+// should not be sent, along with an error if the caller should be told why.
+func (c *Client) buildEvent(name string, payload map[string]any, layers ...map[string]any) (*Event, error) {
+	return c.assembleEvent(name, payload, true, layers...)
+}
+
+// assembleEvent implements buildEvent, skipping sampling unless sample is
+// true.
+func (c *Client) assembleEvent(name string, payload map[string]any, sample bool, layers ...map[string]any) (*Event, error) {
+	if !c.enabled.Load() || c.doNotTrack.Load() {
+		return nil, nil
+	}
 	if name == "" {
-		return errors.New("event name cannot be empty")
+		return nil, errors.New("event name cannot be empty")
 	}
 
 	if c.disposed {
 		c.loggerAdapter.Warn("Cannot track event: Client has been disposed")
-		return nil
+		return nil, nil
 	}
 
 	c.Init()
 
-	// Merge shared metadata with event-specific metadata
+	if c.eventDisabled(name) {
+		c.disabledOut.Add(1)
+		c.loggerAdapter.Debug("Event disabled: %s", name)
+		return nil, nil
+	}
+	if c.eventFiltered(name) {
+		c.filteredOut.Add(1)
+		c.loggerAdapter.Debug("Event filtered: %s", name)
+		return nil, nil
+	}
+
+	keep, rate := true, 1.0
+	if sample {
+		keep, rate = c.sampler.sample(name)
+	}
+	if !keep {
+		c.sampledOut.Add(1)
+		c.loggerAdapter.Debug("Event sampled out: %s", name)
+		return nil, nil
+	}
+
+	// Merge shared metadata with identity, contextual and event-specific metadata
 	eventMetadata := c.metadataManager.GetAll()
-	if len(metadata) > 0 {
-		if len(eventMetadata) == 0 {
-			eventMetadata = metadata
-		} else {
-			for k, v := range metadata {
-				eventMetadata[k] = v
-			}
+	for k, v := range c.identity.metadata() {
+		eventMetadata[k] = v
+	}
+	for _, layer := range layers {
+		for k, v := range layer {
+			eventMetadata[k] = v
 		}
 	}
+	if c.config.AnnotateSampleRate && rate < 1 {
+		eventMetadata[sampleRateMetadataKey] = rate
+	}
 
-	event := Event{
+	event := &Event{
 		Name:      name,
 		Payload:   payload,
 		Metadata:  eventMetadata,
-		IssuedAt:  time.Now().UnixMilli(),
-		SessionID: nil,
-		Platform:  serverPlatform,
+		IssuedAt:  c.dispatcher.clock.Now().UnixMilli(),
+		SessionID: c.sessionID(),
+		Platform:  c.platform,
 	}
 
-	c.loggerAdapter.Debug("Tracking event: %s", name)
-	c.dispatcher.Enqueue(event)
+	event = c.runBeforeSend(event)
+	if event == nil {
+		c.loggerAdapter.Debug("Event dropped by BeforeSend hook: %s", name)
+		return nil, nil
+	}
+
+	c.transformEvent(event)
+
+	if err := c.validateSchema(event); err != nil {
+		return nil, err
+	}
+
+	if c.config.Redactor != nil {
+		c.config.Redactor.apply(event)
+	}
+
+	if err := c.enforceEventSize(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// validateSchema checks the event against the configured SchemaValidator.
+// In lenient and warn modes violations are logged; lenient mode also records
+// them in the event metadata.
+func (c *Client) validateSchema(event *Event) error {
+	validator := c.config.SchemaValidator
+	if validator == nil {
+		return nil
+	}
+
+	err := validator.Validate(event)
+	var schemaErr *SchemaValidationError
+	if err == nil || validator.Mode() == SchemaModeStrict || !errors.As(err, &schemaErr) {
+		return err
+	}
+
+	c.loggerAdapter.Warn("Event does not match schema", map[string]any{
+		"event":  event.Name,
+		"errors": schemaErr.Errors,
+	})
+	if validator.Mode() == SchemaModeWarn {
+		return nil
+	}
+	metadata := make(map[string]any, len(event.Metadata)+1)
+	for k, v := range event.Metadata {
+		metadata[k] = v
+	}
+	metadata[schemaErrorsMetadataKey] = schemaErr.Errors
+	event.Metadata = metadata
 	return nil
 }
 
+// enforceEventSize applies MaxEventBytes, truncating the payload when the
+// OversizedEventTruncate policy is configured.
+func (c *Client) enforceEventSize(event *Event) error {
+	limit := c.config.MaxEventBytes
+	if limit == 0 {
+		return nil
+	}
+
+	size, err := eventSize(event)
+	if err != nil {
+		return err
+	}
+	if size <= limit {
+		return nil
+	}
+
+	if c.config.OversizedEventPolicy == OversizedEventTruncate {
+		event.Payload = nil
+		metadata := make(map[string]any, len(event.Metadata)+1)
+		for k, v := range event.Metadata {
+			metadata[k] = v
+		}
+		metadata[payloadTruncatedMetadataKey] = true
+		event.Metadata = metadata
+
+		if size, err = eventSize(event); err != nil {
+			return err
+		}
+		if size <= limit {
+			c.loggerAdapter.Warn("Event payload truncated to fit size limit: %s", event.Name)
+			return nil
+		}
+	}
+
+	return &EventTooLargeError{Name: event.Name, Size: size, Limit: limit}
+}
+
+// runBeforeSend passes the event through the configured BeforeSend hooks.
+// Returns nil if any hook dropped the event.
+func (c *Client) runBeforeSend(event *Event) *Event {
+	for _, hook := range c.config.BeforeSend {
+		event = hook(event)
+		if event == nil {
+			return nil
+		}
+	}
+	return event
+}
+
+// Stats returns a snapshot of client activity: queue length, delivery
+// counters, last flush time and error, storage size, and delivery latency.
+func (c *Client) Stats() Stats {
+	stats := c.dispatcher.Stats()
+	stats.EventsSampledOut = c.sampledOut.Load()
+	stats.EventsDisabled = c.disabledOut.Load()
+	stats.EventsFiltered = c.filteredOut.Load()
+	return stats
+}
+
 func (c *Client) Flush() {
 	if !c.initialized {
 		c.loggerAdapter.Warn("Flush called before initialization")
@@ -184,17 +495,88 @@ func (c *Client) Flush() {
 	c.dispatcher.Flush()
 }
 
+// FlushAndWait flushes until every queued event has been delivered or
+// dropped, or until ctx is done, and returns ctx.Err() in that case. Unlike
+// Flush, it keeps flushing events re-queued after failures, so ctx should
+// carry a deadline.
+func (c *Client) FlushAndWait(ctx context.Context) error {
+	if c.disposed {
+		return errDisposed
+	}
+	if !c.initialized {
+		c.loggerAdapter.Warn("FlushAndWait called before initialization")
+		return nil
+	}
+
+	c.loggerAdapter.Debug("Flushing events and waiting for delivery")
+	return c.dispatcher.FlushAndWait(ctx)
+}
+
 // Dispose cleans up resources. Matches TS dispose() behavior:
 // aborts retries, clears queue, clears metadata, resets state.
 func (c *Client) Dispose() {
-	c.dispatcher.Dispose()
+	c.dispose(false)
+}
+
+// dispose implements Dispose and returns the dispatcher's shutdown counts.
+func (c *Client) dispose(force bool) (queued, persisted int) {
+	if stop := c.stopSignals; stop != nil {
+		stop()
+	}
+	if c.remoteConfig != nil {
+		c.remoteConfig.Stop()
+	}
+	queued, persisted = c.dispatcher.dispose(force)
 	c.metadataManager.Clear()
+	c.identity.clear()
 	c.disposed = true
 	c.initialized = false
 	c.loggerAdapter.Info("Client disposed")
+	return queued, persisted
+}
+
+// CloseSummary reports what happened to queued events during Close.
+type CloseSummary struct {
+	// Sent is the number of events delivered during the final flush.
+	Sent int
+
+	// Persisted is the number of undelivered events saved to storage.
+	Persisted int
+
+	// Dropped is the number of undelivered events that could not be saved,
+	// including held events, which are never persisted.
+	Dropped int
 }
 
-// Close is an alias for Dispose for idiomatic Go cleanup.
-func (c *Client) Close() {
-	c.Dispose()
+// Close flushes queued events until ctx is done, persists whatever is left
+// to storage regardless of PersistencePolicy and disposes the client. Unlike
+// Dispose, it bounds the final flush by ctx instead of abandoning queued
+// events or retrying forever. Returns ctx.Err() if the deadline cut the flush
+// short.
+func (c *Client) Close(ctx context.Context) (CloseSummary, error) {
+	sentBefore := c.dispatcher.Stats().EventsSent
+	if c.initialized && !c.disposed {
+		// Another flush or a retry round may hold up this one; once ctx is
+		// done, stop it so that its events are re-queued and persisted below.
+		stop := context.AfterFunc(ctx, c.dispatcher.interruptFlush)
+		c.dispatcher.FlushContext(ctx)
+		stop()
+		if ctx.Err() != nil {
+			c.dispatcher.interruptFlush()
+		}
+	}
+	sent := int(c.dispatcher.Stats().EventsSent - sentBefore)
+
+	queued, persisted := c.dispose(true)
+	summary := CloseSummary{
+		Sent:      sent,
+		Persisted: persisted,
+		Dropped:   queued - persisted,
+	}
+	c.loggerAdapter.Info("Client closed", map[string]any{
+		"sent":      summary.Sent,
+		"persisted": summary.Persisted,
+		"dropped":   summary.Dropped,
+	})
+	return summary, ctx.Err()
 }
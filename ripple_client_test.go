@@ -1,6 +1,7 @@
 package ripple
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -96,6 +97,16 @@ func TestClient_ConfigValidation(t *testing.T) {
 		}
 	})
 
+	t.Run("should return error for FlushIntervalJitter outside [0, 1)", func(t *testing.T) {
+		for _, jitter := range []float64{-0.1, 1} {
+			config := createTestConfig()
+			config.FlushIntervalJitter = jitter
+			if _, err := NewClient(config); err == nil {
+				t.Fatalf("expected error for FlushIntervalJitter %v", jitter)
+			}
+		}
+	})
+
 	t.Run("should return error for negative MaxBatchSize", func(t *testing.T) {
 		_, err := NewClient(ClientConfig{
 			APIKey:         "test-key",
@@ -618,9 +629,502 @@ func TestClient_Close(t *testing.T) {
 	})
 
 	client.Init()
-	client.Close()
+	if _, err := client.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if !client.disposed {
 		t.Error("Close should dispose the client")
 	}
 }
+
+func TestClient_CloseWithDeadline(t *testing.T) {
+	t.Run("should report events sent by the final flush", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		client.Init()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+
+		summary, err := client.Close(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary != (CloseSummary{Sent: 2}) {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 send, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should persist undelivered events when the deadline expires", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = &mockHTTPAdapter{err: errors.New("network down")}
+		config.StorageAdapter = storage
+		config.MaxRetries = 100
+		client, _ := NewClient(config)
+		client.Init()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		summary, err := client.Close(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Close should return promptly after the deadline, took %v", elapsed)
+		}
+		if summary != (CloseSummary{Persisted: 2}) {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+		if saved := storage.getSaved(); len(saved) != 2 {
+			t.Fatalf("expected 2 persisted events, got %d", len(saved))
+		}
+	})
+
+	t.Run("should return at the deadline while another flush is retrying", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		httpAdapter := &mockHTTPAdapter{fail: true}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		config.MaxRetries = 100
+		client, _ := NewClient(config)
+		client.Init()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+		go client.Flush()
+		for httpAdapter.getCalls() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		summary, err := client.Close(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Close should return promptly after the deadline, took %v", elapsed)
+		}
+		if summary != (CloseSummary{Persisted: 2}) {
+			t.Fatalf("expected the retrying events to be persisted, got %+v", summary)
+		}
+	})
+
+	t.Run("should persist due retries interrupted by the deadline", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503, Times: 2},
+			adapters.Scenario{Status: 200, Delay: time.Second},
+		)
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		config.MaxBatchSize = 1
+		config.RetryMode = RetryScheduled
+		config.BackoffPolicy = ConstantBackoff(10 * time.Millisecond)
+		client, _ := NewClient(config)
+		client.Init()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+		client.Flush()
+		for httpAdapter.Calls() < 3 {
+			time.Sleep(time.Millisecond)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		summary, err := client.Close(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+		if summary != (CloseSummary{Persisted: 2}) {
+			t.Fatalf("expected both retried events to be persisted, got %+v", summary)
+		}
+		if saved := storage.getSaved(); len(saved) != 2 {
+			t.Fatalf("expected 2 persisted events, got %d", len(saved))
+		}
+	})
+
+	t.Run("should persist events without flushing an uninitialized client", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+
+		summary, err := client.Close(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if summary.Sent != 0 || httpAdapter.getCalls() != 0 {
+			t.Fatalf("expected no sends, got %+v", summary)
+		}
+	})
+}
+
+func TestClient_Stats(t *testing.T) {
+	t.Run("should report activity after successful flush", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Track("b", nil, nil)
+
+		stats := client.Stats()
+		if stats.QueueLength != 2 || stats.EventsTracked != 2 || stats.StorageSize != 2 {
+			t.Fatalf("unexpected stats before flush: %+v", stats)
+		}
+
+		client.Flush()
+
+		stats = client.Stats()
+		if stats.QueueLength != 0 {
+			t.Errorf("expected empty queue, got %d", stats.QueueLength)
+		}
+		if stats.BatchesSent != 1 || stats.BatchesFailed != 0 {
+			t.Errorf("expected 1 sent and 0 failed batches, got %d/%d", stats.BatchesSent, stats.BatchesFailed)
+		}
+		if stats.LastFlushTime.IsZero() {
+			t.Error("expected last flush time to be set")
+		}
+		if stats.LastError != nil {
+			t.Errorf("expected no last error, got %v", stats.LastError)
+		}
+		if stats.StorageSize != 0 {
+			t.Errorf("expected storage to be cleared, got %d", stats.StorageSize)
+		}
+		if stats.Latency.Count != 2 {
+			t.Errorf("expected latency for 2 events, got %d", stats.Latency.Count)
+		}
+	})
+
+	t.Run("should report failed batches and last error", func(t *testing.T) {
+		config := createTestConfig()
+		config.HTTPAdapter = &mockHTTPAdapter{fail: true, statusCode: 400}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("a", nil, nil)
+		client.Flush()
+
+		stats := client.Stats()
+		if stats.BatchesFailed != 1 {
+			t.Fatalf("expected 1 failed batch, got %d", stats.BatchesFailed)
+		}
+		var httpErr *HTTPError
+		if !errors.As(stats.LastError, &httpErr) || httpErr.Status != 400 {
+			t.Errorf("expected HTTPError with status 400, got %v", stats.LastError)
+		}
+	})
+}
+
+func TestClient_BeforeSend(t *testing.T) {
+	t.Run("should run hooks in order and enqueue the result", func(t *testing.T) {
+		config := createTestConfig()
+		config.BeforeSend = []BeforeSendHook{
+			func(event *Event) *Event {
+				event.Payload = map[string]any{"email": "[redacted]"}
+				return event
+			},
+			func(event *Event) *Event {
+				event.Payload["requestId"] = "req-1"
+				return event
+			},
+		}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("signup", map[string]any{"email": "user@example.com"}, nil)
+
+		event, ok := client.dispatcher.queue.Dequeue()
+		if !ok {
+			t.Fatal("expected event to be enqueued")
+		}
+		if event.Payload["email"] != "[redacted]" || event.Payload["requestId"] != "req-1" {
+			t.Errorf("expected hooks to be applied, got %v", event.Payload)
+		}
+	})
+
+	t.Run("should drop events when a hook returns nil", func(t *testing.T) {
+		secondCalled := false
+		config := createTestConfig()
+		config.BeforeSend = []BeforeSendHook{
+			func(event *Event) *Event {
+				if event.Name == "internal" {
+					return nil
+				}
+				return event
+			},
+			func(event *Event) *Event {
+				secondCalled = true
+				return event
+			},
+		}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("internal", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if client.dispatcher.queue.Len() != 0 {
+			t.Fatal("expected event to be dropped")
+		}
+		if secondCalled {
+			t.Error("expected remaining hooks to be skipped after drop")
+		}
+	})
+}
+
+func TestClient_ConfigValidationNoPanic(t *testing.T) {
+	emptyHeader := ""
+
+	tests := []struct {
+		name   string
+		modify func(*ClientConfig)
+	}{
+		{"empty api key header", func(c *ClientConfig) { c.APIKeyHeader = &emptyHeader }},
+		{"empty regional endpoint", func(c *ClientConfig) { c.RegionalEndpoints = []string{"http://eu", ""} }},
+		{"nil before send hook", func(c *ClientConfig) { c.BeforeSend = []BeforeSendHook{nil} }},
+		{"negative probe interval", func(c *ClientConfig) { c.EndpointProbeInterval = -time.Second }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			tt.modify(&config)
+			if _, err := NewClient(config); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func FuzzNewClient(f *testing.F) {
+	f.Add("key", "http://test.com", "X-API-Key", int64(0), 0, 0, 0, true, true)
+	f.Add("", "", "", int64(-1), -1, -1, -1, false, false)
+	f.Add("key", "http://test.com", "", int64(1), 1, 0, 1, true, true)
+	f.Add("key", "http://test.com", "Authorization", int64(time.Second), 100, 5, 50, true, false)
+
+	f.Fuzz(func(t *testing.T, apiKey, endpoint, header string, flushInterval int64, maxBatchSize, maxRetries, maxBufferSize int, withHTTP, withStorage bool) {
+		config := ClientConfig{
+			APIKey:        apiKey,
+			Endpoint:      endpoint,
+			APIKeyHeader:  &header,
+			FlushInterval: time.Duration(flushInterval),
+			MaxBatchSize:  maxBatchSize,
+			MaxRetries:    maxRetries,
+			MaxBufferSize: maxBufferSize,
+			LoggerAdapter: adapters.NewNoOpLoggerAdapter(),
+		}
+		if withHTTP {
+			config.HTTPAdapter = &mockHTTPAdapter{}
+		}
+		if withStorage {
+			config.StorageAdapter = &mockStorageAdapter{}
+		}
+
+		client, err := NewClient(config)
+		if err != nil {
+			if client != nil {
+				t.Fatal("expected nil client alongside error")
+			}
+			return
+		}
+		if client.config.MaxBatchSize <= 0 || client.config.FlushInterval <= 0 || client.config.MaxRetries < 0 {
+			t.Fatalf("accepted invalid config: %+v", client.config)
+		}
+		client.Dispose()
+	})
+}
+
+func TestClient_MetadataMergeIsolation(t *testing.T) {
+	t.Run("should not modify shared or event metadata sources", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("env", "prod")
+		eventMetadata := map[string]any{"env": "staging", "source": "button"}
+		client.Track("test_event", nil, eventMetadata)
+
+		if shared := client.GetMetadata(); len(shared) != 1 || shared["env"] != "prod" {
+			t.Errorf("expected shared metadata to be unchanged, got %v", shared)
+		}
+		if len(eventMetadata) != 2 || eventMetadata["env"] != "staging" {
+			t.Errorf("expected event metadata to be unchanged, got %v", eventMetadata)
+		}
+	})
+
+	t.Run("should snapshot shared metadata at track time", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("version", "1")
+		client.Track("test_event", nil, nil)
+		client.SetMetadata("version", "2")
+
+		event, ok := client.dispatcher.queue.Dequeue()
+		if !ok {
+			t.Fatal("expected event to be in queue")
+		}
+		if event.Metadata["version"] != "1" {
+			t.Errorf("expected version 1, got %v", event.Metadata["version"])
+		}
+	})
+
+	t.Run("should not share metadata maps between events", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("userId", "123")
+		client.Track("first", nil, map[string]any{"n": 1})
+		client.Track("second", nil, nil)
+
+		first, _ := client.dispatcher.queue.Dequeue()
+		second, _ := client.dispatcher.queue.Dequeue()
+		if _, ok := second.Metadata["n"]; ok {
+			t.Error("expected event metadata not to leak into later events")
+		}
+		first.Metadata["userId"] = "changed"
+		if second.Metadata["userId"] != "123" {
+			t.Error("expected each event to own its metadata map")
+		}
+	})
+}
+
+func TestClient_TrackWithContext(t *testing.T) {
+	t.Run("should merge context overrides between shared and event metadata", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("tenantId", "default")
+		client.SetMetadata("env", "prod")
+		err := client.TrackWithContext("test_event", nil,
+			map[string]any{"traceId": "from-event"},
+			map[string]any{"tenantId": "acme", "traceId": "from-context"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		event, ok := client.dispatcher.queue.Dequeue()
+		if !ok {
+			t.Fatal("expected event to be in queue")
+		}
+		if event.Metadata["tenantId"] != "acme" {
+			t.Errorf("expected context override to win over shared metadata, got %v", event.Metadata["tenantId"])
+		}
+		if event.Metadata["traceId"] != "from-event" {
+			t.Errorf("expected event metadata to win over context overrides, got %v", event.Metadata["traceId"])
+		}
+		if event.Metadata["env"] != "prod" {
+			t.Errorf("expected shared metadata to be kept, got %v", event.Metadata["env"])
+		}
+	})
+
+	t.Run("should not mutate shared metadata", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		client.SetMetadata("tenantId", "default")
+		client.TrackWithContext("test_event", nil, nil, map[string]any{"tenantId": "acme"})
+
+		if shared := client.GetMetadata(); shared["tenantId"] != "default" || len(shared) != 1 {
+			t.Errorf("expected shared metadata to be unchanged, got %v", shared)
+		}
+	})
+
+	t.Run("should reject an empty event name", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if err := client.TrackWithContext("", nil, nil, nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestClient_MetadataLifecycle(t *testing.T) {
+	t.Run("should delete a metadata key", func(t *testing.T) {
+		client := createTestClient()
+		client.SetMetadata("userId", "123")
+		client.SetMetadata("env", "prod")
+
+		client.DeleteMetadata("userId")
+		client.DeleteMetadata("missing")
+
+		metadata := client.GetMetadata()
+		if _, ok := metadata["userId"]; ok || metadata["env"] != "prod" {
+			t.Errorf("expected only env to remain, got %v", metadata)
+		}
+	})
+
+	t.Run("should set a metadata map keeping existing keys", func(t *testing.T) {
+		client := createTestClient()
+		client.SetMetadata("env", "prod")
+
+		if err := client.SetMetadataMap(map[string]any{"userId": "123", "env": "staging"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		metadata := client.GetMetadata()
+		if len(metadata) != 2 || metadata["env"] != "staging" || metadata["userId"] != "123" {
+			t.Errorf("unexpected metadata: %v", metadata)
+		}
+	})
+
+	t.Run("should replace all metadata", func(t *testing.T) {
+		client := createTestClient()
+		client.SetMetadata("userId", "123")
+		values := map[string]any{"userId": "456"}
+
+		if err := client.ReplaceMetadata(values); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		values["leak"] = true
+
+		metadata := client.GetMetadata()
+		if len(metadata) != 1 || metadata["userId"] != "456" {
+			t.Errorf("expected replaced metadata, got %v", metadata)
+		}
+
+		if err := client.ReplaceMetadata(nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(client.GetMetadata()) != 0 {
+			t.Error("expected nil to clear metadata")
+		}
+	})
+
+	t.Run("should reject empty keys without partial updates", func(t *testing.T) {
+		client := createTestClient()
+		client.SetMetadata("env", "prod")
+
+		if err := client.SetMetadataMap(map[string]any{"userId": "123", "": "x"}); err == nil {
+			t.Error("expected error from SetMetadataMap")
+		}
+		if err := client.ReplaceMetadata(map[string]any{"": "x"}); err == nil {
+			t.Error("expected error from ReplaceMetadata")
+		}
+
+		metadata := client.GetMetadata()
+		if len(metadata) != 1 || metadata["env"] != "prod" {
+			t.Errorf("expected metadata to be unchanged, got %v", metadata)
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package rippletest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+)
+
+// Clock is a ripple.Clock that only moves when advanced, so flush intervals,
+// retry backoff and periodic checks can be tested without real waits. It is
+// safe for concurrent use.
+//
+// Timers and tickers fire during Advance, in deadline order. AfterFunc
+// callbacks run in their own goroutine, as with the system clock, so use
+// BlockUntil to wait for the code under test to schedule its next timer
+// before advancing again.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*clockWaiter
+	changed chan struct{}
+}
+
+// clockWaiter is a pending timer, ticker or sleep.
+type clockWaiter struct {
+	at     time.Time
+	period time.Duration // non-zero for tickers
+	fire   func(now time.Time)
+}
+
+// NewClock creates a Clock set to start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start, changed: make(chan struct{})}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d, firing every timer, ticker and
+// sleep that falls due on the way.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		next := -1
+		for i, w := range c.waiters {
+			if !w.at.After(target) && (next < 0 || w.at.Before(c.waiters[next].at)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			c.now = target
+			c.mu.Unlock()
+			return
+		}
+
+		w := c.waiters[next]
+		if w.at.After(c.now) {
+			c.now = w.at
+		}
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			c.removeLocked(w)
+		}
+		now := c.now
+		c.mu.Unlock()
+
+		w.fire(now)
+	}
+}
+
+// Pending returns the number of timers, tickers and sleeps waiting on the
+// clock.
+func (c *Clock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers, tickers and sleeps are waiting
+// on the clock, or ctx is done.
+func (c *Clock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		c.mu.Lock()
+		done := len(c.waiters) >= n
+		changed := c.changed
+		c.mu.Unlock()
+		if done {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// NewTicker returns a ticker that fires every d of advanced time. Like
+// time.Ticker, it drops ticks for slow receivers.
+func (c *Clock) NewTicker(d time.Duration) ripple.Ticker {
+	if d <= 0 {
+		panic("rippletest: non-positive interval for NewTicker")
+	}
+	ch := make(chan time.Time, 1)
+	w := &clockWaiter{period: d, fire: func(now time.Time) {
+		select {
+		case ch <- now:
+		default:
+		}
+	}}
+	c.add(w, d)
+	return &clockTicker{clock: c, waiter: w, ch: ch}
+}
+
+// AfterFunc calls f in its own goroutine once d of time has been advanced.
+func (c *Clock) AfterFunc(d time.Duration, f func()) ripple.Timer {
+	w := &clockWaiter{fire: func(time.Time) { go f() }}
+	c.add(w, d)
+	return &clockTimer{clock: c, waiter: w}
+}
+
+// Sleep blocks until d of time has been advanced or ctx is done.
+func (c *Clock) Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	done := make(chan struct{})
+	w := &clockWaiter{fire: func(time.Time) { close(done) }}
+	c.add(w, d)
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		c.remove(w)
+		return false
+	}
+}
+
+// add schedules w to fire d after the current time.
+func (c *Clock) add(w *clockWaiter, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.at = c.now.Add(d)
+	c.waiters = append(c.waiters, w)
+	c.notifyLocked()
+}
+
+// remove unschedules w, returning false if it was not pending.
+func (c *Clock) remove(w *clockWaiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.removeLocked(w)
+}
+
+func (c *Clock) removeLocked(w *clockWaiter) bool {
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.notifyLocked()
+			return true
+		}
+	}
+	return false
+}
+
+// notifyLocked wakes BlockUntil callers. Caller must hold c.mu.
+func (c *Clock) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+type clockTicker struct {
+	clock  *Clock
+	waiter *clockWaiter
+	ch     chan time.Time
+}
+
+func (t *clockTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *clockTicker) Stop() {
+	t.clock.remove(t.waiter)
+}
+
+type clockTimer struct {
+	clock  *Clock
+	waiter *clockWaiter
+}
+
+func (t *clockTimer) Stop() bool {
+	return t.clock.remove(t.waiter)
+}
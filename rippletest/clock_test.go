@@ -0,0 +1,214 @@
+package rippletest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+var clockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// waitUntil polls cond until it holds, failing the test after a second.
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClock(t *testing.T) {
+	t.Run("should only move when advanced", func(t *testing.T) {
+		c := NewClock(clockStart)
+		if !c.Now().Equal(clockStart) {
+			t.Fatalf("unexpected start time: %v", c.Now())
+		}
+		c.Advance(time.Minute)
+		if !c.Now().Equal(clockStart.Add(time.Minute)) {
+			t.Fatalf("unexpected time after advance: %v", c.Now())
+		}
+	})
+
+	t.Run("should run AfterFunc once its deadline is reached", func(t *testing.T) {
+		c := NewClock(clockStart)
+		fired := make(chan struct{})
+		c.AfterFunc(time.Second, func() { close(fired) })
+
+		c.Advance(999 * time.Millisecond)
+		select {
+		case <-fired:
+			t.Fatal("expected timer not to fire early")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		c.Advance(time.Millisecond)
+		select {
+		case <-fired:
+		case <-time.After(time.Second):
+			t.Fatal("expected timer to fire")
+		}
+		if c.Pending() != 0 {
+			t.Fatalf("expected no pending timers, got %d", c.Pending())
+		}
+	})
+
+	t.Run("should not run a stopped timer", func(t *testing.T) {
+		c := NewClock(clockStart)
+		timer := c.AfterFunc(time.Second, func() { t.Error("stopped timer fired") })
+		if !timer.Stop() {
+			t.Fatal("expected Stop to report a pending timer")
+		}
+		if timer.Stop() {
+			t.Fatal("expected a second Stop to report false")
+		}
+		c.Advance(time.Hour)
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	t.Run("should wake sleepers on advance and on cancellation", func(t *testing.T) {
+		c := NewClock(clockStart)
+		ctx := context.Background()
+
+		result := make(chan bool)
+		go func() { result <- c.Sleep(ctx, time.Second) }()
+		if err := c.BlockUntil(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.Advance(time.Second)
+		if !<-result {
+			t.Fatal("expected sleep to complete")
+		}
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		go func() { result <- c.Sleep(cancelCtx, time.Second) }()
+		if err := c.BlockUntil(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cancel()
+		if <-result {
+			t.Fatal("expected cancelled sleep to report false")
+		}
+		if c.Pending() != 0 {
+			t.Fatalf("expected cancelled sleep to be unscheduled, got %d pending", c.Pending())
+		}
+	})
+
+	t.Run("should tick every interval until stopped", func(t *testing.T) {
+		c := NewClock(clockStart)
+		ticker := c.NewTicker(time.Second)
+
+		c.Advance(time.Second)
+		if tick := <-ticker.C(); !tick.Equal(clockStart.Add(time.Second)) {
+			t.Fatalf("unexpected tick time: %v", tick)
+		}
+		c.Advance(time.Second)
+		if tick := <-ticker.C(); !tick.Equal(clockStart.Add(2 * time.Second)) {
+			t.Fatalf("unexpected tick time: %v", tick)
+		}
+
+		ticker.Stop()
+		c.Advance(time.Second)
+		select {
+		case <-ticker.C():
+			t.Fatal("expected no tick after Stop")
+		default:
+		}
+	})
+
+	t.Run("should time out BlockUntil with the context", func(t *testing.T) {
+		c := NewClock(clockStart)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := c.BlockUntil(ctx, 1); err != context.DeadlineExceeded {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func TestClock_DrivesClient(t *testing.T) {
+	t.Run("should flush after the flush interval of advanced time", func(t *testing.T) {
+		c := NewClock(clockStart)
+		r, err := NewRecorderWithConfig(ripple.ClientConfig{
+			FlushInterval: 5 * time.Second,
+			Clock:         c,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer r.Dispose()
+
+		_ = r.Track("a", nil, nil)
+		if issuedAt := r.Events()[0].IssuedAt; issuedAt != clockStart.UnixMilli() {
+			t.Fatalf("expected IssuedAt from the clock, got %d", issuedAt)
+		}
+
+		c.Advance(4 * time.Second)
+		time.Sleep(10 * time.Millisecond)
+		if len(r.Sent()) != 0 {
+			t.Fatal("expected no flush before the interval")
+		}
+
+		c.Advance(time.Second)
+		waitUntil(t, func() bool { return len(r.Sent()) == 1 })
+		if last := r.Stats().LastFlushTime; !last.Equal(clockStart.Add(5 * time.Second)) {
+			t.Fatalf("expected LastFlushTime from the clock, got %v", last)
+		}
+	})
+
+	t.Run("should back off between retries in advanced time", func(t *testing.T) {
+		c := NewClock(clockStart)
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503},
+			adapters.Scenario{Status: 200},
+		)
+		client, err := ripple.NewClient(ripple.ClientConfig{
+			APIKey:         "test-key",
+			Endpoint:       Endpoint,
+			HTTPAdapter:    httpAdapter,
+			StorageAdapter: adapters.NewNoOpStorageAdapter(),
+			LoggerAdapter:  adapters.NewNoOpLoggerAdapter(),
+			MaxRetries:     3,
+			Clock:          c,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		_ = client.Track("a", nil, nil)
+		done := make(chan struct{})
+		go func() {
+			client.Flush()
+			close(done)
+		}()
+
+		// The flush timer is stopped once the flush starts; the only pending
+		// waiter after the failed attempt is the backoff sleep.
+		waitUntil(t, func() bool { return httpAdapter.Calls() == 1 })
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := c.BlockUntil(ctx, 1); err != nil {
+			t.Fatalf("expected a backoff sleep: %v", err)
+		}
+
+		c.Advance(500 * time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		if httpAdapter.Calls() != 1 {
+			t.Fatal("expected no retry before the backoff elapses")
+		}
+
+		// The first backoff is 1s plus up to 1s of jitter.
+		c.Advance(2 * time.Second)
+		<-done
+		if httpAdapter.Calls() != 2 {
+			t.Fatalf("expected one retry, got %d calls", httpAdapter.Calls())
+		}
+	})
+}
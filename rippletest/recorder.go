@@ -0,0 +1,159 @@
+// Package rippletest provides a recording ripple client for unit-testing
+// tracking calls without a network or persistent storage, and a manual Clock
+// for testing time-dependent behaviour without real waits.
+//
+// A Recorder embeds a real *ripple.Client, so it exposes the full client API
+// and can be passed wherever a *ripple.Client is expected via its Client
+// field. Events go through the normal tracking pipeline (sampling, hooks,
+// validation, batching) and are delivered to an in-memory adapter that
+// always succeeds.
+package rippletest
+
+import (
+	"context"
+	"sync"
+
+	ripple "github.com/Tap30/ripple-go"
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// Endpoint is the endpoint recorders deliver to. Nothing is ever sent to it.
+const Endpoint = "http://rippletest.invalid/events"
+
+// Recorder is a ripple client that records every tracked and delivered
+// event. It is safe for concurrent use.
+type Recorder struct {
+	*ripple.Client
+
+	mu      sync.Mutex
+	tracked []ripple.Event
+	sent    []ripple.Event
+	changed chan struct{}
+}
+
+// NewRecorder creates an initialized Recorder with default settings.
+// Call Dispose when done.
+func NewRecorder() *Recorder {
+	r, err := NewRecorderWithConfig(ripple.ClientConfig{})
+	if err != nil {
+		// The default configuration is always valid.
+		panic(err)
+	}
+	return r
+}
+
+// NewRecorderWithConfig creates an initialized Recorder from config, e.g.
+// to exercise BeforeSend hooks or sampling rules. APIKey and Endpoint
+// default to test values; HTTPAdapter is always replaced by the recorder,
+// and StorageAdapter and LoggerAdapter default to no-op adapters.
+func NewRecorderWithConfig(config ripple.ClientConfig) (*Recorder, error) {
+	r := &Recorder{changed: make(chan struct{})}
+
+	if config.APIKey == "" {
+		config.APIKey = "rippletest"
+	}
+	if config.Endpoint == "" && len(config.Endpoints) == 0 && len(config.RegionalEndpoints) == 0 {
+		config.Endpoint = Endpoint
+	}
+	config.HTTPAdapter = (*recordingAdapter)(r)
+	if config.StorageAdapter == nil {
+		config.StorageAdapter = adapters.NewNoOpStorageAdapter()
+	}
+	if config.LoggerAdapter == nil {
+		config.LoggerAdapter = adapters.NewNoOpLoggerAdapter()
+	}
+
+	client, err := ripple.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	r.Client = client
+	client.Subscribe(r.record)
+	client.Init()
+	return r, nil
+}
+
+// Events returns every event accepted by Track or TrackNow, in order.
+func (r *Recorder) Events() []ripple.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ripple.Event(nil), r.tracked...)
+}
+
+// EventsNamed returns the accepted events called name, in order.
+func (r *Recorder) EventsNamed(name string) []ripple.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []ripple.Event
+	for _, event := range r.tracked {
+		if event.Name == name {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Sent returns every event delivered to the recorder's adapter, in delivery
+// order.
+func (r *Recorder) Sent() []ripple.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ripple.Event(nil), r.sent...)
+}
+
+// WaitForFlush flushes the queue and waits until every event accepted so far
+// has been delivered, or ctx is done.
+func (r *Recorder) WaitForFlush(ctx context.Context) error {
+	r.Flush()
+	for {
+		r.mu.Lock()
+		done := len(r.sent) >= len(r.tracked)
+		changed := r.changed
+		r.mu.Unlock()
+		if done {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Reset forgets every recorded event.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tracked = nil
+	r.sent = nil
+}
+
+// record stores an accepted event.
+func (r *Recorder) record(event ripple.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tracked = append(r.tracked, event)
+}
+
+// recordingAdapter is the Recorder's view as an HTTPAdapter.
+type recordingAdapter Recorder
+
+func (a *recordingAdapter) Send(endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *recordingAdapter) RetainsEvents() bool {
+	return false
+}
+
+func (a *recordingAdapter) SendWithContext(ctx context.Context, endpoint string, events []ripple.Event, headers map[string]string) (*ripple.HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sent = append(a.sent, events...)
+	close(a.changed)
+	a.changed = make(chan struct{})
+	return &ripple.HTTPResponse{Status: 200}, nil
+}
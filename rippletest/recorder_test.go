@@ -0,0 +1,82 @@
+package rippletest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ripple "github.com/Tap30/ripple-go"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("should record tracked events", func(t *testing.T) {
+		r := NewRecorder()
+		defer r.Dispose()
+
+		_ = r.Track("signup", map[string]any{"plan": "pro"}, nil)
+		_ = r.Track("login", nil, nil)
+		_ = r.Track("signup", map[string]any{"plan": "free"}, nil)
+
+		if events := r.Events(); len(events) != 3 || events[1].Name != "login" {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+		signups := r.EventsNamed("signup")
+		if len(signups) != 2 || signups[1].Payload["plan"] != "free" {
+			t.Fatalf("unexpected signups: %+v", signups)
+		}
+		if len(r.Sent()) != 0 {
+			t.Fatal("expected nothing delivered before a flush")
+		}
+	})
+
+	t.Run("should wait until tracked events are delivered", func(t *testing.T) {
+		r := NewRecorder()
+		defer r.Dispose()
+
+		_ = r.Track("a", nil, nil)
+		_ = r.Track("b", nil, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := r.WaitForFlush(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent := r.Sent(); len(sent) != 2 || sent[0].Name != "a" {
+			t.Fatalf("unexpected sent events: %+v", sent)
+		}
+	})
+
+	t.Run("should apply the client pipeline from config", func(t *testing.T) {
+		r, err := NewRecorderWithConfig(ripple.ClientConfig{
+			BeforeSend: []ripple.BeforeSendHook{func(e *ripple.Event) *ripple.Event {
+				if e.Name == "internal" {
+					return nil
+				}
+				return e
+			}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer r.Dispose()
+
+		_ = r.Track("internal", nil, nil)
+		_ = r.Track("public", nil, nil)
+
+		if events := r.Events(); len(events) != 1 || events[0].Name != "public" {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("should forget events on Reset", func(t *testing.T) {
+		r := NewRecorder()
+		defer r.Dispose()
+
+		_ = r.Track("a", nil, nil)
+		r.Reset()
+
+		if len(r.Events()) != 0 {
+			t.Fatal("expected no events after Reset")
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package ripple
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// ConfigUpdate changes client settings at runtime. Nil fields are left
+// unchanged.
+type ConfigUpdate struct {
+	// FlushInterval replaces ClientConfig.FlushInterval. A pending scheduled
+	// flush is rescheduled with the new interval.
+	FlushInterval *time.Duration `json:"flushInterval,omitempty"`
+
+	// MaxBatchSize replaces ClientConfig.MaxBatchSize. With adaptive
+	// batching it resets the current batch size, within the adaptive bounds.
+	MaxBatchSize *int `json:"maxBatchSize,omitempty"`
+
+	// SamplingRate replaces ClientConfig.SamplingRate.
+	SamplingRate *float64 `json:"samplingRate,omitempty"`
+
+	// SamplingRules replaces ClientConfig.SamplingRules.
+	SamplingRules map[string]float64 `json:"samplingRules,omitempty"`
+
+	// DisabledEvents replaces the names of events that Track drops. An empty,
+	// non-nil slice re-enables every event.
+	DisabledEvents []string `json:"disabledEvents,omitempty"`
+
+	// AllowedEvents replaces ClientConfig.AllowedEvents. An empty, non-nil
+	// slice allows every event.
+	AllowedEvents []string `json:"allowedEvents,omitempty"`
+
+	// DeniedEvents replaces ClientConfig.DeniedEvents. An empty, non-nil
+	// slice denies none.
+	DeniedEvents []string `json:"deniedEvents,omitempty"`
+
+	// LogLevel changes the level of the client's LoggerAdapter, which must
+	// implement adapters.LevelSetter.
+	LogLevel *LogLevel `json:"logLevel,omitempty"`
+}
+
+// UpdateConfig applies a ConfigUpdate without recreating the client. The
+// update is validated as a whole: if any field is invalid, a *ConfigError
+// lists every problem and nothing is changed. It is safe to call
+// concurrently with Track and Flush.
+func (c *Client) UpdateConfig(update ConfigUpdate) error {
+	if err := c.validateUpdate(update); err != nil {
+		return err
+	}
+
+	fields := map[string]any{}
+	if update.FlushInterval != nil {
+		c.dispatcher.setFlushInterval(*update.FlushInterval)
+		fields["flushInterval"] = update.FlushInterval.String()
+	}
+	if update.MaxBatchSize != nil {
+		c.dispatcher.setMaxBatchSize(*update.MaxBatchSize)
+		fields["maxBatchSize"] = *update.MaxBatchSize
+	}
+	if update.SamplingRate != nil || update.SamplingRules != nil {
+		c.sampler.update(update.SamplingRate, update.SamplingRules)
+		fields["sampling"] = true
+	}
+	if update.DisabledEvents != nil {
+		c.setDisabledEvents(update.DisabledEvents)
+		fields["disabledEvents"] = len(update.DisabledEvents)
+	}
+	if update.AllowedEvents != nil || update.DeniedEvents != nil {
+		c.setEventFilter(update.AllowedEvents, update.DeniedEvents)
+		fields["eventFilter"] = true
+	}
+	if update.LogLevel != nil {
+		c.loggerAdapter.(adapters.LevelSetter).SetLevel(*update.LogLevel)
+		fields["logLevel"] = string(*update.LogLevel)
+	}
+	c.loggerAdapter.Info("Configuration updated", fields)
+	return nil
+}
+
+// validateUpdate reports every invalid field of update.
+func (c *Client) validateUpdate(update ConfigUpdate) error {
+	var problems []string
+	if interval := update.FlushInterval; interval != nil && *interval < time.Millisecond {
+		problems = append(problems, "flush interval must be a positive duration")
+	}
+	if size := update.MaxBatchSize; size != nil {
+		if *size <= 0 {
+			problems = append(problems, "max batch size must be a positive number")
+		} else if c.config.MaxBufferSize > 0 && *size > c.config.MaxBufferSize {
+			problems = append(problems, fmt.Sprintf("max buffer size (%d) must be greater than or equal to max batch size (%d)", c.config.MaxBufferSize, *size))
+		}
+	}
+	if update.SamplingRate != nil && !validateSampleRate(*update.SamplingRate) {
+		problems = append(problems, "sampling rate must be between 0 and 1")
+	}
+	for name, rate := range update.SamplingRules {
+		if !validateSampleRate(rate) {
+			problems = append(problems, fmt.Sprintf("sampling rate for %q must be between 0 and 1", name))
+		}
+	}
+	problems = append(problems, validateEventPatterns("allowed events", update.AllowedEvents)...)
+	problems = append(problems, validateEventPatterns("denied events", update.DeniedEvents)...)
+	if level := update.LogLevel; level != nil {
+		if !validLogLevel(*level) {
+			problems = append(problems, fmt.Sprintf("unknown log level %q", *level))
+		} else if _, ok := c.loggerAdapter.(adapters.LevelSetter); !ok {
+			problems = append(problems, "logger adapter does not support changing the log level")
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ConfigError{Problems: problems}
+}
+
+// setDisabledEvents replaces the set of event names dropped by Track.
+func (c *Client) setDisabledEvents(names []string) {
+	if len(names) == 0 {
+		c.disabledEvents.Store(nil)
+		return
+	}
+	disabled := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		disabled[name] = struct{}{}
+	}
+	c.disabledEvents.Store(&disabled)
+}
+
+// eventDisabled reports whether events named name are dropped.
+func (c *Client) eventDisabled(name string) bool {
+	disabled := c.disabledEvents.Load()
+	if disabled == nil {
+		return false
+	}
+	_, ok := (*disabled)[name]
+	return ok
+}
+
+// validLogLevel reports whether level is one of the adapters.LogLevel values.
+func validLogLevel(level LogLevel) bool {
+	switch level {
+	case adapters.LogLevelDebug, adapters.LogLevelInfo, adapters.LogLevelWarn, adapters.LogLevelError, adapters.LogLevelNone:
+		return true
+	}
+	return false
+}
+
+// setFlushInterval changes the flush interval. A pending scheduled flush is
+// rescheduled so that the new interval applies immediately.
+func (d *Dispatcher) setFlushInterval(interval time.Duration) {
+	d.mu.Lock()
+	d.config.FlushInterval = interval
+	rearm := d.timer != nil && d.timer.Stop()
+	if rearm {
+		d.timer = nil
+	}
+	d.mu.Unlock()
+
+	if rearm {
+		d.scheduleFlush()
+	}
+}
+
+// setMaxBatchSize changes the number of events per batch.
+func (d *Dispatcher) setMaxBatchSize(size int) {
+	d.maxBatchSize.Store(int64(size))
+	if d.sizer != nil {
+		d.sizer.reset(size)
+	}
+}
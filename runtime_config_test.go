@@ -0,0 +1,174 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestClient_UpdateConfig(t *testing.T) {
+	calls := func(m *mockHTTPAdapter) int {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return m.calls
+	}
+
+	t.Run("should reschedule a pending flush with the new interval", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.FlushInterval = time.Hour
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.Track("a", nil, nil)
+		interval := 10 * time.Millisecond
+		if err := client.UpdateConfig(ConfigUpdate{FlushInterval: &interval}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for calls(httpAdapter) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatal("expected a flush after the new interval")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	t.Run("should flush at the new batch size", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.FlushInterval = time.Hour
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		size := 2
+		if err := client.UpdateConfig(ConfigUpdate{MaxBatchSize: &size}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = client.Track("a", nil, nil)
+		_ = client.Track("b", nil, nil)
+		if calls(httpAdapter) != 1 {
+			t.Fatalf("expected a full batch to flush, got %d calls", calls(httpAdapter))
+		}
+		if client.Stats().BatchSize != 2 {
+			t.Fatalf("expected batch size 2, got %d", client.Stats().BatchSize)
+		}
+	})
+
+	t.Run("should reset the adaptive batch size within its bounds", func(t *testing.T) {
+		config := createTestConfig()
+		config.AdaptiveBatching = &AdaptiveBatching{MinBatchSize: 5, MaxBatchSize: 20}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		size := 50
+		_ = client.UpdateConfig(ConfigUpdate{MaxBatchSize: &size})
+		if client.Stats().BatchSize != 20 {
+			t.Fatalf("expected batch size clamped to 20, got %d", client.Stats().BatchSize)
+		}
+	})
+
+	t.Run("should update sampling", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		_ = client.UpdateConfig(ConfigUpdate{SamplingRules: map[string]float64{"a": 0}})
+		_ = client.Track("a", nil, nil)
+		if client.dispatcher.queue.Len() != 0 {
+			t.Fatal("expected event to be sampled out")
+		}
+	})
+
+	t.Run("should drop disabled events until re-enabled", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		_ = client.UpdateConfig(ConfigUpdate{DisabledEvents: []string{"a"}})
+		_ = client.Track("a", nil, nil)
+		_ = client.Track("b", nil, nil)
+		if client.dispatcher.queue.Len() != 1 || client.Stats().EventsDisabled != 1 {
+			t.Fatalf("expected only the disabled event dropped, got %+v", client.Stats())
+		}
+
+		_ = client.UpdateConfig(ConfigUpdate{DisabledEvents: []string{}})
+		_ = client.Track("a", nil, nil)
+		if client.dispatcher.queue.Len() != 2 {
+			t.Fatal("expected the event to be re-enabled")
+		}
+	})
+
+	t.Run("should replace the event allowlist and blocklist", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		_ = client.UpdateConfig(ConfigUpdate{DeniedEvents: []string{"debug_*"}})
+		_ = client.Track("debug_cache", nil, nil)
+		_ = client.Track("a", nil, nil)
+		if client.dispatcher.queue.Len() != 1 || client.Stats().EventsFiltered != 1 {
+			t.Fatalf("expected only the denied event dropped, got %+v", client.Stats())
+		}
+
+		_ = client.UpdateConfig(ConfigUpdate{DeniedEvents: []string{}})
+		_ = client.Track("debug_cache", nil, nil)
+		if client.dispatcher.queue.Len() != 2 {
+			t.Fatal("expected the event to be tracked again")
+		}
+
+		if err := client.UpdateConfig(ConfigUpdate{AllowedEvents: []string{"[a-"}}); err == nil {
+			t.Fatal("expected a malformed pattern to be rejected")
+		}
+	})
+
+	t.Run("should change the log level", func(t *testing.T) {
+		logger := adapters.NewPrintLoggerAdapter(adapters.LogLevelWarn)
+		config := createTestConfig()
+		config.LoggerAdapter = logger
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		level := adapters.LogLevelNone
+		if err := client.UpdateConfig(ConfigUpdate{LogLevel: &level}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		config.LoggerAdapter = &mockLogger{}
+		other, _ := NewClient(config)
+		defer other.Dispose()
+		if err := other.UpdateConfig(ConfigUpdate{LogLevel: &level}); err == nil {
+			t.Fatal("expected error for a logger without SetLevel")
+		}
+	})
+
+	t.Run("should reject the whole update if any field is invalid", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxBufferSize = 20
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		interval := time.Second
+		size := 30
+		rate := 2.0
+		level := LogLevel("LOUD")
+		err := client.UpdateConfig(ConfigUpdate{FlushInterval: &interval, MaxBatchSize: &size, SamplingRate: &rate, LogLevel: &level})
+
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) || len(configErr.Problems) != 3 {
+			t.Fatalf("expected three problems, got %v", err)
+		}
+		if client.dispatcher.config.FlushInterval != defaultFlushInterval {
+			t.Fatal("expected no change on an invalid update")
+		}
+	})
+}
+
+func TestPrintLoggerAdapter_SetLevel(t *testing.T) {
+	var logger LoggerAdapter = adapters.NewPrintLoggerAdapter(adapters.LogLevelWarn)
+	if _, ok := logger.(adapters.LevelSetter); !ok {
+		t.Fatal("expected PrintLoggerAdapter to implement LevelSetter")
+	}
+}
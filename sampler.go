@@ -0,0 +1,76 @@
+package ripple
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// sampleRateMetadataKey is the metadata key used to annotate kept events with
+// the sampling rate that applied to them.
+const sampleRateMetadataKey = "sampleRate"
+
+// sampler decides client-side whether an event is kept, based on a global
+// rate and optional per-event-name rates.
+type sampler struct {
+	mu     sync.RWMutex
+	rate   float64
+	rules  map[string]float64
+	random func() float64
+}
+
+// newSampler creates a sampler. A rate of 0 is treated as 1 (keep all).
+func newSampler(rate float64, rules map[string]float64) *sampler {
+	if rate == 0 {
+		rate = 1
+	}
+	copied := make(map[string]float64, len(rules))
+	for name, r := range rules {
+		copied[name] = r
+	}
+	return &sampler{rate: rate, rules: copied, random: rand.Float64}
+}
+
+// update replaces the global rate and/or rules. Nil arguments are ignored.
+func (s *sampler) update(rate *float64, rules map[string]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rate != nil {
+		s.rate = *rate
+		if s.rate == 0 {
+			s.rate = 1
+		}
+	}
+	if rules != nil {
+		s.rules = make(map[string]float64, len(rules))
+		for name, r := range rules {
+			s.rules[name] = r
+		}
+	}
+}
+
+// rateFor returns the sampling rate for an event name.
+func (s *sampler) rateFor(name string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.rules[name]; ok {
+		return r
+	}
+	return s.rate
+}
+
+// sample reports whether an event should be kept, along with the applied rate.
+func (s *sampler) sample(name string) (bool, float64) {
+	rate := s.rateFor(name)
+	if rate >= 1 {
+		return true, rate
+	}
+	if rate <= 0 {
+		return false, rate
+	}
+	return s.random() < rate, rate
+}
+
+// validateSampleRate reports whether rate lies within [0, 1].
+func validateSampleRate(rate float64) bool {
+	return rate >= 0 && rate <= 1
+}
@@ -0,0 +1,89 @@
+package ripple
+
+import "testing"
+
+func TestSampler(t *testing.T) {
+	t.Run("zero rate keeps everything", func(t *testing.T) {
+		s := newSampler(0, nil)
+		if keep, rate := s.sample("any"); !keep || rate != 1 {
+			t.Fatalf("expected keep with rate 1, got %v %v", keep, rate)
+		}
+	})
+
+	t.Run("per-event rules override global rate", func(t *testing.T) {
+		s := newSampler(0.5, map[string]float64{"noisy": 0, "critical": 1})
+		s.random = func() float64 { return 0.99 }
+
+		if keep, _ := s.sample("noisy"); keep {
+			t.Error("expected rule with rate 0 to drop")
+		}
+		if keep, _ := s.sample("critical"); !keep {
+			t.Error("expected rule with rate 1 to keep")
+		}
+		if keep, rate := s.sample("other"); keep || rate != 0.5 {
+			t.Errorf("expected global rate to drop, got %v %v", keep, rate)
+		}
+	})
+
+	t.Run("keeps when random falls below rate", func(t *testing.T) {
+		s := newSampler(0.25, nil)
+		s.random = func() float64 { return 0.1 }
+		if keep, _ := s.sample("event"); !keep {
+			t.Error("expected event to be kept")
+		}
+	})
+}
+
+func TestClient_Sampling(t *testing.T) {
+	t.Run("should drop sampled-out events and count them", func(t *testing.T) {
+		config := createTestConfig()
+		config.SamplingRules = map[string]float64{"heartbeat": 0}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("heartbeat", nil, nil)
+		client.Track("heartbeat", nil, nil)
+		client.Track("purchase", nil, nil)
+
+		stats := client.Stats()
+		if stats.EventsSampledOut != 2 {
+			t.Errorf("expected 2 sampled-out events, got %d", stats.EventsSampledOut)
+		}
+		if stats.QueueLength != 1 {
+			t.Errorf("expected 1 queued event, got %d", stats.QueueLength)
+		}
+	})
+
+	t.Run("should annotate kept events with sample rate", func(t *testing.T) {
+		config := createTestConfig()
+		config.SamplingRate = 0.5
+		config.AnnotateSampleRate = true
+		client, _ := NewClient(config)
+		defer client.Dispose()
+		client.sampler.random = func() float64 { return 0 }
+
+		client.Track("page_view", nil, nil)
+
+		event, ok := client.dispatcher.queue.Dequeue()
+		if !ok {
+			t.Fatal("expected event to be kept")
+		}
+		if event.Metadata[sampleRateMetadataKey] != 0.5 {
+			t.Errorf("expected sampleRate 0.5, got %v", event.Metadata[sampleRateMetadataKey])
+		}
+	})
+
+	t.Run("should reject out-of-range rates", func(t *testing.T) {
+		config := createTestConfig()
+		config.SamplingRate = 1.5
+		if _, err := NewClient(config); err == nil {
+			t.Error("expected error for global rate > 1")
+		}
+
+		config = createTestConfig()
+		config.SamplingRules = map[string]float64{"x": -0.1}
+		if _, err := NewClient(config); err == nil {
+			t.Error("expected error for negative rule rate")
+		}
+	})
+}
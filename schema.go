@@ -0,0 +1,333 @@
+package ripple
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// schemaVersionMetadataKey selects the registered schema version.
+	schemaVersionMetadataKey = "schemaVersion"
+
+	// schemaErrorsMetadataKey flags non-conforming events in lenient mode.
+	schemaErrorsMetadataKey = "schemaErrors"
+)
+
+// SchemaMode controls how events that fail validation are handled.
+type SchemaMode int
+
+const (
+	// SchemaModeLenient keeps non-conforming events, logs a warning, and
+	// lists the violations under the "schemaErrors" metadata key.
+	SchemaModeLenient SchemaMode = iota
+
+	// SchemaModeStrict rejects non-conforming events; Track returns a
+	// *SchemaValidationError.
+	SchemaModeStrict
+
+	// SchemaModeWarn keeps non-conforming events unchanged and logs a
+	// warning.
+	SchemaModeWarn
+)
+
+// SchemaValidationError reports the ways an event payload violated its schema.
+type SchemaValidationError struct {
+	Name    string
+	Version string
+	Errors  []string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return fmt.Sprintf("event %q does not match schema version %q: %s", e.Name, e.Version, strings.Join(e.Errors, "; "))
+}
+
+// SchemaValidator holds JSON Schemas registered per event name and schema
+// version, and validates event payloads against them.
+//
+// It supports the commonly used subset of JSON Schema: type, properties,
+// required, additionalProperties, items, enum, const, minimum, maximum,
+// minLength, maxLength, pattern, minItems and maxItems.
+type SchemaValidator struct {
+	mode    SchemaMode
+	mu      sync.RWMutex
+	schemas map[schemaKey]*jsonSchema
+}
+
+type schemaKey struct {
+	name    string
+	version string
+}
+
+// NewSchemaValidator creates an empty SchemaValidator.
+func NewSchemaValidator(mode SchemaMode) *SchemaValidator {
+	return &SchemaValidator{
+		mode:    mode,
+		schemas: make(map[schemaKey]*jsonSchema),
+	}
+}
+
+// Register adds or replaces the schema for an event name and version.
+// The version is matched against the "schemaVersion" event metadata; an empty
+// version registers the fallback used when no exact version is registered.
+func (v *SchemaValidator) Register(eventName, version string, schema []byte) error {
+	if eventName == "" {
+		return fmt.Errorf("event name cannot be empty")
+	}
+
+	var parsed jsonSchema
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return fmt.Errorf("invalid schema for %q: %w", eventName, err)
+	}
+	if err := parsed.compile(); err != nil {
+		return fmt.Errorf("invalid schema for %q: %w", eventName, err)
+	}
+
+	v.mu.Lock()
+	v.schemas[schemaKey{eventName, version}] = &parsed
+	v.mu.Unlock()
+	return nil
+}
+
+// Mode returns the validator's SchemaMode.
+func (v *SchemaValidator) Mode() SchemaMode {
+	return v.mode
+}
+
+// Validate checks the event payload against its registered schema.
+// Returns nil if the payload conforms or no schema is registered for the event.
+func (v *SchemaValidator) Validate(event *Event) error {
+	version, _ := event.Metadata[schemaVersionMetadataKey].(string)
+
+	v.mu.RLock()
+	schema, ok := v.schemas[schemaKey{event.Name, version}]
+	if !ok {
+		schema, ok = v.schemas[schemaKey{event.Name, ""}]
+	}
+	v.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	// Normalize Go values (ints, structs, typed slices) to their JSON form.
+	var payload any = map[string]any{}
+	if event.Payload != nil {
+		data, err := json.Marshal(event.Payload)
+		if err != nil {
+			return &SchemaValidationError{Name: event.Name, Version: version, Errors: []string{err.Error()}}
+		}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if err := decoder.Decode(&payload); err != nil {
+			return &SchemaValidationError{Name: event.Name, Version: version, Errors: []string{err.Error()}}
+		}
+	}
+
+	var errs []string
+	schema.validate("payload", payload, &errs)
+	if len(errs) > 0 {
+		return &SchemaValidationError{Name: event.Name, Version: version, Errors: errs}
+	}
+	return nil
+}
+
+// jsonSchema is the supported subset of a JSON Schema document.
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *json.RawMessage       `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+	Const                *any                   `json:"const"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+
+	pattern          *regexp.Regexp
+	allowAdditional  bool
+	additionalSchema *jsonSchema
+}
+
+// schemaTypes accepts "type" as either a string or an array of strings.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = multiple
+	return nil
+}
+
+// compile prepares patterns and additionalProperties for validation.
+func (s *jsonSchema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+
+	s.allowAdditional = true
+	if s.AdditionalProperties != nil {
+		var allowed bool
+		if err := json.Unmarshal(*s.AdditionalProperties, &allowed); err == nil {
+			s.allowAdditional = allowed
+		} else {
+			var sub jsonSchema
+			if err := json.Unmarshal(*s.AdditionalProperties, &sub); err != nil {
+				return fmt.Errorf("additionalProperties must be a boolean or a schema")
+			}
+			s.additionalSchema = &sub
+		}
+	}
+
+	for _, prop := range s.Properties {
+		if err := prop.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(); err != nil {
+			return err
+		}
+	}
+	if s.additionalSchema != nil {
+		return s.additionalSchema.compile()
+	}
+	return nil
+}
+
+func (s *jsonSchema) validate(path string, value any, errs *[]string) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if len(s.Type) > 0 && !matchesAnyType(value, s.Type) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), jsonTypeOf(value))
+		return
+	}
+	if len(s.Enum) > 0 && !containsJSONValue(s.Enum, value) {
+		fail("value is not one of the allowed values")
+	}
+	if s.Const != nil && !jsonEqual(*s.Const, value) {
+		fail("value does not equal the constant")
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(path+"."+name, v[name], errs)
+			} else if s.additionalSchema != nil {
+				s.additionalSchema.validate(path+"."+name, v[name], errs)
+			} else if !s.allowAdditional {
+				fail("unexpected property %q", name)
+			}
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("expected at least %d items, got %d", *s.MinItems, len(v))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("expected at most %d items, got %d", *s.MaxItems, len(v))
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("expected length >= %d, got %d", *s.MinLength, length)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("expected length <= %d, got %d", *s.MaxLength, length)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("does not match pattern %q", s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("expected >= %v, got %v", *s.Minimum, v)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("expected <= %v, got %v", *s.Maximum, v)
+		}
+	}
+}
+
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func matchesAnyType(value any, types []string) bool {
+	actual := jsonTypeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsJSONValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if jsonEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonEqual(a, b any) bool {
+	left, errA := json.Marshal(a)
+	right, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(left, right)
+}
@@ -0,0 +1,206 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+)
+
+const testPurchaseSchema = `{
+	"type": "object",
+	"required": ["orderId", "amount"],
+	"additionalProperties": false,
+	"properties": {
+		"orderId": {"type": "string", "pattern": "^ord_"},
+		"amount": {"type": "number", "minimum": 0},
+		"currency": {"enum": ["USD", "EUR"]},
+		"items": {"type": "array", "maxItems": 2, "items": {"type": "integer"}}
+	}
+}`
+
+func TestSchemaValidator_Register(t *testing.T) {
+	t.Run("should reject invalid schemas", func(t *testing.T) {
+		v := NewSchemaValidator(SchemaModeStrict)
+		if err := v.Register("purchase", "", []byte(`{`)); err == nil {
+			t.Error("expected error for malformed JSON")
+		}
+		if err := v.Register("purchase", "", []byte(`{"pattern": "("}`)); err == nil {
+			t.Error("expected error for invalid pattern")
+		}
+		if err := v.Register("", "", []byte(`{}`)); err == nil {
+			t.Error("expected error for empty event name")
+		}
+	})
+}
+
+func TestSchemaValidator_Validate(t *testing.T) {
+	v := NewSchemaValidator(SchemaModeStrict)
+	if err := v.Register("purchase", "", []byte(testPurchaseSchema)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("should accept conforming payloads", func(t *testing.T) {
+		event := &Event{Name: "purchase", Payload: map[string]any{
+			"orderId": "ord_1", "amount": 10, "currency": "USD", "items": []int{1, 2},
+		}}
+		if err := v.Validate(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should report every violation", func(t *testing.T) {
+		event := &Event{Name: "purchase", Payload: map[string]any{
+			"orderId": "1", "currency": "GBP", "items": []any{1, 2, 3.5}, "extra": true,
+		}}
+		err := v.Validate(event)
+		var schemaErr *SchemaValidationError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("expected SchemaValidationError, got %v", err)
+		}
+		// missing amount, pattern, enum, maxItems, item type, additional property
+		if len(schemaErr.Errors) != 6 {
+			t.Fatalf("expected 6 errors, got %d: %v", len(schemaErr.Errors), schemaErr.Errors)
+		}
+	})
+
+	t.Run("should ignore events without a schema", func(t *testing.T) {
+		if err := v.Validate(&Event{Name: "unknown", Payload: map[string]any{"x": 1}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should select the schema by schemaVersion metadata", func(t *testing.T) {
+		versioned := NewSchemaValidator(SchemaModeStrict)
+		_ = versioned.Register("signup", "1.0.0", []byte(`{"required": ["email"]}`))
+		_ = versioned.Register("signup", "2.0.0", []byte(`{"required": ["phone"]}`))
+
+		event := &Event{Name: "signup", Payload: map[string]any{"email": "a@b.c"}, Metadata: map[string]any{"schemaVersion": "1.0.0"}}
+		if err := versioned.Validate(event); err != nil {
+			t.Fatalf("unexpected error for v1: %v", err)
+		}
+		event.Metadata["schemaVersion"] = "2.0.0"
+		if err := versioned.Validate(event); err == nil {
+			t.Fatal("expected error for v2")
+		}
+		event.Metadata["schemaVersion"] = "3.0.0"
+		if err := versioned.Validate(event); err != nil {
+			t.Fatalf("expected no schema for unregistered version, got %v", err)
+		}
+	})
+}
+
+func TestClient_SchemaValidation(t *testing.T) {
+	t.Run("should reject non-conforming events in strict mode", func(t *testing.T) {
+		validator := NewSchemaValidator(SchemaModeStrict)
+		_ = validator.Register("purchase", "", []byte(testPurchaseSchema))
+
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		config.SchemaValidator = validator
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		err := client.Track("purchase", map[string]any{"orderId": "ord_1"}, nil)
+		var schemaErr *SchemaValidationError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("expected SchemaValidationError, got %v", err)
+		}
+		if len(storage.getSaved()) != 0 {
+			t.Fatal("expected event to be rejected")
+		}
+	})
+
+	t.Run("should flag non-conforming events in lenient mode", func(t *testing.T) {
+		validator := NewSchemaValidator(SchemaModeLenient)
+		_ = validator.Register("purchase", "", []byte(testPurchaseSchema))
+
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		config.SchemaValidator = validator
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("purchase", map[string]any{"orderId": "ord_1"}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		saved := storage.getSaved()
+		if len(saved) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(saved))
+		}
+		if errs, ok := saved[0].Metadata["schemaErrors"].([]string); !ok || len(errs) != 1 {
+			t.Fatalf("expected one schema error in metadata, got %v", saved[0].Metadata["schemaErrors"])
+		}
+	})
+
+	t.Run("should only log non-conforming events in warn mode", func(t *testing.T) {
+		validator := NewSchemaValidator(SchemaModeWarn)
+		_ = validator.Register("purchase", "", []byte(testPurchaseSchema))
+
+		logger := &mockLogger{}
+		config := createTestConfig()
+		config.LoggerAdapter = logger
+		config.SchemaValidator = validator
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("purchase", map[string]any{"orderId": "ord_1"}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		event := client.dispatcher.queue.ToSlice()[0]
+		if _, ok := event.Metadata["schemaErrors"]; ok {
+			t.Fatal("expected no schemaErrors metadata in warn mode")
+		}
+		if logger.warnCount != 1 {
+			t.Fatalf("expected 1 warning, got %d", logger.warnCount)
+		}
+	})
+}
+
+func TestSchemaValidator_RegisterProperties(t *testing.T) {
+	validator := NewSchemaValidator(SchemaModeStrict)
+	err := validator.RegisterProperties("purchase_completed", "",
+		PropertySpec{Name: "orderId", Type: PropertyString, Required: true},
+		PropertySpec{Name: "amount", Type: PropertyNumber, Required: true},
+		PropertySpec{Name: "items", Type: PropertyInteger},
+		PropertySpec{Name: "extra"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("should accept conforming payloads and unlisted properties", func(t *testing.T) {
+		event := &Event{Name: "purchase_completed", Payload: map[string]any{
+			"orderId": "ord_1", "amount": 9.5, "items": 2, "extra": []int{1}, "note": "x",
+		}}
+		if err := validator.Validate(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should report missing and mistyped properties", func(t *testing.T) {
+		event := &Event{Name: "purchase_completed", Payload: map[string]any{
+			"amount": "9.5", "items": 2.5,
+		}}
+		var schemaErr *SchemaValidationError
+		if err := validator.Validate(event); !errors.As(err, &schemaErr) || len(schemaErr.Errors) != 3 {
+			t.Fatalf("expected 3 violations, got %v", err)
+		}
+	})
+
+	t.Run("should reject invalid specs", func(t *testing.T) {
+		specs := [][]PropertySpec{
+			{{Name: ""}},
+			{{Name: "a"}, {Name: "a"}},
+			{{Name: "a", Type: "date"}},
+		}
+		for _, spec := range specs {
+			if err := validator.RegisterProperties("e", "", spec...); err == nil {
+				t.Fatalf("expected an error for %+v", spec)
+			}
+		}
+		if err := validator.RegisterProperties("", ""); err == nil {
+			t.Fatal("expected an error for an empty event name")
+		}
+	})
+}
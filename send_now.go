@@ -0,0 +1,108 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// errDisposed is returned by TrackNow after Dispose.
+	errDisposed = errors.New("client has been disposed")
+
+	// errSendPaused is returned by TrackNow while the server's backoff
+	// pauses sending.
+	errSendPaused = errors.New("sending is paused by server backoff")
+
+	// errRateLimited is returned by TrackNow when RateLimitSpill finds the
+	// rate limit reached.
+	errRateLimited = errors.New("rate limit reached")
+)
+
+// TrackNow tracks an event like Track but bypasses the queue: the event is
+// sent on its own in a single attempt and the delivery error, if any, is
+// returned. Failed events are not retried or persisted, so the caller decides
+// whether to fall back to Track.
+//
+// The send honors the rate limit, waiting for a slot until ctx is done, and
+// fails without sending while the server has asked the client to back off.
+// Events dropped by sampling or BeforeSend hooks return nil without sending.
+func (c *Client) TrackNow(ctx context.Context, name string, payload, metadata map[string]any) error {
+	if c.disposed {
+		return errDisposed
+	}
+	event, err := c.buildEvent(name, payload, metadata)
+	if event == nil {
+		return err
+	}
+
+	c.loggerAdapter.Debug("Sending event immediately: %s", name)
+	return c.dispatcher.SendNow(ctx, *event)
+}
+
+// SendNow sends event as a single-event batch, synchronously and without
+// retries, and returns the delivery error. A non-2xx response is returned as
+// an *HTTPError. Like a flush, it waits for the rate limit and does not send
+// while the server's backoff pauses sending.
+func (d *Dispatcher) SendNow(ctx context.Context, event Event) error {
+	d.mu.Lock()
+	disposed := d.disposed
+	d.mu.Unlock()
+	if disposed {
+		return errDisposed
+	}
+	if remaining := d.backoffRemaining(); remaining > 0 {
+		return fmt.Errorf("%w for %v", errSendPaused, remaining)
+	}
+	if !d.acquireSendSlot(ctx) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return errRateLimited
+	}
+
+	d.mu.Lock()
+	if d.disposed {
+		d.mu.Unlock()
+		return errDisposed
+	}
+	d.stampSequence(&event)
+	d.stampEventID(&event)
+	d.mu.Unlock()
+	d.stats.trackEvent()
+	d.tap.publish(event)
+
+	events := []Event{event}
+	sentAt := d.clock.Now()
+	spanCtx, span := d.tracer.Start(ctx, sendSpanName)
+	span.SetAttribute("batch.size", 1)
+	batchID := newUUID()
+	span.SetAttribute("batch.id", batchID)
+	endpoint, headers := d.route(events, sentAt, batchID)
+	resp, err := d.send(spanCtx, Batch{
+		ID:       batchID,
+		Endpoint: endpoint,
+		Events:   events,
+		Headers:  headers,
+		SentAt:   sentAt,
+	})
+	if err == nil {
+		span.SetAttribute("http.status", resp.Status)
+		if resp.Status < 200 || resp.Status >= 300 || len(rejectedEvents(resp, 1)) > 0 {
+			err = attemptHTTPError(resp, endpoint, 0)
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+	d.recordEndpointResult(endpoint, resp, err)
+
+	if err != nil {
+		d.batchFailed(events, err)
+		return err
+	}
+	d.latency.record(events, sentAt)
+	d.batchDelivered(events)
+	return nil
+}
@@ -0,0 +1,164 @@
+package ripple
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClient_TrackNow(t *testing.T) {
+	t.Run("should send a single event without queueing it", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("queued", nil, nil)
+		if err := client.TrackNow(context.Background(), "urgent", map[string]any{"id": 1}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 send, got %d", httpAdapter.getCalls())
+		}
+		stats := client.Stats()
+		if stats.QueueLength != 1 || stats.EventsSent != 1 {
+			t.Fatalf("expected queued event untouched, got %+v", stats)
+		}
+		if saved := storage.getSaved(); len(saved) != 1 || saved[0].Name != "queued" {
+			t.Fatalf("expected storage to hold only the queued event, got %+v", saved)
+		}
+	})
+
+	t.Run("should return the delivery error without retrying", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{fail: true, statusCode: 503}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		err := client.TrackNow(context.Background(), "urgent", nil, nil)
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != 503 {
+			t.Fatalf("expected HTTPError 503, got %v", err)
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected a single attempt, got %d", httpAdapter.getCalls())
+		}
+		if stats := client.Stats(); stats.QueueLength != 0 || stats.BatchesFailed != 1 {
+			t.Fatalf("expected failed event not to be queued, got %+v", stats)
+		}
+	})
+
+	t.Run("should return network errors", func(t *testing.T) {
+		netErr := errors.New("connection refused")
+		config := createTestConfig()
+		config.HTTPAdapter = &mockHTTPAdapter{err: netErr}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.TrackNow(context.Background(), "urgent", nil, nil); !errors.Is(err, netErr) {
+			t.Fatalf("expected network error, got %v", err)
+		}
+	})
+
+	t.Run("should apply metadata and before-send hooks", func(t *testing.T) {
+		var sent *Event
+		config := createTestConfig()
+		config.BeforeSend = []BeforeSendHook{func(e *Event) *Event {
+			sent = e
+			return e
+		}}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.SetMetadata("shared", "yes")
+		if err := client.TrackNow(context.Background(), "urgent", nil, map[string]any{"local": 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sent == nil || sent.Metadata["shared"] != "yes" || sent.Metadata["local"] != 1 {
+			t.Fatalf("unexpected event: %+v", sent)
+		}
+	})
+
+	t.Run("should honor the rate limit", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.MaxRequestsPerSecond = 0.001
+		config.RateLimitMode = RateLimitSpill
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.TrackNow(context.Background(), "first", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.TrackNow(context.Background(), "second", nil, nil); !errors.Is(err, errRateLimited) {
+			t.Fatalf("expected the rate limit error, got %v", err)
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected 1 send within the burst, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should stop waiting for the rate limit when ctx is done", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		config.MaxRequestsPerSecond = 0.001
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.TrackNow(context.Background(), "first", nil, nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := client.TrackNow(ctx, "second", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the deadline error, got %v", err)
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected the second event not to be sent, got %d sends", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should not send while the server asks to back off", func(t *testing.T) {
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.dispatcher.mu.Lock()
+		client.dispatcher.pausedUntil = time.Now().Add(time.Minute)
+		client.dispatcher.mu.Unlock()
+
+		if err := client.TrackNow(context.Background(), "urgent", nil, nil); !errors.Is(err, errSendPaused) {
+			t.Fatalf("expected the backoff error, got %v", err)
+		}
+		if httpAdapter.getCalls() != 0 {
+			t.Fatalf("expected no send, got %d", httpAdapter.getCalls())
+		}
+	})
+
+	t.Run("should fail after dispose", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+		client.Dispose()
+
+		if err := client.TrackNow(context.Background(), "urgent", nil, nil); err == nil {
+			t.Fatal("expected error after dispose")
+		}
+	})
+
+	t.Run("should reject empty names", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if err := client.TrackNow(context.Background(), "", nil, nil); err == nil {
+			t.Fatal("expected error for empty name")
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package ripple
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newProducerID returns a random identifier for a producer instance.
+func newProducerID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// stampSequence assigns the next sequence number to event, unless it already
+// carries one (e.g. a restored event). Must be called with d.mu held.
+func (d *Dispatcher) stampSequence(event *Event) {
+	if !d.config.SequenceNumbers || event.Sequence != 0 {
+		return
+	}
+	d.sequence++
+	event.ProducerID = d.config.ProducerID
+	event.Sequence = d.sequence
+}
+
+// restoreSequence resumes the counter from the SequenceStore, if any, and from
+// the highest sequence among restored events of this producer.
+func (d *Dispatcher) restoreSequence(events []Event) {
+	if !d.config.SequenceNumbers {
+		return
+	}
+
+	var seq uint64
+	if store, ok := d.storageAdapter.(SequenceStore); ok {
+		loaded, err := store.LoadSequence(d.config.ProducerID)
+		if err != nil {
+			d.logStorageError("Failed to load sequence number", err, nil)
+		}
+		seq = loaded
+	}
+	for _, event := range events {
+		if event.ProducerID == d.config.ProducerID && event.Sequence > seq {
+			seq = event.Sequence
+		}
+	}
+
+	d.mu.Lock()
+	if seq > d.sequence {
+		d.sequence = seq
+		d.savedSequence = seq
+	}
+	d.mu.Unlock()
+}
+
+// saveSequence persists the counter to the SequenceStore if it advanced
+// since the last save.
+func (d *Dispatcher) saveSequence() {
+	if !d.config.SequenceNumbers {
+		return
+	}
+	store, ok := d.storageAdapter.(SequenceStore)
+	if !ok {
+		return
+	}
+
+	d.mu.Lock()
+	seq := d.sequence
+	saved := d.savedSequence
+	d.mu.Unlock()
+	if seq <= saved {
+		return
+	}
+
+	if err := store.SaveSequence(d.config.ProducerID, seq); err != nil {
+		d.logStorageError("Failed to persist sequence number", err, nil)
+		return
+	}
+
+	d.mu.Lock()
+	if seq > d.savedSequence {
+		d.savedSequence = seq
+	}
+	d.mu.Unlock()
+}
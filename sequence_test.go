@@ -0,0 +1,149 @@
+package ripple
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceStorageAdapter is a mockStorageAdapter that also implements SequenceStore.
+type sequenceStorageAdapter struct {
+	mockStorageAdapter
+	seqMu     sync.Mutex
+	sequences map[string]uint64
+}
+
+func (s *sequenceStorageAdapter) LoadSequence(producerID string) (uint64, error) {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+	return s.sequences[producerID], nil
+}
+
+func (s *sequenceStorageAdapter) SaveSequence(producerID string, seq uint64) error {
+	s.seqMu.Lock()
+	defer s.seqMu.Unlock()
+	if s.sequences == nil {
+		s.sequences = map[string]uint64{}
+	}
+	s.sequences[producerID] = seq
+	return nil
+}
+
+// withSequenceNumbers enables sequence numbers for producerID and allows
+// batches of up to 100 events.
+func withSequenceNumbers(producerID string) func(*DispatcherConfig) {
+	return func(c *DispatcherConfig) {
+		c.MaxBatchSize = 100
+		c.SequenceNumbers = true
+		c.ProducerID = producerID
+	}
+}
+
+func TestDispatcher_SequenceNumbers(t *testing.T) {
+	t.Run("should not stamp events when disabled", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			Endpoint:      "http://test.com",
+			FlushInterval: time.Hour,
+			MaxBatchSize:  100,
+		}, &mockHTTPAdapter{}, storage, &mockLogger{})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+
+		saved := storage.getSaved()
+		if saved[0].Sequence != 0 || saved[0].ProducerID != "" {
+			t.Fatalf("expected no sequence, got %d/%q", saved[0].Sequence, saved[0].ProducerID)
+		}
+	})
+
+	t.Run("should assign monotonic sequence numbers", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers("producer-1"))
+		d.Restore()
+		defer d.Dispose()
+
+		for i := 0; i < 3; i++ {
+			d.Enqueue(Event{Name: "a"})
+		}
+
+		saved := storage.getSaved()
+		for i, event := range saved {
+			if event.Sequence != uint64(i+1) {
+				t.Errorf("expected sequence %d, got %d", i+1, event.Sequence)
+			}
+			if event.ProducerID != "producer-1" {
+				t.Errorf("expected producer-1, got %q", event.ProducerID)
+			}
+		}
+	})
+
+	t.Run("should generate a producer id when not set", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers(""))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+
+		if storage.getSaved()[0].ProducerID == "" {
+			t.Fatal("expected generated producer id")
+		}
+	})
+
+	t.Run("should resume from restored events", func(t *testing.T) {
+		storage := &mockStorageAdapter{loaded: []Event{
+			{Name: "a", ProducerID: "producer-1", Sequence: 7},
+			{Name: "b", ProducerID: "other", Sequence: 42},
+		}}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers("producer-1"))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "c"})
+
+		saved := storage.getSaved()
+		if last := saved[len(saved)-1]; last.Sequence != 8 {
+			t.Fatalf("expected sequence 8, got %d", last.Sequence)
+		}
+	})
+
+	t.Run("should persist the counter across restarts via SequenceStore", func(t *testing.T) {
+		storage := &sequenceStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers("producer-1"))
+		d.Restore()
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+		d.Dispose()
+
+		if seq, _ := storage.LoadSequence("producer-1"); seq != 2 {
+			t.Fatalf("expected stored sequence 2, got %d", seq)
+		}
+
+		storage.loaded = nil
+		d = newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers("producer-1"))
+		d.Restore()
+		defer d.Dispose()
+		d.Enqueue(Event{Name: "c"})
+
+		if got := storage.getSaved()[0].Sequence; got != 3 {
+			t.Fatalf("expected sequence 3 after restart, got %d", got)
+		}
+	})
+
+	t.Run("should keep existing sequence numbers", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, withSequenceNumbers("producer-1"))
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a", ProducerID: "producer-1", Sequence: 99})
+
+		if got := storage.getSaved()[0].Sequence; got != 99 {
+			t.Fatalf("expected sequence 99, got %d", got)
+		}
+	})
+}
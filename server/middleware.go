@@ -0,0 +1,98 @@
+package server
+
+import (
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a collector handler with cross-cutting behavior.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middleware so that the first one is outermost.
+func Chain(handler http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
+
+// RequireAPIKey rejects requests whose header does not carry one of keys
+// with 401 Unauthorized.
+func RequireAPIKey(header string, keys ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := []byte(r.Header.Get(header))
+			for _, key := range keys {
+				if subtle.ConstantTimeCompare(provided, []byte(key)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			writeError(w, http.StatusUnauthorized, "invalid api key")
+		})
+	}
+}
+
+// MaxBodyBytes rejects request bodies larger than limit with
+// 413 Request Entity Too Large.
+func MaxBodyBytes(limit int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RateLimit rejects requests beyond requestsPerSecond (with the given burst)
+// with 429 Too Many Requests. A burst below 1 defaults to
+// max(1, ceil(requestsPerSecond)).
+func RateLimit(requestsPerSecond float64, burst int) Middleware {
+	limiter := newLimiter(requestsPerSecond, burst)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.allow() {
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limiter is a token bucket shared by all requests through RateLimit.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	b := float64(burst)
+	if burst < 1 {
+		b = math.Max(1, math.Ceil(rate))
+	}
+	return &limiter{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+func (l *limiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
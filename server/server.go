@@ -0,0 +1,161 @@
+// Package server provides an embeddable Ripple event collector.
+//
+// A collector accepts the SDK wire format ({"events": [...]}, or NDJSON with
+// the application/x-ndjson content type) over HTTP and hands each batch to a
+// Sink. Sinks and middleware are assembled from the
+// existing adapter primitives, so a production-grade collector can be built
+// without writing transport code.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// Config is the typed configuration for a collector.
+type Config struct {
+	// Sink receives every accepted batch.
+	//
+	// Required.
+	Sink Sink
+
+	// APIKeyHeader is the header checked against APIKeys.
+	//
+	// Default: "X-API-Key".
+	APIKeyHeader string
+
+	// APIKeys lists accepted API keys.
+	//
+	// Optional: If empty, requests are not authenticated.
+	APIKeys []string
+
+	// MaxBodyBytes caps the size of a request body.
+	//
+	// Optional: If not set or 0, no limit is applied.
+	MaxBodyBytes int64
+
+	// MaxRequestsPerSecond caps the accepted request rate.
+	//
+	// Optional: If not set or 0, no limit is applied.
+	MaxRequestsPerSecond float64
+
+	// RateLimitBurst is the number of requests accepted back-to-back.
+	//
+	// Default: MaxRequestsPerSecond rounded up, at least 1.
+	RateLimitBurst int
+
+	// Middleware is applied inside the built-in auth, size, and rate-limit
+	// middleware, in order.
+	//
+	// Optional.
+	Middleware []Middleware
+}
+
+// batchPayload is the SDK wire format.
+type batchPayload struct {
+	Events []adapters.Event `json:"events"`
+}
+
+// New builds a collector handler from config.
+func New(config Config) (http.Handler, error) {
+	if config.Sink == nil {
+		return nil, errors.New("sink is required")
+	}
+	if config.MaxBodyBytes < 0 {
+		return nil, errors.New("max body bytes must be a positive number")
+	}
+	if config.MaxRequestsPerSecond < 0 {
+		return nil, errors.New("max requests per second must be a positive number")
+	}
+
+	header := config.APIKeyHeader
+	if header == "" {
+		header = "X-API-Key"
+	}
+
+	var middleware []Middleware
+	if config.MaxRequestsPerSecond > 0 {
+		middleware = append(middleware, RateLimit(config.MaxRequestsPerSecond, config.RateLimitBurst))
+	}
+	if len(config.APIKeys) > 0 {
+		middleware = append(middleware, RequireAPIKey(header, config.APIKeys...))
+	}
+	if config.MaxBodyBytes > 0 {
+		middleware = append(middleware, MaxBodyBytes(config.MaxBodyBytes))
+	}
+	middleware = append(middleware, config.Middleware...)
+
+	return Chain(NewHandler(config.Sink), middleware...), nil
+}
+
+// NewHandler returns a bare collector handler that decodes batches and writes
+// them to sink, without any middleware.
+func NewHandler(sink Sink) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		events, err := decodeEvents(r)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			writeError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+
+		if len(events) > 0 {
+			if err := sink.Write(r.Context(), events); err != nil {
+				writeError(w, http.StatusInternalServerError, "failed to write events")
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{
+			"success":  true,
+			"received": len(events),
+		})
+	})
+}
+
+// decodeEvents reads the events of a request sent either in the
+// {"events": [...]} envelope or as NDJSON, one event per line.
+func decodeEvents(r *http.Request) ([]adapters.Event, error) {
+	dec := json.NewDecoder(r.Body)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != adapters.NDJSONContentType {
+		var payload batchPayload
+		err := dec.Decode(&payload)
+		return payload.Events, err
+	}
+
+	var events []adapters.Event
+	for {
+		var event adapters.Event
+		if err := dec.Decode(&event); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func post(handler http.Handler, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should require a sink", func(t *testing.T) {
+		if _, err := New(Config{}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("should reject negative limits", func(t *testing.T) {
+		sink := NewStorageSink(&memoryStorage{})
+		if _, err := New(Config{Sink: sink, MaxBodyBytes: -1}); err == nil {
+			t.Fatal("expected error for negative max body bytes")
+		}
+		if _, err := New(Config{Sink: sink, MaxRequestsPerSecond: -1}); err == nil {
+			t.Fatal("expected error for negative max requests per second")
+		}
+	})
+
+	t.Run("should accept batches and write them to the sink", func(t *testing.T) {
+		storage := &memoryStorage{}
+		handler, err := New(Config{Sink: NewStorageSink(storage)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rec := post(handler, `{"events":[{"name":"a"},{"name":"b"}]}`, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"received":2`) {
+			t.Fatalf("unexpected body: %s", rec.Body.String())
+		}
+		if stored, _ := storage.Load(); len(stored) != 2 {
+			t.Fatalf("expected 2 stored events, got %d", len(stored))
+		}
+	})
+
+	t.Run("should accept NDJSON batches", func(t *testing.T) {
+		storage := &memoryStorage{}
+		handler, err := New(Config{Sink: NewStorageSink(storage)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rec := post(handler, "{\"name\":\"a\"}\n{\"name\":\"b\"}\n", map[string]string{
+			"Content-Type": adapters.NDJSONContentType,
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if stored, _ := storage.Load(); len(stored) != 2 || stored[1].Name != "b" {
+			t.Fatalf("expected 2 stored events, got %+v", stored)
+		}
+
+		rec = post(handler, "{\"name\":\"a\"}\nnot json\n", map[string]string{
+			"Content-Type": adapters.NDJSONContentType,
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for an invalid line, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should reject invalid JSON", func(t *testing.T) {
+		handler, _ := New(Config{Sink: NewStorageSink(&memoryStorage{})})
+		if rec := post(handler, `{`, nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should reject non-POST requests", func(t *testing.T) {
+		handler, _ := New(Config{Sink: NewStorageSink(&memoryStorage{})})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("expected 405, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should return 500 when the sink fails", func(t *testing.T) {
+		sink := SinkFunc(func(context.Context, []adapters.Event) error { return errors.New("boom") })
+		handler, _ := New(Config{Sink: sink})
+		if rec := post(handler, `{"events":[{"name":"a"}]}`, nil); rec.Code != http.StatusInternalServerError {
+			t.Fatalf("expected 500, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should enforce api keys", func(t *testing.T) {
+		handler, _ := New(Config{
+			Sink:         NewStorageSink(&memoryStorage{}),
+			APIKeyHeader: "X-Ripple-Key",
+			APIKeys:      []string{"secret"},
+		})
+		if rec := post(handler, `{"events":[]}`, nil); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", rec.Code)
+		}
+		if rec := post(handler, `{"events":[]}`, map[string]string{"X-Ripple-Key": "secret"}); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should enforce max body bytes", func(t *testing.T) {
+		handler, _ := New(Config{Sink: NewStorageSink(&memoryStorage{}), MaxBodyBytes: 16})
+		if rec := post(handler, `{"events":[{"name":"too-long"}]}`, nil); rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("expected 413, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should enforce the rate limit", func(t *testing.T) {
+		handler, _ := New(Config{
+			Sink:                 NewStorageSink(&memoryStorage{}),
+			MaxRequestsPerSecond: 1,
+			RateLimitBurst:       1,
+		})
+		if rec := post(handler, `{"events":[]}`, nil); rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		if rec := post(handler, `{"events":[]}`, nil); rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("expected 429, got %d", rec.Code)
+		}
+	})
+
+	t.Run("should apply custom middleware", func(t *testing.T) {
+		called := false
+		handler, _ := New(Config{
+			Sink: NewStorageSink(&memoryStorage{}),
+			Middleware: []Middleware{func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					called = true
+					next.ServeHTTP(w, r)
+				})
+			}},
+		})
+		post(handler, `{"events":[]}`, nil)
+		if !called {
+			t.Fatal("expected custom middleware to run")
+		}
+	})
+}
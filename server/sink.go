@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+// Sink receives batches of events accepted by the collector.
+type Sink interface {
+	// Write delivers a batch. A returned error is reported to the client
+	// as a 5xx so that it retries.
+	Write(ctx context.Context, events []adapters.Event) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(ctx context.Context, events []adapters.Event) error
+
+// Write calls f(ctx, events).
+func (f SinkFunc) Write(ctx context.Context, events []adapters.Event) error {
+	return f(ctx, events)
+}
+
+// StorageSink appends every batch to a StorageAdapter.
+type StorageSink struct {
+	storage adapters.StorageAdapter
+	mu      sync.Mutex
+}
+
+// NewStorageSink creates a sink that persists events via storage.
+func NewStorageSink(storage adapters.StorageAdapter) *StorageSink {
+	return &StorageSink{storage: storage}
+}
+
+// Write loads the stored events, appends the batch, and saves the result.
+func (s *StorageSink) Write(ctx context.Context, events []adapters.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.storage.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load stored events: %w", err)
+	}
+	if err := s.storage.Save(append(stored, events...)); err != nil {
+		return fmt.Errorf("failed to save events: %w", err)
+	}
+	return nil
+}
+
+// ForwardSink relays every batch to another endpoint through an HTTPAdapter.
+type ForwardSink struct {
+	http     adapters.HTTPAdapter
+	endpoint string
+	headers  map[string]string
+}
+
+// NewForwardSink creates a sink that forwards batches to endpoint.
+func NewForwardSink(httpAdapter adapters.HTTPAdapter, endpoint string, headers map[string]string) *ForwardSink {
+	return &ForwardSink{http: httpAdapter, endpoint: endpoint, headers: headers}
+}
+
+// Write forwards the batch and fails on any non-2xx response.
+func (f *ForwardSink) Write(ctx context.Context, events []adapters.Event) error {
+	resp, err := f.http.SendWithContext(ctx, f.endpoint, events, f.headers)
+	if err != nil {
+		return fmt.Errorf("failed to forward events: %w", err)
+	}
+	if resp == nil || resp.Status < 200 || resp.Status >= 300 {
+		status := 0
+		if resp != nil {
+			status = resp.Status
+		}
+		return fmt.Errorf("forward endpoint responded with status %d", status)
+	}
+	return nil
+}
+
+// Publisher is an interface for message brokers (Kafka, NATS, etc.).
+// Implement it with your broker client to use PublishSink.
+type Publisher interface {
+	// Publish sends events to the given topic.
+	Publish(ctx context.Context, topic string, events []adapters.Event) error
+}
+
+// PublishSink publishes every batch to a topic through a Publisher.
+type PublishSink struct {
+	publisher Publisher
+	topic     string
+}
+
+// NewPublishSink creates a sink that publishes batches to topic.
+func NewPublishSink(publisher Publisher, topic string) *PublishSink {
+	return &PublishSink{publisher: publisher, topic: topic}
+}
+
+// Write publishes the batch.
+func (p *PublishSink) Write(ctx context.Context, events []adapters.Event) error {
+	return p.publisher.Publish(ctx, p.topic, events)
+}
+
+// MultiSink writes every batch to all sinks and joins their errors.
+type MultiSink []Sink
+
+// Write delivers the batch to each sink in order.
+func (m MultiSink) Write(ctx context.Context, events []adapters.Event) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.Write(ctx, events); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
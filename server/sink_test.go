@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+type memoryStorage struct {
+	mu     sync.Mutex
+	events []adapters.Event
+}
+
+func (m *memoryStorage) Save(events []adapters.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append([]adapters.Event(nil), events...)
+	return nil
+}
+
+func (m *memoryStorage) Load() ([]adapters.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]adapters.Event(nil), m.events...), nil
+}
+
+func (m *memoryStorage) Clear() error { return m.Save(nil) }
+func (m *memoryStorage) Close() error { return nil }
+
+type recordingPublisher struct {
+	topic  string
+	events []adapters.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, topic string, events []adapters.Event) error {
+	p.topic = topic
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func TestStorageSink(t *testing.T) {
+	t.Run("should append batches to storage", func(t *testing.T) {
+		storage := &memoryStorage{}
+		sink := NewStorageSink(storage)
+
+		_ = sink.Write(context.Background(), []adapters.Event{{Name: "a"}})
+		_ = sink.Write(context.Background(), []adapters.Event{{Name: "b"}})
+
+		stored, _ := storage.Load()
+		if len(stored) != 2 || stored[0].Name != "a" || stored[1].Name != "b" {
+			t.Fatalf("expected [a b], got %+v", stored)
+		}
+	})
+}
+
+func TestForwardSink(t *testing.T) {
+	t.Run("should forward batches with headers", func(t *testing.T) {
+		var apiKey string
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey = r.Header.Get("X-API-Key")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+
+		sink := NewForwardSink(adapters.NewNetHTTPAdapter(), upstream.URL, map[string]string{"X-API-Key": "upstream"})
+		if err := sink.Write(context.Background(), []adapters.Event{{Name: "a"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if apiKey != "upstream" {
+			t.Fatalf("expected upstream api key, got %q", apiKey)
+		}
+	})
+
+	t.Run("should fail on non-2xx responses", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer upstream.Close()
+
+		sink := NewForwardSink(adapters.NewNetHTTPAdapter(), upstream.URL, nil)
+		if err := sink.Write(context.Background(), []adapters.Event{{Name: "a"}}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestPublishSink(t *testing.T) {
+	t.Run("should publish batches to the topic", func(t *testing.T) {
+		publisher := &recordingPublisher{}
+		sink := NewPublishSink(publisher, "events")
+
+		_ = sink.Write(context.Background(), []adapters.Event{{Name: "a"}})
+
+		if publisher.topic != "events" || len(publisher.events) != 1 {
+			t.Fatalf("unexpected publish: topic=%q events=%d", publisher.topic, len(publisher.events))
+		}
+	})
+}
+
+func TestMultiSink(t *testing.T) {
+	t.Run("should write to every sink and join errors", func(t *testing.T) {
+		storage := &memoryStorage{}
+		failing := SinkFunc(func(context.Context, []adapters.Event) error {
+			return errors.New("boom")
+		})
+
+		err := MultiSink{failing, NewStorageSink(storage)}.Write(context.Background(), []adapters.Event{{Name: "a"}})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if stored, _ := storage.Load(); len(stored) != 1 {
+			t.Fatalf("expected storage sink to still receive the batch, got %d", len(stored))
+		}
+	})
+}
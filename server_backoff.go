@@ -0,0 +1,58 @@
+package ripple
+
+import "time"
+
+// maxServerBackoff caps how long a single response can pause sending, so a
+// misbehaving server cannot stall the client indefinitely.
+const maxServerBackoff = 10 * time.Minute
+
+// applyServerBackoff pauses sending when a response asks for it through
+// HTTPResponse.Backoff (the adapters.BackoffHeader for NetHTTPAdapter). With
+// Endpoints, only the endpoint that answered is paused, like one that tripped
+// its failure threshold, and sending stops only once every endpoint is.
+func (d *Dispatcher) applyServerBackoff(endpoint string, resp *HTTPResponse) {
+	backoff := min(resp.Backoff, maxServerBackoff)
+	until := d.clock.Now().Add(backoff)
+	if d.pool != nil {
+		d.pool.Pause(endpoint, until)
+		until = d.pool.PausedUntil()
+		if until.IsZero() {
+			d.loggerAdapter.Warn("Server requested backoff, pausing endpoint", map[string]any{
+				"endpoint": endpoint,
+				"backoff":  backoff.String(),
+			})
+			return
+		}
+	}
+
+	d.mu.Lock()
+	if until.After(d.pausedUntil) {
+		d.pausedUntil = until
+	}
+	d.mu.Unlock()
+	d.loggerAdapter.Warn("Server requested backoff, pausing flushes", map[string]any{
+		"endpoint": endpoint,
+		"backoff":  backoff.String(),
+	})
+}
+
+// backoffRemaining returns how long sending stays paused by the server.
+func (d *Dispatcher) backoffRemaining() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pausedUntil.IsZero() {
+		return 0
+	}
+	return max(d.pausedUntil.Sub(d.clock.Now()), 0)
+}
+
+// deferForBackoff schedules the next flush for when a server-requested
+// pause ends. Returns false if sending is not paused.
+func (d *Dispatcher) deferForBackoff() bool {
+	remaining := d.backoffRemaining()
+	if remaining <= 0 {
+		return false
+	}
+	d.scheduleFlushIn(remaining)
+	return true
+}
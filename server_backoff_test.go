@@ -0,0 +1,92 @@
+package ripple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tap30/ripple-go/adapters"
+)
+
+func TestDispatcher_ServerBackoff(t *testing.T) {
+	newDispatcher := func(httpAdapter HTTPAdapter, endpoints ...string) *Dispatcher {
+		return NewDispatcher(DispatcherConfig{
+			APIKey:        "test-key",
+			APIKeyHeader:  "X-API-Key",
+			Endpoint:      "http://test.com",
+			Endpoints:     endpoints,
+			FlushInterval: 10 * time.Second,
+			MaxBatchSize:  10,
+			MaxRetries:    3,
+		}, httpAdapter, &mockStorageAdapter{}, &mockLogger{})
+	}
+
+	t.Run("should pause flushes after a successful response asking for backoff", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 200, Backoff: 100 * time.Millisecond},
+			adapters.Scenario{Status: 200},
+		)
+		d := newDispatcher(httpAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+		d.Enqueue(Event{Name: "b"})
+		d.Flush()
+
+		if got := httpAdapter.Calls(); got != 1 {
+			t.Fatalf("expected no send while paused, got %d sends", got)
+		}
+		if stats := d.Stats(); stats.PausedFor <= 0 || stats.QueueLength != 1 {
+			t.Fatalf("expected a pause with b queued, got %+v", stats)
+		}
+
+		time.Sleep(150 * time.Millisecond)
+		d.Flush()
+		if got := httpAdapter.Calls(); got != 2 {
+			t.Fatalf("expected b to be sent after the pause, got %d sends", got)
+		}
+	})
+
+	t.Run("should re-queue rejected batches without retrying or failing them", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503, Backoff: time.Minute},
+		)
+		d := newDispatcher(httpAdapter)
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+
+		if got := httpAdapter.Calls(); got != 1 {
+			t.Fatalf("expected a single attempt, got %d", got)
+		}
+		stats := d.Stats()
+		if stats.BatchesFailed != 0 || stats.QueueLength != 1 {
+			t.Fatalf("expected the batch re-queued without failing, got %+v", stats)
+		}
+	})
+
+	t.Run("should move to another endpoint when one asks for backoff", func(t *testing.T) {
+		httpAdapter := adapters.NewScriptedHTTPAdapter(
+			adapters.Scenario{Status: 503, Backoff: time.Minute},
+			adapters.Scenario{Status: 200},
+		)
+		d := newDispatcher(httpAdapter, "http://a.test", "http://b.test")
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Flush()
+		d.Flush()
+
+		requests := httpAdapter.Requests()
+		if len(requests) != 2 || requests[0].Endpoint != "http://a.test" || requests[1].Endpoint != "http://b.test" {
+			t.Fatalf("unexpected requests %+v", requests)
+		}
+		if stats := d.Stats(); stats.PausedFor != 0 || stats.EventsSent != 1 {
+			t.Fatalf("expected delivery through b without a pause, got %+v", stats)
+		}
+	})
+}
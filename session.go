@@ -0,0 +1,89 @@
+package ripple
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSessionIdleTimeout = 30 * time.Minute
+)
+
+// SessionProvider supplies the session ID stamped onto each event, letting
+// long-running workers group events per run or per unit of work.
+type SessionProvider interface {
+	// SessionID returns the current session ID, or nil for no session.
+	// It is called once per tracked event.
+	SessionID() *string
+}
+
+// SessionProviderFunc adapts a function to the SessionProvider interface.
+type SessionProviderFunc func() *string
+
+// SessionID calls f().
+func (f SessionProviderFunc) SessionID() *string {
+	return f()
+}
+
+// IdleSessionProvider generates UUID session IDs that rotate after a period
+// of inactivity or once a session reaches its maximum lifetime.
+type IdleSessionProvider struct {
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+	now         func() time.Time
+
+	mu           sync.Mutex
+	id           string
+	startedAt    time.Time
+	lastActivity time.Time
+}
+
+// NewIdleSessionProvider creates a session provider. A non-positive
+// idleTimeout defaults to 30 minutes; a non-positive maxLifetime means
+// sessions only end through inactivity or Reset.
+func NewIdleSessionProvider(idleTimeout, maxLifetime time.Duration) *IdleSessionProvider {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultSessionIdleTimeout
+	}
+	return &IdleSessionProvider{
+		idleTimeout: idleTimeout,
+		maxLifetime: maxLifetime,
+		now:         time.Now,
+	}
+}
+
+// SessionID returns the current session ID, starting a new session if the
+// previous one expired, and records activity.
+func (p *IdleSessionProvider) SessionID() *string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	if p.id == "" || now.Sub(p.lastActivity) >= p.idleTimeout ||
+		(p.maxLifetime > 0 && now.Sub(p.startedAt) >= p.maxLifetime) {
+		p.id = newUUID()
+		p.startedAt = now
+	}
+	p.lastActivity = now
+
+	id := p.id
+	return &id
+}
+
+// Reset ends the current session; the next event starts a new one.
+func (p *IdleSessionProvider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.id = ""
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
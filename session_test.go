@@ -0,0 +1,84 @@
+package ripple
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func newTestSessionProvider(idle, lifetime time.Duration) (*IdleSessionProvider, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	p := NewIdleSessionProvider(idle, lifetime)
+	p.now = func() time.Time { return now }
+	return p, &now
+}
+
+func TestIdleSessionProvider(t *testing.T) {
+	t.Run("should return a stable UUID while active", func(t *testing.T) {
+		p, now := newTestSessionProvider(time.Minute, 0)
+
+		first := *p.SessionID()
+		*now = now.Add(50 * time.Second)
+		second := *p.SessionID()
+
+		if first != second {
+			t.Fatalf("expected the same session, got %s and %s", first, second)
+		}
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(first) {
+			t.Fatalf("expected a v4 UUID, got %s", first)
+		}
+	})
+
+	t.Run("should rotate after the idle timeout", func(t *testing.T) {
+		p, now := newTestSessionProvider(time.Minute, 0)
+
+		first := *p.SessionID()
+		*now = now.Add(time.Minute)
+		if *p.SessionID() == first {
+			t.Fatal("expected a new session after the idle timeout")
+		}
+	})
+
+	t.Run("should rotate after the max lifetime despite activity", func(t *testing.T) {
+		p, now := newTestSessionProvider(time.Minute, 2*time.Minute)
+
+		first := *p.SessionID()
+		for i := 0; i < 3; i++ {
+			*now = now.Add(45 * time.Second)
+			p.SessionID()
+		}
+		if *p.SessionID() == first {
+			t.Fatal("expected a new session after the max lifetime")
+		}
+	})
+
+	t.Run("should start a new session after Reset", func(t *testing.T) {
+		p, _ := newTestSessionProvider(time.Minute, 0)
+
+		first := *p.SessionID()
+		p.Reset()
+		if *p.SessionID() == first {
+			t.Fatal("expected a new session after Reset")
+		}
+	})
+}
+
+func TestClient_SessionProvider(t *testing.T) {
+	t.Run("should stamp the session ID onto events", func(t *testing.T) {
+		sessionID := "run-42"
+		config := createTestConfig()
+		config.SessionProvider = SessionProviderFunc(func() *string { return &sessionID })
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		client.Track("test_event", nil, nil)
+
+		event, _ := client.dispatcher.queue.Dequeue()
+		if event.SessionID == nil || *event.SessionID != "run-42" {
+			t.Fatalf("expected session run-42, got %v", event.SessionID)
+		}
+		if got := client.GetSessionId(); got == nil || *got != "run-42" {
+			t.Fatalf("expected GetSessionId to return run-42, got %v", got)
+		}
+	})
+}
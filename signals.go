@@ -0,0 +1,87 @@
+package ripple
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// shutdownSignals are the signals handled when HandleSignals is enabled.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// raiseSignal sends a handled signal to the current process. Overridden in
+// tests.
+var raiseSignal = defaultRaiseSignal
+
+// defaultRaiseSignal sends sig to the current process.
+func defaultRaiseSignal(sig os.Signal) error {
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		return err
+	}
+	return process.Signal(sig)
+}
+
+// handleSignals installs handlers for shutdownSignals and returns a function
+// that uninstalls them. Installing a handler clears a signal's ignored state,
+// so the signals ignored until then are recorded first and never raised.
+func (c *Client) handleSignals() func() {
+	c.ignoredSignals = make(map[os.Signal]bool)
+	for _, sig := range shutdownSignals {
+		if signal.Ignored(sig) {
+			c.ignoredSignals[sig] = true
+		}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, shutdownSignals...)
+
+	done := make(chan struct{})
+	go c.awaitSignal(sigCh, done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+		})
+	}
+}
+
+// awaitSignal closes the client when a signal arrives on sigCh, unless done
+// is closed first, and then hands the signal to OnShutdownSignal or raises it
+// again. Close uninstalls the handlers, so a raised signal gets the default
+// action. Signals the process ignored are not raised. Go cannot tell whether
+// the application also called signal.Notify, in which case it would receive
+// the raised signal a second time, so such applications set OnShutdownSignal.
+func (c *Client) awaitSignal(sigCh <-chan os.Signal, done <-chan struct{}) {
+	select {
+	case sig := <-sigCh:
+		c.loggerAdapter.Info("Received shutdown signal, closing client", map[string]any{
+			"signal": sig.String(),
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.config.ShutdownTimeout)
+		defer cancel()
+		if _, err := c.Close(ctx); err != nil {
+			c.loggerAdapter.Warn("Shutdown deadline exceeded before all events were sent", map[string]any{
+				"error": err.Error(),
+			})
+		}
+		switch {
+		case c.config.OnShutdownSignal != nil:
+			c.config.OnShutdownSignal(sig)
+		case c.ignoredSignals[sig]:
+		default:
+			if err := raiseSignal(sig); err != nil {
+				c.loggerAdapter.Warn("Failed to raise shutdown signal", map[string]any{
+					"signal": sig.String(),
+					"error":  err.Error(),
+				})
+			}
+		}
+	case <-done:
+	}
+}
@@ -0,0 +1,151 @@
+package ripple
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClient_HandleSignals(t *testing.T) {
+	t.Run("should close the client and raise the signal again", func(t *testing.T) {
+		raised := make(chan os.Signal, 1)
+		raiseSignal = func(sig os.Signal) error {
+			raised <- sig
+			return nil
+		}
+		defer func() { raiseSignal = defaultRaiseSignal }()
+
+		httpAdapter := &mockHTTPAdapter{}
+		config := createTestConfig()
+		config.HTTPAdapter = httpAdapter
+		client, _ := NewClient(config)
+		client.Init()
+		client.Track("a", nil, nil)
+
+		sigCh := make(chan os.Signal, 1)
+		go client.awaitSignal(sigCh, make(chan struct{}))
+		sigCh <- syscall.SIGTERM
+
+		select {
+		case sig := <-raised:
+			if sig != syscall.SIGTERM {
+				t.Fatalf("expected SIGTERM to be raised, got %v", sig)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the signal to be raised after closing")
+		}
+		if httpAdapter.getCalls() != 1 {
+			t.Fatalf("expected queued event to be flushed, got %d sends", httpAdapter.getCalls())
+		}
+		if !client.disposed {
+			t.Fatal("expected client to be disposed")
+		}
+	})
+
+	t.Run("should pass the signal to OnShutdownSignal instead of raising it", func(t *testing.T) {
+		raiseSignal = func(sig os.Signal) error {
+			t.Errorf("expected %v not to be raised", sig)
+			return nil
+		}
+		defer func() { raiseSignal = defaultRaiseSignal }()
+
+		handled := make(chan os.Signal, 1)
+		config := createTestConfig()
+		config.OnShutdownSignal = func(sig os.Signal) { handled <- sig }
+		client, _ := NewClient(config)
+		client.Init()
+
+		sigCh := make(chan os.Signal, 1)
+		go client.awaitSignal(sigCh, make(chan struct{}))
+		sigCh <- os.Interrupt
+
+		select {
+		case sig := <-handled:
+			if sig != os.Interrupt {
+				t.Fatalf("expected os.Interrupt, got %v", sig)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected OnShutdownSignal to be called")
+		}
+		if !client.disposed {
+			t.Fatal("expected client to be disposed")
+		}
+	})
+
+	t.Run("should not raise a signal the process ignored", func(t *testing.T) {
+		raiseSignal = func(sig os.Signal) error {
+			t.Errorf("expected ignored %v not to be raised", sig)
+			return nil
+		}
+		defer func() { raiseSignal = defaultRaiseSignal }()
+
+		client := createTestClient()
+		client.Init()
+		client.ignoredSignals = map[os.Signal]bool{syscall.SIGTERM: true}
+
+		sigCh := make(chan os.Signal, 1)
+		done := make(chan struct{})
+		go func() {
+			client.awaitSignal(sigCh, make(chan struct{}))
+			close(done)
+		}()
+		sigCh <- syscall.SIGTERM
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected the client to close")
+		}
+		if !client.disposed {
+			t.Fatal("expected client to be disposed")
+		}
+	})
+
+	t.Run("should record shutdown signals ignored before Init", func(t *testing.T) {
+		signal.Ignore(syscall.SIGTERM)
+		defer signal.Reset(syscall.SIGTERM)
+
+		config := createTestConfig()
+		config.HandleSignals = true
+		client, _ := NewClient(config)
+		client.Init()
+		defer client.Dispose()
+
+		if !client.ignoredSignals[syscall.SIGTERM] || client.ignoredSignals[os.Interrupt] {
+			t.Fatalf("expected only SIGTERM recorded as ignored, got %v", client.ignoredSignals)
+		}
+	})
+
+	t.Run("should stop waiting for signals on dispose", func(t *testing.T) {
+		config := createTestConfig()
+		config.HandleSignals = true
+		client, _ := NewClient(config)
+		client.Init()
+		if client.stopSignals == nil {
+			t.Fatal("expected signal handlers to be installed")
+		}
+
+		client.Dispose()
+		client.Dispose()
+	})
+
+	t.Run("should not install handlers by default", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+		defer client.Dispose()
+
+		if client.stopSignals != nil {
+			t.Fatal("expected no signal handlers")
+		}
+	})
+
+	t.Run("should reject negative shutdown timeout", func(t *testing.T) {
+		config := createTestConfig()
+		config.ShutdownTimeout = -time.Second
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error for negative shutdown timeout")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package ripple
+
+import (
+	"sync"
+	"time"
+)
+
+// statsRecorder accumulates dispatcher counters exposed through Stats.
+type statsRecorder struct {
+	mu            sync.Mutex
+	eventsTracked int64
+	batchesSent   int64
+	eventsSent    int64
+	batchesFailed int64
+	duplicates    int64
+	evicted       int64
+	rejected      int64
+	expired       int64
+	migrated      int64
+	lastFlush     time.Time
+	lastDelivery  time.Time
+	lastError     error
+	storageError  error
+	storageSize   int
+}
+
+func (s *statsRecorder) trackEvent() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventsTracked++
+}
+
+func (s *statsRecorder) batchSent(events int, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchesSent++
+	s.eventsSent += int64(events)
+	s.lastDelivery = at
+}
+
+func (s *statsRecorder) batchFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchesFailed++
+	s.lastError = err
+}
+
+func (s *statsRecorder) duplicatesDropped(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.duplicates += int64(n)
+}
+
+func (s *statsRecorder) eventsRejected(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rejected += int64(n)
+}
+
+func (s *statsRecorder) eventsExpired(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expired += int64(n)
+}
+
+func (s *statsRecorder) eventsMigrated(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.migrated += int64(n)
+}
+
+func (s *statsRecorder) storageEvicted(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evicted += int64(n)
+}
+
+func (s *statsRecorder) flushed(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastFlush = at
+}
+
+func (s *statsRecorder) setStorageSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storageSize = size
+	s.storageError = nil
+}
+
+func (s *statsRecorder) storageFailed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storageError = err
+}
+
+// snapshot copies the counters into a Stats value.
+func (s *statsRecorder) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Stats{
+		EventsTracked:     s.eventsTracked,
+		BatchesSent:       s.batchesSent,
+		EventsSent:        s.eventsSent,
+		BatchesFailed:     s.batchesFailed,
+		DuplicatesDropped: s.duplicates,
+		StorageEvicted:    s.evicted,
+		EventsRejected:    s.rejected,
+		EventsExpired:     s.expired,
+		EventsMigrated:    s.migrated,
+		LastFlushTime:     s.lastFlush,
+		LastDeliveryTime:  s.lastDelivery,
+		LastError:         s.lastError,
+		StorageError:      s.storageError,
+		StorageSize:       s.storageSize,
+	}
+}
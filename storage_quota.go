@@ -0,0 +1,85 @@
+package ripple
+
+import "sort"
+
+// StorageEvictionPolicy decides which events are dropped when saving the
+// queue would exceed MaxStorageEvents or MaxStorageBytes.
+type StorageEvictionPolicy int
+
+const (
+	// StorageEvictOldest drops the oldest events first.
+	StorageEvictOldest StorageEvictionPolicy = iota
+
+	// StorageEvictLowestPriority drops the events with the lowest
+	// EventPriority first, oldest first among equal priorities.
+	StorageEvictLowestPriority
+)
+
+// EventPriorityFunc ranks events for StorageEvictLowestPriority. Events with
+// lower values are evicted first.
+type EventPriorityFunc func(event Event) int
+
+// applyStorageQuota returns the events that fit within MaxStorageEvents and
+// MaxStorageBytes, in their original order, evicting per StorageEviction.
+func (d *Dispatcher) applyStorageQuota(events []Event) []Event {
+	maxEvents, maxBytes := d.config.MaxStorageEvents, d.config.MaxStorageBytes
+	if maxEvents <= 0 && maxBytes <= 0 {
+		return events
+	}
+
+	var sizes []int
+	total := batchEnvelopeBytes
+	if maxBytes > 0 {
+		sizes = make([]int, len(events))
+		for i := range events {
+			sizes[i], _ = eventSize(&events[i])
+			total += sizes[i]
+		}
+	}
+	count := len(events)
+	fits := func() bool {
+		return (maxEvents <= 0 || count <= maxEvents) && (maxBytes <= 0 || total <= maxBytes)
+	}
+	if fits() {
+		return events
+	}
+
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+	if d.config.StorageEviction == StorageEvictLowestPriority && d.config.EventPriority != nil {
+		priorities := make([]int, len(events))
+		for i, event := range events {
+			priorities[i] = d.config.EventPriority(event)
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return priorities[order[a]] < priorities[order[b]]
+		})
+	}
+
+	evicted := make(map[int]struct{})
+	for _, i := range order {
+		if fits() {
+			break
+		}
+		evicted[i] = struct{}{}
+		count--
+		if sizes != nil {
+			total -= sizes[i]
+		}
+	}
+
+	kept := make([]Event, 0, count)
+	for i, event := range events {
+		if _, ok := evicted[i]; !ok {
+			kept = append(kept, event)
+		}
+	}
+	d.stats.storageEvicted(len(evicted))
+	d.loggerAdapter.Warn("Storage quota exceeded, evicting events", map[string]any{
+		"evicted":  len(evicted),
+		"retained": len(kept),
+	})
+	return kept
+}
@@ -0,0 +1,93 @@
+package ripple
+
+import "testing"
+
+func savedNames(storage *mockStorageAdapter) []string {
+	var names []string
+	for _, event := range storage.getSaved() {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func TestDispatcher_StorageQuota(t *testing.T) {
+	t.Run("should evict the oldest events past MaxStorageEvents", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, func(c *DispatcherConfig) { c.MaxStorageEvents = 2 })
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "a"})
+		d.Enqueue(Event{Name: "b"})
+		d.Enqueue(Event{Name: "c"})
+
+		if names := savedNames(storage); len(names) != 2 || names[0] != "b" || names[1] != "c" {
+			t.Fatalf("expected [b c] in storage, got %v", names)
+		}
+		if d.queue.Len() != 3 {
+			t.Fatalf("expected evicted events to stay queued, got %d", d.queue.Len())
+		}
+		if stats := d.Stats(); stats.StorageEvicted != 1 || stats.StorageSize != 2 {
+			t.Fatalf("unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("should evict the lowest priority events first", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, func(c *DispatcherConfig) {
+			c.MaxStorageEvents = 2
+			c.StorageEviction = StorageEvictLowestPriority
+			c.EventPriority = func(event Event) int {
+				if event.Name == "payment" {
+					return 10
+				}
+				return 0
+			}
+		})
+		d.Restore()
+		defer d.Dispose()
+
+		d.Enqueue(Event{Name: "payment"})
+		d.Enqueue(Event{Name: "click"})
+		d.Enqueue(Event{Name: "scroll"})
+
+		if names := savedNames(storage); len(names) != 2 || names[0] != "payment" || names[1] != "scroll" {
+			t.Fatalf("expected [payment scroll] in storage, got %v", names)
+		}
+	})
+
+	t.Run("should evict past MaxStorageBytes", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		event := Event{Name: "a", Payload: map[string]any{"data": "0123456789"}}
+		size, _ := eventSize(&event)
+		d := newTestDispatcher(&mockHTTPAdapter{}, storage, func(c *DispatcherConfig) { c.MaxStorageBytes = batchEnvelopeBytes + 2*size })
+		d.Restore()
+		defer d.Dispose()
+
+		for i := 0; i < 3; i++ {
+			d.Enqueue(event)
+		}
+
+		if got := len(storage.getSaved()); got != 2 {
+			t.Fatalf("expected 2 events within the byte quota, got %d", got)
+		}
+	})
+}
+
+func TestNewClient_StorageQuotaValidation(t *testing.T) {
+	t.Run("should require EventPriority for priority eviction", func(t *testing.T) {
+		config := createTestConfig()
+		config.StorageEviction = StorageEvictLowestPriority
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error without EventPriority")
+		}
+	})
+
+	t.Run("should reject negative quotas", func(t *testing.T) {
+		config := createTestConfig()
+		config.MaxStorageBytes = -1
+		if _, err := NewClient(config); err == nil {
+			t.Fatal("expected error for negative quota")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package ripple
+
+import "sync"
+
+// EventSubscriber receives a copy of a tracked event. It runs synchronously
+// on the tracking goroutine, so it should return quickly.
+type EventSubscriber func(event Event)
+
+// eventTap fans accepted events out to subscribers.
+type eventTap struct {
+	mu          sync.RWMutex
+	next        int
+	subscribers map[int]EventSubscriber
+}
+
+// subscribe registers fn and returns a function that removes it.
+func (t *eventTap) subscribe(fn EventSubscriber) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.subscribers == nil {
+		t.subscribers = make(map[int]EventSubscriber)
+	}
+	id := t.next
+	t.next++
+	t.subscribers[id] = fn
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			delete(t.subscribers, id)
+		})
+	}
+}
+
+// publish passes a copy of event to every subscriber.
+func (t *eventTap) publish(event Event) {
+	t.mu.RLock()
+	subscribers := make([]EventSubscriber, 0, len(t.subscribers))
+	for _, fn := range t.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	t.mu.RUnlock()
+
+	for _, fn := range subscribers {
+		fn(copyEvent(event))
+	}
+}
+
+// copyEvent returns event with its own payload and metadata maps.
+func copyEvent(event Event) Event {
+	event.Payload = copyMap(event.Payload)
+	event.Metadata = copyMap(event.Metadata)
+	if event.SessionID != nil {
+		sessionID := *event.SessionID
+		event.SessionID = &sessionID
+	}
+	return event
+}
+
+// Subscribe registers fn to receive a copy of every event accepted by Track
+// or TrackNow, after sequence numbers and event IDs are stamped. Events
+// dropped by sampling, BeforeSend hooks or validation are not published.
+// It is meant for debug UIs, tests and log mirroring. Returns a function
+// that unsubscribes fn.
+func (c *Client) Subscribe(fn EventSubscriber) (unsubscribe func()) {
+	return c.dispatcher.tap.subscribe(fn)
+}
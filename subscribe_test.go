@@ -0,0 +1,85 @@
+package ripple
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestClient_Subscribe(t *testing.T) {
+	t.Run("should receive a copy of every tracked event", func(t *testing.T) {
+		config := createTestConfig()
+		config.EventIDs = true
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		var mu sync.Mutex
+		var received []Event
+		client.Subscribe(func(event Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			event.Payload["mutated"] = true
+			received = append(received, event)
+		})
+
+		if err := client.Track("a", map[string]any{"k": 1}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := client.TrackNow(context.Background(), "b", map[string]any{"k": 2}, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) != 2 || received[0].Name != "a" || received[1].Name != "b" {
+			t.Fatalf("unexpected events: %+v", received)
+		}
+		if received[0].ID == "" {
+			t.Error("expected published events to carry their event ID")
+		}
+		queued := client.dispatcher.queue.ToSlice()
+		if _, ok := queued[0].Payload["mutated"]; ok {
+			t.Error("expected subscribers to receive a copy of the payload")
+		}
+	})
+
+	t.Run("should skip sampled out events", func(t *testing.T) {
+		config := createTestConfig()
+		config.SamplingRules = map[string]float64{"a": 0}
+		client, err := NewClient(config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client.Init()
+		defer client.Dispose()
+
+		calls := 0
+		client.Subscribe(func(Event) { calls++ })
+		_ = client.Track("a", nil, nil)
+
+		if calls != 0 {
+			t.Fatalf("expected no events published, got %d", calls)
+		}
+	})
+
+	t.Run("should stop after unsubscribe", func(t *testing.T) {
+		client := createTestClient()
+		client.Init()
+		defer client.Dispose()
+
+		calls := 0
+		unsubscribe := client.Subscribe(func(Event) { calls++ })
+		_ = client.Track("a", nil, nil)
+		unsubscribe()
+		unsubscribe()
+		_ = client.Track("b", nil, nil)
+
+		if calls != 1 {
+			t.Fatalf("expected 1 event before unsubscribe, got %d", calls)
+		}
+	})
+}
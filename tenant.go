@@ -0,0 +1,60 @@
+package ripple
+
+import "time"
+
+// Tenant is the delivery destination for a subset of events.
+type Tenant struct {
+	// APIKey replaces the client's API key for the tenant's batches.
+	// Empty uses the client's API key.
+	APIKey string
+
+	// Endpoint replaces the client's endpoint for the tenant's batches.
+	// Empty uses the client's endpoint selection.
+	Endpoint string
+}
+
+// TenantResolver maps an event to its tenant, typically from its metadata.
+// Returning the zero Tenant sends the event with the client's own API key
+// and endpoint. It must be deterministic, as it may be called several times
+// per event.
+type TenantResolver func(event Event) Tenant
+
+// groupByTenant splits events into per-tenant groups, keeping each tenant's
+// events in FIFO order. Without a TenantResolver all events form one group.
+func (d *Dispatcher) groupByTenant(events []Event) [][]Event {
+	if d.config.TenantResolver == nil {
+		return [][]Event{events}
+	}
+
+	var groups [][]Event
+	index := make(map[Tenant]int)
+	for _, event := range events {
+		tenant := d.config.TenantResolver(event)
+		i, ok := index[tenant]
+		if !ok {
+			i = len(groups)
+			index[tenant] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], event)
+	}
+	return groups
+}
+
+// route returns the endpoint and headers for an attempt to deliver events,
+// which all belong to the same tenant.
+func (d *Dispatcher) route(events []Event, sentAt time.Time, batchID string) (string, map[string]string) {
+	headers := d.attemptHeaders(sentAt, batchID)
+	if d.config.TenantResolver == nil || len(events) == 0 {
+		return d.endpoint(), headers
+	}
+
+	tenant := d.config.TenantResolver(events[0])
+	if tenant.APIKey != "" {
+		headers[d.config.APIKeyHeader] = tenant.APIKey
+	}
+	if tenant.Endpoint != "" {
+		return tenant.Endpoint, headers
+	}
+	return d.endpoint(), headers
+}
@@ -0,0 +1,105 @@
+package ripple
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// tenantSend is one batch received by tenantHTTPAdapter.
+type tenantSend struct {
+	endpoint string
+	apiKey   string
+	names    []string
+}
+
+// tenantHTTPAdapter records the endpoint, API key and events of every send.
+type tenantHTTPAdapter struct {
+	mu    sync.Mutex
+	sends []tenantSend
+}
+
+func (a *tenantHTTPAdapter) Send(endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	return a.SendWithContext(context.Background(), endpoint, events, headers)
+}
+
+func (a *tenantHTTPAdapter) SendWithContext(ctx context.Context, endpoint string, events []Event, headers map[string]string) (*HTTPResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	send := tenantSend{endpoint: endpoint, apiKey: headers["X-API-Key"]}
+	for _, event := range events {
+		send.names = append(send.names, event.Name)
+	}
+	a.sends = append(a.sends, send)
+	return &HTTPResponse{Status: 200}, nil
+}
+
+func resolveTenantID(event Event) Tenant {
+	switch event.Metadata["tenantId"] {
+	case "acme":
+		return Tenant{APIKey: "acme-key", Endpoint: "http://acme.test"}
+	case "globex":
+		return Tenant{APIKey: "globex-key"}
+	}
+	return Tenant{}
+}
+
+func newTenantTestClient(t *testing.T, httpAdapter *tenantHTTPAdapter) *Client {
+	t.Helper()
+	config := createTestConfig()
+	config.HTTPAdapter = httpAdapter
+	config.TenantResolver = resolveTenantID
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.Init()
+	return client
+}
+
+func TestClient_TenantResolver(t *testing.T) {
+	t.Run("should batch and route events per tenant", func(t *testing.T) {
+		httpAdapter := &tenantHTTPAdapter{}
+		client := newTenantTestClient(t, httpAdapter)
+		defer client.Dispose()
+
+		client.Track("a1", nil, map[string]any{"tenantId": "acme"})
+		client.Track("d1", nil, nil)
+		client.Track("g1", nil, map[string]any{"tenantId": "globex"})
+		client.Track("a2", nil, map[string]any{"tenantId": "acme"})
+		client.Flush()
+
+		want := []tenantSend{
+			{endpoint: "http://acme.test", apiKey: "acme-key", names: []string{"a1", "a2"}},
+			{endpoint: "http://test.com", apiKey: "test-key", names: []string{"d1"}},
+			{endpoint: "http://test.com", apiKey: "globex-key", names: []string{"g1"}},
+		}
+		if len(httpAdapter.sends) != len(want) {
+			t.Fatalf("expected %d batches, got %+v", len(want), httpAdapter.sends)
+		}
+		for i, send := range httpAdapter.sends {
+			if send.endpoint != want[i].endpoint || send.apiKey != want[i].apiKey || len(send.names) != len(want[i].names) {
+				t.Fatalf("batch %d: expected %+v, got %+v", i, want[i], send)
+			}
+			for j, name := range send.names {
+				if name != want[i].names[j] {
+					t.Fatalf("batch %d: expected %v, got %v", i, want[i].names, send.names)
+				}
+			}
+		}
+	})
+
+	t.Run("should route TrackNow to the event's tenant", func(t *testing.T) {
+		httpAdapter := &tenantHTTPAdapter{}
+		client := newTenantTestClient(t, httpAdapter)
+		defer client.Dispose()
+
+		if err := client.TrackNow(context.Background(), "a1", nil, map[string]any{"tenantId": "acme"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(httpAdapter.sends) != 1 || httpAdapter.sends[0].endpoint != "http://acme.test" || httpAdapter.sends[0].apiKey != "acme-key" {
+			t.Fatalf("unexpected sends: %+v", httpAdapter.sends)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package ripple
+
+import "context"
+
+// Tracker tracks events through a Client with preset metadata layered on top
+// of the client's shared metadata. It shares the client's dispatcher and
+// queue, so it is cheap to create per request.
+type Tracker struct {
+	client   *Client
+	metadata map[string]any
+}
+
+// WithMetadata returns a scoped Tracker that applies metadata to every event
+// on top of the shared metadata. The tracker shares the client's dispatcher
+// and queue; metadata is copied, so later changes to it have no effect.
+func (c *Client) WithMetadata(metadata map[string]any) *Tracker {
+	return &Tracker{client: c, metadata: copyMap(metadata)}
+}
+
+// WithMetadata returns a child Tracker whose preset metadata is this
+// tracker's, overridden by metadata.
+func (t *Tracker) WithMetadata(metadata map[string]any) *Tracker {
+	merged := make(map[string]any, len(t.metadata)+len(metadata))
+	for k, v := range t.metadata {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return &Tracker{client: t.client, metadata: merged}
+}
+
+// Client returns the client the tracker sends events through.
+func (t *Tracker) Client() *Client {
+	return t.client
+}
+
+// Metadata returns a copy of the tracker's preset metadata.
+func (t *Tracker) Metadata() map[string]any {
+	return copyMap(t.metadata)
+}
+
+// Track tracks an event with the tracker's preset metadata.
+// Metadata precedence: shared < preset < event-specific.
+func (t *Tracker) Track(name string, payload, metadata map[string]any) error {
+	return t.client.track(name, payload, t.metadata, metadata)
+}
+
+// TrackContext tracks an event with the tracker's preset metadata and the
+// request-scoped metadata in ctx.
+// Metadata precedence: shared < preset < context < event-specific.
+func (t *Tracker) TrackContext(ctx context.Context, name string, payload, metadata map[string]any) error {
+	return t.client.track(name, payload, t.metadata, MetadataFromContext(ctx), metadata)
+}
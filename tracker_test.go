@@ -0,0 +1,111 @@
+package ripple
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	t.Run("should layer context metadata between preset and event metadata", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		tracker := client.WithMetadata(map[string]any{"tenant": "preset", "scope": "tracker"})
+		ctx := ContextWithMetadata(context.Background(), map[string]any{"tenant": "context"})
+		if err := tracker.TrackContext(ctx, "event", nil, map[string]any{"scope": "event"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved := storage.getSaved()
+		if len(saved) != 1 || saved[0].Metadata["tenant"] != "context" || saved[0].Metadata["scope"] != "event" {
+			t.Fatalf("unexpected metadata: %+v", saved)
+		}
+	})
+
+	t.Run("should return a copy of the preset metadata", func(t *testing.T) {
+		tracker := createTestClient().WithMetadata(map[string]any{"tenant": "a"})
+		defer tracker.Client().Dispose()
+
+		tracker.Metadata()["tenant"] = "mutated"
+		if tracker.Metadata()["tenant"] != "a" {
+			t.Fatal("expected Metadata to return a copy")
+		}
+	})
+
+	t.Run("should apply scoped metadata over shared metadata", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+		client.SetMetadata("app", "api")
+		client.SetMetadata("tenant", "shared")
+
+		acme := client.WithMetadata(map[string]any{"tenant": "acme"})
+		globex := client.WithMetadata(map[string]any{"tenant": "globex"})
+		acme.Track("a", nil, nil)
+		globex.Track("b", nil, nil)
+		client.Track("c", nil, nil)
+
+		saved := storage.getSaved()
+		if len(saved) != 3 {
+			t.Fatalf("expected 3 events, got %d", len(saved))
+		}
+		for i, tenant := range []string{"acme", "globex", "shared"} {
+			if saved[i].Metadata["tenant"] != tenant || saved[i].Metadata["app"] != "api" {
+				t.Fatalf("event %d: unexpected metadata %v", i, saved[i].Metadata)
+			}
+		}
+		if client.Stats().QueueLength != 3 {
+			t.Fatal("expected trackers to share the client queue")
+		}
+	})
+
+	t.Run("should nest scopes", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		parent := client.WithMetadata(map[string]any{"tenant": "acme", "region": "eu"})
+		child := parent.WithMetadata(map[string]any{"region": "us", "requestId": "r1"})
+
+		metadata := child.Metadata()
+		if metadata["tenant"] != "acme" || metadata["region"] != "us" || metadata["requestId"] != "r1" {
+			t.Fatalf("unexpected child metadata: %v", metadata)
+		}
+		if parent.Metadata()["region"] != "eu" || parent.Metadata()["requestId"] != nil {
+			t.Fatalf("expected parent to be unchanged, got %v", parent.Metadata())
+		}
+	})
+
+	t.Run("should copy the scope metadata", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		metadata := map[string]any{"tenant": "acme"}
+		tracker := client.WithMetadata(metadata)
+		metadata["tenant"] = "mutated"
+
+		if tracker.Metadata()["tenant"] != "acme" {
+			t.Fatal("expected scope metadata to be copied")
+		}
+	})
+
+	t.Run("should store scoped trackers in a context", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		ctx := WithTracker(context.Background(), client.WithMetadata(map[string]any{"tenant": "acme", "requestId": "old"}))
+		ctx = ContextWithMetadata(ctx, map[string]any{"requestId": "r1"})
+
+		if FromContext(ctx) != client {
+			t.Fatal("expected client from tracker context")
+		}
+		metadata := TrackerFromContext(ctx).Metadata()
+		if metadata["tenant"] != "acme" || metadata["requestId"] != "r1" {
+			t.Fatalf("unexpected metadata: %v", metadata)
+		}
+	})
+}
@@ -0,0 +1,358 @@
+package ripple
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// TransformOp is what a TransformStep does.
+type TransformOp int
+
+const (
+	// TransformRename moves the value at Path to To. Missing fields are
+	// ignored.
+	TransformRename TransformOp = iota
+
+	// TransformCoerce converts the value at Path to Type. Values that cannot
+	// be converted are left unchanged.
+	TransformCoerce
+
+	// TransformDerive sets the field at Path to the value returned by Derive.
+	TransformDerive
+)
+
+// FieldType is the type a TransformCoerce step converts a value to.
+type FieldType int
+
+const (
+	// FieldString formats the value with fmt.Sprint.
+	FieldString FieldType = iota
+
+	// FieldInt converts numbers, numeric strings and booleans to int64.
+	// Floats are only converted if they have no fractional part.
+	FieldInt
+
+	// FieldFloat converts numbers and numeric strings to float64.
+	FieldFloat
+
+	// FieldBool converts booleans, strconv.ParseBool strings and numbers
+	// (non-zero is true) to bool.
+	FieldBool
+)
+
+// TransformStep is one step of an event transformation.
+type TransformStep struct {
+	// Op is what the step does.
+	Op TransformOp
+
+	// Path is the dot-separated path of the field, rooted at "payload" or
+	// "metadata" like RedactionRule.Path: the source of a rename, the value
+	// to coerce, or where a derived value is set.
+	Path string
+
+	// To is the destination path of a TransformRename. Missing parent maps
+	// are created.
+	To string
+
+	// Type is the target type of a TransformCoerce.
+	Type FieldType
+
+	// Derive computes the value of a TransformDerive from the event as
+	// transformed so far. It must not modify the event's maps. If it returns
+	// false the field is left unchanged.
+	Derive func(event Event) (any, bool)
+}
+
+// allEventsTransform is the Transformers key whose steps apply to every
+// event.
+const allEventsTransform = "*"
+
+// validateTransformers reports every invalid step.
+func validateTransformers(transformers map[string][]TransformStep) []string {
+	var problems []string
+	for _, name := range slices.Sorted(maps.Keys(transformers)) {
+		if name == "" {
+			problems = append(problems, "transformer event name cannot be empty")
+		}
+		for _, step := range transformers[name] {
+			problems = append(problems, step.validate(name)...)
+		}
+	}
+	return problems
+}
+
+// validate reports the problems of a step configured for events named name.
+func (s TransformStep) validate(name string) []string {
+	var problems []string
+	if !validFieldPath(s.Path) {
+		problems = append(problems, fmt.Sprintf("transform path %q for %q must start with payload. or metadata.", s.Path, name))
+	}
+	switch s.Op {
+	case TransformRename:
+		if !validFieldPath(s.To) {
+			problems = append(problems, fmt.Sprintf("transform rename target %q for %q must start with payload. or metadata.", s.To, name))
+		}
+	case TransformCoerce:
+		if s.Type < FieldString || s.Type > FieldBool {
+			problems = append(problems, fmt.Sprintf("transform type for %q on %q is unknown", s.Path, name))
+		}
+	case TransformDerive:
+		if s.Derive == nil {
+			problems = append(problems, fmt.Sprintf("transform derive function for %q on %q cannot be nil", s.Path, name))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("transform operation for %q on %q is unknown", s.Path, name))
+	}
+	return problems
+}
+
+// validFieldPath reports whether path is rooted at payload or metadata.
+func validFieldPath(path string) bool {
+	root, rest := splitRedactionPath(path)
+	return (root == "payload" || root == "metadata") && len(rest) > 0
+}
+
+// transformEvent runs the steps configured for every event, then those for
+// the event's name, in order. Each step sees the result of the previous one.
+// The maps passed to Track are never modified.
+func (c *Client) transformEvent(event *Event) {
+	if len(c.config.Transformers) == 0 {
+		return
+	}
+	for _, step := range c.config.Transformers[allEventsTransform] {
+		step.apply(event)
+	}
+	if event.Name == allEventsTransform {
+		return
+	}
+	for _, step := range c.config.Transformers[event.Name] {
+		step.apply(event)
+	}
+}
+
+// apply runs the step on event, copying any map it changes.
+func (s TransformStep) apply(event *Event) {
+	switch s.Op {
+	case TransformRename:
+		value, ok := getField(event, s.Path)
+		if !ok {
+			return
+		}
+		deleteField(event, s.Path)
+		setField(event, s.To, value)
+	case TransformCoerce:
+		value, ok := getField(event, s.Path)
+		if !ok {
+			return
+		}
+		if coerced, ok := coerce(value, s.Type); ok {
+			setField(event, s.Path, coerced)
+		}
+	case TransformDerive:
+		if value, ok := s.Derive(*event); ok {
+			setField(event, s.Path, value)
+		}
+	}
+}
+
+// getField returns the value at path.
+func getField(event *Event, path string) (any, bool) {
+	root, keys := splitRedactionPath(path)
+	var value any = event.Payload
+	if root == "metadata" {
+		value = event.Metadata
+	}
+	for _, key := range keys {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// setField sets the value at path, creating missing maps.
+func setField(event *Event, path string, value any) {
+	root, keys := splitRedactionPath(path)
+	if root == "metadata" {
+		event.Metadata = setPath(event.Metadata, keys, value)
+	} else {
+		event.Payload = setPath(event.Payload, keys, value)
+	}
+}
+
+// deleteField removes the value at path.
+func deleteField(event *Event, path string) {
+	root, keys := splitRedactionPath(path)
+	if root == "metadata" {
+		event.Metadata = deletePath(event.Metadata, keys)
+	} else {
+		event.Payload = deletePath(event.Payload, keys)
+	}
+}
+
+// setPath returns a copy of m with value set at keys. Every map along the
+// path is copied, so m itself is never modified. A non-map value along the
+// path is replaced by a map.
+func setPath(m map[string]any, keys []string, value any) map[string]any {
+	copied := maps.Clone(m)
+	if copied == nil {
+		copied = make(map[string]any, 1)
+	}
+	if len(keys) == 1 {
+		copied[keys[0]] = value
+		return copied
+	}
+	child, _ := copied[keys[0]].(map[string]any)
+	copied[keys[0]] = setPath(child, keys[1:], value)
+	return copied
+}
+
+// deletePath returns m without the value at keys, copying every map along
+// the path.
+func deletePath(m map[string]any, keys []string) map[string]any {
+	value, ok := m[keys[0]]
+	if !ok {
+		return m
+	}
+	copied := maps.Clone(m)
+	if len(keys) == 1 {
+		delete(copied, keys[0])
+		return copied
+	}
+	child, ok := value.(map[string]any)
+	if !ok {
+		return m
+	}
+	copied[keys[0]] = deletePath(child, keys[1:])
+	return copied
+}
+
+// coerce converts value to typ, reporting whether it could.
+func coerce(value any, typ FieldType) (any, bool) {
+	switch typ {
+	case FieldString:
+		if value == nil {
+			return nil, false
+		}
+		return fmt.Sprint(value), true
+	case FieldInt:
+		return coerceInt(value)
+	case FieldFloat:
+		return coerceFloat(value)
+	case FieldBool:
+		return coerceBool(value)
+	}
+	return nil, false
+}
+
+func coerceInt(value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, true
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return coerceInt(f)
+		}
+	case bool:
+		if v {
+			return int64(1), true
+		}
+		return int64(0), true
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint:
+		if uint64(v) <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case float32:
+		return coerceInt(float64(v))
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return nil, false
+}
+
+func coerceFloat(value any) (any, bool) {
+	if v, ok := value.(string); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+		return nil, false
+	}
+	return toFloat(value)
+}
+
+func coerceBool(value any) (any, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b, true
+		}
+		return nil, false
+	}
+	if f, ok := toFloat(value); ok {
+		return f != 0, true
+	}
+	return nil, false
+}
+
+// toFloat converts Go numeric types to float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
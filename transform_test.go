@@ -0,0 +1,149 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransformers(t *testing.T) {
+	t.Run("should rename, coerce and derive fields without touching the caller's maps", func(t *testing.T) {
+		config := createTestConfig()
+		config.Transformers = map[string][]TransformStep{
+			"purchase": {
+				{Op: TransformRename, Path: "payload.amt", To: "payload.order.amount"},
+				{Op: TransformCoerce, Path: "payload.order.amount", Type: FieldFloat},
+				{Op: TransformRename, Path: "metadata.src", To: "metadata.source"},
+				{Op: TransformDerive, Path: "payload.order.large", Derive: func(event Event) (any, bool) {
+					amount, ok := event.Payload["order"].(map[string]any)["amount"].(float64)
+					return amount > 100, ok
+				}},
+			},
+		}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		payload := map[string]any{"amt": "150.5"}
+		metadata := map[string]any{"src": "web"}
+		_ = client.Track("purchase", payload, metadata)
+
+		event := client.dispatcher.queue.ToSlice()[0]
+		order := event.Payload["order"].(map[string]any)
+		if order["amount"] != 150.5 || order["large"] != true {
+			t.Fatalf("unexpected order: %v", order)
+		}
+		if _, ok := event.Payload["amt"]; ok {
+			t.Fatalf("expected the renamed field to be removed, got %v", event.Payload)
+		}
+		if event.Metadata["source"] != "web" {
+			t.Fatalf("unexpected metadata: %v", event.Metadata)
+		}
+		if payload["amt"] != "150.5" || len(payload) != 1 || metadata["src"] != "web" || len(metadata) != 1 {
+			t.Fatal("expected the caller's maps to be unchanged")
+		}
+	})
+
+	t.Run("should run wildcard steps before named steps, in order", func(t *testing.T) {
+		var order []string
+		step := func(label string) TransformStep {
+			return TransformStep{Op: TransformDerive, Path: "payload.last", Derive: func(Event) (any, bool) {
+				order = append(order, label)
+				return label, true
+			}}
+		}
+		config := createTestConfig()
+		config.Transformers = map[string][]TransformStep{
+			"signup": {step("named-1"), step("named-2")},
+			"*":      {step("all")},
+		}
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		_ = client.Track("signup", nil, nil)
+		_ = client.Track("other", nil, nil)
+
+		events := client.dispatcher.queue.ToSlice()
+		if events[0].Payload["last"] != "named-2" || events[1].Payload["last"] != "all" {
+			t.Fatalf("unexpected results: %v, %v", events[0].Payload, events[1].Payload)
+		}
+		if len(order) != 4 || order[0] != "all" || order[1] != "named-1" || order[2] != "named-2" || order[3] != "all" {
+			t.Fatalf("unexpected step order: %v", order)
+		}
+	})
+
+	t.Run("should see the result of earlier steps", func(t *testing.T) {
+		event := &Event{Payload: map[string]any{"count": "7"}}
+		for _, step := range []TransformStep{
+			{Op: TransformCoerce, Path: "payload.count", Type: FieldInt},
+			{Op: TransformRename, Path: "payload.count", To: "payload.total"},
+			{Op: TransformCoerce, Path: "payload.total", Type: FieldString},
+		} {
+			step.apply(event)
+		}
+		if event.Payload["total"] != "7" || len(event.Payload) != 1 {
+			t.Fatalf("unexpected payload: %v", event.Payload)
+		}
+	})
+
+	t.Run("should run before schema validation", func(t *testing.T) {
+		config := createTestConfig()
+		config.Transformers = map[string][]TransformStep{
+			"signup": {{Op: TransformRename, Path: "payload.mail", To: "payload.email"}},
+		}
+		validator := NewSchemaValidator(SchemaModeStrict)
+		if err := validator.Register("signup", "", []byte(`{"type":"object","required":["email"]}`)); err != nil {
+			t.Fatal(err)
+		}
+		config.SchemaValidator = validator
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		if err := client.Track("signup", map[string]any{"mail": "a@example.com"}, nil); err != nil {
+			t.Fatalf("expected the renamed field to satisfy the schema, got %v", err)
+		}
+	})
+
+	t.Run("should reject invalid steps", func(t *testing.T) {
+		config := createTestConfig()
+		config.Transformers = map[string][]TransformStep{
+			"a": {
+				{Op: TransformRename, Path: "payload.x", To: "x"},
+				{Op: TransformCoerce, Path: "event.x", Type: FieldInt},
+				{Op: TransformDerive, Path: "payload.y"},
+				{Op: TransformOp(9), Path: "payload.z"},
+			},
+		}
+		var configErr *ConfigError
+		if err := config.Validate(); !errors.As(err, &configErr) || len(configErr.Problems) != 4 {
+			t.Fatalf("expected 4 problems, got %v", err)
+		}
+	})
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		value any
+		typ   FieldType
+		want  any
+		ok    bool
+	}{
+		{value: 42, typ: FieldString, want: "42", ok: true},
+		{value: "12", typ: FieldInt, want: int64(12), ok: true},
+		{value: 3.0, typ: FieldInt, want: int64(3), ok: true},
+		{value: 3.5, typ: FieldInt, ok: false},
+		{value: int64(1 << 62), typ: FieldInt, want: int64(1 << 62), ok: true},
+		{value: true, typ: FieldInt, want: int64(1), ok: true},
+		{value: "2.5", typ: FieldFloat, want: 2.5, ok: true},
+		{value: 7, typ: FieldFloat, want: 7.0, ok: true},
+		{value: "abc", typ: FieldFloat, ok: false},
+		{value: "true", typ: FieldBool, want: true, ok: true},
+		{value: 0, typ: FieldBool, want: false, ok: true},
+		{value: "maybe", typ: FieldBool, ok: false},
+		{value: nil, typ: FieldString, ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := coerce(tt.value, tt.typ)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("coerce(%#v, %d) = %#v, %v; want %#v, %v", tt.value, tt.typ, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ripple
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TypedEvent is an event definition whose payload shape is fixed by its Go
+// type. The struct itself is the payload, encoded with its JSON tags:
+//
+//	type PageView struct {
+//		Page     string `json:"page"`
+//		Referrer string `json:"referrer,omitempty"`
+//	}
+//
+//	func (PageView) Name() string { return "page_view" }
+type TypedEvent interface {
+	// Name returns the event name/identifier.
+	Name() string
+}
+
+// TrackTyped tracks a typed event, so that payload shape mismatches are
+// caught at compile time rather than by the backend. It behaves like
+// Client.Track with the event encoded as the payload.
+//
+// Returns an error if the event does not encode to a JSON object.
+func TrackTyped[T TypedEvent](c *Client, event T, metadata map[string]any) error {
+	payload, err := typedPayload(event)
+	if err != nil {
+		return err
+	}
+	return c.Track(event.Name(), payload, metadata)
+}
+
+// typedPayload encodes a typed event into a payload map.
+func typedPayload(event TypedEvent) (map[string]any, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode typed event %q: %w", event.Name(), err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("typed event %q must encode as a JSON object", event.Name())
+	}
+	return payload, nil
+}
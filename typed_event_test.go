@@ -0,0 +1,58 @@
+package ripple
+
+import (
+	"testing"
+)
+
+type testPageView struct {
+	Page     string `json:"page"`
+	Referrer string `json:"referrer,omitempty"`
+}
+
+func (testPageView) Name() string { return "page_view" }
+
+type testScalarEvent int
+
+func (testScalarEvent) Name() string { return "scalar" }
+
+func TestTrackTyped(t *testing.T) {
+	t.Run("should track the struct as the payload", func(t *testing.T) {
+		storage := &mockStorageAdapter{}
+		config := createTestConfig()
+		config.StorageAdapter = storage
+		client, _ := NewClient(config)
+		defer client.Dispose()
+
+		err := TrackTyped(client, testPageView{Page: "/home"}, map[string]any{"source": "test"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		saved := storage.getSaved()
+		if len(saved) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(saved))
+		}
+		event := saved[0]
+		if event.Name != "page_view" {
+			t.Errorf("expected name page_view, got %q", event.Name)
+		}
+		if event.Payload["page"] != "/home" {
+			t.Errorf("expected page /home, got %v", event.Payload["page"])
+		}
+		if _, ok := event.Payload["referrer"]; ok {
+			t.Error("expected omitempty field to be omitted")
+		}
+		if event.Metadata["source"] != "test" {
+			t.Errorf("expected metadata source=test, got %v", event.Metadata["source"])
+		}
+	})
+
+	t.Run("should reject events that do not encode as an object", func(t *testing.T) {
+		client := createTestClient()
+		defer client.Dispose()
+
+		if err := TrackTyped(client, testScalarEvent(1), nil); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
@@ -2,6 +2,9 @@ package ripple
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/Tap30/ripple-go/adapters"
@@ -18,12 +21,29 @@ type (
 	// Platform describes the runtime environment (e.g., server, client).
 	Platform = adapters.Platform
 
+	// AppInfo identifies the service that produced an event.
+	AppInfo = adapters.AppInfo
+
+	// KubernetesInfo locates the pod that produced an event.
+	KubernetesInfo = adapters.KubernetesInfo
+
 	// HTTPAdapter defines the interface used by the client to perform HTTP requests.
 	HTTPAdapter = adapters.HTTPAdapter
 
 	// HTTPResponse represents a response returned by an HTTPAdapter.
 	HTTPResponse = adapters.HTTPResponse
 
+	// Batch is a single delivery attempt of a batch of events.
+	Batch = adapters.Batch
+
+	// BatchSender is an optional HTTPAdapter extension that receives batch
+	// metadata such as the batch ID.
+	BatchSender = adapters.BatchSender
+
+	// EventRetainer is an optional HTTPAdapter extension declaring whether
+	// the adapter keeps the events of a send after it returns.
+	EventRetainer = adapters.EventRetainer
+
 	// StorageAdapter defines the interface used for event persistence and retries.
 	StorageAdapter = adapters.StorageAdapter
 
@@ -35,22 +55,101 @@ type (
 
 	// StorageQuotaExceededError indicates that the storage quota has been exceeded.
 	StorageQuotaExceededError = adapters.StorageQuotaExceededError
+
+	// StorageCorruptedError indicates that corrupt storage was quarantined.
+	StorageCorruptedError = adapters.StorageCorruptedError
+
+	// SequenceStore is an optional StorageAdapter extension that persists
+	// event sequence counters.
+	SequenceStore = adapters.SequenceStore
+
+	// IdentityStore is an optional StorageAdapter extension that persists
+	// the identified user.
+	IdentityStore = adapters.IdentityStore
+
+	// OpenFilesReporter is an optional adapter extension that reports held
+	// file descriptors.
+	OpenFilesReporter = adapters.OpenFilesReporter
+
+	// TracerProvider defines the interface used to emit tracing spans.
+	TracerProvider = adapters.TracerProvider
+
+	// Span is a single traced operation started by a TracerProvider.
+	Span = adapters.Span
 )
 
 // HTTPError represents an HTTP error response.
 // Can be used by custom HTTPAdapter implementations to wrap HTTP errors.
+// Errors built by the dispatcher also carry the structured error the server
+// returned in the response body, if any.
 type HTTPError struct {
 	Status int
+
+	// Endpoint is the URL the failed request was sent to, if known.
+	Endpoint string
+
+	// Attempts is the number of delivery attempts made for the batch,
+	// including the failed one. Zero if unknown.
+	Attempts int
+
+	// Code is the server's machine-readable error code, if any.
+	Code string
+
+	// Message is the server's explanation of the error, if any.
+	Message string
+
+	// RejectedEvents holds the batch indices of the events the server
+	// rejected, if it reported them.
+	RejectedEvents []int
+
+	// Body is the decoded response body (HTTPResponse.Data).
+	Body any
 }
 
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("HTTP request failed with status %d", e.Status)
+	var b strings.Builder
+	b.WriteString("HTTP request ")
+	if e.Endpoint != "" {
+		fmt.Fprintf(&b, "to %s ", e.Endpoint)
+	}
+	fmt.Fprintf(&b, "failed with status %d", e.Status)
+	if text := http.StatusText(e.Status); text != "" {
+		b.WriteString(" " + text)
+	}
+	if e.Attempts > 0 {
+		fmt.Fprintf(&b, " (attempt %d)", e.Attempts)
+	}
+
+	detail := e.Message
+	if e.Code != "" && detail != "" {
+		detail = e.Code + ": " + detail
+	} else if e.Code != "" {
+		detail = e.Code
+	} else if detail == "" {
+		detail = bodySnippet(e.Body)
+	}
+	if detail != "" {
+		b.WriteString(": " + detail)
+	}
+	if len(e.RejectedEvents) > 0 {
+		fmt.Fprintf(&b, " (rejected events %v)", e.RejectedEvents)
+	}
+	return b.String()
 }
 
+// BeforeSendHook inspects or rewrites an event before it is enqueued.
+// Return the (possibly modified) event to keep it, or nil to drop it.
+type BeforeSendHook func(event *Event) *Event
+
+// DeliveryCallback is invoked once per batch after delivery completes.
+// err is nil when the batch was accepted with a 2xx response; otherwise it
+// describes why the batch was dropped, re-queued, or abandoned.
+type DeliveryCallback func(batch []Event, err error)
+
 type ClientConfig struct {
 	// APIKey is the authentication key used to authorize requests.
 	//
-	// Required.
+	// Required unless AuthProvider is set.
 	APIKey string
 
 	// Endpoint is the base HTTPS URL of the Ripple API.
@@ -65,12 +164,43 @@ type ClientConfig struct {
 	// Default: "X-API-Key"
 	APIKeyHeader *string
 
+	// AuthProvider supplies a bearer token, sent in the Authorization
+	// header with every batch, e.g. NewClientCredentialsAuth for OAuth2.
+	// On a 401 response, providers implementing AuthInvalidator are asked
+	// for a fresh token and the batch is resent once.
+	//
+	// Optional: If nil, requests are authorized by APIKey alone.
+	AuthProvider AuthProvider
+
+	// Headers are added to every batch request, e.g. a tenant or
+	// environment ID. They cannot replace the headers the client sets
+	// itself: the API key header, Content-Type, SentAtHeader, BatchIDHeader
+	// and, with AuthProvider, Authorization.
+	//
+	// Optional.
+	Headers map[string]string
+
+	// HeaderProvider returns headers added to each delivery attempt, after
+	// Headers, for values that change such as trace IDs or rotating tokens.
+	// Reserved headers it returns are ignored.
+	//
+	// Optional.
+	HeaderProvider HeaderProvider
+
 	// FlushInterval controls how often events are automatically flushed
 	// to the server.
 	//
 	// Default: 5 seconds.
 	FlushInterval time.Duration
 
+	// FlushIntervalJitter randomizes each scheduled flush within this
+	// fraction of FlushInterval in either direction, e.g. 0.2 waits between
+	// 80% and 120% of the interval, so that many replicas sharing a
+	// FlushInterval do not flush in lockstep. Must be in [0, 1).
+	//
+	// Optional: If not set or 0, every flush waits exactly FlushInterval.
+	FlushIntervalJitter float64
+
 	// MaxBatchSize is the maximum number of events sent in a single request.
 	//
 	// Default: 10.
@@ -81,6 +211,57 @@ type ClientConfig struct {
 	// Default: 3.
 	MaxRetries int
 
+	// BackoffPolicy decides how long to wait between retries of a failed
+	// batch. See ExponentialBackoff, ExponentialBackoffWithJitter,
+	// ConstantBackoff and FibonacciBackoff.
+	//
+	// Default: ExponentialBackoffWithJitter(time.Second, 30*time.Second, time.Second).
+	BackoffPolicy BackoffPolicy
+
+	// RetryMode decides whether a failed batch blocks the flush while it
+	// backs off (RetryInline) or waits in a retry queue while later batches
+	// are sent (RetryScheduled). Under RetryScheduled, Flush and Close do not
+	// wait for pending retries; Close persists them instead.
+	//
+	// Default: RetryInline.
+	RetryMode RetryMode
+
+	// Ordering decides whether events may be delivered ahead of an older
+	// batch that failed. With OrderingGlobal or OrderingPerKey, events that
+	// must not overtake a failed batch are re-queued behind it instead of
+	// being sent in the same flush. Requires RetryInline.
+	//
+	// Default: OrderingNone.
+	Ordering OrderingMode
+
+	// OrderingKey is the field OrderingPerKey orders events by: "sessionId",
+	// or a path under payload or metadata such as "payload.userId".
+	//
+	// Optional: Required with OrderingPerKey.
+	OrderingKey string
+
+	// FlushTimeout bounds how long a single flush may take, including retry
+	// backoff, so a slow endpoint cannot stall Flush indefinitely. Events not
+	// delivered in time are re-queued and checkpointed for the next flush.
+	//
+	// Optional: If not set or 0, a flush runs until every batch is delivered
+	// or out of retries.
+	FlushTimeout time.Duration
+
+	// RequestTimeout bounds each delivery attempt. An attempt that times out
+	// counts as a network error and is retried.
+	//
+	// Optional: If not set or 0, attempts are bounded only by the HTTPAdapter.
+	RequestTimeout time.Duration
+
+	// AdaptiveBatching grows the batch size while the endpoint keeps up and
+	// shrinks it after timeouts and 413 responses, within its bounds.
+	// MaxBatchSize is the starting size. A 413 response re-queues the batch
+	// to be resent in smaller batches instead of dropping it.
+	//
+	// Optional: If nil, every batch holds up to MaxBatchSize events.
+	AdaptiveBatching *AdaptiveBatching
+
 	// HTTPAdapter is the transport layer used to perform HTTP requests.
 	//
 	// Required.
@@ -96,11 +277,397 @@ type ClientConfig struct {
 	// Default: PrintLoggerAdapter with WARN level.
 	LoggerAdapter LoggerAdapter
 
+	// LogRateLimit is the maximum number of times each Warn or Error message
+	// is logged per minute, so repeated failures during an outage do not
+	// flood the application log. Further occurrences are dropped, and their
+	// count is logged as "Suppressed N similar messages" after the minute.
+	//
+	// Optional: If 0, every message is logged.
+	LogRateLimit int
+
 	// MaxBufferSize is the maximum number of events to persist to storage.
 	// When limit is exceeded, oldest events are evicted using FIFO policy.
 	//
 	// Optional: If not set or 0, no limit is applied.
 	MaxBufferSize int
+
+	// RegionalEndpoints lists alternative regional endpoints. When set, they
+	// are probed at Init and every EndpointProbeInterval, and batches are sent
+	// to the lowest-latency healthy one. Endpoint may be left empty, in which
+	// case the first regional endpoint is used until probing completes.
+	//
+	// Optional.
+	RegionalEndpoints []string
+
+	// EndpointProbeInterval controls how often RegionalEndpoints are probed.
+	//
+	// Default: 1 minute.
+	EndpointProbeInterval time.Duration
+
+	// Endpoints lists endpoints to spread delivery across according to
+	// EndpointStrategy. An endpoint that fails EndpointFailureThreshold
+	// attempts in a row is skipped until EndpointCooldown has passed. Health
+	// per endpoint is reported in Stats. Cannot be combined with
+	// RegionalEndpoints; Endpoint may be left empty.
+	//
+	// Optional.
+	Endpoints []string
+
+	// EndpointStrategy selects failover or round-robin across Endpoints.
+	//
+	// Default: EndpointFailover.
+	EndpointStrategy EndpointStrategy
+
+	// EndpointFailureThreshold is the number of consecutive failed attempts
+	// (network errors or 5xx) after which an endpoint is marked unhealthy.
+	//
+	// Default: 3.
+	EndpointFailureThreshold int
+
+	// EndpointCooldown is how long an unhealthy endpoint is skipped before
+	// it is tried again.
+	//
+	// Default: 30 seconds.
+	EndpointCooldown time.Duration
+
+	// TracerProvider receives a span around every Flush and every batch
+	// delivery attempt, so SDK latency shows up in distributed traces.
+	//
+	// Default: NoOpTracerProvider.
+	TracerProvider TracerProvider
+
+	// PersistencePolicy controls when queued events are checkpointed to
+	// the StorageAdapter.
+	//
+	// Default: persist on every enqueue and after every failed batch.
+	PersistencePolicy PersistencePolicy
+
+	// PersistInterval checkpoints the queue on a timer whenever it changed
+	// since it was last saved, in addition to PersistencePolicy. Pair it with
+	// a lighter policy such as PersistOnFailure to bound both write volume
+	// and the events a crash can lose. Storage is still cleared only after
+	// successful delivery.
+	//
+	// Optional: If not set or 0, only PersistencePolicy applies.
+	PersistInterval time.Duration
+
+	// DeliveryGuarantee selects between checkpointing the queue per
+	// PersistencePolicy (DeliveryBestEffort) and writing every event to
+	// storage before Track returns (DeliveryAtLeastOnce). AtLeastOnce
+	// requires a persistent StorageAdapter, enables EventIDs, makes Track
+	// return an error when the write fails, and ignores PersistencePolicy and
+	// PersistInterval. It cannot be combined with MemoryPressure,
+	// SpillThreshold or BudgetDegradeSpill, nor with MaxBufferSize,
+	// MaxStorageEvents or MaxStorageBytes, which would evict accepted events.
+	//
+	// Default: DeliveryBestEffort.
+	DeliveryGuarantee DeliveryGuarantee
+
+	// AuditStorage journals events tracked with TrackAudit. Each audit event
+	// is written to it before TrackAudit enqueues it and removed once the
+	// backend answers its batch, independently of StorageAdapter and
+	// DeliveryGuarantee, so regular events keep their cheaper persistence.
+	//
+	// Optional: If nil, TrackAudit returns an error.
+	AuditStorage StorageAdapter
+
+	// MaxStorageEvents caps the number of events written to the
+	// StorageAdapter. Events beyond it are evicted per StorageEviction: they
+	// stay queued in memory but are lost if the process exits first.
+	//
+	// Optional: If not set or 0, the event count is not limited.
+	MaxStorageEvents int
+
+	// MaxStorageBytes caps the serialized size of the events written to the
+	// StorageAdapter, evicting per StorageEviction like MaxStorageEvents.
+	//
+	// Optional: If not set or 0, the size is not limited.
+	MaxStorageBytes int
+
+	// StorageEviction decides which events are evicted when a storage quota
+	// is exceeded.
+	//
+	// Default: StorageEvictOldest.
+	StorageEviction StorageEvictionPolicy
+
+	// EventPriority ranks events for StorageEvictLowestPriority and
+	// FireAndForget.
+	//
+	// Required with StorageEvictLowestPriority and FireAndForget.
+	EventPriority EventPriorityFunc
+
+	// FireAndForget sends events ranked at or below its MaxPriority once
+	// through a separate adapter, such as adapters.UDPAdapter, without
+	// retries or persistence.
+	//
+	// Optional: If nil, every event is delivered reliably.
+	FireAndForget *FireAndForget
+
+	// BeforeSend hooks run in order on every tracked event before it is
+	// enqueued, letting applications enrich, redact, or drop events.
+	// A hook returning nil drops the event and skips the remaining hooks.
+	//
+	// Optional.
+	BeforeSend []BeforeSendHook
+
+	// OnDelivery is called after each batch completes, successfully or not,
+	// so applications can alert on persistent failures or mirror events.
+	// It runs synchronously on the flush path and should return quickly.
+	// The batch is copied only when a callback is set, and it may be kept.
+	//
+	// Optional.
+	OnDelivery DeliveryCallback
+
+	// SamplingRate is the fraction of events kept client-side, in [0, 1].
+	// Sampled-out events are dropped before enqueue and counted in Stats.
+	//
+	// Default: 1 (keep everything). 0 is treated as unset.
+	SamplingRate float64
+
+	// SamplingRules overrides SamplingRate per event name. Rates must be
+	// in [0, 1]; a rate of 0 drops every event with that name.
+	//
+	// Optional.
+	SamplingRules map[string]float64
+
+	// AnnotateSampleRate adds a "sampleRate" metadata field to kept events
+	// whose rate is below 1, so the backend can re-weight them.
+	//
+	// Default: false.
+	AnnotateSampleRate bool
+
+	// AllowedEvents restricts tracking to events whose name equals one of
+	// these entries or matches one as a glob pattern (path.Match syntax,
+	// e.g. "checkout_*"). Other events are dropped by Track and counted in
+	// Stats.EventsFiltered. Can be changed with ConfigUpdate or RemoteConfig.
+	//
+	// Optional: If empty, every event not in DeniedEvents is tracked.
+	AllowedEvents []string
+
+	// DeniedEvents drops events whose name equals one of these entries or
+	// matches one as a glob pattern, even if they are in AllowedEvents, so
+	// ops can silence a misbehaving event source without a code change.
+	//
+	// Optional.
+	DeniedEvents []string
+
+	// MemoryPressure is polled every MemoryCheckInterval. When it reports
+	// pressure, the in-memory queue is persisted to the StorageAdapter and
+	// trimmed, and new events are written straight to storage until the next
+	// flush reloads them. See HeapAboveBytes and HeapAboveMemoryLimitFraction.
+	//
+	// Optional.
+	MemoryPressure MemoryPressureFunc
+
+	// MemoryCheckInterval controls how often MemoryPressure is polled.
+	//
+	// Default: 5 seconds.
+	MemoryCheckInterval time.Duration
+
+	// SpillThreshold is the number of in-memory events above which the queue
+	// is spilled to the StorageAdapter, like under MemoryPressure. Overflow
+	// events stay on disk until the next flush reloads them, so it needs a
+	// persistent StorageAdapter.
+	//
+	// Optional: If not set or 0, the queue is never spilled by size.
+	SpillThreshold int
+
+	// MaxRequestsPerSecond caps outbound batch requests using a token bucket,
+	// so bursts of flushes don't overwhelm the ingestion endpoint.
+	//
+	// Optional: If not set or 0, no limit is applied.
+	MaxRequestsPerSecond float64
+
+	// RateLimitBurst is the number of batch requests that may be sent
+	// back-to-back before MaxRequestsPerSecond applies.
+	//
+	// Default: MaxRequestsPerSecond rounded up, at least 1.
+	RateLimitBurst int
+
+	// RateLimitMode decides whether excess batches wait for capacity or are
+	// re-queued and persisted for a later flush.
+	//
+	// Default: RateLimitWait.
+	RateLimitMode RateLimitMode
+
+	// ImportBatchSize is the number of events per request sent by
+	// ImportEvents.
+	//
+	// Default: 100.
+	ImportBatchSize int
+
+	// ImportRequestsPerSecond caps the requests sent by ImportEvents,
+	// independently of MaxRequestsPerSecond, so a backfill does not starve
+	// live traffic.
+	//
+	// Default: 2.
+	ImportRequestsPerSecond float64
+
+	// MaxBatchBytes caps the serialized size of a single batch request.
+	// Batches are split by whichever of MaxBatchSize or MaxBatchBytes is
+	// reached first.
+	//
+	// Optional: If not set or 0, batches are sized by count only.
+	MaxBatchBytes int
+
+	// MaxEventBytes caps the serialized size of a single event. Oversized
+	// events are handled according to OversizedEventPolicy.
+	//
+	// Optional: If not set or 0, event size is not checked.
+	MaxEventBytes int
+
+	// OversizedEventPolicy decides whether oversized events are rejected or
+	// have their payload truncated.
+	//
+	// Default: OversizedEventReject.
+	OversizedEventPolicy OversizedEventPolicy
+
+	// SequenceNumbers stamps every event with ProducerID and a monotonic
+	// Sequence so the backend can detect lost and replayed events. The
+	// counter survives restarts when the StorageAdapter implements
+	// SequenceStore.
+	//
+	// Default: false.
+	SequenceNumbers bool
+
+	// ProducerID identifies this client instance in sequenced events. Set a
+	// stable value (e.g. hostname) to continue the sequence across restarts.
+	//
+	// Optional: If not set, a random ID is generated per client.
+	ProducerID string
+
+	// EventIDs stamps every event with a random UUID in Event.ID so the
+	// backend can deduplicate events delivered more than once.
+	//
+	// Default: false.
+	EventIDs bool
+
+	// DedupWindow remembers the IDs of delivered events for this long and
+	// skips re-queued or restored copies of them. Requires EventIDs.
+	//
+	// Optional: If not set or 0, delivered events are not remembered.
+	DedupWindow time.Duration
+
+	// EventTTL drops queued events issued longer ago than this at flush
+	// time, so events delayed by outages or restored from storage do not
+	// arrive as stale data. Dropped events are counted in
+	// Stats.EventsExpired.
+	//
+	// Optional: If not set or 0, events never expire.
+	EventTTL time.Duration
+
+	// TenantResolver routes each event to a tenant's API key and endpoint,
+	// e.g. based on a "tenantId" metadata value. Events are batched per
+	// tenant while sharing the queue, flush loop and storage.
+	//
+	// Optional: If not set, all events use APIKey and the configured endpoint.
+	TenantResolver TenantResolver
+
+	// SchemaValidator validates event payloads against JSON Schemas
+	// registered per event name and "schemaVersion" metadata. Its SchemaMode
+	// decides whether non-conforming events are rejected or flagged.
+	//
+	// Optional: If not set, payloads are not validated.
+	SchemaValidator *SchemaValidator
+
+	// ResourceBudget enables accounting of queue and storage bytes and sets
+	// hard limits that trigger degradation when exceeded. See ResourceUsage.
+	//
+	// Optional: If nil, storage writes are not metered and no limits apply.
+	ResourceBudget *ResourceBudget
+
+	// App identifies the service in the platform of every event.
+	//
+	// Optional.
+	App *AppInfo
+
+	// Enrichment stamps the process ID and Go runtime version onto the
+	// platform of every event.
+	//
+	// Default: false.
+	Enrichment bool
+
+	// Redactor hashes, masks or drops personal data in events before they
+	// are queued.
+	//
+	// Optional: If nil, events are not redacted.
+	Redactor *Redactor
+
+	// Transformers rename, coerce and derive event fields before they are
+	// queued, keyed by event name. Steps under "*" run first for every
+	// event, then the steps for the event's name, each in order and each
+	// seeing the result of the previous one. They run after BeforeSend hooks
+	// and before schema validation and the Redactor, and never modify the
+	// maps passed to Track.
+	//
+	// Optional: If nil, events are not transformed.
+	Transformers map[string][]TransformStep
+
+	// SchemaMigrations upgrade event payloads from one "schemaVersion" to
+	// the next at flush time, so events queued or persisted under an old
+	// schema conform to the current one when they are finally sent.
+	// Migrations chain: an event migrated from "1" to "2" is then migrated
+	// by a "2" to "3" migration, if registered.
+	//
+	// Optional.
+	SchemaMigrations []SchemaMigration
+
+	// DoNotTrack starts the client in Do Not Track mode: stored events are
+	// purged on Init instead of restored, and tracked events are dropped
+	// until SetDoNotTrack(false) is called.
+	//
+	// Default: false.
+	DoNotTrack bool
+
+	// Enabled sets whether the client starts enabled. A disabled client
+	// drops tracked events until Enable is called; see Client.Disable.
+	//
+	// Default: true.
+	Enabled *bool
+
+	// SessionProvider supplies the session ID of each event, e.g.
+	// NewIdleSessionProvider to group a worker's events per run.
+	//
+	// Optional: If not set, events have no session ID.
+	SessionProvider SessionProvider
+
+	// HandleSignals installs SIGINT and SIGTERM handlers on Init that Close
+	// the client within ShutdownTimeout, persisting undelivered events, and
+	// then pass the signal to OnShutdownSignal. Dispose removes them.
+	//
+	// Default: false.
+	HandleSignals bool
+
+	// OnShutdownSignal is called with the signal once HandleSignals has
+	// closed the client, e.g. to stop the application's server or exit.
+	//
+	// Applications that call signal.Notify for SIGINT or SIGTERM themselves
+	// should set it, as they already receive the signal.
+	//
+	// Optional: If not set, the signal is raised again after the handlers
+	// are removed, so its default action, terminating the process, applies.
+	// Signals the process ignored before Init are not raised.
+	OnShutdownSignal func(sig os.Signal)
+
+	// ShutdownTimeout bounds the final flush when HandleSignals closes the
+	// client.
+	//
+	// Default: 10 seconds.
+	ShutdownTimeout time.Duration
+
+	// Clock drives flush scheduling, retry backoff, rate limiting, periodic
+	// checks and event timestamps. Tests can inject rippletest.Clock to
+	// advance time manually instead of waiting.
+	//
+	// Default: the system clock.
+	Clock Clock
+
+	// RemoteConfig periodically fetches settings from a control-plane
+	// endpoint and applies them with UpdateConfig, so operators can tune
+	// sampling, batch sizes and disabled events without a redeploy.
+	//
+	// Optional: If nil, the client only uses its local configuration.
+	RemoteConfig *RemoteConfig
 }
 
 type DispatcherConfig struct {
@@ -110,19 +677,263 @@ type DispatcherConfig struct {
 	// APIKeyHeader is the HTTP header name used to send the API key.
 	APIKeyHeader string
 
+	// AuthProvider supplies a bearer token for every batch.
+	AuthProvider AuthProvider
+
+	// Headers are static headers added to every batch request.
+	Headers map[string]string
+
+	// HeaderProvider returns headers added to each delivery attempt.
+	HeaderProvider HeaderProvider
+
 	// Endpoint is the base HTTPS URL of the Ripple API.
 	Endpoint string
 
 	// FlushInterval controls how often queued events are flushed.
 	FlushInterval time.Duration
 
+	// FlushIntervalJitter randomizes each flush interval by this fraction.
+	FlushIntervalJitter float64
+
 	// MaxBatchSize is the maximum number of events per batch.
 	MaxBatchSize int
 
 	// MaxRetries is the maximum number of retry attempts for failed requests.
 	MaxRetries int
 
+	// BackoffPolicy decides the delay between retries.
+	BackoffPolicy BackoffPolicy
+
+	// RetryMode decides whether retries wait inline or in a retry queue.
+	RetryMode RetryMode
+
+	// Ordering decides whether events may overtake a failed batch.
+	Ordering OrderingMode
+
+	// OrderingKey is the field OrderingPerKey orders events by.
+	OrderingKey string
+
+	// FlushTimeout bounds each flush, including retries. 0 means no bound.
+	FlushTimeout time.Duration
+
+	// RequestTimeout bounds each delivery attempt. 0 means no bound.
+	RequestTimeout time.Duration
+
+	// AdaptiveBatching tunes the batch size to endpoint latency.
+	AdaptiveBatching *AdaptiveBatching
+
 	// MaxBufferSize is the maximum number of events to persist to storage.
 	// When limit is exceeded, oldest events are evicted using FIFO policy.
 	MaxBufferSize int
+
+	// RegionalEndpoints lists endpoints to choose from by probed latency.
+	RegionalEndpoints []string
+
+	// EndpointProbeInterval controls how often RegionalEndpoints are probed.
+	EndpointProbeInterval time.Duration
+
+	// Endpoints lists endpoints to spread delivery across with health tracking.
+	Endpoints []string
+
+	// EndpointStrategy selects failover or round-robin across Endpoints.
+	EndpointStrategy EndpointStrategy
+
+	// EndpointFailureThreshold is the consecutive failures before an endpoint is skipped.
+	EndpointFailureThreshold int
+
+	// EndpointCooldown is how long an unhealthy endpoint is skipped.
+	EndpointCooldown time.Duration
+
+	// TracerProvider emits spans around flushes and delivery attempts.
+	TracerProvider TracerProvider
+
+	// PersistencePolicy controls when queued events are checkpointed.
+	PersistencePolicy PersistencePolicy
+
+	// PersistInterval checkpoints unsaved queue changes periodically.
+	PersistInterval time.Duration
+
+	// DeliveryGuarantee selects best-effort or at-least-once persistence.
+	DeliveryGuarantee DeliveryGuarantee
+
+	// AuditStorage journals audit events until they are answered.
+	AuditStorage StorageAdapter
+
+	// SchemaMigrations upgrade event payloads between schema versions at
+	// flush time.
+	SchemaMigrations []SchemaMigration
+
+	// MaxStorageEvents caps the number of persisted events.
+	MaxStorageEvents int
+
+	// MaxStorageBytes caps the serialized size of persisted events.
+	MaxStorageBytes int
+
+	// StorageEviction decides which events exceed a storage quota.
+	StorageEviction StorageEvictionPolicy
+
+	// EventPriority ranks events for StorageEvictLowestPriority and
+	// FireAndForget.
+	EventPriority EventPriorityFunc
+
+	// FireAndForget sends low-priority events once without retries.
+	FireAndForget *FireAndForget
+
+	// OnDelivery is called after each batch completes.
+	OnDelivery DeliveryCallback
+
+	// MemoryPressure triggers spilling the queue to storage when it reports true.
+	MemoryPressure MemoryPressureFunc
+
+	// MemoryCheckInterval controls how often MemoryPressure is polled.
+	MemoryCheckInterval time.Duration
+
+	// SpillThreshold is the in-memory event count above which the queue spills to storage.
+	SpillThreshold int
+
+	// MaxRequestsPerSecond caps outbound batch requests. 0 disables the limit.
+	MaxRequestsPerSecond float64
+
+	// RateLimitBurst is the token bucket capacity.
+	RateLimitBurst int
+
+	// RateLimitMode decides whether excess batches wait or spill to storage.
+	RateLimitMode RateLimitMode
+
+	// ImportBatchSize is the number of events per import request.
+	ImportBatchSize int
+
+	// ImportRequestsPerSecond caps import requests.
+	ImportRequestsPerSecond float64
+
+	// MaxBatchBytes caps the serialized size of a batch. 0 disables the limit.
+	MaxBatchBytes int
+
+	// SequenceNumbers stamps events with ProducerID and a monotonic Sequence.
+	SequenceNumbers bool
+
+	// ProducerID identifies this dispatcher in sequenced events.
+	ProducerID string
+
+	// EventIDs stamps every event with a random UUID.
+	EventIDs bool
+
+	// DedupWindow is how long delivered event IDs are remembered.
+	DedupWindow time.Duration
+
+	// EventTTL is how old an event may be when it is flushed.
+	EventTTL time.Duration
+
+	// TenantResolver routes events to per-tenant API keys and endpoints.
+	TenantResolver TenantResolver
+
+	// ResourceBudget enables resource accounting and hard limits.
+	ResourceBudget *ResourceBudget
+
+	// Clock is the source of time for timers, backoff and timestamps.
+	Clock Clock
+}
+
+// LatencyStats summarizes end-to-end delivery latency, measured from an
+// event's IssuedAt to the SentAt of the attempt that delivered it.
+type LatencyStats struct {
+	// Count is the number of delivered events included in the summary.
+	Count int64
+
+	// Average is the mean delivery latency.
+	Average time.Duration
+
+	// Max is the largest delivery latency observed.
+	Max time.Duration
+}
+
+// Stats is a point-in-time snapshot of client activity, intended for
+// health dashboards.
+type Stats struct {
+	// QueueLength is the number of events waiting to be sent.
+	QueueLength int
+
+	// HeldEvents is the number of events quarantined with HoldEvents.
+	HeldEvents int
+
+	// PausedFor is how long flushes remain paused by a server-requested
+	// backoff, or zero if they are not.
+	PausedFor time.Duration
+
+	// BatchSize is the current maximum number of events per batch, which
+	// differs from MaxBatchSize under AdaptiveBatching.
+	BatchSize int
+
+	// PendingRetries is the number of events in failed batches waiting for
+	// their next attempt under RetryScheduled.
+	PendingRetries int
+
+	// EventsTracked is the number of events accepted by the dispatcher.
+	EventsTracked int64
+
+	// EventsSampledOut is the number of events dropped by sampling.
+	EventsSampledOut int64
+
+	// EventsDisabled is the number of events dropped because their name was
+	// disabled with ConfigUpdate.DisabledEvents.
+	EventsDisabled int64
+
+	// EventsFiltered is the number of events dropped by AllowedEvents or
+	// DeniedEvents.
+	EventsFiltered int64
+
+	// BatchesSent is the number of batches delivered with a 2xx response.
+	BatchesSent int64
+
+	// EventsSent is the number of events delivered with a 2xx response.
+	EventsSent int64
+
+	// BatchesFailed is the number of batches dropped or re-queued after
+	// a failed delivery.
+	BatchesFailed int64
+
+	// DuplicatesDropped is the number of queued events skipped because an
+	// event with the same ID was delivered within DedupWindow.
+	DuplicatesDropped int64
+
+	// EventsRejected is the number of events the server rejected
+	// individually while accepting the rest of their batch.
+	EventsRejected int64
+
+	// EventsExpired is the number of queued events dropped at flush time
+	// because they were older than EventTTL.
+	EventsExpired int64
+
+	// EventsMigrated is the number of events upgraded by SchemaMigrations
+	// at flush time.
+	EventsMigrated int64
+
+	// StorageEvicted is the number of events dropped to keep storage within
+	// MaxStorageEvents and MaxStorageBytes.
+	StorageEvicted int64
+
+	// LastFlushTime is when the last non-empty flush completed.
+	// Zero if no flush has happened yet.
+	LastFlushTime time.Time
+
+	// LastDeliveryTime is when a batch was last delivered successfully.
+	// Zero if no batch has been delivered yet.
+	LastDeliveryTime time.Time
+
+	// LastError is the error of the most recent failed batch, or nil.
+	LastError error
+
+	// StorageSize is the number of events last persisted to storage.
+	StorageSize int
+
+	// StorageError is the error of the most recent failed storage operation,
+	// or nil if storage has worked since.
+	StorageError error
+
+	// Latency summarizes end-to-end delivery latency. Its Average is the
+	// client lag servers see as sentAt minus issuedAt.
+	Latency LatencyStats
+
+	// Endpoints reports per-endpoint health when Endpoints is configured.
+	Endpoints []EndpointHealth
 }
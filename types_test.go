@@ -3,10 +3,38 @@ package ripple
 import "testing"
 
 func TestHTTPError_Error(t *testing.T) {
-	err := &HTTPError{Status: 500}
-	expected := "HTTP request failed with status 500"
-	if err.Error() != expected {
-		t.Fatalf("expected %q, got %q", expected, err.Error())
+	tests := []struct {
+		name     string
+		err      *HTTPError
+		expected string
+	}{
+		{
+			name:     "status only",
+			err:      &HTTPError{Status: 500},
+			expected: "HTTP request failed with status 500 Internal Server Error",
+		},
+		{
+			name:     "unknown status",
+			err:      &HTTPError{Status: 599},
+			expected: "HTTP request failed with status 599",
+		},
+		{
+			name:     "endpoint and attempt",
+			err:      &HTTPError{Status: 503, Endpoint: "https://api.example.com/events", Attempts: 3},
+			expected: "HTTP request to https://api.example.com/events failed with status 503 Service Unavailable (attempt 3)",
+		},
+		{
+			name:     "body snippet",
+			err:      &HTTPError{Status: 400, Body: map[string]any{"detail": "bad"}},
+			expected: `HTTP request failed with status 400 Bad Request: {"detail":"bad"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, tt.err.Error())
+			}
+		})
 	}
 }
 